@@ -10,17 +10,23 @@ import (
 	"os"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"google.golang.org/api/option"
 
 	"github.com/gabrielgcmr/sonnda/internal/application/bootstrap"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
 	"github.com/gabrielgcmr/sonnda/internal/config"
+	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
+	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
 	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
 	"github.com/gabrielgcmr/sonnda/internal/kernel/observability"
 
 	"github.com/gabrielgcmr/sonnda/internal/api"
+	"github.com/gabrielgcmr/sonnda/internal/api/handlers"
 	apimw "github.com/gabrielgcmr/sonnda/internal/api/middleware"
 	"github.com/gabrielgcmr/sonnda/internal/infrastructure/ai"
 	authinfra "github.com/gabrielgcmr/sonnda/internal/infrastructure/auth"
+	"github.com/gabrielgcmr/sonnda/internal/infrastructure/mllp"
 	filestorage "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/filestorage"
 	postgress "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres"
 )
@@ -65,25 +71,87 @@ func main() {
 	defer dbClient.Close()
 
 	//6. Conectando outros servicos
-	//6.1 Storage Service (GCS)
+	//6.1 Storage Service (GCS, S3 ou disco local)
 	gcpOpts := buildGCPClientOptions(cfg)
-	storageService, err := filestorage.NewGCSObjectStorage(ctx, cfg.Storage.GCSBucket, cfg.Storage.GCPProjectID, gcpOpts...)
-	if err != nil {
-		logInfraFatal("falha ao criar storage do GCS", err)
+	var (
+		storageService domainstorage.FileStorageService
+		fileReader     domainstorage.FileReader
+		filesHandler   *handlers.FilesHandler
+	)
+	switch cfg.Storage.Driver {
+	case config.StorageDriverLocal:
+		localStorage, err := filestorage.NewLocalFileStorage(
+			cfg.Storage.LocalDir,
+			cfg.Storage.LocalPublicURL,
+			[]byte(cfg.Storage.LocalSigningKey),
+		)
+		if err != nil {
+			logInfraFatal("falha ao criar storage local", err)
+		}
+		storageService = localStorage
+		fileReader = localStorage
+		filesHandler = handlers.NewFilesHandler(localStorage)
+	case config.StorageDriverS3:
+		s3Storage, err := filestorage.NewS3ObjectStorage(filestorage.S3Config{
+			Endpoint:        cfg.Storage.S3Endpoint,
+			Region:          cfg.Storage.S3Region,
+			Bucket:          cfg.Storage.S3Bucket,
+			AccessKeyID:     cfg.Storage.S3AccessKeyID,
+			SecretAccessKey: cfg.Storage.S3SecretAccessKey,
+			UseSSL:          cfg.Storage.S3UseSSL,
+			ForcePathStyle:  cfg.Storage.S3ForcePathStyle,
+		})
+		if err != nil {
+			logInfraFatal("falha ao criar storage S3", err)
+		}
+		storageService = s3Storage
+		fileReader = s3Storage
+	default:
+		gcsStorage, err := filestorage.NewGCSObjectStorage(ctx, cfg.Storage.GCSBucket, cfg.Storage.GCPProjectID, gcpOpts...)
+		if err != nil {
+			logInfraFatal("falha ao criar storage do GCS", err)
+		}
+		defer gcsStorage.Close()
+		storageService = gcsStorage
+		fileReader = gcsStorage
 	}
-	defer storageService.Close()
 
-	//6.2 Document AI Service
-	docAIClient, err := ai.NewClient(ctx, cfg.Storage.GCPProjectID, cfg.Storage.GCPLocation, gcpOpts...)
-	if err != nil {
-		logInfraFatal("falha ao criar DocAI client", err)
+	//6.2 Extração de laudos (camada de texto do PDF e/ou Document AI)
+	var (
+		docAIExtractor domainai.DocumentExtractorService
+		docAIAdapter   *ai.DocumentAIAdapter
+	)
+	if cfg.Extractor.UsesDocumentAI() {
+		docAIClient, err := ai.NewClient(ctx, cfg.Storage.GCPProjectID, cfg.Storage.GCPLocation, gcpOpts...)
+		if err != nil {
+			logInfraFatal("falha ao criar DocAI client", err)
+		}
+		defer docAIClient.Close()
+
+		docAIAdapter = ai.NewDocumentAIAdapter(
+			*docAIClient,
+			cfg.Storage.GCPExtractLabsProcessorID,
+			fileReader,
+		)
+		docAIExtractor = docAIAdapter
+	}
+	if cfg.Extractor.FixturesMode != "" {
+		fixtureExtractor, err := ai.NewFixtureExtractor(
+			fileReader,
+			cfg.Extractor.FixturesDir,
+			ai.FixtureMode(cfg.Extractor.FixturesMode),
+			docAIAdapter,
+		)
+		if err != nil {
+			logInfraFatal("falha ao criar extractor de fixtures", err)
+		}
+		docAIExtractor = fixtureExtractor
 	}
-	defer docAIClient.Close()
 
-	docExtractor := ai.NewDocumentAIAdapter(
-		*docAIClient,
-		cfg.Storage.GCPExtractLabsProcessorID,
-	)
+	docExtractor := docAIExtractor
+	if cfg.Extractor.Driver != config.LabExtractorDocumentAI {
+		docExtractor = ai.NewPDFTextExtractor(fileReader, docAIExtractor)
+	}
 
 	//6.3 Auth (Supabase)
 	apiAuthProvider, err := authinfra.NewSupabaseBearerProvider(authinfra.SupabaseBearerConfig{
@@ -96,7 +164,37 @@ func main() {
 	}
 
 	//7. Módulos
-	modules := bootstrap.NewModules(dbClient, docExtractor, storageService)
+	modules := bootstrap.NewModules(dbClient, docExtractor, storageService, labsuc.LabJobWorkerConfig{
+		Concurrency:     cfg.Worker.LabConcurrency,
+		PollInterval:    cfg.Worker.LabPollInterval,
+		JobTimeout:      cfg.Worker.LabJobTimeout,
+		RequeueInterval: cfg.Worker.LabRequeueInterval,
+		MaxAttempts:     cfg.Worker.LabMaxAttempts,
+	})
+
+	//7.1 Worker de processamento de laudos (fila no Postgres)
+	workerCtx, stopWorker := context.WithCancel(observability.IntoContext(ctx, appLogger))
+	defer stopWorker()
+	go modules.Labs.Worker.Run(workerCtx)
+
+	//7.2 Listener MLLP para resultados HL7 v2 (opcional)
+	if cfg.HL7.MLLPEnabled() {
+		// Já validado em config.Load.
+		allowedNets, _ := cfg.HL7.MLLPAllowedPrefixes()
+		mllpServer := mllp.NewServer(mllp.Config{
+			Addr:           cfg.HL7.MLLPAddr,
+			ReadTimeout:    cfg.HL7.MLLPReadTimeout,
+			MaxMessageSize: cfg.HL7.MLLPMaxMessageKB * 1024,
+			AllowedNets:    allowedNets,
+			MaxConns:       cfg.HL7.MLLPMaxConnections,
+		}, labsuc.NewHL7Receiver(modules.Labs.HL7Import, uuid.MustParse(cfg.HL7.MLLPUploaderUserID)), appLogger)
+		go func() {
+			if err := mllpServer.ListenAndServe(workerCtx); err != nil {
+				slog.Error("failed to start MLLP listener", "error", err)
+			}
+		}()
+		slog.Info("HL7 MLLP listener enabled", slog.String("addr", cfg.HL7.MLLPAddr))
+	}
 
 	//8 Middlewares
 	//8.1 API
@@ -122,6 +220,15 @@ func main() {
 			UserHandler:            modules.User.Handler,
 			PatientHandler:         modules.Patient.Handler,
 			LabsHandler:            modules.Labs.Handler,
+			AnalytesHandler:        modules.Labs.AnalytesHandler,
+			LabsFHIRHandler:        modules.Labs.FHIRHandler,
+			LabsHL7Handler:         modules.Labs.HL7Handler,
+			LabsManualHandler:      modules.Labs.ManualHandler,
+			LabsAmendHandler:       modules.Labs.AmendHandler,
+			LabsReviewHandler:      modules.Labs.ReviewHandler,
+			LabsCriticalHandler:    modules.Labs.CriticalHandler,
+			LabsExportHandler:      modules.Labs.ExportHandler,
+			FilesHandler:           filesHandler,
 		},
 	})
 
@@ -0,0 +1,106 @@
+// internal/api/handlers/analytes.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	authorization "github.com/gabrielgcmr/sonnda/internal/application/services/authorization"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+)
+
+// AnalytesHandler expõe o catálogo de analitos (LOINC) usado para unificar séries.
+type AnalytesHandler struct {
+	catalog labsvc.AnalyteCatalog
+	authz   authorization.Authorizer
+}
+
+type analyteSynonymRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func NewAnalytesHandler(catalog labsvc.AnalyteCatalog, authz authorization.Authorizer) *AnalytesHandler {
+	return &AnalytesHandler{
+		catalog: catalog,
+		authz:   authz,
+	}
+}
+
+// GET /labs/analytes
+func (h *AnalytesHandler) List(c *gin.Context) {
+	list, err := h.catalog.ListAnalytes(c.Request.Context())
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// POST /labs/analytes/:code/synonyms
+func (h *AnalytesHandler) AddSynonym(c *gin.Context) {
+	if !h.requireManage(c) {
+		return
+	}
+
+	var req analyteSynonymRequest
+	if err := helpers.BindJSON(c, &req); err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	out, err := h.catalog.AddSynonym(c.Request.Context(), c.Param("code"), req.Name)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// DELETE /labs/analytes/:code/synonyms/:name
+func (h *AnalytesHandler) RemoveSynonym(c *gin.Context) {
+	if !h.requireManage(c) {
+		return
+	}
+
+	out, err := h.catalog.RemoveSynonym(c.Request.Context(), c.Param("code"), c.Param("name"))
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// POST /labs/analytes/remap
+func (h *AnalytesHandler) Remap(c *gin.Context) {
+	if !h.requireManage(c) {
+		return
+	}
+
+	out, err := h.catalog.Remap(c.Request.Context())
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *AnalytesHandler) requireManage(c *gin.Context) bool {
+	if h.authz == nil {
+		return true
+	}
+
+	currentUser := helpers.MustGetCurrentUser(c)
+	if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionManageLabCatalog, nil); err != nil {
+		presenter.ErrorResponder(c, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+// internal/api/handlers/files.go
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SignedFileOpener abre arquivos a partir de um link assinado (storage local).
+type SignedFileOpener interface {
+	OpenSigned(objectName, expires, signature string) (*os.File, error)
+}
+
+// FilesHandler serve os links gerados por GetSignedURL do storage local.
+// A autorização está na assinatura do link, por isso a rota é pública.
+type FilesHandler struct {
+	opener SignedFileOpener
+}
+
+func NewFilesHandler(opener SignedFileOpener) *FilesHandler {
+	return &FilesHandler{opener: opener}
+}
+
+func (h *FilesHandler) Download(c *gin.Context) {
+	objectName := c.Param("path")
+
+	f, err := h.opener.OpenSigned(objectName, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		presenter.ErrorResponder(c, &apperr.AppError{
+			Kind:    apperr.INFRA_STORAGE_ERROR,
+			Message: "falha ao ler arquivo",
+			Cause:   err,
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	http.ServeContent(c.Writer, c.Request, path.Base(objectName), info.ModTime(), f)
+}
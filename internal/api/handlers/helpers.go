@@ -49,6 +49,49 @@ func parsePatientIDParam(c *gin.Context, id string) (uuid.UUID, bool) {
 	return parsedID, true
 }
 
+// parseUUIDQuery lê um UUID obrigatório da query string.
+func parseUUIDQuery(c *gin.Context, param string) (uuid.UUID, bool) {
+	idStr := c.Query(param)
+	if idStr == "" {
+		presenter.ErrorResponder(c, apperr.Validation("parâmetros inválidos",
+			apperr.Violation{Field: param, Reason: "required"}))
+		return uuid.UUID{}, false
+	}
+
+	parsedID, err := uuid.Parse(idStr)
+	if err != nil {
+		presenter.ErrorResponder(c, apperr.Validation("parâmetros inválidos",
+			apperr.Violation{Field: param, Reason: "invalid_uuid"}))
+		return uuid.UUID{}, false
+	}
+
+	return parsedID, true
+}
+
+// parseUUIDParam lê um path param UUID; field é o nome usado nas mensagens de erro.
+func parseUUIDParam(c *gin.Context, param, field string) (uuid.UUID, bool) {
+	idStr := c.Param(param)
+	if idStr == "" {
+		presenter.ErrorResponder(c, &apperr.AppError{
+			Kind:    apperr.REQUIRED_FIELD_MISSING,
+			Message: field + " é obrigatório",
+		})
+		return uuid.UUID{}, false
+	}
+
+	parsedID, err := uuid.Parse(idStr)
+	if err != nil {
+		presenter.ErrorResponder(c, &apperr.AppError{
+			Kind:    apperr.INVALID_FIELD_FORMAT,
+			Message: field + " inválido",
+			Cause:   err,
+		})
+		return uuid.UUID{}, false
+	}
+
+	return parsedID, true
+}
+
 func ParseGender(genderStr string) (demographics.Gender, error) {
 	gender, err := demographics.ParseGender(genderStr)
 	if err != nil {
@@ -2,6 +2,10 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,29 +21,37 @@ import (
 
 	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
 	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
 	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
 	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
 )
 
 type LabsHandler struct {
-	svc      labsvc.Service
-	createUC labsuc.CreateLabReportFromDocumentUseCase
-	storage  domainstorage.FileStorageService
-	authz    authorization.Authorizer
+	svc       labsvc.Service
+	enqueueUC labsuc.EnqueueLabReportProcessingUseCase
+	storage   domainstorage.FileStorageService
+	authz     authorization.Authorizer
+}
+
+// labJobResponse expõe o job; Error segue o contrato Problem Details quando o job falhou.
+type labJobResponse struct {
+	*labsvc.ProcessingJobOutput
+	Error *presenter.Problem `json:"error,omitempty"`
 }
 
 func NewLabs(
 	svc labsvc.Service,
-	createUC labsuc.CreateLabReportFromDocumentUseCase,
+	enqueueUC labsuc.EnqueueLabReportProcessingUseCase,
 	storageClient domainstorage.FileStorageService,
 	authz authorization.Authorizer,
 ) *LabsHandler {
 	return &LabsHandler{
-		svc:      svc,
-		createUC: createUC,
-		storage:  storageClient,
-		authz:    authz,
+		svc:       svc,
+		enqueueUC: enqueueUC,
+		storage:   storageClient,
+		authz:     authz,
 	}
 }
 
@@ -86,6 +98,8 @@ func (h *LabsHandler) ListLabs(c *gin.Context) {
 // Handler unico para upload de laudo
 // POST /:patientID/labs
 // field: file (PDF/JPEG/PNG)
+// O processamento é assíncrono: responde 202 com o job a ser acompanhado em
+// GET /:patientID/labs/jobs/:jobID.
 func (h *LabsHandler) UploadAndProcessLabs(c *gin.Context) {
 	currentUser := helpers.MustGetCurrentUser(c)
 
@@ -101,80 +115,437 @@ func (h *LabsHandler) UploadAndProcessLabs(c *gin.Context) {
 		}
 	}
 
-	documentURI, mimeType, uploadErr := h.handleFileUpload(c, patientID)
+	identityOverride, ok := h.parseIdentityOverride(c, currentUser, patientID)
+	if !ok {
+		return
+	}
+
+	documentURI, mimeType, contentHash, uploadErr := h.handleFileUpload(c, patientID)
 	if uploadErr != nil {
-		presenter.ErrorResponder(c, uploadErr)
+		respondUploadError(c, patientID, uploadErr)
 		return
 	}
 
-	output, uploadErr := h.createUC.Execute(c.Request.Context(), labsuc.CreateLabReportFromDocumentInput{
+	job, err := h.enqueueUC.Execute(c.Request.Context(), labsuc.CreateLabReportFromDocumentInput{
 		PatientID:        patientID,
 		DocumentURI:      documentURI,
 		MimeType:         mimeType,
 		UploadedByUserID: currentUser.ID,
+		ContentHash:      contentHash,
+		IdentityOverride: identityOverride,
 	})
-	if uploadErr != nil {
-		presenter.ErrorResponder(c, uploadErr)
+	if err != nil {
+		// Sem job, o arquivo enviado ficaria órfão no storage.
+		_ = h.storage.Delete(c.Request.Context(), documentURI)
+		respondUploadError(c, patientID, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, output)
+	c.Header("Location", labJobLocation(patientID, job.ID))
+	c.JSON(http.StatusAccepted, labJobResponse{ProcessingJobOutput: job})
 }
 
-// handleFileUpload centraliza toda a logica de:
-// - ler o arquivo do multipart
-// - detectar/validar content-type
-// - fazer upload pro storage
-// - retornar (URI, MIME)
+// GET /:patientID/labs/timeline?analyte=hba1c&analyte=creatinine&from=&to=
+// analyte pode se repetir ou vir separado por vírgula; cada um vira uma série.
+func (h *LabsHandler) GetTimeline(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	limit, _, ok := parsePagination(c, 100, 0)
+	if !ok {
+		return
+	}
+
+	timeline, err := h.svc.Timeline(c.Request.Context(), labsvc.TimelineInput{
+		PatientID: patientID,
+		Analytes:  analyteQuery(c),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+		Limit:     limit,
+	})
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+// GET /:patientID/labs/compare?a=&b=
+// a é a referência (em geral o laudo anterior); as diferenças são de b em relação a a.
+func (h *LabsHandler) CompareLabs(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	reportA, ok := parseUUIDQuery(c, "a")
+	if !ok {
+		return
+	}
+	reportB, ok := parseUUIDQuery(c, "b")
+	if !ok {
+		return
+	}
+
+	out, err := h.svc.Compare(c.Request.Context(), patientID, reportA, reportB)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// GET /:patientID/labs/search?q=
+func (h *LabsHandler) SearchLabs(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	limit, offset, ok := parsePagination(c, 20, 0)
+	if !ok {
+		return
+	}
+
+	hits, err := h.svc.Search(c.Request.Context(), patientID, c.Query("q"), limit, offset)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hits)
+}
+
+// GET /:patientID/labs/:reportID
+func (h *LabsHandler) GetLab(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reportID, ok := parseUUIDParam(c, "reportID", "report_id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	report, err := h.svc.Get(c.Request.Context(), patientID, reportID)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// DELETE /:patientID/labs/:reportID
+// Remove o laudo e o arquivo original do storage.
+func (h *LabsHandler) DeleteLab(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reportID, ok := parseUUIDParam(c, "reportID", "report_id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionDeleteLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), patientID, reportID); err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /:patientID/labs/:reportID/history
+// Lista as correções do laudo com quem alterou e o valor anterior de cada campo.
+func (h *LabsHandler) GetLabHistory(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reportID, ok := parseUUIDParam(c, "reportID", "report_id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	history, err := h.svc.History(c.Request.Context(), patientID, reportID)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GET /:patientID/labs/:reportID/document
+// Devolve um link temporário (assinado) para o arquivo original.
+func (h *LabsHandler) GetLabDocument(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reportID, ok := parseUUIDParam(c, "reportID", "report_id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	document, err := h.svc.DocumentURL(c.Request.Context(), patientID, reportID)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	// O link expira; não deve ser reaproveitado por caches intermediários.
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, document)
+}
+
+// GET /:patientID/labs/jobs/:jobID
+func (h *LabsHandler) GetLabJob(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	jobID, ok := parseUUIDParam(c, "jobID", "job_id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	job, err := h.svc.GetJob(c.Request.Context(), patientID, jobID)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	resp := labJobResponse{ProcessingJobOutput: job}
+	if job.Status == string(labs.JobStatusFailed) {
+		resp.Error = jobProblem(job)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func labJobLocation(patientID, jobID uuid.UUID) string {
+	return fmt.Sprintf("/v1/patients/%s/labs/jobs/%s", patientID, jobID)
+}
+
+// parseIdentityOverride lê o campo identity_override do multipart. Aceitar um
+// laudo que não confere com o paciente exige permissão própria.
+func (h *LabsHandler) parseIdentityOverride(c *gin.Context, currentUser *user.User, patientID uuid.UUID) (bool, bool) {
+	raw := strings.TrimSpace(c.PostForm("identity_override"))
+	if raw == "" {
+		return false, true
+	}
+	override, err := strconv.ParseBool(raw)
+	if err != nil {
+		presenter.ErrorResponder(c, apperr.Validation("entrada inválida", apperr.Violation{Field: "identity_override", Reason: "invalid"}))
+		return false, false
+	}
+	if override && h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionOverrideLabIdentity, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return false, false
+		}
+	}
+	return override, true
+}
+
+// respondUploadError aponta, no 409 de arquivo repetido, para o laudo que já
+// existe ou para o job que ainda o processa.
+func respondUploadError(c *gin.Context, patientID uuid.UUID, err error) {
+	var appErr *apperr.AppError
+	if errors.As(err, &appErr) && appErr.ResourceID != "" {
+		switch appErr.Kind {
+		case apperr.RESOURCE_ALREADY_EXISTS:
+			c.Header("Location", fmt.Sprintf("/v1/patients/%s/labs/%s", patientID, appErr.ResourceID))
+		case apperr.RESOURCE_CONFLICT:
+			if jobID, err := uuid.Parse(appErr.ResourceID); err == nil {
+				c.Header("Location", labJobLocation(patientID, jobID))
+			}
+		}
+	}
+	presenter.ErrorResponder(c, err)
+}
+
+// jobProblem reconstrói o Problem Details a partir do erro gravado no job.
+func jobProblem(job *labsvc.ProcessingJobOutput) *presenter.Problem {
+	kind := apperr.INTERNAL_ERROR
+	if job.ErrorCode != nil && *job.ErrorCode != "" {
+		kind = apperr.ErrorKind(*job.ErrorCode)
+	}
+	message := "erro inesperado"
+	if job.ErrorMessage != nil && *job.ErrorMessage != "" {
+		message = *job.ErrorMessage
+	}
+
+	_, problem := presenter.ToProblem(&apperr.AppError{Kind: kind, Message: message}, presenter.ProblemMeta{
+		Instance: "urn:sonnda:lab-job:" + job.ID.String(),
+	})
+	if job.FinishedAt != nil {
+		problem.Timestamp = *job.FinishedAt
+	}
+	return &problem
+}
+
+// maxLabFileSize vale para cada arquivo, avulso ou dentro de um lote.
+const maxLabFileSize = 10 * 1024 * 1024 // 10MB
+
+// labUpload é um arquivo de laudo a gravar, vindo do multipart ou de um ZIP.
+type labUpload struct {
+	size        int64
+	contentType string // vazio: detectado pelo conteúdo
+	file        io.ReadSeeker
+	// claimHash, quando presente, recusa o hash antes da checagem no banco
+	// (ex.: arquivo repetido dentro do mesmo lote).
+	claimHash func(contentHash string) error
+}
+
+// handleFileUpload lê o campo file do multipart e o grava com storeLabDocument.
 func (h *LabsHandler) handleFileUpload(
 	c *gin.Context,
 	patientID uuid.UUID,
-) (string, string, error) {
-	const MaxFileSize = 10 * 1024 * 1024 // 10MB
-
+) (string, string, string, error) {
 	fileHeader, err := c.FormFile("file")
 	if err != nil {
-		return "", "", &apperr.AppError{
+		return "", "", "", &apperr.AppError{
 			Kind:    apperr.REQUIRED_FIELD_MISSING,
 			Message: "arquivo é obrigatório",
 			Cause:   err,
 		}
 	}
-	if fileHeader.Size == 0 {
-		return "", "", &apperr.AppError{
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", "", "", apperr.Internal("falha ao abrir arquivo", err)
+	}
+	defer file.Close()
+
+	return h.storeLabDocument(c.Request.Context(), patientID, labUpload{
+		size:        fileHeader.Size,
+		contentType: fileHeader.Header.Get("Content-Type"),
+		file:        file,
+	})
+}
+
+// storeLabDocument centraliza toda a logica de:
+// - detectar/validar content-type e tamanho
+// - calcular o SHA-256 e recusar arquivo já enviado para o paciente
+// - fazer upload pro storage
+// - retornar (URI, MIME, SHA-256)
+func (h *LabsHandler) storeLabDocument(
+	ctx context.Context,
+	patientID uuid.UUID,
+	upload labUpload,
+) (string, string, string, error) {
+	if upload.size == 0 {
+		return "", "", "", &apperr.AppError{
 			Kind:    apperr.VALIDATION_FAILED,
 			Message: "arquivo vazio",
 		}
 	}
 
-	if fileHeader.Size > MaxFileSize {
-		return "", "", &apperr.AppError{
+	if upload.size > maxLabFileSize {
+		return "", "", "", &apperr.AppError{
 			Kind:    apperr.UPLOAD_SIZE_EXCEEDED,
 			Message: "arquivo muito grande",
 		}
 	}
 
-	file, err := fileHeader.Open()
-	if err != nil {
-		return "", "", apperr.Internal("falha ao abrir arquivo", err)
-	}
-	defer file.Close()
-
-	contentType := fileHeader.Header.Get("Content-Type")
+	file := upload.file
+	contentType := upload.contentType
 	if contentType == "" {
 		buf := make([]byte, 512)
 		n, _ := file.Read(buf)
 		contentType = http.DetectContentType(buf[:n])
 
-		if seeker, ok := file.(io.Seeker); ok {
-			_, _ = seeker.Seek(0, io.SeekStart)
-		}
+		_, _ = file.Seek(0, io.SeekStart)
 	}
 
 	contentType = normalizeMimeType(contentType)
 
 	if !isSupportedMimeType(contentType) {
-		return "", "", &apperr.AppError{
+		return "", "", "", &apperr.AppError{
 			Kind:    apperr.INVALID_FIELD_FORMAT,
 			Message: "tipo de arquivo não suportado",
 			Cause:   fmt.Errorf("content_type=%s", contentType),
@@ -184,7 +555,7 @@ func (h *LabsHandler) handleFileUpload(
 	uniqueID := uuid.NewString()
 	ext := mimeToExt(contentType)
 	if ext == "" {
-		return "", "", &apperr.AppError{
+		return "", "", "", &apperr.AppError{
 			Kind:    apperr.INVALID_FIELD_FORMAT,
 			Message: "tipo de arquivo não suportado",
 			Cause:   fmt.Errorf("content_type=%s", contentType),
@@ -192,21 +563,43 @@ func (h *LabsHandler) handleFileUpload(
 	}
 
 	if patientID == uuid.Nil {
-		return "", "", apperr.Validation("entrada inválida", apperr.Violation{Field: "patient_id", Reason: "required"})
+		return "", "", "", apperr.Validation("entrada inválida", apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+
+	// O hash sai antes do upload: duplicata não chega ao storage nem à IA.
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", "", "", apperr.Internal("falha ao ler arquivo", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", "", "", apperr.Internal("falha ao ler arquivo", err)
+	}
+	contentHash := hex.EncodeToString(hasher.Sum(nil))
+
+	if upload.claimHash != nil {
+		if err := upload.claimHash(contentHash); err != nil {
+			return "", "", "", err
+		}
+	}
+	if err := h.svc.CheckDuplicateDocument(ctx, patientID, contentHash); err != nil {
+		return "", "", "", err
+	}
+	if err := h.svc.CheckDocumentInProcessing(ctx, patientID, contentHash); err != nil {
+		return "", "", "", err
 	}
 
 	objectName := fmt.Sprintf("patients/%s/lab-reports/%s%s", patientID.String(), uniqueID, ext)
 
-	uri, err := h.storage.Upload(c.Request.Context(), file, objectName, contentType)
+	uri, err := h.storage.Upload(ctx, file, objectName, contentType)
 	if err != nil {
-		return "", "", &apperr.AppError{
+		return "", "", "", &apperr.AppError{
 			Kind:    apperr.INFRA_STORAGE_ERROR,
 			Message: "falha no upload",
 			Cause:   err,
 		}
 	}
 
-	return uri, contentType, nil
+	return uri, contentType, contentHash, nil
 }
 
 func parsePagination(c *gin.Context, defaultLimit, defaultOffset int) (limit, offset int, ok bool) {
@@ -0,0 +1,104 @@
+// internal/api/handlers/labs_amend.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	authorization "github.com/gabrielgcmr/sonnda/internal/application/services/authorization"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+)
+
+// LabsAmendHandler corrige laudos já gravados.
+type LabsAmendHandler struct {
+	amendUC labsuc.AmendLabReportUseCase
+	authz   authorization.Authorizer
+}
+
+// Como no cadastro manual, a validação (inclusive version obrigatória) fica
+// no caso de uso, que aponta o índice de cada alteração inválida.
+type amendLabReportRequest struct {
+	Version *int                    `json:"version"`
+	Status  *string                 `json:"status,omitempty"`
+	Reason  *string                 `json:"reason,omitempty"`
+	Changes []labFieldChangeRequest `json:"changes"`
+}
+
+type labFieldChangeRequest struct {
+	Target   string  `json:"target"`
+	TargetID *string `json:"target_id,omitempty"`
+	Field    string  `json:"field"`
+	Value    *string `json:"value"`
+}
+
+func NewLabsAmendHandler(
+	amendUC labsuc.AmendLabReportUseCase,
+	authz authorization.Authorizer,
+) *LabsAmendHandler {
+	return &LabsAmendHandler{
+		amendUC: amendUC,
+		authz:   authz,
+	}
+}
+
+// PATCH /:patientID/labs/:reportID
+func (h *LabsAmendHandler) AmendLab(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reportID, ok := parseUUIDParam(c, "reportID", "report_id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionAmendLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	var req amendLabReportRequest
+	if err := helpers.BindJSON(c, &req); err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	out, err := h.amendUC.Execute(c.Request.Context(), req.toInput(patientID, reportID, currentUser.ID))
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (r amendLabReportRequest) toInput(patientID, reportID, changedBy uuid.UUID) labsuc.AmendLabReportInput {
+	input := labsuc.AmendLabReportInput{
+		PatientID:       patientID,
+		ReportID:        reportID,
+		ChangedByUserID: changedBy,
+		Version:         r.Version,
+		Status:          r.Status,
+		Reason:          r.Reason,
+		Changes:         make([]labsuc.LabFieldEditInput, 0, len(r.Changes)),
+	}
+	for _, ch := range r.Changes {
+		input.Changes = append(input.Changes, labsuc.LabFieldEditInput{
+			Target:   ch.Target,
+			TargetID: ch.TargetID,
+			Field:    ch.Field,
+			Value:    ch.Value,
+		})
+	}
+	return input
+}
@@ -0,0 +1,80 @@
+// internal/api/handlers/labs_amend_test.go
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeAmendUseCase struct {
+	input *labsuc.AmendLabReportInput
+}
+
+func (f *fakeAmendUseCase) Execute(ctx context.Context, input labsuc.AmendLabReportInput) (*labsvc.LabReportOutput, error) {
+	f.input = &input
+	return &labsvc.LabReportOutput{ID: input.ReportID, PatientID: input.PatientID, Status: "corrected", Version: 2}, nil
+}
+
+func TestAmendLab_MapsRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uc := &fakeAmendUseCase{}
+	h := NewLabsAmendHandler(uc, allowAllAuthorizer{})
+	userID := uuid.Must(uuid.NewV7())
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: userID, AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.PATCH("/patients/:id/labs/:reportID", h.AmendLab)
+
+	itemID := uuid.Must(uuid.NewV7())
+	body := `{
+		"version": 1,
+		"reason": "Valor digitado errado",
+		"changes": [
+			{"target": "item", "target_id": "` + itemID.String() + `", "field": "result_value", "value": "9,2"},
+			{"target": "report", "field": "lab_phone", "value": null}
+		]
+	}`
+	patientID := uuid.Must(uuid.NewV7())
+	reportID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodPatch, "/patients/"+patientID.String()+"/labs/"+reportID.String(), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, resp.Code, resp.Body.String())
+	}
+
+	in := uc.input
+	if in == nil || in.PatientID != patientID || in.ReportID != reportID || in.ChangedByUserID != userID {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+	if in.Version == nil || *in.Version != 1 || in.Status != nil {
+		t.Fatalf("unexpected version/status: %+v", in)
+	}
+	if len(in.Changes) != 2 {
+		t.Fatalf("expected two changes, got %+v", in.Changes)
+	}
+	first := in.Changes[0]
+	if first.Target != "item" || first.TargetID == nil || *first.TargetID != itemID.String() || *first.Value != "9,2" {
+		t.Fatalf("unexpected first change: %+v", first)
+	}
+	if in.Changes[1].Value != nil {
+		t.Fatalf("expected null value to clear the field, got %q", *in.Changes[1].Value)
+	}
+}
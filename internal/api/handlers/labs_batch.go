@@ -0,0 +1,431 @@
+// internal/api/handlers/labs_batch.go
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+const (
+	// maxLabBatchSize limita o corpo inteiro (todas as partes ou o ZIP).
+	maxLabBatchSize = 100 * 1024 * 1024 // 100MB
+	// maxLabBatchFiles conta os arquivos já expandidos dos ZIPs.
+	maxLabBatchFiles = 50
+	// labBatchParallelism é quantos arquivos são gravados ao mesmo tempo.
+	labBatchParallelism = 4
+)
+
+// Resultado de cada arquivo do lote.
+const (
+	labBatchCreated   = "created"
+	labBatchDuplicate = "duplicate"
+	labBatchFailed    = "failed"
+)
+
+type labBatchResponse struct {
+	Results []labBatchResult `json:"results"`
+}
+
+// labBatchResult descreve um arquivo do lote. Archive é o ZIP de onde o
+// arquivo saiu; Error segue o contrato Problem Details.
+type labBatchResult struct {
+	File        string                      `json:"file"`
+	Archive     string                      `json:"archive,omitempty"`
+	Status      string                      `json:"status"`
+	Job         *labsvc.ProcessingJobOutput `json:"job,omitempty"`
+	Location    string                      `json:"location,omitempty"`
+	LabReportID string                      `json:"lab_report_id,omitempty"`
+	Error       *presenter.Problem          `json:"error,omitempty"`
+}
+
+// labBatchEntry é um arquivo a gravar. open roda dentro do worker, então só
+// labBatchParallelism entradas de ZIP ficam descompactadas em memória.
+type labBatchEntry struct {
+	file    string
+	archive string
+	open    func() (labUpload, func(), error)
+}
+
+// POST /:patientID/labs/batch
+// field: file (repetível; PDF/JPEG/PNG ou ZIP com esses arquivos)
+// Cada arquivo vira um job, como no upload avulso. Responde 207 com o
+// resultado de cada arquivo; um arquivo inválido não derruba o lote.
+func (h *LabsHandler) UploadLabsBatch(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionUploadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxLabBatchSize)
+	form, err := c.MultipartForm()
+	if err != nil {
+		presenter.ErrorResponder(c, batchFormError(err))
+		return
+	}
+
+	identityOverride, ok := h.parseIdentityOverride(c, currentUser, patientID)
+	if !ok {
+		return
+	}
+
+	entries, cleanup, err := collectLabBatchEntries(form.File["file"])
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+	defer cleanup()
+
+	seen := newLabBatchHashes()
+	results := make([]labBatchResult, len(entries))
+	sem := make(chan struct{}, labBatchParallelism)
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = h.processLabBatchEntry(c, patientID, currentUser.ID, identityOverride, entry, seen)
+		}()
+	}
+	wg.Wait()
+
+	c.JSON(http.StatusMultiStatus, labBatchResponse{Results: results})
+}
+
+// processLabBatchEntry grava e enfileira um arquivo do lote.
+func (h *LabsHandler) processLabBatchEntry(
+	c *gin.Context,
+	patientID, uploadedBy uuid.UUID,
+	identityOverride bool,
+	entry labBatchEntry,
+	seen *labBatchHashes,
+) labBatchResult {
+	ctx := c.Request.Context()
+	result := labBatchResult{File: entry.file, Archive: entry.archive}
+
+	upload, closeFn, err := entry.open()
+	if err != nil {
+		return failedBatchResult(c, result, err)
+	}
+	defer closeFn()
+
+	// O hash só fica reservado se o job for criado; em qualquer falha a cópia
+	// seguinte do mesmo arquivo no lote assume a vez.
+	var settle func(kept bool)
+	upload.claimHash = func(contentHash string) (err error) {
+		settle, err = seen.claim(ctx, contentHash, entry.file)
+		return err
+	}
+	enqueued := false
+	defer func() {
+		if settle != nil {
+			settle(enqueued)
+		}
+	}()
+
+	documentURI, mimeType, contentHash, err := h.storeLabDocument(ctx, patientID, upload)
+	if err != nil {
+		return failedBatchResult(c, result, err)
+	}
+
+	job, err := h.enqueueUC.Execute(ctx, labsuc.CreateLabReportFromDocumentInput{
+		PatientID:        patientID,
+		DocumentURI:      documentURI,
+		MimeType:         mimeType,
+		UploadedByUserID: uploadedBy,
+		ContentHash:      contentHash,
+		IdentityOverride: identityOverride,
+	})
+	if err != nil {
+		// Sem job, o arquivo enviado ficaria órfão no storage.
+		_ = h.storage.Delete(ctx, documentURI)
+		return failedBatchResult(c, result, err)
+	}
+	enqueued = true
+
+	result.Status = labBatchCreated
+	result.Job = job
+	result.Location = labJobLocation(patientID, job.ID)
+	return result
+}
+
+// failedBatchResult converte o erro em Problem Details. Arquivo repetido vira
+// "duplicate", com o laudo existente quando ele já estava gravado.
+func failedBatchResult(c *gin.Context, result labBatchResult, err error) labBatchResult {
+	result.Status = labBatchFailed
+	if apperr.HasCode(err, apperr.RESOURCE_ALREADY_EXISTS) {
+		result.Status = labBatchDuplicate
+		var appErr *apperr.AppError
+		if errors.As(err, &appErr) {
+			result.LabReportID = appErr.ResourceID
+		}
+	}
+
+	rid := strings.TrimSpace(c.GetString("request_id"))
+	instance := c.Request.URL.Path
+	if rid != "" {
+		instance = "urn:sonnda:request-id:" + rid
+	}
+	_, problem := presenter.ToProblem(err, presenter.ProblemMeta{Instance: instance, TraceID: rid})
+	result.Error = &problem
+	return result
+}
+
+func batchFormError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return &apperr.AppError{
+			Kind:    apperr.UPLOAD_SIZE_EXCEEDED,
+			Message: "lote muito grande",
+			Cause:   err,
+		}
+	}
+	return &apperr.AppError{
+		Kind:    apperr.VALIDATION_FAILED,
+		Message: "multipart inválido",
+		Cause:   err,
+	}
+}
+
+// labBatchHashes recusa conteúdo repetido dentro do próprio lote: o primeiro
+// arquivo ainda não virou laudo, então a checagem no banco não o pegaria.
+type labBatchHashes struct {
+	mu     sync.Mutex
+	claims map[string]*labBatchClaim
+}
+
+// labBatchClaim é a reserva de um hash; done fecha quando o arquivo que a
+// detém termina, com kept indicando se o job foi criado.
+type labBatchClaim struct {
+	file string
+	done chan struct{}
+	kept bool
+}
+
+func newLabBatchHashes() *labBatchHashes {
+	return &labBatchHashes{claims: make(map[string]*labBatchClaim)}
+}
+
+// claim reserva o hash para file. Se outro arquivo do lote já o reservou,
+// espera o resultado: com o job criado, file é duplicata; se aquele falhou,
+// a reserva passa para file. settle deve ser chamado ao fim do processamento.
+func (s *labBatchHashes) claim(ctx context.Context, contentHash, file string) (settle func(kept bool), err error) {
+	for {
+		s.mu.Lock()
+		held, ok := s.claims[contentHash]
+		if !ok {
+			cl := &labBatchClaim{file: file, done: make(chan struct{})}
+			s.claims[contentHash] = cl
+			s.mu.Unlock()
+			return func(kept bool) { s.settle(contentHash, cl, kept) }, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-held.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if held.kept {
+			return nil, apperr.AlreadyExists(fmt.Sprintf("arquivo repetido no lote (igual a %s)", held.file))
+		}
+	}
+}
+
+func (s *labBatchHashes) settle(contentHash string, cl *labBatchClaim, kept bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cl.kept = kept
+	if !kept {
+		delete(s.claims, contentHash)
+	}
+	close(cl.done)
+}
+
+// collectLabBatchEntries expande os ZIPs e aplica o limite de arquivos.
+// cleanup fecha os ZIPs abertos e deve rodar depois de processar as entradas.
+func collectLabBatchEntries(headers []*multipart.FileHeader) ([]labBatchEntry, func(), error) {
+	var closers []io.Closer
+	cleanup := func() {
+		for _, cl := range closers {
+			_ = cl.Close()
+		}
+	}
+
+	if len(headers) == 0 {
+		return nil, cleanup, &apperr.AppError{
+			Kind:    apperr.REQUIRED_FIELD_MISSING,
+			Message: "arquivo é obrigatório",
+		}
+	}
+
+	var entries []labBatchEntry
+	for _, fh := range headers {
+		if !isZipUpload(fh) {
+			entries = append(entries, multipartBatchEntry(fh))
+		} else if file, err := fh.Open(); err != nil {
+			entries = append(entries, failedBatchEntry(fh.Filename, apperr.Internal("falha ao abrir arquivo", err)))
+		} else {
+			closers = append(closers, file)
+
+			// ZIP corrompido ou vazio vira uma entrada "failed"; os demais
+			// arquivos do lote seguem.
+			zipEntries, err := zipBatchEntries(fh.Filename, file, fh.Size)
+			if err != nil {
+				entries = append(entries, failedBatchEntry(fh.Filename, err))
+			} else {
+				entries = append(entries, zipEntries...)
+			}
+		}
+
+		if len(entries) > maxLabBatchFiles {
+			cleanup()
+			return nil, func() {}, apperr.Validation(
+				fmt.Sprintf("o lote aceita até %d arquivos", maxLabBatchFiles),
+				apperr.Violation{Field: "file", Reason: "too_many"},
+			)
+		}
+	}
+	return entries, cleanup, nil
+}
+
+func multipartBatchEntry(fh *multipart.FileHeader) labBatchEntry {
+	return labBatchEntry{
+		file: fh.Filename,
+		open: func() (labUpload, func(), error) {
+			file, err := fh.Open()
+			if err != nil {
+				return labUpload{}, nil, apperr.Internal("falha ao abrir arquivo", err)
+			}
+			return labUpload{
+				size:        fh.Size,
+				contentType: fh.Header.Get("Content-Type"),
+				file:        file,
+			}, func() { _ = file.Close() }, nil
+		},
+	}
+}
+
+// failedBatchEntry representa um arquivo que já falhou ao ser listado; o erro
+// aparece no resultado como os demais.
+func failedBatchEntry(file string, err error) labBatchEntry {
+	return labBatchEntry{
+		file: file,
+		open: func() (labUpload, func(), error) {
+			return labUpload{}, nil, err
+		},
+	}
+}
+
+func isZipUpload(fh *multipart.FileHeader) bool {
+	switch normalizeMimeType(fh.Header.Get("Content-Type")) {
+	case "application/zip", "application/x-zip-compressed":
+		return true
+	}
+	return strings.EqualFold(path.Ext(fh.Filename), ".zip")
+}
+
+// zipBatchEntries lista os arquivos do ZIP, ignorando pastas e metadados do
+// macOS. ZIP dentro de ZIP não é expandido e falha como tipo não suportado.
+func zipBatchEntries(archive string, r io.ReaderAt, size int64) ([]labBatchEntry, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:       apperr.INVALID_FIELD_FORMAT,
+			Message:    "ZIP inválido",
+			Violations: []apperr.Violation{{Field: "file", Reason: "invalid_zip"}},
+			Cause:      fmt.Errorf("%s: %w", archive, err),
+		}
+	}
+
+	var entries []labBatchEntry
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || skipZipEntry(zf.Name) {
+			continue
+		}
+		entries = append(entries, labBatchEntry{
+			file:    zf.Name,
+			archive: archive,
+			open: func() (labUpload, func(), error) {
+				return openZipEntry(zf)
+			},
+		})
+	}
+	if len(entries) == 0 {
+		return nil, apperr.Validation("ZIP sem arquivos", apperr.Violation{Field: "file", Reason: "empty_zip"})
+	}
+	return entries, nil
+}
+
+func skipZipEntry(name string) bool {
+	return strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".")
+}
+
+// openZipEntry descompacta a entrada em memória. O tamanho declarado no ZIP
+// não é confiável, por isso a leitura também para em maxLabFileSize.
+func openZipEntry(zf *zip.File) (labUpload, func(), error) {
+	tooLarge := &apperr.AppError{
+		Kind:    apperr.UPLOAD_SIZE_EXCEEDED,
+		Message: "arquivo muito grande",
+	}
+	if zf.UncompressedSize64 > maxLabFileSize {
+		return labUpload{}, nil, tooLarge
+	}
+
+	rc, err := zf.Open()
+	if err != nil {
+		return labUpload{}, nil, &apperr.AppError{
+			Kind:    apperr.INVALID_FIELD_FORMAT,
+			Message: "ZIP inválido",
+			Cause:   err,
+		}
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxLabFileSize+1))
+	if err != nil {
+		return labUpload{}, nil, &apperr.AppError{
+			Kind:    apperr.INVALID_FIELD_FORMAT,
+			Message: "ZIP inválido",
+			Cause:   err,
+		}
+	}
+	if len(data) > maxLabFileSize {
+		return labUpload{}, nil, tooLarge
+	}
+
+	// Dentro do ZIP não há Content-Type; storeLabDocument detecta pelo conteúdo.
+	return labUpload{
+		size: int64(len(data)),
+		file: bytes.NewReader(data),
+	}, func() {}, nil
+}
@@ -0,0 +1,279 @@
+// internal/api/handlers/labs_batch_test.go
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"sync"
+	"testing"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeEnqueueUC struct {
+	mu     sync.Mutex
+	inputs []labsuc.CreateLabReportFromDocumentInput
+}
+
+func (f *fakeEnqueueUC) Execute(ctx context.Context, input labsuc.CreateLabReportFromDocumentInput) (*labsvc.ProcessingJobOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inputs = append(f.inputs, input)
+	return &labsvc.ProcessingJobOutput{ID: uuid.Must(uuid.NewV7()), PatientID: input.PatientID, Status: "queued"}, nil
+}
+
+type countingStorage struct {
+	mu      sync.Mutex
+	uploads int
+}
+
+func (s *countingStorage) Upload(ctx context.Context, file io.Reader, objectName, contentType string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads++
+	return "gs://bucket/" + objectName, nil
+}
+
+func (s *countingStorage) Delete(ctx context.Context, uri string) error { return nil }
+
+func (s *countingStorage) GetSignedURL(ctx context.Context, uri string, expirationMinutes int) (string, error) {
+	return "", nil
+}
+
+func TestUploadLabsBatch_ReportsEachFile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &fakeLabsService{}
+	enqueue := &fakeEnqueueUC{}
+	storage := &countingStorage{}
+	h := NewLabs(svc, enqueue, storage, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.POST("/patients/:id/labs/batch", h.UploadLabsBatch)
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for name, content := range map[string]string{
+		"2019/hemograma.pdf":       "%PDF-1.4 hemograma",
+		"2020/glicemia.pdf":        "%PDF-1.4 glicemia",
+		"2020/copia-hemograma.pdf": "%PDF-1.4 hemograma",
+		"__MACOSX/._glicemia.pdf":  "metadata",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip create: %v", err)
+		}
+		_, _ = w.Write([]byte(content))
+	}
+	_ = zw.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	writePart := func(filename, contentType string, data []byte) {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+		header.Set("Content-Type", contentType)
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			t.Fatalf("create part: %v", err)
+		}
+		_, _ = part.Write(data)
+	}
+	writePart("exames.zip", "application/zip", archive.Bytes())
+	writePart("notas.txt", "text/plain", []byte("não é laudo"))
+	_ = mw.Close()
+
+	patientID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+patientID.String()+"/labs/batch", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusMultiStatus {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusMultiStatus, resp.Code, resp.Body.String())
+	}
+
+	var out struct {
+		Results []struct {
+			File    string `json:"file"`
+			Archive string `json:"archive"`
+			Status  string `json:"status"`
+			Error   *struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	counts := map[string]int{}
+	for _, res := range out.Results {
+		counts[res.Status]++
+		if res.Status != labBatchCreated && res.Error == nil {
+			t.Errorf("%s: expected problem for status %s", res.File, res.Status)
+		}
+		if res.File == "notas.txt" && (res.Status != labBatchFailed || res.Error.Code != "INVALID_FIELD_FORMAT") {
+			t.Errorf("expected text file to fail as unsupported, got %+v", res)
+		}
+	}
+	if len(out.Results) != 4 || counts[labBatchCreated] != 2 || counts[labBatchDuplicate] != 1 || counts[labBatchFailed] != 1 {
+		t.Fatalf("unexpected results: %+v", out.Results)
+	}
+	if storage.uploads != 2 || len(enqueue.inputs) != 2 {
+		t.Fatalf("expected 2 uploads and jobs, got %d and %d", storage.uploads, len(enqueue.inputs))
+	}
+}
+
+func TestUploadLabsBatch_BadZipFailsOnlyItself(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	enqueue := &fakeEnqueueUC{}
+	h := NewLabs(&fakeLabsService{}, enqueue, &countingStorage{}, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.POST("/patients/:id/labs/batch", h.UploadLabsBatch)
+
+	var empty bytes.Buffer
+	_ = zip.NewWriter(&empty).Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, f := range []struct{ name, contentType, data string }{
+		{"corrompido.zip", "application/zip", "PK\x03\x04 não é zip"},
+		{"vazio.zip", "application/zip", empty.String()},
+		{"hemograma.pdf", "application/pdf", "%PDF-1.4 hemograma"},
+	} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="file"; filename="`+f.name+`"`)
+		header.Set("Content-Type", f.contentType)
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			t.Fatalf("create part: %v", err)
+		}
+		_, _ = part.Write([]byte(f.data))
+	}
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+uuid.Must(uuid.NewV7()).String()+"/labs/batch", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusMultiStatus {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusMultiStatus, resp.Code, resp.Body.String())
+	}
+
+	var out labBatchResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(out.Results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", out.Results)
+	}
+	for _, res := range out.Results[:2] {
+		if res.Status != labBatchFailed || res.Error == nil {
+			t.Errorf("%s: expected failed with problem, got %+v", res.File, res)
+		}
+	}
+	if out.Results[0].Error != nil && out.Results[0].Error.Code != "INVALID_FIELD_FORMAT" {
+		t.Errorf("expected corrupt zip to fail as INVALID_FIELD_FORMAT, got %s", out.Results[0].Error.Code)
+	}
+	if out.Results[2].Status != labBatchCreated || len(enqueue.inputs) != 1 {
+		t.Fatalf("expected the pdf to be queued, got %+v", out.Results[2])
+	}
+}
+
+// failFirstStorage recusa o primeiro upload.
+type failFirstStorage struct {
+	countingStorage
+	failed bool
+}
+
+func (s *failFirstStorage) Upload(ctx context.Context, file io.Reader, objectName, contentType string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads++
+	if !s.failed {
+		s.failed = true
+		return "", errors.New("storage indisponível")
+	}
+	return "gs://bucket/" + objectName, nil
+}
+
+func TestUploadLabsBatch_FailedCopyReleasesHash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	enqueue := &fakeEnqueueUC{}
+	storage := &failFirstStorage{}
+	h := NewLabs(&fakeLabsService{}, enqueue, storage, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.POST("/patients/:id/labs/batch", h.UploadLabsBatch)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, name := range []string{"hemograma.pdf", "copia-hemograma.pdf"} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="file"; filename="`+name+`"`)
+		header.Set("Content-Type", "application/pdf")
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			t.Fatalf("create part: %v", err)
+		}
+		_, _ = part.Write([]byte("%PDF-1.4 hemograma"))
+	}
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+uuid.Must(uuid.NewV7()).String()+"/labs/batch", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusMultiStatus {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusMultiStatus, resp.Code, resp.Body.String())
+	}
+
+	var out labBatchResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	counts := map[string]int{}
+	for _, res := range out.Results {
+		counts[res.Status]++
+	}
+	if counts[labBatchFailed] != 1 || counts[labBatchCreated] != 1 || counts[labBatchDuplicate] != 0 {
+		t.Fatalf("expected one failed and one created, got %+v", out.Results)
+	}
+	if storage.uploads != 2 || len(enqueue.inputs) != 1 {
+		t.Fatalf("expected 2 uploads and 1 job, got %d and %d", storage.uploads, len(enqueue.inputs))
+	}
+}
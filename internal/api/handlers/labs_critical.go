@@ -0,0 +1,151 @@
+// internal/api/handlers/labs_critical.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	authorization "github.com/gabrielgcmr/sonnda/internal/application/services/authorization"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+// LabsCriticalHandler expõe a caixa de alertas de valores críticos e o
+// cadastro das regras que os disparam.
+type LabsCriticalHandler struct {
+	svc   labsvc.CriticalValues
+	authz authorization.Authorizer
+}
+
+func NewLabsCriticalHandler(svc labsvc.CriticalValues, authz authorization.Authorizer) *LabsCriticalHandler {
+	return &LabsCriticalHandler{
+		svc:   svc,
+		authz: authz,
+	}
+}
+
+// GET /labs/alerts?status=open|all
+func (h *LabsCriticalHandler) ListAlerts(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	if !h.require(c, rbac.ActionReadLabAlerts) {
+		return
+	}
+
+	var onlyOpen bool
+	switch c.DefaultQuery("status", "open") {
+	case "open":
+		onlyOpen = true
+	case "all":
+	default:
+		presenter.ErrorResponder(c, apperr.Validation("parâmetros inválidos",
+			apperr.Violation{Field: "status", Reason: "must_be_open_or_all"}))
+		return
+	}
+
+	limit, offset, ok := parsePagination(c, 50, 0)
+	if !ok {
+		return
+	}
+
+	list, err := h.svc.ListAlerts(c.Request.Context(), currentUser.ID, onlyOpen, limit, offset)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// POST /labs/alerts/:alertID/acknowledge
+func (h *LabsCriticalHandler) Acknowledge(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	if !h.require(c, rbac.ActionReadLabAlerts) {
+		return
+	}
+
+	alertID, ok := parseUUIDParam(c, "alertID", "alert_id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Acknowledge(c.Request.Context(), alertID, currentUser.ID); err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /labs/critical-rules
+func (h *LabsCriticalHandler) ListRules(c *gin.Context) {
+	list, err := h.svc.ListRules(c.Request.Context())
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// POST /labs/critical-rules
+func (h *LabsCriticalHandler) CreateRule(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	if !h.require(c, rbac.ActionManageLabCriticalRules) {
+		return
+	}
+
+	var req labsvc.CriticalRuleInput
+	if err := helpers.BindJSON(c, &req); err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	out, err := h.svc.CreateRule(c.Request.Context(), req, currentUser.ID)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+// DELETE /labs/critical-rules/:ruleID
+func (h *LabsCriticalHandler) DeleteRule(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	if !h.require(c, rbac.ActionManageLabCriticalRules) {
+		return
+	}
+
+	ruleID, ok := parseUUIDParam(c, "ruleID", "rule_id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteRule(c.Request.Context(), ruleID, currentUser.ID); err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LabsCriticalHandler) require(c *gin.Context, action rbac.Action) bool {
+	if h.authz == nil {
+		return true
+	}
+
+	currentUser := helpers.MustGetCurrentUser(c)
+	if err := h.authz.Require(c.Request.Context(), currentUser, action, nil); err != nil {
+		presenter.ErrorResponder(c, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+// internal/api/handlers/labs_critical_test.go
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeCriticalValues struct {
+	alertsUserID uuid.UUID
+	onlyOpen     bool
+	ackAlertID   uuid.UUID
+	ackUserID    uuid.UUID
+	ruleInput    *labsvc.CriticalRuleInput
+	ruleUserID   uuid.UUID
+}
+
+func (f *fakeCriticalValues) ListRules(ctx context.Context) ([]labsvc.CriticalRuleOutput, error) {
+	return []labsvc.CriticalRuleOutput{}, nil
+}
+
+func (f *fakeCriticalValues) CreateRule(ctx context.Context, input labsvc.CriticalRuleInput, userID uuid.UUID) (*labsvc.CriticalRuleOutput, error) {
+	f.ruleInput = &input
+	f.ruleUserID = userID
+	return &labsvc.CriticalRuleOutput{ID: uuid.Must(uuid.NewV7()), AnalyteCode: input.AnalyteCode, Unit: input.Unit}, nil
+}
+
+func (f *fakeCriticalValues) DeleteRule(ctx context.Context, ruleID, userID uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeCriticalValues) ListAlerts(ctx context.Context, userID uuid.UUID, onlyOpen bool, limit, offset int) ([]labsvc.CriticalAlertOutput, error) {
+	f.alertsUserID = userID
+	f.onlyOpen = onlyOpen
+	return []labsvc.CriticalAlertOutput{}, nil
+}
+
+func (f *fakeCriticalValues) Acknowledge(ctx context.Context, alertID, userID uuid.UUID) error {
+	f.ackAlertID = alertID
+	f.ackUserID = userID
+	return nil
+}
+
+func newCriticalRouter(h *LabsCriticalHandler, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: userID, AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.GET("/labs/alerts", h.ListAlerts)
+	r.POST("/labs/alerts/:alertID/acknowledge", h.Acknowledge)
+	r.POST("/labs/critical-rules", h.CreateRule)
+	return r
+}
+
+func TestListAlerts_StatusFilter(t *testing.T) {
+	svc := &fakeCriticalValues{}
+	userID := uuid.Must(uuid.NewV7())
+	r := newCriticalRouter(NewLabsCriticalHandler(svc, allowAllAuthorizer{}), userID)
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/labs/alerts", nil))
+	if resp.Code != http.StatusOK || svc.alertsUserID != userID || !svc.onlyOpen {
+		t.Fatalf("expected open alerts of current user, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/labs/alerts?status=all", nil))
+	if resp.Code != http.StatusOK || svc.onlyOpen {
+		t.Fatalf("expected all alerts, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/labs/alerts?status=closed", nil))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, resp.Code, resp.Body.String())
+	}
+}
+
+func TestAcknowledgeAlert_ReturnsNoContent(t *testing.T) {
+	svc := &fakeCriticalValues{}
+	userID := uuid.Must(uuid.NewV7())
+	r := newCriticalRouter(NewLabsCriticalHandler(svc, allowAllAuthorizer{}), userID)
+
+	alertID := uuid.Must(uuid.NewV7())
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/labs/alerts/"+alertID.String()+"/acknowledge", nil))
+
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusNoContent, resp.Code, resp.Body.String())
+	}
+	if svc.ackAlertID != alertID || svc.ackUserID != userID {
+		t.Fatalf("unexpected acknowledge: %+v", svc)
+	}
+}
+
+func TestCreateCriticalRule_RequiresCriticalRulesPermission(t *testing.T) {
+	svc := &fakeCriticalValues{}
+	r := newCriticalRouter(NewLabsCriticalHandler(svc, denyActionAuthorizer{action: rbac.ActionManageLabCriticalRules}), uuid.Must(uuid.NewV7()))
+
+	body := `{"analyte_code": "potassium", "low": 2.5, "high": 6.5, "unit": "mmol/L"}`
+	req := httptest.NewRequest(http.MethodPost, "/labs/critical-rules", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusForbidden || svc.ruleInput != nil {
+		t.Fatalf("expected status %d without calling the service, got %d: %s", http.StatusForbidden, resp.Code, resp.Body.String())
+	}
+}
+
+func TestCreateCriticalRule_RecordsAdmin(t *testing.T) {
+	svc := &fakeCriticalValues{}
+	userID := uuid.Must(uuid.NewV7())
+	r := newCriticalRouter(NewLabsCriticalHandler(svc, allowAllAuthorizer{}), userID)
+
+	body := `{"analyte_code": "potassium", "low": 2.5, "high": 6.5, "unit": "mmol/L"}`
+	req := httptest.NewRequest(http.MethodPost, "/labs/critical-rules", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusCreated || svc.ruleUserID != userID {
+		t.Fatalf("expected rule created by the current user, got %d (%s): %s", resp.Code, svc.ruleUserID, resp.Body.String())
+	}
+}
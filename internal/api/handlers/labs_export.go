@@ -0,0 +1,114 @@
+// internal/api/handlers/labs_export.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	authorization "github.com/gabrielgcmr/sonnda/internal/application/services/authorization"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+)
+
+// LabsExportHandler entrega o histórico de exames do paciente como arquivo.
+type LabsExportHandler struct {
+	cumulative labsvc.CumulativeReport
+	history    labsvc.HistoryExport
+	authz      authorization.Authorizer
+}
+
+func NewLabsExportHandler(
+	cumulative labsvc.CumulativeReport,
+	history labsvc.HistoryExport,
+	authz authorization.Authorizer,
+) *LabsExportHandler {
+	return &LabsExportHandler{
+		cumulative: cumulative,
+		history:    history,
+		authz:      authz,
+	}
+}
+
+// GET /:patientID/labs/cumulative.pdf?analyte=&from=&to=
+// Sem analyte, traz todos os analitos do período.
+func (h *LabsExportHandler) CumulativePDF(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	out, err := h.cumulative.Render(c.Request.Context(), labsvc.CumulativeInput{
+		PatientID: patientID,
+		Analytes:  analyteQuery(c),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+	})
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	presenter.Attachment(c, http.StatusOK, out.ContentType, out.Filename, out.Content)
+}
+
+// GET /:patientID/labs/export?format=csv|xlsx
+// O arquivo é enviado conforme os itens são lidos; depois do primeiro byte
+// um erro só interrompe o download.
+func (h *LabsExportHandler) ExportHistory(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	out, err := h.history.Export(c.Request.Context(), labsvc.HistoryExportInput{
+		PatientID: patientID,
+		Format:    c.Query("format"),
+	})
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	presenter.StreamAttachment(c, http.StatusOK, out.ContentType, out.Filename)
+	if _, err := out.Content.WriteTo(c.Writer); err != nil {
+		if c.Writer.Written() {
+			// Parte do arquivo já saiu: só registra o erro para o log.
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		presenter.ErrorResponder(c, err)
+	}
+}
+
+// analyteQuery aceita analyte repetido ou separado por vírgula.
+func analyteQuery(c *gin.Context) []string {
+	var analytes []string
+	for _, raw := range c.QueryArray("analyte") {
+		analytes = append(analytes, strings.Split(raw, ",")...)
+	}
+	return analytes
+}
@@ -0,0 +1,149 @@
+// internal/api/handlers/labs_export_test.go
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeCumulativeReport struct {
+	input *labsvc.CumulativeInput
+}
+
+func (f *fakeCumulativeReport) Render(ctx context.Context, input labsvc.CumulativeInput) (*labsvc.CumulativeReportOutput, error) {
+	f.input = &input
+	return &labsvc.CumulativeReportOutput{
+		Content:     []byte("%PDF-1.4"),
+		ContentType: "application/pdf",
+		Filename:    "relatorio-cumulativo-2026-06-01.pdf",
+	}, nil
+}
+
+type fakeHistoryExport struct {
+	input   *labsvc.HistoryExportInput
+	err     error
+	content io.WriterTo
+}
+
+func (f *fakeHistoryExport) Export(ctx context.Context, input labsvc.HistoryExportInput) (*labsvc.HistoryExportOutput, error) {
+	f.input = &input
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &labsvc.HistoryExportOutput{
+		ContentType: "text/csv; charset=utf-8",
+		Filename:    "exames-2026-06-01.csv",
+		Content:     f.content,
+	}, nil
+}
+
+// failingContent falha antes de escrever qualquer byte.
+type failingContent struct{}
+
+func (failingContent) WriteTo(w io.Writer) (int64, error) {
+	return 0, &apperr.AppError{Kind: apperr.INFRA_DATABASE_ERROR, Message: "falha técnica", Cause: errors.New("boom")}
+}
+
+func newExportRouter(h *LabsExportHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.GET("/patients/:id/labs/cumulative.pdf", h.CumulativePDF)
+	r.GET("/patients/:id/labs/export", h.ExportHistory)
+	return r
+}
+
+func TestCumulativePDF(t *testing.T) {
+	svc := &fakeCumulativeReport{}
+	r := newExportRouter(NewLabsExportHandler(svc, &fakeHistoryExport{}, allowAllAuthorizer{}))
+
+	patientID := uuid.Must(uuid.NewV7())
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet,
+		"/patients/"+patientID.String()+"/labs/cumulative.pdf?analyte=glucose,hba1c&analyte=ldl&from=2026-01-01&to=2026-06-30", nil))
+
+	if resp.Code != http.StatusOK || resp.Header().Get("Content-Type") != "application/pdf" {
+		t.Fatalf("expected pdf, got %d %q: %s", resp.Code, resp.Header().Get("Content-Type"), resp.Body.String())
+	}
+	if got := resp.Header().Get("Content-Disposition"); got != `attachment; filename=relatorio-cumulativo-2026-06-01.pdf` {
+		t.Fatalf("unexpected Content-Disposition %q", got)
+	}
+	in := svc.input
+	if in == nil || in.PatientID != patientID || len(in.Analytes) != 3 || in.From != "2026-01-01" || in.To != "2026-06-30" {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+}
+
+func TestCumulativePDF_Forbidden(t *testing.T) {
+	svc := &fakeCumulativeReport{}
+	r := newExportRouter(NewLabsExportHandler(svc, &fakeHistoryExport{}, denyActionAuthorizer{action: rbac.ActionReadLabs}))
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/patients/"+uuid.Must(uuid.NewV7()).String()+"/labs/cumulative.pdf", nil))
+	if resp.Code != http.StatusForbidden || svc.input != nil {
+		t.Fatalf("expected 403 without rendering, got %d", resp.Code)
+	}
+}
+
+func TestExportHistory(t *testing.T) {
+	svc := &fakeHistoryExport{content: bytes.NewBufferString("report_id,item_id\n")}
+	r := newExportRouter(NewLabsExportHandler(&fakeCumulativeReport{}, svc, allowAllAuthorizer{}))
+
+	patientID := uuid.Must(uuid.NewV7())
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/patients/"+patientID.String()+"/labs/export?format=csv", nil))
+
+	if resp.Code != http.StatusOK || resp.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Fatalf("expected csv, got %d %q: %s", resp.Code, resp.Header().Get("Content-Type"), resp.Body.String())
+	}
+	if got := resp.Header().Get("Content-Disposition"); got != "attachment; filename=exames-2026-06-01.csv" {
+		t.Fatalf("unexpected Content-Disposition %q", got)
+	}
+	if resp.Body.String() != "report_id,item_id\n" {
+		t.Fatalf("unexpected body %q", resp.Body.String())
+	}
+	if svc.input == nil || svc.input.PatientID != patientID || svc.input.Format != "csv" {
+		t.Fatalf("unexpected input: %+v", svc.input)
+	}
+}
+
+func TestExportHistory_InvalidFormat(t *testing.T) {
+	svc := &fakeHistoryExport{err: apperr.Validation("entrada inválida", apperr.Violation{Field: "format", Reason: "invalid"})}
+	r := newExportRouter(NewLabsExportHandler(&fakeCumulativeReport{}, svc, allowAllAuthorizer{}))
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/patients/"+uuid.Must(uuid.NewV7()).String()+"/labs/export?format=pdf", nil))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestExportHistory_FailsBeforeFirstByte(t *testing.T) {
+	svc := &fakeHistoryExport{content: failingContent{}}
+	r := newExportRouter(NewLabsExportHandler(&fakeCumulativeReport{}, svc, allowAllAuthorizer{}))
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/patients/"+uuid.Must(uuid.NewV7()).String()+"/labs/export", nil))
+	if resp.Code != http.StatusServiceUnavailable && resp.Code != http.StatusInternalServerError {
+		t.Fatalf("expected an error status, got %d", resp.Code)
+	}
+	if resp.Header().Get("Content-Disposition") != "" {
+		t.Fatalf("expected no attachment on error")
+	}
+}
@@ -0,0 +1,173 @@
+// internal/api/handlers/labs_fhir.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	authorization "github.com/gabrielgcmr/sonnda/internal/application/services/authorization"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/diagnostics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+// maxFHIRBundleSize limita o corpo de POST /labs/fhir.
+const maxFHIRBundleSize = 5 * 1024 * 1024 // 5MB
+
+// LabsFHIRHandler expõe os laudos em HL7 FHIR R5 para integração com prontuários parceiros.
+type LabsFHIRHandler struct {
+	exporter labsvc.FHIRExporter
+	importUC labsuc.CreateLabReportFromFHIRUseCase
+	authz    authorization.Authorizer
+}
+
+func NewLabsFHIRHandler(
+	exporter labsvc.FHIRExporter,
+	importUC labsuc.CreateLabReportFromFHIRUseCase,
+	authz authorization.Authorizer,
+) *LabsFHIRHandler {
+	return &LabsFHIRHandler{
+		exporter: exporter,
+		importUC: importUC,
+		authz:    authz,
+	}
+}
+
+// GET /:patientID/labs/:reportID/fhir
+func (h *LabsFHIRHandler) ExportReport(c *gin.Context) {
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reportID, ok := parseUUIDParam(c, "reportID", "report_id")
+	if !ok {
+		return
+	}
+
+	if !h.require(c, rbac.ActionReadLabs, patientID) {
+		return
+	}
+
+	bundle, err := h.exporter.ExportReport(c.Request.Context(), patientID, reportID)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	presenter.FHIR(c, http.StatusOK, bundle)
+}
+
+// GET /:patientID/$everything
+func (h *LabsFHIRHandler) ExportPatient(c *gin.Context) {
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if !h.require(c, rbac.ActionReadLabs, patientID) {
+		return
+	}
+
+	bundle, err := h.exporter.ExportPatient(c.Request.Context(), patientID)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	presenter.FHIR(c, http.StatusOK, bundle)
+}
+
+// POST /:patientID/labs/fhir
+// Recebe um Bundle transaction (R5 ou R4) com DiagnosticReport + Observations.
+// Erros respondem OperationOutcome.
+func (h *LabsFHIRHandler) ImportBundle(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionUploadLabs, &patientID); err != nil {
+			presenter.FHIRErrorResponder(c, err)
+			return
+		}
+	}
+
+	bundle, err := decodeFHIRBundle(c)
+	if err != nil {
+		presenter.FHIRErrorResponder(c, err)
+		return
+	}
+
+	out, err := h.importUC.Execute(c.Request.Context(), labsuc.CreateLabReportFromFHIRInput{
+		PatientID:        patientID,
+		UploadedByUserID: currentUser.ID,
+		Bundle:           bundle,
+	})
+	if err != nil {
+		presenter.FHIRErrorResponder(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/v1/patients/%s/labs/%s/fhir", patientID, out.ID))
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *LabsFHIRHandler) require(c *gin.Context, action rbac.Action, patientID uuid.UUID) bool {
+	if h.authz == nil {
+		return true
+	}
+	currentUser := helpers.MustGetCurrentUser(c)
+	if err := h.authz.Require(c.Request.Context(), currentUser, action, &patientID); err != nil {
+		presenter.ErrorResponder(c, err)
+		return false
+	}
+	return true
+}
+
+// decodeFHIRBundle aceita application/fhir+json (fhirVersion 4.0 ou 5.0) e application/json.
+func decodeFHIRBundle(c *gin.Context) (*diagnostics.Bundle, error) {
+	mediaType, params, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || (mediaType != "application/fhir+json" && mediaType != "application/json") {
+		return nil, apperr.Validation("use Content-Type application/fhir+json",
+			apperr.Violation{Field: "Content-Type", Reason: "unsupported"})
+	}
+	switch version := params["fhirversion"]; version {
+	case "", "4.0", "4.0.1", "5.0", "5.0.0":
+	default:
+		return nil, apperr.Validation("versão FHIR não suportada",
+			apperr.Violation{Field: "fhirVersion", Reason: "unsupported"})
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxFHIRBundleSize)
+	var bundle diagnostics.Bundle
+	if err := json.NewDecoder(body).Decode(&bundle); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, &apperr.AppError{
+				Kind:    apperr.UPLOAD_SIZE_EXCEEDED,
+				Message: "Bundle muito grande",
+				Cause:   err,
+			}
+		}
+		return nil, &apperr.AppError{
+			Kind:    apperr.VALIDATION_FAILED,
+			Message: "JSON FHIR inválido",
+			Cause:   err,
+		}
+	}
+	return &bundle, nil
+}
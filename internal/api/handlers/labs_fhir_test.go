@@ -0,0 +1,125 @@
+// internal/api/handlers/labs_fhir_test.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/diagnostics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeFHIRImportUseCase struct {
+	err   error
+	input *labsuc.CreateLabReportFromFHIRInput
+}
+
+func (f *fakeFHIRImportUseCase) Execute(ctx context.Context, input labsuc.CreateLabReportFromFHIRInput) (*labsvc.LabReportOutput, error) {
+	f.input = &input
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &labsvc.LabReportOutput{ID: uuid.Must(uuid.NewV7()), PatientID: input.PatientID}, nil
+}
+
+func newFHIRImportRouter(uc labsuc.CreateLabReportFromFHIRUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewLabsFHIRHandler(nil, uc, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.POST("/patients/:id/labs/fhir", h.ImportBundle)
+	return r
+}
+
+func TestImportBundle_Created(t *testing.T) {
+	uc := &fakeFHIRImportUseCase{}
+	r := newFHIRImportRouter(uc)
+
+	id := uuid.Must(uuid.NewV7()).String()
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+id+"/labs/fhir",
+		strings.NewReader(`{"resourceType":"Bundle","type":"transaction","entry":[]}`))
+	req.Header.Set("Content-Type", "application/fhir+json; fhirVersion=4.0")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, resp.Code, resp.Body.String())
+	}
+	if uc.input == nil || uc.input.Bundle == nil || uc.input.Bundle.Type != diagnostics.BundleTypeTransaction {
+		t.Fatalf("bundle was not passed to the use case: %+v", uc.input)
+	}
+	if !strings.HasSuffix(resp.Header().Get("Location"), "/fhir") {
+		t.Fatalf("unexpected Location: %q", resp.Header().Get("Location"))
+	}
+}
+
+func TestImportBundle_ValidationReturnsOperationOutcome(t *testing.T) {
+	outcome := diagnostics.NewOperationOutcome(
+		diagnostics.NewIssue(diagnostics.IssueRequired, "Bundle.entry", "o Bundle deve conter um DiagnosticReport"),
+	)
+	uc := &fakeFHIRImportUseCase{err: &apperr.AppError{
+		Kind:    apperr.VALIDATION_FAILED,
+		Message: "Bundle FHIR inválido",
+		Cause:   &diagnostics.OutcomeError{Outcome: outcome},
+	}}
+	r := newFHIRImportRouter(uc)
+
+	id := uuid.Must(uuid.NewV7()).String()
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+id+"/labs/fhir",
+		strings.NewReader(`{"resourceType":"Bundle","type":"transaction"}`))
+	req.Header.Set("Content-Type", "application/fhir+json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, resp.Code)
+	}
+	if ct := resp.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/fhir+json") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	var body diagnostics.OperationOutcome
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if body.ResourceType != diagnostics.ResourceOperationOutcome || len(body.Issue) != 1 ||
+		body.Issue[0].Expression[0] != "Bundle.entry" {
+		t.Fatalf("unexpected outcome: %+v", body)
+	}
+}
+
+func TestImportBundle_RejectsOtherContentTypes(t *testing.T) {
+	uc := &fakeFHIRImportUseCase{}
+	r := newFHIRImportRouter(uc)
+
+	id := uuid.Must(uuid.NewV7()).String()
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+id+"/labs/fhir", strings.NewReader(`<Bundle/>`))
+	req.Header.Set("Content-Type", "application/fhir+xml")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest || uc.input != nil {
+		t.Fatalf("expected 400 without calling the use case, got %d", resp.Code)
+	}
+	var body diagnostics.OperationOutcome
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil || body.ResourceType != diagnostics.ResourceOperationOutcome {
+		t.Fatalf("expected OperationOutcome, got %s", resp.Body.String())
+	}
+}
@@ -0,0 +1,110 @@
+// internal/api/handlers/labs_hl7.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	authorization "github.com/gabrielgcmr/sonnda/internal/application/services/authorization"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+// maxHL7MessageSize limita o corpo de POST /labs/hl7.
+const maxHL7MessageSize = 1024 * 1024 // 1MB
+
+// acceptedHL7ContentTypes cobre os media types que os LIS costumam enviar para ER7.
+var acceptedHL7ContentTypes = map[string]bool{
+	"x-application/hl7-v2+er7": true,
+	"application/hl7-v2":       true,
+	"application/edi-hl7":      true,
+	"text/plain":               true,
+}
+
+// LabsHL7Handler recebe resultados HL7 v2 (ORU^R01) por HTTP, para
+// laboratórios que não usam o listener MLLP.
+type LabsHL7Handler struct {
+	importUC labsuc.CreateLabReportFromHL7UseCase
+	authz    authorization.Authorizer
+}
+
+func NewLabsHL7Handler(
+	importUC labsuc.CreateLabReportFromHL7UseCase,
+	authz authorization.Authorizer,
+) *LabsHL7Handler {
+	return &LabsHL7Handler{
+		importUC: importUC,
+		authz:    authz,
+	}
+}
+
+// POST /labs/hl7
+// O paciente vem do PID (CPF/CNS); a permissão de upload é conferida depois
+// de localizá-lo. A resposta é sempre o ACK, com o status HTTP do resultado.
+func (h *LabsHL7Handler) ImportMessage(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	message, err := readHL7Message(c)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	input := labsuc.CreateLabReportFromHL7Input{
+		Message:          message,
+		UploadedByUserID: currentUser.ID,
+	}
+	if h.authz != nil {
+		input.Authorize = func(ctx context.Context, patientID uuid.UUID) error {
+			return h.authz.Require(ctx, currentUser, rbac.ActionUploadLabs, &patientID)
+		}
+	}
+
+	out, err := h.importUC.Execute(c.Request.Context(), input)
+	if err != nil {
+		ack := ""
+		if out != nil {
+			ack = out.ACK
+		}
+		presenter.HL7ErrorResponder(c, err, ack)
+		return
+	}
+
+	c.Header("Location", "/v1/patients/"+out.Report.PatientID.String()+"/labs/"+out.Report.ID.String()+"/fhir")
+	presenter.HL7(c, http.StatusCreated, out.ACK)
+}
+
+func readHL7Message(c *gin.Context) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || !acceptedHL7ContentTypes[mediaType] {
+		return nil, apperr.Validation("use Content-Type x-application/hl7-v2+er7",
+			apperr.Violation{Field: "Content-Type", Reason: "unsupported"})
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxHL7MessageSize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, &apperr.AppError{
+				Kind:    apperr.UPLOAD_SIZE_EXCEEDED,
+				Message: "mensagem muito grande",
+				Cause:   err,
+			}
+		}
+		return nil, &apperr.AppError{
+			Kind:    apperr.VALIDATION_FAILED,
+			Message: "falha ao ler a mensagem",
+			Cause:   err,
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,112 @@
+// internal/api/handlers/labs_hl7_test.go
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const testORU = "MSH|^~\\&|LIS|LAB|SONNDA|SONNDA|20250311||ORU^R01|MSG1|P|2.5.1\rPID|1||12345678909^^^RFB^CPF\r"
+
+type fakeHL7ImportUseCase struct {
+	err   error
+	input *labsuc.CreateLabReportFromHL7Input
+}
+
+func (f *fakeHL7ImportUseCase) Execute(ctx context.Context, input labsuc.CreateLabReportFromHL7Input) (*labsuc.CreateLabReportFromHL7Output, error) {
+	f.input = &input
+	if f.err != nil {
+		return &labsuc.CreateLabReportFromHL7Output{ACK: "MSH|...\rMSA|AE|MSG1|acesso negado\r"}, f.err
+	}
+	if err := input.Authorize(ctx, uuid.Must(uuid.NewV7())); err != nil {
+		return nil, err
+	}
+	return &labsuc.CreateLabReportFromHL7Output{
+		ACK:    "MSH|...\rMSA|AA|MSG1|laudo recebido\r",
+		Report: &labsvc.LabReportOutput{ID: uuid.Must(uuid.NewV7()), PatientID: uuid.Must(uuid.NewV7())},
+	}, nil
+}
+
+func newHL7ImportRouter(uc labsuc.CreateLabReportFromHL7UseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewLabsHL7Handler(uc, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.POST("/labs/hl7", h.ImportMessage)
+	return r
+}
+
+func TestImportHL7_Created(t *testing.T) {
+	uc := &fakeHL7ImportUseCase{}
+	r := newHL7ImportRouter(uc)
+
+	req := httptest.NewRequest(http.MethodPost, "/labs/hl7", strings.NewReader(testORU))
+	req.Header.Set("Content-Type", "x-application/hl7-v2+er7")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, resp.Code, resp.Body.String())
+	}
+	if uc.input == nil || string(uc.input.Message) != testORU || uc.input.Authorize == nil {
+		t.Fatalf("message was not passed to the use case: %+v", uc.input)
+	}
+	if !strings.Contains(resp.Body.String(), "MSA|AA|MSG1") {
+		t.Fatalf("expected ACK AA, got %q", resp.Body.String())
+	}
+	if !strings.HasPrefix(resp.Header().Get("Content-Type"), "x-application/hl7-v2+er7") {
+		t.Fatalf("unexpected content type: %q", resp.Header().Get("Content-Type"))
+	}
+}
+
+func TestImportHL7_ErrorReturnsACK(t *testing.T) {
+	uc := &fakeHL7ImportUseCase{err: &apperr.AppError{Kind: apperr.ACCESS_DENIED, Message: "acesso negado"}}
+	r := newHL7ImportRouter(uc)
+
+	req := httptest.NewRequest(http.MethodPost, "/labs/hl7", strings.NewReader(testORU))
+	req.Header.Set("Content-Type", "application/hl7-v2")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, resp.Code)
+	}
+	if !strings.Contains(resp.Body.String(), "MSA|AE|MSG1") {
+		t.Fatalf("expected ACK AE, got %q", resp.Body.String())
+	}
+}
+
+func TestImportHL7_RejectsContentType(t *testing.T) {
+	uc := &fakeHL7ImportUseCase{}
+	r := newHL7ImportRouter(uc)
+
+	req := httptest.NewRequest(http.MethodPost, "/labs/hl7", strings.NewReader(testORU))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, resp.Code)
+	}
+	if uc.input != nil {
+		t.Fatal("use case should not be called")
+	}
+}
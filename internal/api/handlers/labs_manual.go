@@ -0,0 +1,122 @@
+// internal/api/handlers/labs_manual.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	authorization "github.com/gabrielgcmr/sonnda/internal/application/services/authorization"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+)
+
+// LabsManualHandler recebe laudos digitados pelo profissional, sem arquivo.
+type LabsManualHandler struct {
+	createUC labsuc.CreateLabReportManualUseCase
+	authz    authorization.Authorizer
+}
+
+// A validação fica no caso de uso (construtores do domínio), que devolve o
+// caminho de cada campo inválido; por isso não há tags binding aqui.
+type manualLabReportRequest struct {
+	LabName          *string                `json:"lab_name,omitempty"`
+	RequestingDoctor *string                `json:"requesting_doctor,omitempty"`
+	ReportDate       *string                `json:"report_date,omitempty"`
+	Tests            []manualLabTestRequest `json:"tests"`
+}
+
+type manualLabTestRequest struct {
+	TestName    string                 `json:"test_name"`
+	Material    *string                `json:"material,omitempty"`
+	Method      *string                `json:"method,omitempty"`
+	CollectedAt *string                `json:"collected_at,omitempty"`
+	ReleaseAt   *string                `json:"release_at,omitempty"`
+	Items       []manualLabItemRequest `json:"items"`
+}
+
+type manualLabItemRequest struct {
+	ParameterName string  `json:"parameter_name"`
+	ResultValue   *string `json:"result_value,omitempty"`
+	ResultUnit    *string `json:"result_unit,omitempty"`
+	ReferenceText *string `json:"reference_text,omitempty"`
+}
+
+func NewLabsManualHandler(
+	createUC labsuc.CreateLabReportManualUseCase,
+	authz authorization.Authorizer,
+) *LabsManualHandler {
+	return &LabsManualHandler{
+		createUC: createUC,
+		authz:    authz,
+	}
+}
+
+// POST /:patientID/labs/manual
+func (h *LabsManualHandler) CreateManual(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionUploadLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	var req manualLabReportRequest
+	if err := helpers.BindJSON(c, &req); err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	out, err := h.createUC.Execute(c.Request.Context(), req.toInput(patientID, currentUser.ID))
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/v1/patients/%s/labs/%s", patientID, out.ID))
+	c.JSON(http.StatusCreated, out)
+}
+
+func (r manualLabReportRequest) toInput(patientID, uploadedBy uuid.UUID) labsuc.CreateLabReportManualInput {
+	input := labsuc.CreateLabReportManualInput{
+		PatientID:        patientID,
+		UploadedByUserID: uploadedBy,
+		LabName:          r.LabName,
+		RequestingDoctor: r.RequestingDoctor,
+		ReportDate:       r.ReportDate,
+		Tests:            make([]labsuc.ManualLabTestInput, 0, len(r.Tests)),
+	}
+
+	for _, t := range r.Tests {
+		test := labsuc.ManualLabTestInput{
+			TestName:    t.TestName,
+			Material:    t.Material,
+			Method:      t.Method,
+			CollectedAt: t.CollectedAt,
+			ReleaseAt:   t.ReleaseAt,
+			Items:       make([]labsuc.ManualLabItemInput, 0, len(t.Items)),
+		}
+		for _, it := range t.Items {
+			test.Items = append(test.Items, labsuc.ManualLabItemInput{
+				ParameterName: it.ParameterName,
+				ResultValue:   it.ResultValue,
+				ResultUnit:    it.ResultUnit,
+				ReferenceText: it.ReferenceText,
+			})
+		}
+		input.Tests = append(input.Tests, test)
+	}
+
+	return input
+}
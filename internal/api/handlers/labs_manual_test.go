@@ -0,0 +1,76 @@
+// internal/api/handlers/labs_manual_test.go
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeManualUseCase struct {
+	input *labsuc.CreateLabReportManualInput
+}
+
+func (f *fakeManualUseCase) Execute(ctx context.Context, input labsuc.CreateLabReportManualInput) (*labsvc.LabReportOutput, error) {
+	f.input = &input
+	return &labsvc.LabReportOutput{ID: uuid.Must(uuid.NewV7()), PatientID: input.PatientID, Source: "manual"}, nil
+}
+
+func TestCreateManual_MapsRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uc := &fakeManualUseCase{}
+	h := NewLabsManualHandler(uc, allowAllAuthorizer{})
+	userID := uuid.Must(uuid.NewV7())
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: userID, AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.POST("/patients/:id/labs/manual", h.CreateManual)
+
+	body := `{
+		"lab_name": "Laboratório Central",
+		"report_date": "2025-03-11",
+		"tests": [{
+			"test_name": "Glicemia de jejum",
+			"collected_at": "2025-03-11T07:30:00-03:00",
+			"items": [{"parameter_name": "Glicose", "result_value": "92", "result_unit": "mg/dL", "reference_text": "70 a 99"}]
+		}]
+	}`
+	patientID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+patientID.String()+"/labs/manual", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, resp.Code, resp.Body.String())
+	}
+	if !strings.HasPrefix(resp.Header().Get("Location"), "/v1/patients/"+patientID.String()+"/labs/") {
+		t.Fatalf("unexpected Location: %q", resp.Header().Get("Location"))
+	}
+
+	in := uc.input
+	if in == nil || in.PatientID != patientID || in.UploadedByUserID != userID {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+	if len(in.Tests) != 1 || len(in.Tests[0].Items) != 1 {
+		t.Fatalf("expected one test with one item, got %+v", in.Tests)
+	}
+	item := in.Tests[0].Items[0]
+	if item.ParameterName != "Glicose" || *item.ResultValue != "92" || *item.ReferenceText != "70 a 99" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+}
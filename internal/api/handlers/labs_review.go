@@ -0,0 +1,127 @@
+// internal/api/handlers/labs_review.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	"github.com/gabrielgcmr/sonnda/internal/api/presenter"
+	authorization "github.com/gabrielgcmr/sonnda/internal/application/services/authorization"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
+)
+
+// LabsReviewHandler atende a revisão de laudos extraídos com baixa confiança.
+type LabsReviewHandler struct {
+	svc      labsvc.Service
+	reviewUC labsuc.ReviewLabReportUseCase
+	authz    authorization.Authorizer
+}
+
+// As alterações usam o mesmo formato do PATCH de correção.
+type reviewLabReportRequest struct {
+	Version *int                    `json:"version"`
+	Changes []labFieldChangeRequest `json:"changes"`
+	Confirm []string                `json:"confirm"`
+	Approve bool                    `json:"approve"`
+	Reason  *string                 `json:"reason,omitempty"`
+}
+
+func NewLabsReviewHandler(
+	svc labsvc.Service,
+	reviewUC labsuc.ReviewLabReportUseCase,
+	authz authorization.Authorizer,
+) *LabsReviewHandler {
+	return &LabsReviewHandler{
+		svc:      svc,
+		reviewUC: reviewUC,
+		authz:    authz,
+	}
+}
+
+// GET /labs/reviews
+func (h *LabsReviewHandler) ListPending(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionListLabReviews, nil); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	limit, offset, ok := parsePagination(c, 50, 0)
+	if !ok {
+		return
+	}
+
+	list, err := h.svc.ListPendingReviews(c.Request.Context(), currentUser.ID, limit, offset)
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// POST /:patientID/labs/:reportID/review
+func (h *LabsReviewHandler) ReviewLab(c *gin.Context) {
+	currentUser := helpers.MustGetCurrentUser(c)
+
+	patientID, ok := parsePatientIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reportID, ok := parseUUIDParam(c, "reportID", "report_id")
+	if !ok {
+		return
+	}
+
+	if h.authz != nil {
+		if err := h.authz.Require(c.Request.Context(), currentUser, rbac.ActionReviewLabs, &patientID); err != nil {
+			presenter.ErrorResponder(c, err)
+			return
+		}
+	}
+
+	var req reviewLabReportRequest
+	if err := helpers.BindJSON(c, &req); err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	out, err := h.reviewUC.Execute(c.Request.Context(), req.toInput(patientID, reportID, currentUser.ID))
+	if err != nil {
+		presenter.ErrorResponder(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (r reviewLabReportRequest) toInput(patientID, reportID, reviewedBy uuid.UUID) labsuc.ReviewLabReportInput {
+	input := labsuc.ReviewLabReportInput{
+		PatientID:        patientID,
+		ReportID:         reportID,
+		ReviewedByUserID: reviewedBy,
+		Version:          r.Version,
+		Confirm:          r.Confirm,
+		Approve:          r.Approve,
+		Reason:           r.Reason,
+		Changes:          make([]labsuc.LabFieldEditInput, 0, len(r.Changes)),
+	}
+	for _, ch := range r.Changes {
+		input.Changes = append(input.Changes, labsuc.LabFieldEditInput{
+			Target:   ch.Target,
+			TargetID: ch.TargetID,
+			Field:    ch.Field,
+			Value:    ch.Value,
+		})
+	}
+	return input
+}
@@ -0,0 +1,85 @@
+// internal/api/handlers/labs_review_test.go
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeReviewUseCase struct {
+	input *labsuc.ReviewLabReportInput
+}
+
+func (f *fakeReviewUseCase) Execute(ctx context.Context, input labsuc.ReviewLabReportInput) (*labsvc.LabReportOutput, error) {
+	f.input = &input
+	return &labsvc.LabReportOutput{ID: input.ReportID, PatientID: input.PatientID, Status: "final", Version: 2}, nil
+}
+
+func TestReviewLab_MapsRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &fakeLabsService{}
+	uc := &fakeReviewUseCase{}
+	h := NewLabsReviewHandler(svc, uc, allowAllAuthorizer{})
+	userID := uuid.Must(uuid.NewV7())
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: userID, AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.GET("/labs/reviews", h.ListPending)
+	r.POST("/patients/:id/labs/:reportID/review", h.ReviewLab)
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/labs/reviews?limit=10", nil))
+	if resp.Code != http.StatusOK || svc.reviewsUserID != userID {
+		t.Fatalf("expected queue for current user, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	itemID := uuid.Must(uuid.NewV7())
+	confirmedID := uuid.Must(uuid.NewV7())
+	body := `{
+		"version": 1,
+		"changes": [
+			{"target": "item", "target_id": "` + itemID.String() + `", "field": "result_value", "value": "13,5"}
+		],
+		"confirm": ["` + confirmedID.String() + `"],
+		"approve": true
+	}`
+	patientID := uuid.Must(uuid.NewV7())
+	reportID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+patientID.String()+"/labs/"+reportID.String()+"/review", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, resp.Code, resp.Body.String())
+	}
+
+	in := uc.input
+	if in == nil || in.PatientID != patientID || in.ReportID != reportID || in.ReviewedByUserID != userID {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+	if in.Version == nil || *in.Version != 1 || !in.Approve {
+		t.Fatalf("unexpected version/approve: %+v", in)
+	}
+	if len(in.Changes) != 1 || *in.Changes[0].TargetID != itemID.String() {
+		t.Fatalf("unexpected changes: %+v", in.Changes)
+	}
+	if len(in.Confirm) != 1 || in.Confirm[0] != confirmedID.String() {
+		t.Fatalf("unexpected confirm: %+v", in.Confirm)
+	}
+}
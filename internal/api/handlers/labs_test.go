@@ -2,15 +2,22 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
+	"strings"
 	"testing"
 
-	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
 	helpers "github.com/gabrielgcmr/sonnda/internal/api/helpers"
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/rbac"
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/user"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -18,6 +25,15 @@ import (
 type fakeLabsService struct {
 	listCalled     bool
 	listFullCalled bool
+	job            *labsvc.ProcessingJobOutput
+	document       *labsvc.DocumentURLOutput
+	deletedID      uuid.UUID
+	timelineInput  *labsvc.TimelineInput
+	duplicateOf    *uuid.UUID
+	processingJob  *uuid.UUID
+	reviewsUserID  uuid.UUID
+	searchQuery    string
+	compared       [2]uuid.UUID
 }
 
 type allowAllAuthorizer struct{}
@@ -36,6 +52,76 @@ func (f *fakeLabsService) ListFull(ctx context.Context, patientID uuid.UUID, lim
 	return []*labsvc.LabReportOutput{}, nil
 }
 
+func (f *fakeLabsService) Get(ctx context.Context, patientID, reportID uuid.UUID) (*labsvc.LabReportOutput, error) {
+	return &labsvc.LabReportOutput{ID: reportID, PatientID: patientID}, nil
+}
+
+func (f *fakeLabsService) Delete(ctx context.Context, patientID, reportID uuid.UUID) error {
+	f.deletedID = reportID
+	return nil
+}
+
+func (f *fakeLabsService) CheckDuplicateDocument(ctx context.Context, patientID uuid.UUID, contentHash string) error {
+	if f.duplicateOf != nil {
+		return labsvc.DuplicateDocumentError(*f.duplicateOf)
+	}
+	return nil
+}
+
+func (f *fakeLabsService) CheckDocumentInProcessing(ctx context.Context, patientID uuid.UUID, contentHash string) error {
+	if f.processingJob != nil {
+		return labsvc.DocumentInProcessingError(*f.processingJob)
+	}
+	return nil
+}
+
+func (f *fakeLabsService) History(ctx context.Context, patientID, reportID uuid.UUID) (*labsvc.LabReportHistoryOutput, error) {
+	panic("unused")
+}
+
+func (f *fakeLabsService) DocumentURL(ctx context.Context, patientID, reportID uuid.UUID) (*labsvc.DocumentURLOutput, error) {
+	return f.document, nil
+}
+
+func (f *fakeLabsService) ListPendingReviews(ctx context.Context, userID uuid.UUID, limit, offset int) ([]labsvc.ReviewQueueOutput, error) {
+	f.reviewsUserID = userID
+	return []labsvc.ReviewQueueOutput{}, nil
+}
+
+func (f *fakeLabsService) GetJob(ctx context.Context, patientID, jobID uuid.UUID) (*labsvc.ProcessingJobOutput, error) {
+	return f.job, nil
+}
+
+func (f *fakeLabsService) Compare(ctx context.Context, patientID, reportA, reportB uuid.UUID) (*labsvc.LabComparisonOutput, error) {
+	f.compared = [2]uuid.UUID{reportA, reportB}
+	return &labsvc.LabComparisonOutput{PatientID: patientID, Items: []labsvc.ItemComparisonOutput{}}, nil
+}
+
+func (f *fakeLabsService) Search(ctx context.Context, patientID uuid.UUID, query string, limit, offset int) ([]labsvc.LabSearchHitOutput, error) {
+	f.searchQuery = query
+	return []labsvc.LabSearchHitOutput{}, nil
+}
+
+func (f *fakeLabsService) Timeline(ctx context.Context, input labsvc.TimelineInput) (*labsvc.PatientTimelineOutput, error) {
+	f.timelineInput = &input
+	return &labsvc.PatientTimelineOutput{PatientID: input.PatientID, Series: []labsvc.TimelineOutput{}}, nil
+}
+
+type recordingStorage struct {
+	uploaded bool
+}
+
+func (s *recordingStorage) Upload(ctx context.Context, file io.Reader, objectName, contentType string) (string, error) {
+	s.uploaded = true
+	return "gs://bucket/" + objectName, nil
+}
+
+func (s *recordingStorage) Delete(ctx context.Context, uri string) error { return nil }
+
+func (s *recordingStorage) GetSignedURL(ctx context.Context, uri string, expirationMinutes int) (string, error) {
+	return "", nil
+}
+
 func TestListLabs_DefaultUsesSummary(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -125,3 +211,356 @@ func TestListLabs_IncludeResultsUsesFull(t *testing.T) {
 		t.Fatal("did not expect List to be called")
 	}
 }
+
+func TestGetLabJob_FailedReturnsProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	code := "INFRA_EXTERNAL_SERVICE_ERROR"
+	message := "falha ao processar documento"
+	svc := &fakeLabsService{job: &labsvc.ProcessingJobOutput{
+		ID:           uuid.Must(uuid.NewV7()),
+		Status:       "failed",
+		ErrorCode:    &code,
+		ErrorMessage: &message,
+	}}
+	h := NewLabs(svc, nil, nil, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.GET("/patients/:id/labs/jobs/:jobID", h.GetLabJob)
+
+	id := uuid.Must(uuid.NewV7()).String()
+	req := httptest.NewRequest(http.MethodGet, "/patients/"+id+"/labs/jobs/"+svc.job.ID.String(), nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.Code)
+	}
+
+	var body struct {
+		Status string `json:"status"`
+		Error  *struct {
+			Status int    `json:"status"`
+			Code   string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if body.Error == nil {
+		t.Fatal("expected error problem in body")
+	}
+	if body.Error.Code != code || body.Error.Status != http.StatusBadGateway {
+		t.Fatalf("unexpected problem: %+v", body.Error)
+	}
+}
+
+func TestDeleteLab_ReturnsNoContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &fakeLabsService{}
+	h := NewLabs(svc, nil, nil, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.DELETE("/patients/:id/labs/:reportID", h.DeleteLab)
+
+	reportID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodDelete, "/patients/"+uuid.Must(uuid.NewV7()).String()+"/labs/"+reportID.String(), nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, resp.Code)
+	}
+	if svc.deletedID != reportID {
+		t.Fatalf("expected report %s to be deleted, got %s", reportID, svc.deletedID)
+	}
+}
+
+func TestGetLabDocument_ReturnsSignedURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &fakeLabsService{document: &labsvc.DocumentURLOutput{URL: "https://signed.example/a.pdf"}}
+	h := NewLabs(svc, nil, nil, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.GET("/patients/:id/labs/:reportID/document", h.GetLabDocument)
+
+	req := httptest.NewRequest(http.MethodGet, "/patients/"+uuid.Must(uuid.NewV7()).String()+"/labs/"+uuid.Must(uuid.NewV7()).String()+"/document", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.Code)
+	}
+	if resp.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("expected Cache-Control no-store, got %q", resp.Header().Get("Cache-Control"))
+	}
+
+	var body labsvc.DocumentURLOutput
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if body.URL != svc.document.URL {
+		t.Fatalf("unexpected url: %q", body.URL)
+	}
+}
+
+func TestGetTimeline_CollectsAnalytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &fakeLabsService{}
+	h := NewLabs(svc, nil, nil, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.GET("/patients/:id/labs/timeline", h.GetTimeline)
+
+	patientID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodGet, "/patients/"+patientID.String()+"/labs/timeline?analyte=hba1c,creatinine&analyte=ldl&from=2024-01-01&to=2024-12-31&limit=20", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, resp.Code, resp.Body.String())
+	}
+	in := svc.timelineInput
+	if in == nil || in.PatientID != patientID || in.Limit != 20 {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+	if strings.Join(in.Analytes, "|") != "hba1c|creatinine|ldl" {
+		t.Fatalf("unexpected analytes: %v", in.Analytes)
+	}
+	if in.From != "2024-01-01" || in.To != "2024-12-31" {
+		t.Fatalf("unexpected period: %q %q", in.From, in.To)
+	}
+}
+
+func TestSearchLabs_PassesQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &fakeLabsService{}
+	h := NewLabs(svc, nil, nil, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.GET("/patients/:id/labs/search", h.SearchLabs)
+
+	patientID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodGet, "/patients/"+patientID.String()+"/labs/search?q=hepatite+B&limit=5", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, resp.Code, resp.Body.String())
+	}
+	if svc.searchQuery != "hepatite B" {
+		t.Fatalf("unexpected query: %q", svc.searchQuery)
+	}
+}
+
+func TestCompareLabs_RequiresBothReports(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &fakeLabsService{}
+	h := NewLabs(svc, nil, nil, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeProfessional})
+		c.Next()
+	})
+	r.GET("/patients/:id/labs/compare", h.CompareLabs)
+
+	patientID := uuid.Must(uuid.NewV7())
+	reportA := uuid.Must(uuid.NewV7())
+	reportB := uuid.Must(uuid.NewV7())
+	base := "/patients/" + patientID.String() + "/labs/compare"
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, base+"?a="+reportA.String()+"&b="+reportB.String(), nil))
+	if resp.Code != http.StatusOK || svc.compared != [2]uuid.UUID{reportA, reportB} {
+		t.Fatalf("expected comparison of a and b, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, base+"?a="+reportA.String(), nil))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d without b, got %d: %s", http.StatusBadRequest, resp.Code, resp.Body.String())
+	}
+}
+
+func TestUploadLab_DuplicateReturnsExistingReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	existingID := uuid.Must(uuid.NewV7())
+	svc := &fakeLabsService{duplicateOf: &existingID}
+	storage := &recordingStorage{}
+	h := NewLabs(svc, nil, storage, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.POST("/patients/:id/labs", h.UploadAndProcessLabs)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="laudo.pdf"`)
+	header.Set("Content-Type", "application/pdf")
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		t.Fatalf("create part: %v", err)
+	}
+	_, _ = part.Write([]byte("%PDF-1.4 laudo"))
+	_ = mw.Close()
+
+	patientID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+patientID.String()+"/labs", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusConflict, resp.Code, resp.Body.String())
+	}
+	if storage.uploaded {
+		t.Fatalf("expected duplicate to be rejected before storage upload")
+	}
+
+	var problem struct {
+		ResourceID string `json:"resource_id"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if problem.ResourceID != existingID.String() {
+		t.Fatalf("expected resource_id %s, got %q", existingID, problem.ResourceID)
+	}
+	wantLocation := "/v1/patients/" + patientID.String() + "/labs/" + existingID.String()
+	if got := resp.Header().Get("Location"); got != wantLocation {
+		t.Fatalf("expected Location %q, got %q", wantLocation, got)
+	}
+}
+
+func TestUploadLab_InProcessingRejectedBeforeUpload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jobID := uuid.Must(uuid.NewV7())
+	storage := &recordingStorage{}
+	h := NewLabs(&fakeLabsService{processingJob: &jobID}, nil, storage, allowAllAuthorizer{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.POST("/patients/:id/labs", h.UploadAndProcessLabs)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="laudo.pdf"`)
+	header.Set("Content-Type", "application/pdf")
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		t.Fatalf("create part: %v", err)
+	}
+	_, _ = part.Write([]byte("%PDF-1.4 laudo"))
+	_ = mw.Close()
+
+	patientID := uuid.Must(uuid.NewV7())
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+patientID.String()+"/labs", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusConflict, resp.Code, resp.Body.String())
+	}
+	if storage.uploaded {
+		t.Fatalf("expected file in processing to be rejected before storage upload")
+	}
+	wantLocation := "/v1/patients/" + patientID.String() + "/labs/jobs/" + jobID.String()
+	if got := resp.Header().Get("Location"); got != wantLocation {
+		t.Fatalf("expected Location %q, got %q", wantLocation, got)
+	}
+}
+
+type denyActionAuthorizer struct {
+	action rbac.Action
+}
+
+func (a denyActionAuthorizer) Require(ctx context.Context, actor *user.User, action rbac.Action, patientID *uuid.UUID) error {
+	if action == a.action {
+		return apperr.Forbidden("acesso negado")
+	}
+	return nil
+}
+
+func TestUploadLab_IdentityOverrideRequiresPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := &recordingStorage{}
+	h := NewLabs(&fakeLabsService{}, nil, storage, denyActionAuthorizer{action: rbac.ActionOverrideLabIdentity})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		helpers.SetCurrentUser(c, &user.User{ID: uuid.Must(uuid.NewV7()), AccountType: user.AccountTypeBasicCare})
+		c.Next()
+	})
+	r.POST("/patients/:id/labs", h.UploadAndProcessLabs)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("identity_override", "true")
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="laudo.pdf"`)
+	header.Set("Content-Type", "application/pdf")
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		t.Fatalf("create part: %v", err)
+	}
+	_, _ = part.Write([]byte("%PDF-1.4 laudo"))
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/patients/"+uuid.Must(uuid.NewV7()).String()+"/labs", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusForbidden, resp.Code, resp.Body.String())
+	}
+	if storage.uploaded {
+		t.Fatalf("expected override to be refused before storage upload")
+	}
+}
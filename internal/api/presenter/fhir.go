@@ -0,0 +1,81 @@
+// internal/api/presenter/fhir.go
+package presenter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/diagnostics"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FHIRJSONContentType é o media type oficial de recursos FHIR em JSON.
+const FHIRJSONContentType = "application/fhir+json; charset=utf-8"
+
+// FHIR serializa um recurso FHIR com o media type correto.
+func FHIR(c *gin.Context, status int, resource any) {
+	b, err := json.Marshal(resource)
+	if err != nil {
+		ErrorResponder(c, apperr.Internal("falha ao serializar recurso FHIR", err))
+		return
+	}
+	c.Data(status, FHIRJSONContentType, b)
+}
+
+// FHIRErrorResponder responde erros como OperationOutcome, o equivalente FHIR
+// do Problem Details. Log e metadados seguem o ErrorResponder.
+func FHIRErrorResponder(c *gin.Context, err error) {
+	if c.Writer.Written() {
+		c.Abort()
+		return
+	}
+
+	status, problem := recordError(c, err)
+
+	c.Abort()
+	FHIR(c, status, ToOperationOutcome(err, problem))
+}
+
+// ToOperationOutcome usa o OperationOutcome da validação quando existe; nos
+// demais casos monta um a partir do Problem.
+func ToOperationOutcome(err error, problem Problem) *diagnostics.OperationOutcome {
+	var outcomeErr *diagnostics.OutcomeError
+	if errors.As(err, &outcomeErr) && outcomeErr.Outcome != nil {
+		return outcomeErr.Outcome
+	}
+
+	code := issueTypeFromStatus(problem.Status)
+	if len(problem.Violations) > 0 {
+		issues := make([]diagnostics.OperationOutcomeIssue, 0, len(problem.Violations))
+		for _, v := range problem.Violations {
+			issues = append(issues, diagnostics.NewIssue(code, v.Field, problem.Detail+": "+v.Reason))
+		}
+		return diagnostics.NewOperationOutcome(issues...)
+	}
+
+	return diagnostics.NewOperationOutcome(diagnostics.NewIssue(code, "", problem.Detail))
+}
+
+func issueTypeFromStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return diagnostics.IssueInvalid
+	case http.StatusUnauthorized:
+		return "login"
+	case http.StatusForbidden:
+		return diagnostics.IssueForbidden
+	case http.StatusNotFound:
+		return diagnostics.IssueNotFound
+	case http.StatusConflict:
+		return diagnostics.IssueDuplicate
+	case http.StatusRequestEntityTooLarge:
+		return "too-long"
+	case http.StatusUnsupportedMediaType:
+		return diagnostics.IssueNotSupported
+	default:
+		return diagnostics.IssueException
+	}
+}
@@ -0,0 +1,26 @@
+// internal/api/presenter/file.go
+package presenter
+
+import (
+	"mime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Attachment responde um arquivo para download com o nome sugerido.
+func Attachment(c *gin.Context, status int, contentType, filename string, content []byte) {
+	setAttachment(c, filename)
+	c.Data(status, contentType, content)
+}
+
+// StreamAttachment prepara os cabeçalhos de um download que será escrito
+// aos poucos em c.Writer.
+func StreamAttachment(c *gin.Context, status int, contentType, filename string) {
+	setAttachment(c, filename)
+	c.Header("Content-Type", contentType)
+	c.Status(status)
+}
+
+func setAttachment(c *gin.Context, filename string) {
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+}
@@ -0,0 +1,33 @@
+// internal/api/presenter/hl7.go
+package presenter
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// HL7v2ContentType é o media type de mensagens HL7 v2 em ER7 (pipes).
+const HL7v2ContentType = "x-application/hl7-v2+er7; charset=utf-8"
+
+// HL7 responde a mensagem (em geral o ACK) como corpo.
+func HL7(c *gin.Context, status int, message string) {
+	c.Data(status, HL7v2ContentType, []byte(message))
+}
+
+// HL7ErrorResponder usa o status HTTP do erro, com log e metadados do
+// ErrorResponder, mas responde o ACK (AE/AR) em vez do Problem Details.
+// Sem ACK, cai no ErrorResponder.
+func HL7ErrorResponder(c *gin.Context, err error, ack string) {
+	if ack == "" {
+		ErrorResponder(c, err)
+		return
+	}
+	if c.Writer.Written() {
+		c.Abort()
+		return
+	}
+
+	status, _ := recordError(c, err)
+
+	c.Abort()
+	HL7(c, status, ack)
+}
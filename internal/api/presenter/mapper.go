@@ -13,7 +13,9 @@ func ToProblem(err error, meta ProblemMeta) (status int, body Problem) {
 
 	if errors.As(err, &appErr) && appErr != nil {
 		status = StatusFromCode(appErr.Kind)
-		return status, NewProblem(status, appErr.Kind, appErr.Message, appErr.Violations, meta, err)
+		body = NewProblem(status, appErr.Kind, appErr.Message, appErr.Violations, meta, err)
+		body.ResourceID = appErr.ResourceID
+		return status, body
 	}
 
 	status = StatusFromCode(apperr.INTERNAL_ERROR)
@@ -47,7 +49,8 @@ func StatusFromCode(code apperr.ErrorKind) int {
 		return http.StatusConflict // 409
 
 	// DOMAIN
-	case apperr.DOMAIN_RULE_VIOLATION:
+	case apperr.DOMAIN_RULE_VIOLATION,
+		apperr.PATIENT_IDENTITY_MISMATCH:
 		return http.StatusUnprocessableEntity // 422
 
 	// RATE
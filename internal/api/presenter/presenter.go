@@ -20,6 +20,16 @@ func ErrorResponder(c *gin.Context, err error) {
 		c.Abort()
 		return
 	}
+
+	status, resp := recordError(c, err)
+
+	// Resposta final padronizada.
+	c.Abort()
+	writeProblem(c, status, resp)
+}
+
+// recordError mapeia o erro para HTTP + Problem e registra metadados/log.
+func recordError(c *gin.Context, err error) (int, Problem) {
 	// Registra erro no contexto do Gin para middleware de log.
 	if err != nil {
 		_ = c.Error(err)
@@ -53,9 +63,7 @@ func ErrorResponder(c *gin.Context, err error) {
 		log.Log(c.Request.Context(), level, "handler_error", attrs...)
 	}
 
-	// Resposta final padronizada.
-	c.Abort()
-	writeProblem(c, status, resp)
+	return status, resp
 }
 
 func shouldSkipErrorLog(c *gin.Context) bool {
@@ -22,10 +22,11 @@ type Problem struct {
 	Instance string `json:"instance,omitempty"` // URI específica da ocorrência
 
 	// Extensões RFC 9457 (opcionais)
-	Code       ErrorCode   `json:"code,omitempty"`       // Código estável do erro (contrato Sonnda)
-	Violations []Violation `json:"violations,omitempty"` // Validações
-	TraceID    string      `json:"traceId,omitempty"`    // ID para rastreamento (normalmente X-Request-ID)
-	Timestamp  time.Time   `json:"timestamp,omitempty"`  // Quando ocorreu
+	Code       ErrorCode   `json:"code,omitempty"`        // Código estável do erro (contrato Sonnda)
+	Violations []Violation `json:"violations,omitempty"`  // Validações
+	ResourceID string      `json:"resource_id,omitempty"` // Recurso existente (conflitos)
+	TraceID    string      `json:"traceId,omitempty"`     // ID para rastreamento (normalmente X-Request-ID)
+	Timestamp  time.Time   `json:"timestamp,omitempty"`   // Quando ocorreu
 
 	// Campo interno para causa/original
 	cause error `json:"-"`
@@ -89,6 +90,8 @@ func ProblemTitleFromCode(code ErrorCode, status int) string {
 	// DOMAIN
 	case apperr.DOMAIN_RULE_VIOLATION:
 		return "Regra de negócio violada"
+	case apperr.PATIENT_IDENTITY_MISMATCH:
+		return "Paciente não confere"
 
 	// RATE
 	case apperr.RATE_LIMIT_EXCEEDED:
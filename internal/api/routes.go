@@ -19,6 +19,16 @@ type APIDependencies struct {
 	UserHandler            *handlers.UserHandler
 	PatientHandler         *handlers.PatientHandler
 	LabsHandler            *handlers.LabsHandler
+	AnalytesHandler        *handlers.AnalytesHandler
+	LabsFHIRHandler        *handlers.LabsFHIRHandler
+	LabsHL7Handler         *handlers.LabsHL7Handler
+	LabsManualHandler      *handlers.LabsManualHandler
+	LabsAmendHandler       *handlers.LabsAmendHandler
+	LabsReviewHandler      *handlers.LabsReviewHandler
+	LabsCriticalHandler    *handlers.LabsCriticalHandler
+	LabsExportHandler      *handlers.LabsExportHandler
+	// Opcional: presente apenas com o storage local.
+	FilesHandler *handlers.FilesHandler
 }
 
 type RootInfo struct {
@@ -39,6 +49,10 @@ func SetupRoutes(
 	registerPublicRoutes(r)
 	registerDocsRoutes(r)
 	registerOpenAPIRoute(r)
+	if deps.FilesHandler != nil {
+		// Links assinados do storage local (a assinatura substitui o bearer token).
+		r.GET("/files/*path", deps.FilesHandler.Download)
+	}
 
 	v1 := r.Group("/v1")
 
@@ -87,14 +101,57 @@ func SetupRoutes(
 
 			//Dados básicos do paciente
 			patients.GET("/:id", deps.PatientHandler.GetPatient)
+			//Bundle FHIR R5 com todos os laudos (estilo Patient/$everything)
+			patients.GET("/:id/$everything", deps.LabsFHIRHandler.ExportPatient)
 
 			labs := patients.Group("/:id/labs")
 			{
 				labs.GET("", deps.LabsHandler.ListLabs)
 				labs.POST("", deps.LabsHandler.UploadAndProcessLabs)
+				labs.POST("/batch", deps.LabsHandler.UploadLabsBatch)
+				labs.GET("/jobs/:jobID", deps.LabsHandler.GetLabJob)
+				labs.GET("/timeline", deps.LabsHandler.GetTimeline)
+				labs.GET("/search", deps.LabsHandler.SearchLabs)
+				labs.GET("/compare", deps.LabsHandler.CompareLabs)
+				labs.GET("/cumulative.pdf", deps.LabsExportHandler.CumulativePDF)
+				labs.GET("/export", deps.LabsExportHandler.ExportHistory)
+				labs.POST("/fhir", deps.LabsFHIRHandler.ImportBundle)
+				labs.POST("/manual", deps.LabsManualHandler.CreateManual)
+				labs.GET("/:reportID", deps.LabsHandler.GetLab)
+				labs.PATCH("/:reportID", deps.LabsAmendHandler.AmendLab)
+				labs.DELETE("/:reportID", deps.LabsHandler.DeleteLab)
+				labs.GET("/:reportID/history", deps.LabsHandler.GetLabHistory)
+				labs.POST("/:reportID/review", deps.LabsReviewHandler.ReviewLab)
+				labs.GET("/:reportID/document", deps.LabsHandler.GetLabDocument)
+				labs.GET("/:reportID/fhir", deps.LabsFHIRHandler.ExportReport)
 			}
 
 		}
+
+		//Resultados HL7 v2 (ORU^R01); o paciente vem do PID
+		registered.POST("/labs/hl7", deps.LabsHL7Handler.ImportMessage)
+
+		//Fila de revisão dos laudos extraídos com baixa confiança
+		registered.GET("/labs/reviews", deps.LabsReviewHandler.ListPending)
+
+		//Alertas de valores críticos e suas regras
+		registered.GET("/labs/alerts", deps.LabsCriticalHandler.ListAlerts)
+		registered.POST("/labs/alerts/:alertID/acknowledge", deps.LabsCriticalHandler.Acknowledge)
+		criticalRules := registered.Group("/labs/critical-rules")
+		{
+			criticalRules.GET("", deps.LabsCriticalHandler.ListRules)
+			criticalRules.POST("", deps.LabsCriticalHandler.CreateRule)
+			criticalRules.DELETE("/:ruleID", deps.LabsCriticalHandler.DeleteRule)
+		}
+
+		//Catálogo de analitos (LOINC)
+		analytes := registered.Group("/labs/analytes")
+		{
+			analytes.GET("", deps.AnalytesHandler.List)
+			analytes.POST("/remap", deps.AnalytesHandler.Remap)
+			analytes.POST("/:code/synonyms", deps.AnalytesHandler.AddSynonym)
+			analytes.DELETE("/:code/synonyms/:name", deps.AnalytesHandler.RemoveSynonym)
+		}
 	}
 }
 
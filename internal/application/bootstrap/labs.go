@@ -8,28 +8,81 @@ import (
 	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
 	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
 	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
+	"github.com/gabrielgcmr/sonnda/internal/infrastructure/notification"
+	"github.com/gabrielgcmr/sonnda/internal/infrastructure/pdf"
 	postgress "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres"
 	"github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres/repo"
+	"github.com/gabrielgcmr/sonnda/internal/infrastructure/spreadsheet"
 )
 
 type LabsModule struct {
-	Handler *handlers.LabsHandler
+	Handler         *handlers.LabsHandler
+	AnalytesHandler *handlers.AnalytesHandler
+	FHIRHandler     *handlers.LabsFHIRHandler
+	HL7Handler      *handlers.LabsHL7Handler
+	ManualHandler   *handlers.LabsManualHandler
+	AmendHandler    *handlers.LabsAmendHandler
+	ReviewHandler   *handlers.LabsReviewHandler
+	CriticalHandler *handlers.LabsCriticalHandler
+	ExportHandler   *handlers.LabsExportHandler
+	Worker          *labsuc.LabJobWorker
+	// HL7Import também atende o listener MLLP, quando habilitado.
+	HL7Import labsuc.CreateLabReportFromHL7UseCase
 }
 
 func NewLabsModule(
 	dbClient *postgress.Client,
 	docExtractor domainai.DocumentExtractorService,
 	storage domainstorage.FileStorageService,
+	workerCfg labsuc.LabJobWorkerConfig,
 ) *LabsModule {
 	patientRepo := repo.NewPatientRepository(dbClient)
 	accessRepo := repo.NewPatientAccessRepository(dbClient)
 	profRepo := repo.NewProfessionalRepository(dbClient)
 	labsRepo := repo.NewLabsRepository(dbClient)
+	jobsRepo := repo.NewLabJobsRepository(dbClient)
+	analytesRepo := repo.NewAnalytesRepository(dbClient)
+	alertsRepo := repo.NewCriticalAlertsRepository(dbClient)
 
-	svc := labsvc.New(patientRepo, labsRepo)
-	createUC := labsuc.NewCreateLabReportFromDocument(patientRepo, labsRepo, docExtractor)
+	alerter := labsuc.NewCriticalValueAlerter(alertsRepo, accessRepo, notification.NewLogNotifier())
+	calculator := labsuc.NewDerivedValueCalculator(labsRepo)
+	svc := labsvc.New(patientRepo, labsRepo, jobsRepo, storage)
+	createUC := labsuc.NewCreateLabReportFromDocument(patientRepo, labsRepo, analytesRepo, docExtractor, alerter, calculator)
+	enqueueUC := labsuc.NewEnqueueLabReportProcessing(patientRepo, labsRepo, jobsRepo)
+	hl7UC := labsuc.NewCreateLabReportFromHL7(patientRepo, labsRepo, analytesRepo, alerter, calculator)
 	authz := authorization.New(patientRepo, accessRepo, profRepo)
 	return &LabsModule{
-		Handler: handlers.NewLabs(svc, createUC, storage, authz),
+		Handler:         handlers.NewLabs(svc, enqueueUC, storage, authz),
+		AnalytesHandler: handlers.NewAnalytesHandler(labsvc.NewAnalyteCatalog(analytesRepo, labsRepo), authz),
+		FHIRHandler: handlers.NewLabsFHIRHandler(
+			labsvc.NewFHIRExporter(patientRepo, labsRepo, analytesRepo, storage),
+			labsuc.NewCreateLabReportFromFHIR(patientRepo, labsRepo, analytesRepo, alerter, calculator),
+			authz,
+		),
+		HL7Handler: handlers.NewLabsHL7Handler(hl7UC, authz),
+		ManualHandler: handlers.NewLabsManualHandler(
+			labsuc.NewCreateLabReportManual(patientRepo, labsRepo, analytesRepo, alerter, calculator),
+			authz,
+		),
+		AmendHandler: handlers.NewLabsAmendHandler(
+			labsuc.NewAmendLabReport(patientRepo, labsRepo, analytesRepo, alerter, calculator),
+			authz,
+		),
+		ReviewHandler: handlers.NewLabsReviewHandler(
+			svc,
+			labsuc.NewReviewLabReport(patientRepo, labsRepo, analytesRepo, alerter, calculator),
+			authz,
+		),
+		CriticalHandler: handlers.NewLabsCriticalHandler(
+			labsvc.NewCriticalValues(alertsRepo, analytesRepo),
+			authz,
+		),
+		ExportHandler: handlers.NewLabsExportHandler(
+			labsvc.NewCumulativeReport(patientRepo, labsRepo, pdf.NewCumulativeRenderer()),
+			labsvc.NewHistoryExport(patientRepo, labsRepo, spreadsheet.NewCSVFormat(), spreadsheet.NewXLSXFormat()),
+			authz,
+		),
+		Worker:    labsuc.NewLabJobWorker(jobsRepo, createUC, workerCfg),
+		HL7Import: hl7UC,
 	}
 }
@@ -4,6 +4,7 @@
 package bootstrap
 
 import (
+	labsuc "github.com/gabrielgcmr/sonnda/internal/application/usecase/labs"
 	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
 	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
 	postgress "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres"
@@ -19,10 +20,11 @@ func NewModules(
 	dbClient *postgress.Client,
 	docExtractor domainai.DocumentExtractorService,
 	storage domainstorage.FileStorageService,
+	labWorkerCfg labsuc.LabJobWorkerConfig,
 ) *Modules {
 	return &Modules{
 		User:    NewUserModule(dbClient),
 		Patient: NewPatientModule(dbClient),
-		Labs:    NewLabsModule(dbClient, docExtractor, storage),
+		Labs:    NewLabsModule(dbClient, docExtractor, storage, labWorkerCfg),
 	}
 }
@@ -177,6 +177,10 @@ func isPatientScoped(action rbac.Action) bool {
 		rbac.ActionWriteClinicalNote,
 		rbac.ActionReadLabs,
 		rbac.ActionUploadLabs,
+		rbac.ActionDeleteLabs,
+		rbac.ActionAmendLabs,
+		rbac.ActionOverrideLabIdentity,
+		rbac.ActionReviewLabs,
 		rbac.ActionReadPrescriptions,
 		rbac.ActionWritePrescriptions:
 		return true
@@ -0,0 +1,17 @@
+// internal/application/services/labs/catalog.go
+package labsvc
+
+import (
+	"context"
+)
+
+// AnalyteCatalog gerencia o catálogo de analitos e o vínculo do histórico de itens.
+type AnalyteCatalog interface {
+	ListAnalytes(ctx context.Context) ([]AnalyteOutput, error)
+	// AddSynonym cadastra o sinônimo e revincula o histórico.
+	AddSynonym(ctx context.Context, analyteCode, name string) (*AnalyteOutput, error)
+	// RemoveSynonym remove o sinônimo e revincula o histórico.
+	RemoveSynonym(ctx context.Context, analyteCode, name string) (*AnalyteOutput, error)
+	// Remap recalcula o analito de todos os itens já gravados.
+	Remap(ctx context.Context) (*AnalyteRemapOutput, error)
+}
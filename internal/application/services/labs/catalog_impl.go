@@ -0,0 +1,239 @@
+// internal/application/services/labs/catalog_impl.go
+package labsvc
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+type analyteCatalog struct {
+	analytesRepo repository.Analytes
+	labsRepo     repository.Labs
+}
+
+var _ AnalyteCatalog = (*analyteCatalog)(nil)
+
+func NewAnalyteCatalog(analytesRepo repository.Analytes, labsRepo repository.Labs) AnalyteCatalog {
+	return &analyteCatalog{
+		analytesRepo: analytesRepo,
+		labsRepo:     labsRepo,
+	}
+}
+
+func (s *analyteCatalog) ListAnalytes(ctx context.Context) ([]AnalyteOutput, error) {
+	analytes, err := s.analytesRepo.List(ctx)
+	if err != nil {
+		return nil, mapRepoError("analytes.list", err)
+	}
+
+	out := make([]AnalyteOutput, 0, len(analytes))
+	for i := range analytes {
+		out = append(out, *ToAnalyteOutput(&analytes[i]))
+	}
+	return out, nil
+}
+
+func (s *analyteCatalog) AddSynonym(ctx context.Context, analyteCode, name string) (*AnalyteOutput, error) {
+	analyteCode, name, err := validateSynonymInput(analyteCode, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findAnalyte(ctx, analyteCode); err != nil {
+		return nil, err
+	}
+
+	nameKey := labs.NormalizeAnalyteName(name)
+	owner, err := s.analytesRepo.FindSynonymOwner(ctx, nameKey)
+	if err != nil {
+		return nil, mapRepoError("analytes.find_synonym_owner", err)
+	}
+	switch owner {
+	case "":
+		relinks, err := s.relinksFor(ctx, nameKey, func(a *labs.Analyte) {
+			if a.Code == analyteCode {
+				a.Synonyms = append(a.Synonyms, name)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := s.analytesRepo.AddSynonym(ctx, analyteCode, name, relinks); err != nil {
+			return nil, mapRepoError("analytes.add_synonym", err)
+		}
+	case analyteCode:
+		// Idempotente: o nome já aponta para este analito.
+	default:
+		return nil, &apperr.AppError{
+			Kind:    apperr.RESOURCE_CONFLICT,
+			Message: "sinônimo já pertence a outro analito",
+			Violations: []apperr.Violation{
+				{Field: "name", Reason: "already_mapped_to:" + owner},
+			},
+		}
+	}
+
+	return s.analyteOutput(ctx, analyteCode)
+}
+
+func (s *analyteCatalog) RemoveSynonym(ctx context.Context, analyteCode, name string) (*AnalyteOutput, error) {
+	analyteCode, name, err := validateSynonymInput(analyteCode, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findAnalyte(ctx, analyteCode); err != nil {
+		return nil, err
+	}
+
+	nameKey := labs.NormalizeAnalyteName(name)
+	relinks, err := s.relinksFor(ctx, nameKey, func(a *labs.Analyte) {
+		if a.Code != analyteCode {
+			return
+		}
+		a.Synonyms = slices.DeleteFunc(slices.Clone(a.Synonyms), func(synonym string) bool {
+			return labs.NormalizeAnalyteName(synonym) == nameKey
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.analytesRepo.RemoveSynonym(ctx, analyteCode, name, relinks)
+	if err != nil {
+		return nil, mapRepoError("analytes.remove_synonym", err)
+	}
+	if !removed {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "sinônimo não encontrado",
+		}
+	}
+
+	return s.analyteOutput(ctx, analyteCode)
+}
+
+// relinksFor calcula o novo vínculo só dos nomes de parâmetro afetados pelo
+// sinônimo nameKey, com o catálogo já alterado por change.
+func (s *analyteCatalog) relinksFor(ctx context.Context, nameKey string, change func(*labs.Analyte)) ([]labs.AnalyteRelink, error) {
+	analytes, err := s.analytesRepo.List(ctx)
+	if err != nil {
+		return nil, mapRepoError("analytes.list", err)
+	}
+	for i := range analytes {
+		change(&analytes[i])
+	}
+	catalog := labs.NewAnalyteCatalog(analytes)
+
+	names, err := s.labsRepo.ListParameterNamesByKey(ctx, nameKey)
+	if err != nil {
+		return nil, mapRepoError("labs.list_parameter_names", err)
+	}
+
+	relinks := make([]labs.AnalyteRelink, 0, len(names))
+	for _, name := range names {
+		relinks = append(relinks, relinkFor(catalog, name))
+	}
+	return relinks, nil
+}
+
+func (s *analyteCatalog) Remap(ctx context.Context) (*AnalyteRemapOutput, error) {
+	analytes, err := s.analytesRepo.List(ctx)
+	if err != nil {
+		return nil, mapRepoError("analytes.list", err)
+	}
+	catalog := labs.NewAnalyteCatalog(analytes)
+
+	names, err := s.labsRepo.ListDistinctParameterNames(ctx)
+	if err != nil {
+		return nil, mapRepoError("labs.list_parameter_names", err)
+	}
+
+	out := &AnalyteRemapOutput{ParameterNames: len(names)}
+	relinks := make([]labs.AnalyteRelink, 0, len(names))
+	for _, name := range names {
+		relink := relinkFor(catalog, name)
+		if relink.AnalyteCode == nil {
+			out.Unmatched = append(out.Unmatched, name)
+		}
+		relinks = append(relinks, relink)
+	}
+
+	n, err := s.labsRepo.Relink(ctx, relinks)
+	if err != nil {
+		return nil, mapRepoError("labs.relink", err)
+	}
+	out.UpdatedItems = n
+	return out, nil
+}
+
+func relinkFor(catalog *labs.AnalyteCatalog, parameterName string) labs.AnalyteRelink {
+	relink := labs.AnalyteRelink{ParameterName: parameterName}
+	if a := catalog.Match(parameterName); a != nil {
+		code := a.Code
+		relink.AnalyteCode = &code
+	}
+	return relink
+}
+
+func (s *analyteCatalog) findAnalyte(ctx context.Context, code string) (*labs.Analyte, error) {
+	analyte, err := s.analytesRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, mapRepoError("analytes.find_by_code", err)
+	}
+	if analyte == nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "analito não encontrado",
+		}
+	}
+	return analyte, nil
+}
+
+func (s *analyteCatalog) analyteOutput(ctx context.Context, code string) (*AnalyteOutput, error) {
+	analyte, err := s.findAnalyte(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return ToAnalyteOutput(analyte), nil
+}
+
+func validateSynonymInput(analyteCode, name string) (string, string, error) {
+	analyteCode = strings.TrimSpace(analyteCode)
+	name = strings.Join(strings.Fields(name), " ")
+
+	var violations []apperr.Violation
+	if analyteCode == "" {
+		violations = append(violations, apperr.Violation{Field: "code", Reason: "required"})
+	}
+	if labs.NormalizeAnalyteName(name) == "" {
+		violations = append(violations, apperr.Violation{Field: "name", Reason: "required"})
+	}
+	if len(violations) > 0 {
+		return "", "", apperr.Validation("entrada inválida", violations...)
+	}
+	return analyteCode, name, nil
+}
+
+// ToAnalyteOutput converte o analito de domínio no DTO de saída.
+func ToAnalyteOutput(a *labs.Analyte) *AnalyteOutput {
+	if a == nil {
+		return nil
+	}
+	synonyms := a.Synonyms
+	if synonyms == nil {
+		synonyms = []string{}
+	}
+	return &AnalyteOutput{
+		Code:        a.Code,
+		LOINC:       a.LOINC,
+		DisplayName: a.DisplayName,
+		DefaultUnit: a.DefaultUnit,
+		Synonyms:    synonyms,
+	}
+}
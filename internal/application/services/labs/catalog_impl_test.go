@@ -0,0 +1,168 @@
+// internal/application/services/labs/catalog_impl_test.go
+package labsvc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+type fakeAnalytesRepo struct {
+	analytes []labs.Analyte
+	added    []string
+	// relinks registra os vínculos aplicados junto com o sinônimo.
+	relinks []labs.AnalyteRelink
+}
+
+func (r *fakeAnalytesRepo) List(ctx context.Context) ([]labs.Analyte, error) {
+	out := make([]labs.Analyte, len(r.analytes))
+	for i, a := range r.analytes {
+		a.Synonyms = slices.Clone(a.Synonyms)
+		out[i] = a
+	}
+	return out, nil
+}
+func (r *fakeAnalytesRepo) FindByCode(ctx context.Context, code string) (*labs.Analyte, error) {
+	for i := range r.analytes {
+		if r.analytes[i].Code == code {
+			return &r.analytes[i], nil
+		}
+	}
+	return nil, nil
+}
+func (r *fakeAnalytesRepo) FindSynonymOwner(ctx context.Context, nameKey string) (string, error) {
+	if a := labs.NewAnalyteCatalog(r.analytes).Match(nameKey); a != nil {
+		return a.Code, nil
+	}
+	return "", nil
+}
+func (r *fakeAnalytesRepo) AddSynonym(ctx context.Context, analyteCode, name string, relinks []labs.AnalyteRelink) error {
+	for i := range r.analytes {
+		if r.analytes[i].Code == analyteCode {
+			r.analytes[i].Synonyms = append(r.analytes[i].Synonyms, name)
+		}
+	}
+	r.added = append(r.added, name)
+	r.relinks = append(r.relinks, relinks...)
+	return nil
+}
+func (r *fakeAnalytesRepo) RemoveSynonym(ctx context.Context, analyteCode, name string, relinks []labs.AnalyteRelink) (bool, error) {
+	key := labs.NormalizeAnalyteName(name)
+	for i := range r.analytes {
+		a := &r.analytes[i]
+		if a.Code != analyteCode {
+			continue
+		}
+		for j, synonym := range a.Synonyms {
+			if labs.NormalizeAnalyteName(synonym) == key {
+				a.Synonyms = slices.Delete(a.Synonyms, j, j+1)
+				r.relinks = append(r.relinks, relinks...)
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func seedAnalytes() []labs.Analyte {
+	return []labs.Analyte{
+		{Code: "hemoglobin", DisplayName: "Hemoglobina", Synonyms: []string{"Hb"}},
+		{Code: "glucose", DisplayName: "Glicose", Synonyms: []string{"Glicemia"}},
+	}
+}
+
+func TestRemap_LinksKnownNamesAndReportsUnmatched(t *testing.T) {
+	labsRepo := &fakeLabsRepo{parameterNames: []string{"HEMOGLOBINA", "Hb", "Glicose (jejum)", "Ferritina"}}
+	svc := NewAnalyteCatalog(&fakeAnalytesRepo{analytes: seedAnalytes()}, labsRepo)
+
+	out, err := svc.Remap(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, want := range map[string]string{"HEMOGLOBINA": "hemoglobin", "Hb": "hemoglobin", "Glicose (jejum)": "glucose"} {
+		got := labsRepo.analyteByName[name]
+		if got == nil || *got != want {
+			t.Errorf("%q: expected %s, got %v", name, want, got)
+		}
+	}
+	if labsRepo.analyteByName["Ferritina"] != nil {
+		t.Errorf("expected Ferritina to stay unlinked")
+	}
+	if len(out.Unmatched) != 1 || out.Unmatched[0] != "Ferritina" {
+		t.Fatalf("unexpected unmatched %v", out.Unmatched)
+	}
+}
+
+func TestAddSynonym_RelinksOnlyAffectedNames(t *testing.T) {
+	analytesRepo := &fakeAnalytesRepo{analytes: seedAnalytes()}
+	labsRepo := &fakeLabsRepo{parameterNames: []string{"Hgb", "HGB (automatizado)", "Hgb total", "Glicose"}}
+	svc := NewAnalyteCatalog(analytesRepo, labsRepo)
+
+	out, err := svc.AddSynonym(context.Background(), "hemoglobin", "  Hgb ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(analytesRepo.added) != 1 || analytesRepo.added[0] != "Hgb" {
+		t.Fatalf("expected synonym to be stored trimmed, got %v", analytesRepo.added)
+	}
+	if len(analytesRepo.relinks) != 2 {
+		t.Fatalf("expected only the names matching the synonym, got %+v", analytesRepo.relinks)
+	}
+	for _, relink := range analytesRepo.relinks {
+		if relink.AnalyteCode == nil || *relink.AnalyteCode != "hemoglobin" {
+			t.Errorf("%q: expected hemoglobin, got %v", relink.ParameterName, relink.AnalyteCode)
+		}
+	}
+	if labsRepo.analyteByName != nil {
+		t.Fatalf("expected no full remap, got %v", labsRepo.analyteByName)
+	}
+	if len(out.Synonyms) != 2 {
+		t.Fatalf("unexpected synonyms %v", out.Synonyms)
+	}
+}
+
+func TestRemoveSynonym_UnlinksAffectedNames(t *testing.T) {
+	analytesRepo := &fakeAnalytesRepo{analytes: seedAnalytes()}
+	labsRepo := &fakeLabsRepo{parameterNames: []string{"Hb", "Hemoglobina"}}
+	svc := NewAnalyteCatalog(analytesRepo, labsRepo)
+
+	if _, err := svc.RemoveSynonym(context.Background(), "hemoglobin", "HB"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(analytesRepo.relinks) != 1 || analytesRepo.relinks[0].ParameterName != "Hb" || analytesRepo.relinks[0].AnalyteCode != nil {
+		t.Fatalf("expected Hb to be unlinked, got %+v", analytesRepo.relinks)
+	}
+}
+
+func TestAddSynonym_OwnedByOtherAnalyte_ReturnsConflict(t *testing.T) {
+	svc := NewAnalyteCatalog(&fakeAnalytesRepo{analytes: seedAnalytes()}, &fakeLabsRepo{})
+
+	_, err := svc.AddSynonym(context.Background(), "hemoglobin", "GLICEMIA")
+
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("expected AppError, got %T", err)
+	}
+	if appErr.Kind != apperr.RESOURCE_CONFLICT {
+		t.Fatalf("expected RESOURCE_CONFLICT, got %s", appErr.Kind)
+	}
+}
+
+func TestAddSynonym_UnknownAnalyte_ReturnsNotFound(t *testing.T) {
+	svc := NewAnalyteCatalog(&fakeAnalytesRepo{analytes: seedAnalytes()}, &fakeLabsRepo{})
+
+	_, err := svc.AddSynonym(context.Background(), "unknown", "Hb")
+
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("expected AppError, got %T", err)
+	}
+	if appErr.Kind != apperr.NOT_FOUND {
+		t.Fatalf("expected NOT_FOUND, got %s", appErr.Kind)
+	}
+}
@@ -0,0 +1,22 @@
+// internal/application/services/labs/critical.go
+package labsvc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CriticalValues gerencia as regras de valores críticos e a caixa de
+// alertas de cada profissional.
+type CriticalValues interface {
+	ListRules(ctx context.Context) ([]CriticalRuleOutput, error)
+	// CreateRule e DeleteRule registram o administrador (userID) que alterou a regra.
+	CreateRule(ctx context.Context, input CriticalRuleInput, userID uuid.UUID) (*CriticalRuleOutput, error)
+	DeleteRule(ctx context.Context, ruleID, userID uuid.UUID) error
+
+	// ListAlerts lista os alertas enviados ao usuário; onlyOpen esconde os já confirmados.
+	ListAlerts(ctx context.Context, userID uuid.UUID, onlyOpen bool, limit, offset int) ([]CriticalAlertOutput, error)
+	// Acknowledge registra que o usuário viu o alerta. Repetir não altera a data.
+	Acknowledge(ctx context.Context, alertID, userID uuid.UUID) error
+}
@@ -0,0 +1,205 @@
+// internal/application/services/labs/critical_impl.go
+package labsvc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+type criticalValues struct {
+	alertsRepo   repository.CriticalAlerts
+	analytesRepo repository.Analytes
+}
+
+var _ CriticalValues = (*criticalValues)(nil)
+
+func NewCriticalValues(alertsRepo repository.CriticalAlerts, analytesRepo repository.Analytes) CriticalValues {
+	return &criticalValues{
+		alertsRepo:   alertsRepo,
+		analytesRepo: analytesRepo,
+	}
+}
+
+func (s *criticalValues) ListRules(ctx context.Context) ([]CriticalRuleOutput, error) {
+	rules, err := s.alertsRepo.ListRules(ctx)
+	if err != nil {
+		return nil, mapRepoError("critical.list_rules", err)
+	}
+
+	out := make([]CriticalRuleOutput, 0, len(rules))
+	for i := range rules {
+		out = append(out, *ToCriticalRuleOutput(&rules[i]))
+	}
+	return out, nil
+}
+
+func (s *criticalValues) CreateRule(ctx context.Context, input CriticalRuleInput, userID uuid.UUID) (*CriticalRuleOutput, error) {
+	sex, err := validateCriticalRuleInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := labs.NewCriticalRule(input.AnalyteCode, sex, input.MinAge, input.MaxAge, input.Low, input.High, input.Unit)
+	if err != nil {
+		return nil, &apperr.AppError{Kind: apperr.VALIDATION_FAILED, Message: "regra inválida", Cause: err}
+	}
+	rule.CreatedBy = &userID
+
+	analyte, err := s.analytesRepo.FindByCode(ctx, rule.AnalyteCode)
+	if err != nil {
+		return nil, mapRepoError("analytes.find_by_code", err)
+	}
+	if analyte == nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "analito não encontrado",
+		}
+	}
+
+	if err := s.alertsRepo.CreateRule(ctx, rule); err != nil {
+		if errors.Is(err, labs.ErrCriticalRuleExists) {
+			return nil, &apperr.AppError{
+				Kind:    apperr.RESOURCE_ALREADY_EXISTS,
+				Message: "já existe regra para este analito, sexo e faixa etária",
+			}
+		}
+		return nil, mapRepoError("critical.create_rule", err)
+	}
+	return ToCriticalRuleOutput(rule), nil
+}
+
+func (s *criticalValues) DeleteRule(ctx context.Context, ruleID, userID uuid.UUID) error {
+	deleted, err := s.alertsRepo.DeleteRule(ctx, ruleID, userID, time.Now().UTC())
+	if err != nil {
+		return mapRepoError("critical.delete_rule", err)
+	}
+	if !deleted {
+		return &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "regra não encontrada",
+		}
+	}
+	return nil
+}
+
+func (s *criticalValues) ListAlerts(ctx context.Context, userID uuid.UUID, onlyOpen bool, limit, offset int) ([]CriticalAlertOutput, error) {
+	alerts, err := s.alertsRepo.ListByRecipient(ctx, userID, onlyOpen, limit, offset)
+	if err != nil {
+		return nil, mapRepoError("critical.list_alerts", err)
+	}
+
+	out := make([]CriticalAlertOutput, 0, len(alerts))
+	for i := range alerts {
+		out = append(out, ToCriticalAlertOutput(&alerts[i]))
+	}
+	return out, nil
+}
+
+func (s *criticalValues) Acknowledge(ctx context.Context, alertID, userID uuid.UUID) error {
+	ok, err := s.alertsRepo.Acknowledge(ctx, alertID, userID, time.Now().UTC())
+	if err != nil {
+		return mapRepoError("critical.acknowledge", err)
+	}
+	if !ok {
+		// Quem não é destinatário não enxerga o alerta.
+		return &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "alerta não encontrado",
+		}
+	}
+	return nil
+}
+
+// validateCriticalRuleInput devolve todas as violações de uma vez, com as
+// mesmas regras de labs.NewCriticalRule.
+func validateCriticalRuleInput(input CriticalRuleInput) (*demographics.Gender, error) {
+	var violations []apperr.Violation
+	if strings.TrimSpace(input.AnalyteCode) == "" {
+		violations = append(violations, apperr.Violation{Field: "analyte_code", Reason: "required"})
+	}
+	if _, ok := labs.ParseUCUM(input.Unit); !ok {
+		violations = append(violations, apperr.Violation{Field: "unit", Reason: "unknown_ucum_unit"})
+	}
+	switch {
+	case input.Low == nil && input.High == nil:
+		violations = append(violations, apperr.Violation{Field: "low", Reason: "low_or_high_required"})
+	case input.Low != nil && input.High != nil && *input.Low >= *input.High:
+		violations = append(violations, apperr.Violation{Field: "low", Reason: "must_be_below_high"})
+	}
+	if input.MinAge != nil && *input.MinAge < 0 {
+		violations = append(violations, apperr.Violation{Field: "min_age", Reason: "negative"})
+	}
+	if input.MaxAge != nil && *input.MaxAge <= 0 {
+		violations = append(violations, apperr.Violation{Field: "max_age", Reason: "must_be_positive"})
+	}
+	if input.MinAge != nil && input.MaxAge != nil && *input.MinAge >= *input.MaxAge {
+		violations = append(violations, apperr.Violation{Field: "min_age", Reason: "must_be_below_max_age"})
+	}
+
+	var sex *demographics.Gender
+	if strings.TrimSpace(input.Sex) != "" {
+		g, err := demographics.ParseGender(input.Sex)
+		if err != nil {
+			violations = append(violations, apperr.Violation{Field: "sex", Reason: "invalid"})
+		} else {
+			sex = &g
+		}
+	}
+
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+	return sex, nil
+}
+
+// ToCriticalRuleOutput converte a regra de domínio no DTO de saída.
+func ToCriticalRuleOutput(r *labs.CriticalRule) *CriticalRuleOutput {
+	out := &CriticalRuleOutput{
+		ID:              r.ID,
+		AnalyteCode:     r.AnalyteCode,
+		MinAge:          r.MinAge,
+		MaxAge:          r.MaxAge,
+		Low:             r.Low,
+		High:            r.High,
+		Unit:            r.Unit,
+		CreatedAt:       r.CreatedAt,
+		CreatedByUserID: r.CreatedBy,
+	}
+	if r.Sex != nil {
+		sex := string(*r.Sex)
+		out.Sex = &sex
+	}
+	return out
+}
+
+// ToCriticalAlertOutput converte o alerta na visão do destinatário (o único
+// listado em Recipients).
+func ToCriticalAlertOutput(a *labs.CriticalAlert) CriticalAlertOutput {
+	out := CriticalAlertOutput{
+		ID:             a.ID,
+		ReportID:       a.ReportID,
+		PatientID:      a.PatientID,
+		ItemID:         a.ItemID,
+		AnalyteCode:    a.AnalyteCode,
+		ParameterName:  a.ParameterName,
+		Value:          a.Value,
+		Limit:          a.Limit,
+		Unit:           a.Unit,
+		Interpretation: string(a.Interpretation),
+		CreatedAt:      a.CreatedAt,
+	}
+	if len(a.Recipients) > 0 {
+		out.NotifiedAt = a.Recipients[0].NotifiedAt
+		out.AcknowledgedAt = a.Recipients[0].AcknowledgedAt
+	}
+	return out
+}
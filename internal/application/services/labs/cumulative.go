@@ -0,0 +1,10 @@
+// internal/application/services/labs/cumulative.go
+package labsvc
+
+import "context"
+
+// CumulativeReport gera o relatório cumulativo de exames do paciente:
+// analitos nas linhas e datas de coleta nas colunas.
+type CumulativeReport interface {
+	Render(ctx context.Context, input CumulativeInput) (*CumulativeReportOutput, error)
+}
@@ -0,0 +1,142 @@
+// internal/application/services/labs/cumulative_impl.go
+package labsvc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+// Limites do relatório cumulativo: analitos pedidos e itens lidos. Acima do
+// limite de itens ficam só os mais recentes.
+const (
+	maxCumulativeAnalytes = 30
+	maxCumulativeItems    = 5000
+)
+
+type cumulativeReport struct {
+	patientRepo repository.Patient
+	labsRepo    repository.Labs
+	renderer    document.CumulativeReportRenderer
+}
+
+var _ CumulativeReport = (*cumulativeReport)(nil)
+
+func NewCumulativeReport(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	renderer document.CumulativeReportRenderer,
+) CumulativeReport {
+	return &cumulativeReport{
+		patientRepo: patientRepo,
+		labsRepo:    labsRepo,
+		renderer:    renderer,
+	}
+}
+
+func (s *cumulativeReport) Render(ctx context.Context, input CumulativeInput) (*CumulativeReportOutput, error) {
+	analytes := uniqueAnalytes(input.Analytes)
+
+	var violations []apperr.Violation
+	if input.PatientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if len(analytes) > maxCumulativeAnalytes {
+		violations = append(violations, apperr.Violation{Field: "analyte", Reason: "invalid"})
+	}
+	from, ok := parseTimelineBound(input.From, false)
+	if !ok {
+		violations = append(violations, apperr.Violation{Field: "from", Reason: "invalid_date"})
+	}
+	to, ok := parseTimelineBound(input.To, true)
+	if !ok {
+		violations = append(violations, apperr.Violation{Field: "to", Reason: "invalid_date"})
+	}
+	if from != nil && to != nil && from.After(*to) {
+		violations = append(violations, apperr.Violation{Field: "to", Reason: "invalid"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	p, err := s.patientRepo.FindByID(ctx, input.PatientID)
+	if err != nil {
+		return nil, mapRepoError("patient.find_by_id", err)
+	}
+	if p == nil {
+		return nil, patientNotFound()
+	}
+
+	items, err := loadHistoryItems(ctx, s.labsRepo, p.ID, analytes, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	content, err := s.renderer.RenderCumulative(ctx, document.CumulativeReport{
+		Patient:     p,
+		Table:       labs.BuildCumulativeTable(items),
+		From:        from,
+		To:          to,
+		GeneratedAt: now,
+	})
+	if err != nil {
+		return nil, apperr.Internal("falha ao gerar o relatório", err)
+	}
+
+	return &CumulativeReportOutput{
+		Content:     content,
+		ContentType: s.renderer.ContentType(),
+		Filename:    "relatorio-cumulativo-" + now.Format("2006-01-02") + ".pdf",
+	}, nil
+}
+
+// loadHistoryItems lê os itens do paciente no período, do mais recente para
+// o mais antigo. Com analitos, cada um é resolvido como na timeline (código,
+// sinônimo ou nome) e itens repetidos entre eles aparecem uma vez só.
+func loadHistoryItems(
+	ctx context.Context,
+	labsRepo repository.Labs,
+	patientID uuid.UUID,
+	analytes []string,
+	from, to *time.Time,
+) ([]labs.LabResultItemTimeline, error) {
+	if len(analytes) == 0 {
+		items, err := labsRepo.ListItemsByPatient(ctx, patientID, from, to, maxCumulativeItems, 0)
+		if err != nil {
+			return nil, mapRepoError("labs.list_items", err)
+		}
+		return items, nil
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var items []labs.LabResultItemTimeline
+	for _, analyte := range analytes {
+		found, err := labsRepo.ListItemsByPatientAndParameter(ctx, patientID, analyte, from, to, maxTimelinePoints, 0)
+		if err != nil {
+			return nil, mapRepoError("labs.list_timeline", err)
+		}
+		for _, it := range found {
+			if seen[it.ItemID] {
+				continue
+			}
+			seen[it.ItemID] = true
+			items = append(items, it)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].EffectiveAt(), items[j].EffectiveAt()
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.After(*b)
+	})
+	return items, nil
+}
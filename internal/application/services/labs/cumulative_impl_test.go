@@ -0,0 +1,104 @@
+// internal/application/services/labs/cumulative_impl_test.go
+package labsvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+type fakeCumulativeRenderer struct {
+	got document.CumulativeReport
+	err error
+}
+
+func (r *fakeCumulativeRenderer) ContentType() string { return "application/pdf" }
+func (r *fakeCumulativeRenderer) RenderCumulative(ctx context.Context, report document.CumulativeReport) ([]byte, error) {
+	r.got = report
+	return []byte("%PDF-1.4"), r.err
+}
+
+func timelineItem(name string, at time.Time, value string, interp labs.Interpretation) labs.LabResultItemTimeline {
+	return labs.LabResultItemTimeline{
+		ReportID: uuid.New(), ItemID: uuid.New(), CollectedAt: &at,
+		TestName: "Bioquímica", ParameterName: name, ResultValue: &value, Interpretation: interp,
+	}
+}
+
+func TestCumulativeReport_Render(t *testing.T) {
+	p := &patient.Patient{ID: uuid.New(), FullName: "Maria"}
+	labsRepo := &fakeLabsRepo{itemsRes: []labs.LabResultItemTimeline{
+		timelineItem("Glicose", time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), "126", labs.InterpretationHigh),
+		timelineItem("Creatinina", time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC), "0,9", labs.InterpretationNormal),
+	}}
+	renderer := &fakeCumulativeRenderer{}
+
+	svc := NewCumulativeReport(&fakePatientRepo{findByIDRes: p}, labsRepo, renderer)
+	out, err := svc.Render(context.Background(), CumulativeInput{PatientID: p.ID, From: "2026-01-01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.ContentType != "application/pdf" || string(out.Content) != "%PDF-1.4" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+
+	if labsRepo.itemsFrom == nil || !labsRepo.itemsFrom.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the period to reach the repository, got %v", labsRepo.itemsFrom)
+	}
+	if renderer.got.Patient != p || renderer.got.From == nil {
+		t.Fatalf("expected patient and period in the report, got %+v", renderer.got)
+	}
+	if table := renderer.got.Table; len(table.Dates) != 2 || len(table.Rows) != 2 || table.AbnormalCount() != 1 {
+		t.Fatalf("unexpected table: %+v", table)
+	}
+}
+
+func TestCumulativeReport_RenderAnalyteFilter(t *testing.T) {
+	p := &patient.Patient{ID: uuid.New()}
+	older := timelineItem("Glicose", time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC), "90", labs.InterpretationNormal)
+	newer := timelineItem("Glicose", time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), "126", labs.InterpretationHigh)
+	// O fake devolve os mesmos itens para cada analito pedido.
+	labsRepo := &fakeLabsRepo{timelineRes: []labs.LabResultItemTimeline{older, newer}}
+	renderer := &fakeCumulativeRenderer{}
+
+	svc := NewCumulativeReport(&fakePatientRepo{findByIDRes: p}, labsRepo, renderer)
+	if _, err := svc.Render(context.Background(), CumulativeInput{PatientID: p.ID, Analytes: []string{"glucose", "glicemia"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	table := renderer.got.Table
+	if len(table.Rows) != 1 || len(table.Dates) != 2 {
+		t.Fatalf("expected repeated items once, got %+v", table)
+	}
+	if cell := table.Rows[0].Cells[1]; cell == nil || cell.Value != "126" {
+		t.Fatalf("expected the newest value in the last column, got %+v", cell)
+	}
+}
+
+func TestCumulativeReport_RenderValidation(t *testing.T) {
+	svc := NewCumulativeReport(&fakePatientRepo{}, &fakeLabsRepo{}, &fakeCumulativeRenderer{})
+
+	_, err := svc.Render(context.Background(), CumulativeInput{PatientID: uuid.New(), From: "2026-03-01", To: "2026-01-01"})
+	if !apperr.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestCumulativeReport_RenderFailure(t *testing.T) {
+	p := &patient.Patient{ID: uuid.New()}
+	renderer := &fakeCumulativeRenderer{err: errors.New("boom")}
+	svc := NewCumulativeReport(&fakePatientRepo{findByIDRes: p}, &fakeLabsRepo{}, renderer)
+
+	_, err := svc.Render(context.Background(), CumulativeInput{PatientID: p.ID})
+	if !apperr.IsInternal(err) {
+		t.Fatalf("expected INTERNAL_ERROR, got %v", err)
+	}
+}
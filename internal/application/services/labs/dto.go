@@ -4,25 +4,44 @@ package labsvc
 import (
 	"time"
 
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+
 	"github.com/google/uuid"
 )
 
 type LabReportOutput struct {
-	ID                uuid.UUID          `json:"id"`
-	PatientID         uuid.UUID          `json:"patient_id"`
-	PatientName       *string            `json:"patient_name,omitempty"`
-	PatientDOB        *time.Time         `json:"patient_dob,omitempty"`
-	LabName           *string            `json:"lab_name,omitempty"`
-	LabPhone          *string            `json:"lab_phone,omitempty"`
-	InsuranceProvider *string            `json:"insurance_provider,omitempty"`
-	RequestingDoctor  *string            `json:"requesting_doctor,omitempty"`
-	TechnicalManager  *string            `json:"technical_manager,omitempty"`
-	ReportDate        *time.Time         `json:"report_date,omitempty"`
-	UploadedByUserID  uuid.UUID          `json:"uploaded_by_user_id"`
-	Fingerprint       *string            `json:"fingerprint,omitempty"`
-	TestResults       []TestResultOutput `json:"test_results"`
-	CreatedAt         time.Time          `json:"created_at"`
-	UpdatedAt         time.Time          `json:"updated_at"`
+	ID                uuid.UUID  `json:"id"`
+	PatientID         uuid.UUID  `json:"patient_id"`
+	PatientName       *string    `json:"patient_name,omitempty"`
+	PatientDOB        *time.Time `json:"patient_dob,omitempty"`
+	LabName           *string    `json:"lab_name,omitempty"`
+	LabPhone          *string    `json:"lab_phone,omitempty"`
+	InsuranceProvider *string    `json:"insurance_provider,omitempty"`
+	RequestingDoctor  *string    `json:"requesting_doctor,omitempty"`
+	TechnicalManager  *string    `json:"technical_manager,omitempty"`
+	ReportDate        *time.Time `json:"report_date,omitempty"`
+	UploadedByUserID  uuid.UUID  `json:"uploaded_by_user_id"`
+	Fingerprint       *string    `json:"fingerprint,omitempty"`
+	// Source indica a origem: document (extraído por IA), manual, fhir ou hl7v2.
+	Source string `json:"source"`
+	// Status segue o DiagnosticReport.status do FHIR; Version cresce a cada correção.
+	Status  string `json:"status"`
+	Version int    `json:"version"`
+	// HasDocument indica se o arquivo original pode ser baixado em .../document.
+	HasDocument bool `json:"has_document"`
+	// IdentityStatus diz se nome, nascimento e CPF impressos conferem com o
+	// paciente: verified, flagged, unverified ou overridden.
+	IdentityStatus *string  `json:"identity_status,omitempty"`
+	IdentityScore  *float64 `json:"identity_score,omitempty"`
+	// ReviewStatus é needs_review enquanto valores de baixa confiança
+	// aguardam um profissional e approved depois da aprovação.
+	ReviewStatus     *string              `json:"review_status,omitempty"`
+	ReviewedByUserID *uuid.UUID           `json:"reviewed_by_user_id,omitempty"`
+	ReviewedAt       *time.Time           `json:"reviewed_at,omitempty"`
+	FieldConfidence  labs.FieldConfidence `json:"field_confidence,omitempty"`
+	TestResults      []TestResultOutput   `json:"test_results"`
+	CreatedAt        time.Time            `json:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at"`
 }
 
 type TestResultOutput struct {
@@ -41,6 +60,36 @@ type TestItemOutput struct {
 	ResultValue   *string   `json:"result_value,omitempty"`
 	ResultUnit    *string   `json:"result_unit,omitempty"`
 	ReferenceText *string   `json:"reference_text,omitempty"`
+	AnalyteCode   *string   `json:"analyte_code,omitempty"`
+
+	// Campos estruturados extraídos de result_value/reference_text.
+	NumericValue     *float64 `json:"numeric_value,omitempty"`
+	UCUMUnit         *string  `json:"ucum_unit,omitempty"`
+	Comparator       string   `json:"comparator,omitempty"`
+	QualitativeValue *string  `json:"qualitative_value,omitempty"`
+	ReferenceLow     *float64 `json:"reference_low,omitempty"`
+	ReferenceHigh    *float64 `json:"reference_high,omitempty"`
+	// Interpretation segue os códigos HL7: N, L, H, LL, HH ou A.
+	Interpretation string `json:"interpretation,omitempty"`
+
+	// Confiança da extração (0 a 1) no item e em cada campo. NeedsReview
+	// marca os itens de baixa confiança ainda não confirmados.
+	Confidence      *float64             `json:"confidence,omitempty"`
+	FieldConfidence labs.FieldConfidence `json:"field_confidence,omitempty"`
+	Confirmed       bool                 `json:"confirmed"`
+	NeedsReview     bool                 `json:"needs_review"`
+
+	// Derived marca valores calculados a partir de outros itens do laudo
+	// (TFG, LDL, não-HDL, HOMA-IR); Formula diz qual equação foi usada.
+	Derived bool   `json:"derived"`
+	Formula string `json:"formula,omitempty"`
+}
+
+// Usado em: GET /patients/:patientID/labs/:reportID/document.
+type DocumentURLOutput struct {
+	URL       string    `json:"url"`
+	MimeType  *string   `json:"mime_type,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // Usado em: GET /patients/:patientID/labs/summary.
@@ -62,3 +111,241 @@ type ResultItemSummaryOutput struct {
 	ResultValue   *string `json:"result_value,omitempty"`
 	ResultUnit    *string `json:"result_unit,omitempty"`
 }
+
+// TimelineInput pede as séries de um ou mais analitos (código do catálogo,
+// sinônimo ou nome do parâmetro no laudo). From e To aceitam AAAA-MM-DD ou
+// RFC 3339 e filtram pela data da coleta; Limit vale para cada série.
+type TimelineInput struct {
+	PatientID uuid.UUID
+	Analytes  []string
+	From      string
+	To        string
+	Limit     int
+}
+
+// Usado em: GET /patients/:patientID/labs/timeline.
+type PatientTimelineOutput struct {
+	PatientID uuid.UUID        `json:"patient_id"`
+	From      *time.Time       `json:"from,omitempty"`
+	To        *time.Time       `json:"to,omitempty"`
+	Series    []TimelineOutput `json:"series"`
+}
+
+// Série temporal de um parâmetro. Unit é a unidade UCUM comum da série:
+// a unidade padrão do analito ou, sem catálogo, a do resultado mais recente.
+type TimelineOutput struct {
+	Parameter   string                `json:"parameter"`
+	AnalyteCode *string               `json:"analyte_code,omitempty"`
+	Unit        *string               `json:"unit,omitempty"`
+	Points      []TimelinePointOutput `json:"points"`
+}
+
+type TimelinePointOutput struct {
+	ReportID    uuid.UUID  `json:"report_id"`
+	ItemID      uuid.UUID  `json:"item_id"`
+	ReportDate  *time.Time `json:"report_date,omitempty"`
+	CollectedAt *time.Time `json:"collected_at,omitempty"`
+	// EffectiveAt é o eixo do gráfico: a coleta ou, sem ela, a data do laudo.
+	EffectiveAt   *time.Time `json:"effective_at,omitempty"`
+	TestName      string     `json:"test_name"`
+	ParameterName string     `json:"parameter_name"`
+	// Value e a faixa de referência estão na unidade da série; ausentes quando
+	// não há valor numérico ou a unidade original não é conversível.
+	Value          *float64 `json:"value,omitempty"`
+	ReferenceLow   *float64 `json:"reference_low,omitempty"`
+	ReferenceHigh  *float64 `json:"reference_high,omitempty"`
+	Interpretation string   `json:"interpretation,omitempty"`
+	// Abnormal é true para qualquer flag diferente de normal (L, H, LL, HH, A).
+	Abnormal      bool    `json:"abnormal"`
+	OriginalValue *string `json:"original_value,omitempty"`
+	OriginalUnit  *string `json:"original_unit,omitempty"`
+	ReferenceText *string `json:"reference_text,omitempty"`
+	// Derived e Formula marcam pontos calculados, como em TestItemOutput.
+	Derived bool   `json:"derived"`
+	Formula string `json:"formula,omitempty"`
+}
+
+// CumulativeInput filtra o relatório cumulativo. Analytes vazio traz todos
+// os analitos; From e To seguem o formato da timeline.
+type CumulativeInput struct {
+	PatientID uuid.UUID
+	Analytes  []string
+	From      string
+	To        string
+}
+
+// Usado em: GET /patients/:patientID/labs/cumulative.pdf.
+type CumulativeReportOutput struct {
+	Content     []byte
+	ContentType string
+	Filename    string
+}
+
+// HistoryExportInput pede o histórico em planilha; Format é csv (padrão)
+// ou xlsx.
+type HistoryExportInput struct {
+	PatientID uuid.UUID
+	Format    string
+}
+
+// Usado em: GET /patients/:patientID/labs/jobs/:jobID.
+type ProcessingJobOutput struct {
+	ID           uuid.UUID  `json:"id"`
+	PatientID    uuid.UUID  `json:"patient_id"`
+	Status       string     `json:"status"`
+	Attempts     int        `json:"attempts"`
+	LabReportID  *uuid.UUID `json:"lab_report_id,omitempty"`
+	ErrorCode    *string    `json:"-"`
+	ErrorMessage *string    `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// Usado em: GET /patients/:patientID/labs/compare.
+type LabComparisonOutput struct {
+	PatientID uuid.UUID            `json:"patient_id"`
+	A         ComparedReportOutput `json:"a"`
+	B         ComparedReportOutput `json:"b"`
+	// Items segue a ordem de A; os que só existem em B vêm no final.
+	Items []ItemComparisonOutput `json:"items"`
+	// HighlightedCount conta os itens com highlighted true.
+	HighlightedCount int `json:"highlighted_count"`
+}
+
+type ComparedReportOutput struct {
+	ID         uuid.UUID  `json:"id"`
+	LabName    *string    `json:"lab_name,omitempty"`
+	ReportDate *time.Time `json:"report_date,omitempty"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ItemComparisonOutput struct {
+	// Key é o analyte_code ou, sem vínculo, o nome normalizado do parâmetro.
+	Key           string  `json:"key"`
+	AnalyteCode   *string `json:"analyte_code,omitempty"`
+	ParameterName string  `json:"parameter_name"`
+	// Presence: both, appeared (só em B) ou disappeared (só em A).
+	Presence string          `json:"presence"`
+	A        *TestItemOutput `json:"a,omitempty"`
+	B        *TestItemOutput `json:"b,omitempty"`
+
+	// Valores e diferenças na unidade de B (A convertido), só quando os dois
+	// resultados são números exatos em unidades conversíveis.
+	Unit          *string  `json:"unit,omitempty"`
+	ValueA        *float64 `json:"value_a,omitempty"`
+	ValueB        *float64 `json:"value_b,omitempty"`
+	AbsoluteDelta *float64 `json:"absolute_delta,omitempty"`
+	PercentDelta  *float64 `json:"percent_delta,omitempty"`
+
+	// CrossedReference indica mudança de faixa (ex.: N -> H); Highlighted
+	// junta isso com itens que apareceram ou sumiram.
+	CrossedReference bool `json:"crossed_reference"`
+	Highlighted      bool `json:"highlighted"`
+}
+
+// Usado em: GET /patients/:patientID/labs/search.
+type LabSearchHitOutput struct {
+	ReportID   uuid.UUID  `json:"report_id"`
+	LabName    *string    `json:"lab_name,omitempty"`
+	ReportDate *time.Time `json:"report_date,omitempty"`
+	Source     string     `json:"source"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Rank       float64    `json:"rank"`
+	// Snippet traz os trechos encontrados, com HTML escapado e os termos entre <mark></mark>.
+	Snippet string `json:"snippet"`
+}
+
+// Usado em: GET /labs/reviews.
+type ReviewQueueOutput struct {
+	ReportID     uuid.UUID  `json:"report_id"`
+	PatientID    uuid.UUID  `json:"patient_id"`
+	PatientName  string     `json:"patient_name"`
+	LabName      *string    `json:"lab_name,omitempty"`
+	ReportDate   *time.Time `json:"report_date,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	PendingItems int        `json:"pending_items"`
+}
+
+// Usado em: GET /labs/analytes.
+type AnalyteOutput struct {
+	Code        string   `json:"code"`
+	LOINC       *string  `json:"loinc,omitempty"`
+	DisplayName string   `json:"display_name"`
+	DefaultUnit *string  `json:"default_unit,omitempty"`
+	Synonyms    []string `json:"synonyms"`
+}
+
+// Usado em: POST /labs/analytes/remap.
+type AnalyteRemapOutput struct {
+	ParameterNames int      `json:"parameter_names"`
+	UpdatedItems   int64    `json:"updated_items"`
+	Unmatched      []string `json:"unmatched,omitempty"`
+}
+
+// LabReportHistoryOutput lista as correções de um laudo, da mais recente para a mais antiga.
+type LabReportHistoryOutput struct {
+	ReportID  uuid.UUID        `json:"report_id"`
+	Status    string           `json:"status"`
+	Version   int              `json:"version"`
+	Revisions []RevisionOutput `json:"revisions"`
+}
+
+type RevisionOutput struct {
+	Version         int                `json:"version"`
+	Status          string             `json:"status"`
+	ChangedByUserID uuid.UUID          `json:"changed_by_user_id"`
+	ChangedAt       time.Time          `json:"changed_at"`
+	Reason          *string            `json:"reason,omitempty"`
+	Changes         []labs.FieldChange `json:"changes"`
+}
+
+// CriticalRuleInput cadastra uma regra de valor crítico. Sex vazio vale para
+// todos; MinAge (inclusivo) e MaxAge (exclusivo) são em anos completos.
+type CriticalRuleInput struct {
+	AnalyteCode string   `json:"analyte_code"`
+	Sex         string   `json:"sex,omitempty"`
+	MinAge      *int     `json:"min_age,omitempty"`
+	MaxAge      *int     `json:"max_age,omitempty"`
+	Low         *float64 `json:"low,omitempty"`
+	High        *float64 `json:"high,omitempty"`
+	Unit        string   `json:"unit"`
+}
+
+// Usado em: GET /labs/critical-rules.
+type CriticalRuleOutput struct {
+	ID          uuid.UUID `json:"id"`
+	AnalyteCode string    `json:"analyte_code"`
+	Sex         *string   `json:"sex,omitempty"`
+	MinAge      *int      `json:"min_age,omitempty"`
+	MaxAge      *int      `json:"max_age,omitempty"`
+	Low         *float64  `json:"low,omitempty"`
+	High        *float64  `json:"high,omitempty"`
+	Unit        string    `json:"unit"`
+	CreatedAt   time.Time `json:"created_at"`
+	// CreatedByUserID é o administrador que cadastrou a regra; ausente nas
+	// regras padrão.
+	CreatedByUserID *uuid.UUID `json:"created_by_user_id,omitempty"`
+}
+
+// Usado em: GET /labs/alerts.
+type CriticalAlertOutput struct {
+	ID            uuid.UUID `json:"id"`
+	ReportID      uuid.UUID `json:"report_id"`
+	PatientID     uuid.UUID `json:"patient_id"`
+	ItemID        uuid.UUID `json:"item_id"`
+	AnalyteCode   string    `json:"analyte_code"`
+	ParameterName string    `json:"parameter_name"`
+	// Value e Limit estão na unidade da regra.
+	Value float64 `json:"value"`
+	Limit float64 `json:"limit"`
+	Unit  string  `json:"unit"`
+	// Interpretation é LL (criticamente baixo) ou HH (criticamente alto).
+	Interpretation string     `json:"interpretation"`
+	CreatedAt      time.Time  `json:"created_at"`
+	NotifiedAt     *time.Time `json:"notified_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres/repo"
 	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
 )
 
 func mapRepoError(op string, err error) error {
@@ -39,3 +41,44 @@ func patientNotFound() error {
 		Message: "paciente não encontrado",
 	}
 }
+
+func jobNotFound() error {
+	return &apperr.AppError{
+		Kind:    apperr.NOT_FOUND,
+		Message: "processamento não encontrado",
+	}
+}
+
+func documentNotFound() error {
+	return &apperr.AppError{
+		Kind:    apperr.NOT_FOUND,
+		Message: "laudo sem documento original",
+	}
+}
+
+func reportNotFound() error {
+	return &apperr.AppError{
+		Kind:    apperr.NOT_FOUND,
+		Message: "laudo não encontrado",
+	}
+}
+
+// DuplicateDocumentError indica que o paciente já tem um laudo com o mesmo
+// arquivo; ResourceID leva o laudo existente para o cliente.
+func DuplicateDocumentError(reportID uuid.UUID) error {
+	return &apperr.AppError{
+		Kind:       apperr.RESOURCE_ALREADY_EXISTS,
+		Message:    "este arquivo já foi enviado para o paciente",
+		ResourceID: reportID.String(),
+	}
+}
+
+// DocumentInProcessingError indica que o mesmo arquivo ainda está na fila
+// (queued/running) para o paciente; ResourceID leva o job em andamento.
+func DocumentInProcessingError(jobID uuid.UUID) error {
+	return &apperr.AppError{
+		Kind:       apperr.RESOURCE_CONFLICT,
+		Message:    "este arquivo já está em processamento para o paciente",
+		ResourceID: jobID.String(),
+	}
+}
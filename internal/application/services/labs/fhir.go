@@ -0,0 +1,18 @@
+// internal/application/services/labs/fhir.go
+package labsvc
+
+import (
+	"context"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/diagnostics"
+
+	"github.com/google/uuid"
+)
+
+// FHIRExporter expõe os laudos como recursos HL7 FHIR R5.
+type FHIRExporter interface {
+	// ExportReport devolve um Bundle collection com Patient, DiagnosticReport e Observations.
+	ExportReport(ctx context.Context, patientID, reportID uuid.UUID) (*diagnostics.Bundle, error)
+	// ExportPatient devolve um Bundle searchset no estilo Patient/$everything.
+	ExportPatient(ctx context.Context, patientID uuid.UUID) (*diagnostics.Bundle, error)
+}
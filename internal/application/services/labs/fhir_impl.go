@@ -0,0 +1,204 @@
+// internal/application/services/labs/fhir_impl.go
+package labsvc
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/diagnostics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Validade do link do documento original em presentedForm.
+	fhirDocumentURLMinutes = 60
+	// Tamanho da página ao percorrer todos os laudos do paciente.
+	fhirExportPageSize = 100
+)
+
+type fhirExporter struct {
+	patientRepo  repository.Patient
+	labsRepo     repository.Labs
+	analytesRepo repository.Analytes
+	storage      domainstorage.FileStorageService
+}
+
+var _ FHIRExporter = (*fhirExporter)(nil)
+
+func NewFHIRExporter(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	analytesRepo repository.Analytes,
+	storage domainstorage.FileStorageService,
+) FHIRExporter {
+	return &fhirExporter{
+		patientRepo:  patientRepo,
+		labsRepo:     labsRepo,
+		analytesRepo: analytesRepo,
+		storage:      storage,
+	}
+}
+
+func (s *fhirExporter) ExportReport(ctx context.Context, patientID, reportID uuid.UUID) (*diagnostics.Bundle, error) {
+	var violations []apperr.Violation
+	if patientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if reportID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "report_id", Reason: "required"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	p, err := s.findPatient(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.labsRepo.FindByID(ctx, reportID)
+	if err != nil {
+		return nil, mapRepoError("labs.find_by_id", err)
+	}
+	// Laudo de outro paciente é tratado como inexistente.
+	if report == nil || report.PatientID != p.ID {
+		return nil, reportNotFound()
+	}
+
+	catalog, err := s.loadCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fhirPatient := diagnostics.FromPatient(p)
+	bundle := diagnostics.NewBundle(diagnostics.BundleTypeCollection, time.Now())
+	bundle.Add(fhirPatient.ID, fhirPatient, "")
+	if err := s.addReport(ctx, bundle, report, fhirPatient, catalog, ""); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func (s *fhirExporter) ExportPatient(ctx context.Context, patientID uuid.UUID) (*diagnostics.Bundle, error) {
+	if patientID == uuid.Nil {
+		return nil, apperr.Validation("entrada inválida", apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+
+	p, err := s.findPatient(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := s.loadCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fhirPatient := diagnostics.FromPatient(p)
+	bundle := diagnostics.NewBundle(diagnostics.BundleTypeSearchset, time.Now())
+	bundle.Add(fhirPatient.ID, fhirPatient, "match")
+
+	for offset := 0; ; offset += fhirExportPageSize {
+		headers, err := s.labsRepo.ListLabs(ctx, p.ID, fhirExportPageSize, offset)
+		if err != nil {
+			return nil, mapRepoError("labs.list", err)
+		}
+
+		for _, header := range headers {
+			report, err := s.labsRepo.FindByID(ctx, header.ID)
+			if err != nil {
+				return nil, mapRepoError("labs.find_by_id", err)
+			}
+			if report == nil {
+				continue
+			}
+			if err := s.addReport(ctx, bundle, report, fhirPatient, catalog, "include"); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(headers) < fhirExportPageSize {
+			break
+		}
+	}
+
+	total := len(bundle.Entry)
+	bundle.Total = &total
+	return bundle, nil
+}
+
+// addReport inclui o DiagnosticReport e suas Observations no Bundle.
+func (s *fhirExporter) addReport(
+	ctx context.Context,
+	bundle *diagnostics.Bundle,
+	report *labs.LabReport,
+	fhirPatient *diagnostics.Patient,
+	catalog *labs.AnalyteCatalog,
+	searchMode string,
+) error {
+	document, err := s.documentAttachment(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	dr, observations := diagnostics.FromLabReport(report, diagnostics.PatientReference(fhirPatient), catalog, document)
+	bundle.Add(dr.ID, dr, searchMode)
+	for i := range observations {
+		bundle.Add(observations[i].ID, &observations[i], searchMode)
+	}
+	return nil
+}
+
+// documentAttachment aponta para o arquivo enviado via link assinado; nil
+// quando o laudo não veio de um upload.
+func (s *fhirExporter) documentAttachment(ctx context.Context, report *labs.LabReport) (*diagnostics.Attachment, error) {
+	if report.DocumentURI == nil || s.storage == nil {
+		return nil, nil
+	}
+
+	url, err := s.storage.GetSignedURL(ctx, *report.DocumentURI, fhirDocumentURLMinutes)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_STORAGE_ERROR,
+			Message: "falha ao gerar link do documento",
+			Cause:   err,
+		}
+	}
+
+	var contentType string
+	if report.MimeType != nil {
+		contentType = *report.MimeType
+	}
+
+	return &diagnostics.Attachment{
+		ContentType: contentType,
+		URL:         url,
+		Title:       "Laudo original",
+		Creation:    report.CreatedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (s *fhirExporter) findPatient(ctx context.Context, patientID uuid.UUID) (*patient.Patient, error) {
+	p, err := s.patientRepo.FindByID(ctx, patientID)
+	if err != nil {
+		return nil, mapRepoError("patient.find_by_id", err)
+	}
+	if p == nil {
+		return nil, patientNotFound()
+	}
+	return p, nil
+}
+
+func (s *fhirExporter) loadCatalog(ctx context.Context) (*labs.AnalyteCatalog, error) {
+	analytes, err := s.analytesRepo.List(ctx)
+	if err != nil {
+		return nil, mapRepoError("analytes.list", err)
+	}
+	return labs.NewAnalyteCatalog(analytes), nil
+}
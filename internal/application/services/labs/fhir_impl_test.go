@@ -0,0 +1,61 @@
+// internal/application/services/labs/fhir_impl_test.go
+package labsvc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/diagnostics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+func TestFHIRExporter_ExportReport(t *testing.T) {
+	p := &patient.Patient{ID: uuid.New(), CPF: "12345678909", FullName: "Maria"}
+	value := "92"
+	report := &labs.LabReport{
+		ID:        uuid.New(),
+		PatientID: p.ID,
+		TestResults: []labs.LabResult{{
+			ID:       uuid.New(),
+			TestName: "Glicose",
+			Items:    []labs.LabResultItem{{ID: uuid.New(), ParameterName: "Glicose", ResultValue: &value}},
+		}},
+	}
+	labsRepo := &fakeLabsRepo{reports: map[uuid.UUID]*labs.LabReport{report.ID: report}}
+
+	svc := NewFHIRExporter(&fakePatientRepo{findByIDRes: p}, labsRepo, &fakeAnalytesRepo{}, nil)
+
+	bundle, err := svc.ExportReport(context.Background(), p.ID, report.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bundle.Type != diagnostics.BundleTypeCollection || len(bundle.Entry) != 3 {
+		t.Fatalf("expected Patient + DiagnosticReport + Observation, got %d entries", len(bundle.Entry))
+	}
+
+	fhirPatient, ok := bundle.Entry[0].Resource.(*diagnostics.Patient)
+	if !ok || fhirPatient.Identifier[0].System != diagnostics.SystemCPF {
+		t.Fatalf("expected patient identified by CPF, got %+v", bundle.Entry[0].Resource)
+	}
+	dr, ok := bundle.Entry[1].Resource.(*diagnostics.DiagnosticReport)
+	if !ok || dr.Subject.Reference != bundle.Entry[0].FullURL {
+		t.Fatalf("subject should resolve to the patient entry, got %+v", bundle.Entry[1].Resource)
+	}
+}
+
+func TestFHIRExporter_ExportReport_OtherPatient(t *testing.T) {
+	p := &patient.Patient{ID: uuid.New()}
+	report := &labs.LabReport{ID: uuid.New(), PatientID: uuid.New()}
+	labsRepo := &fakeLabsRepo{reports: map[uuid.UUID]*labs.LabReport{report.ID: report}}
+
+	svc := NewFHIRExporter(&fakePatientRepo{findByIDRes: p}, labsRepo, &fakeAnalytesRepo{}, nil)
+
+	_, err := svc.ExportReport(context.Background(), p.ID, report.ID)
+	if !apperr.IsNotFound(err) {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+}
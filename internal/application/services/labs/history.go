@@ -0,0 +1,24 @@
+// internal/application/services/labs/history.go
+package labsvc
+
+import (
+	"context"
+	"io"
+)
+
+// HistoryExport exporta o histórico de exames do paciente em planilha,
+// uma linha por item.
+type HistoryExport interface {
+	// Export valida o pedido e prepara o arquivo; os itens só são lidos do
+	// banco ao gravar Content.
+	Export(ctx context.Context, input HistoryExportInput) (*HistoryExportOutput, error)
+}
+
+// Usado em: GET /patients/:patientID/labs/export.
+type HistoryExportOutput struct {
+	ContentType string
+	Filename    string
+	// Content grava o arquivo conforme lê os itens, com o contexto de
+	// Export. Um erro no meio deixa o arquivo incompleto.
+	Content io.WriterTo
+}
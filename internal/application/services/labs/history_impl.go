@@ -0,0 +1,192 @@
+// internal/application/services/labs/history_impl.go
+package labsvc
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Itens lidos do banco por vez durante a exportação.
+	historyPageSize = 1000
+	// Formato usado quando o pedido não informa um.
+	defaultHistoryFormat = "csv"
+	historySheetName     = "Exames"
+)
+
+// historyColumns é o cabeçalho da planilha, na ordem de historyCells.
+var historyColumns = []string{
+	"report_id", "report_date", "lab_name", "source", "status",
+	"result_id", "test_name", "material", "method", "collected_at",
+	"item_id", "parameter_name", "analyte_code",
+	"result_value", "result_unit", "comparator", "numeric_value", "ucum_unit", "qualitative_value",
+	"reference_text", "reference_low", "reference_high", "interpretation",
+	"normalized_value", "normalized_unit",
+	"derived", "formula",
+}
+
+type historyExport struct {
+	patientRepo repository.Patient
+	labsRepo    repository.Labs
+	formats     map[string]document.TableFormat
+}
+
+var _ HistoryExport = (*historyExport)(nil)
+
+// NewHistoryExport recebe os formatos aceitos pela extensão (csv, xlsx).
+func NewHistoryExport(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	formats ...document.TableFormat,
+) HistoryExport {
+	byExt := make(map[string]document.TableFormat, len(formats))
+	for _, f := range formats {
+		byExt[f.Extension()] = f
+	}
+	return &historyExport{
+		patientRepo: patientRepo,
+		labsRepo:    labsRepo,
+		formats:     byExt,
+	}
+}
+
+func (s *historyExport) Export(ctx context.Context, input HistoryExportInput) (*HistoryExportOutput, error) {
+	name := strings.ToLower(strings.TrimSpace(input.Format))
+	if name == "" {
+		name = defaultHistoryFormat
+	}
+	format, ok := s.formats[name]
+
+	var violations []apperr.Violation
+	if input.PatientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if !ok {
+		violations = append(violations, apperr.Violation{Field: "format", Reason: "invalid"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	p, err := s.patientRepo.FindByID(ctx, input.PatientID)
+	if err != nil {
+		return nil, mapRepoError("patient.find_by_id", err)
+	}
+	if p == nil {
+		return nil, patientNotFound()
+	}
+
+	return &HistoryExportOutput{
+		ContentType: format.ContentType(),
+		Filename:    "exames-" + time.Now().UTC().Format("2006-01-02") + "." + format.Extension(),
+		Content:     &historyStream{ctx: ctx, export: s, format: format, patientID: p.ID},
+	}, nil
+}
+
+// historyStream adia a leitura dos itens até o arquivo ser gravado.
+type historyStream struct {
+	ctx       context.Context
+	export    *historyExport
+	format    document.TableFormat
+	patientID uuid.UUID
+}
+
+func (h *historyStream) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := h.export.stream(h.ctx, cw, h.format, h.patientID)
+	return cw.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (s *historyExport) stream(ctx context.Context, w io.Writer, format document.TableFormat, patientID uuid.UUID) error {
+	tw, err := format.NewTableWriter(w, historySheetName)
+	if err != nil {
+		return apperr.Internal("falha ao gerar a planilha", err)
+	}
+	if err := tw.WriteHeader(historyColumns); err != nil {
+		return apperr.Internal("falha ao gerar a planilha", err)
+	}
+
+	for offset := 0; ; offset += historyPageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rows, err := s.labsRepo.ListHistory(ctx, patientID, historyPageSize, offset)
+		if err != nil {
+			return mapRepoError("labs.list_history", err)
+		}
+		for _, row := range rows {
+			if err := tw.WriteRow(historyCells(row)); err != nil {
+				return apperr.Internal("falha ao gerar a planilha", err)
+			}
+		}
+		if len(rows) < historyPageSize {
+			break
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return apperr.Internal("falha ao gerar a planilha", err)
+	}
+	return nil
+}
+
+// historyCells monta a linha na ordem de historyColumns.
+func historyCells(row labs.HistoryRow) []document.Cell {
+	it := row.Item
+	var normalizedValue, normalizedUnit document.Cell
+	if v, unit, ok := row.Normalized(); ok {
+		normalizedValue, normalizedUnit = v, unit
+	}
+	return []document.Cell{
+		row.ReportID.String(), timeCell(row.ReportDate), textCell(row.LabName), string(row.Source), string(row.Status),
+		row.ResultID.String(), row.TestName, textCell(row.Material), textCell(row.Method), timeCell(row.CollectedAt),
+		it.ID.String(), it.ParameterName, textCell(it.AnalyteCode),
+		textCell(it.ResultValue), textCell(it.ResultUnit), string(it.Comparator), numberCell(it.NumericValue),
+		textCell(it.UCUMUnit), textCell(it.QualitativeValue),
+		textCell(it.ReferenceText), numberCell(it.ReferenceLow), numberCell(it.ReferenceHigh), string(it.Interpretation),
+		normalizedValue, normalizedUnit,
+		strconv.FormatBool(it.Derived), string(it.Formula),
+	}
+}
+
+func textCell(s *string) document.Cell {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func numberCell(v *float64) document.Cell {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func timeCell(t *time.Time) document.Cell {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
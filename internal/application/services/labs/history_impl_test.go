@@ -0,0 +1,122 @@
+// internal/application/services/labs/history_impl_test.go
+package labsvc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+type fakeTableFormat struct {
+	ext    string
+	header []string
+	rows   [][]document.Cell
+	closed bool
+}
+
+func (f *fakeTableFormat) ContentType() string { return "text/" + f.ext }
+func (f *fakeTableFormat) Extension() string   { return f.ext }
+func (f *fakeTableFormat) NewTableWriter(w io.Writer, sheet string) (document.TableWriter, error) {
+	return f, nil
+}
+func (f *fakeTableFormat) WriteHeader(columns []string) error {
+	f.header = columns
+	return nil
+}
+func (f *fakeTableFormat) WriteRow(cells []document.Cell) error {
+	f.rows = append(f.rows, cells)
+	return nil
+}
+func (f *fakeTableFormat) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestHistoryExport_Stream(t *testing.T) {
+	p := &patient.Patient{ID: uuid.New()}
+	glucose, mgdl, mmol := "glucose", "mg/dL", "mmol/L"
+	value := 5.5
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	// Uma página cheia e mais uma linha: o export precisa pedir a segunda página.
+	history := make([]labs.HistoryRow, historyPageSize+1)
+	for i := range history {
+		history[i] = labs.HistoryRow{ReportID: uuid.New(), ResultID: uuid.New(), Item: labs.LabResultItem{ID: uuid.New(), ParameterName: "Sódio"}}
+	}
+	history[0] = labs.HistoryRow{
+		ReportID: uuid.New(), Source: labs.SourceDocument, ResultID: uuid.New(), TestName: "Glicemia", CollectedAt: &at,
+		Item: labs.LabResultItem{
+			ID: uuid.New(), ParameterName: "Glicose", AnalyteCode: &glucose,
+			NumericValue: &value, UCUMUnit: &mmol, Interpretation: labs.InterpretationNormal,
+		},
+		CanonicalUnit: &mgdl,
+	}
+
+	csv, xlsx := &fakeTableFormat{ext: "csv"}, &fakeTableFormat{ext: "xlsx"}
+	svc := NewHistoryExport(&fakePatientRepo{findByIDRes: p}, &fakeLabsRepo{historyRes: history}, csv, xlsx)
+
+	out, err := svc.Export(context.Background(), HistoryExportInput{PatientID: p.ID, Format: "XLSX"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.ContentType != "text/xlsx" || out.Filename[len(out.Filename)-5:] != ".xlsx" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if xlsx.header != nil {
+		t.Fatalf("expected nothing written before Content")
+	}
+
+	if _, err := out.Content.WriteTo(&bytes.Buffer{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(xlsx.header) != len(historyColumns) || len(xlsx.rows) != len(history) || !xlsx.closed {
+		t.Fatalf("expected header and %d rows, got %d rows (closed %v)", len(history), len(xlsx.rows), xlsx.closed)
+	}
+
+	row := xlsx.rows[0]
+	if len(row) != len(historyColumns) {
+		t.Fatalf("expected %d cells, got %d", len(historyColumns), len(row))
+	}
+	if row[9] != at || row[11] != "Glicose" || row[16] != 5.5 || row[22] != "N" {
+		t.Fatalf("unexpected cells: %v", row)
+	}
+	if v, ok := row[23].(float64); !ok || v < 99 || v > 99.2 || row[24] != "mg/dL" {
+		t.Fatalf("expected normalized value in mg/dL, got %v %v", row[23], row[24])
+	}
+	if row := xlsx.rows[1]; row[16] != nil || row[23] != nil {
+		t.Fatalf("expected empty numeric cells, got %v", row)
+	}
+}
+
+func TestHistoryExport_DefaultAndInvalidFormat(t *testing.T) {
+	p := &patient.Patient{ID: uuid.New()}
+	svc := NewHistoryExport(&fakePatientRepo{findByIDRes: p}, &fakeLabsRepo{}, &fakeTableFormat{ext: "csv"})
+
+	out, err := svc.Export(context.Background(), HistoryExportInput{PatientID: p.ID})
+	if err != nil || out.ContentType != "text/csv" {
+		t.Fatalf("expected csv by default, got %+v %v", out, err)
+	}
+
+	_, err = svc.Export(context.Background(), HistoryExportInput{PatientID: p.ID, Format: "pdf"})
+	if !apperr.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestHistoryExport_PatientNotFound(t *testing.T) {
+	svc := NewHistoryExport(&fakePatientRepo{}, &fakeLabsRepo{}, &fakeTableFormat{ext: "csv"})
+
+	_, err := svc.Export(context.Background(), HistoryExportInput{PatientID: uuid.New(), Format: "csv"})
+	if !apperr.IsNotFound(err) {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+}
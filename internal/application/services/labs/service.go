@@ -9,4 +9,29 @@ import (
 type Service interface {
 	List(ctx context.Context, patientID uuid.UUID, limit, offset int) ([]LabReportSummaryOutput, error)
 	ListFull(ctx context.Context, patientID uuid.UUID, limit, offset int) ([]*LabReportOutput, error)
+	Get(ctx context.Context, patientID, reportID uuid.UUID) (*LabReportOutput, error)
+	// Delete remove o laudo e o arquivo original do storage.
+	Delete(ctx context.Context, patientID, reportID uuid.UUID) error
+	// History lista as correções do laudo com o valor anterior de cada campo.
+	History(ctx context.Context, patientID, reportID uuid.UUID) (*LabReportHistoryOutput, error)
+	// DocumentURL gera um link temporário para o arquivo original do laudo.
+	DocumentURL(ctx context.Context, patientID, reportID uuid.UUID) (*DocumentURLOutput, error)
+	// CheckDuplicateDocument recusa um arquivo (SHA-256) já enviado para o
+	// paciente antes de gastar com upload e extração.
+	CheckDuplicateDocument(ctx context.Context, patientID uuid.UUID, contentHash string) error
+	// CheckDocumentInProcessing recusa um arquivo que ainda tem job
+	// queued/running para o paciente, também antes do upload.
+	CheckDocumentInProcessing(ctx context.Context, patientID uuid.UUID, contentHash string) error
+	// ListPendingReviews lista os laudos aguardando revisão nos pacientes que
+	// o usuário acessa, dos mais antigos para os mais novos.
+	ListPendingReviews(ctx context.Context, userID uuid.UUID, limit, offset int) ([]ReviewQueueOutput, error)
+	GetJob(ctx context.Context, patientID, jobID uuid.UUID) (*ProcessingJobOutput, error)
+	// Compare alinha os itens de dois laudos do paciente por analito, com as
+	// diferenças em B em relação a A.
+	Compare(ctx context.Context, patientID, reportA, reportB uuid.UUID) (*LabComparisonOutput, error)
+	// Search busca no texto bruto dos laudos do paciente, do mais relevante
+	// para o menos relevante, com trechos destacados.
+	Search(ctx context.Context, patientID uuid.UUID, query string, limit, offset int) ([]LabSearchHitOutput, error)
+	// Timeline retorna uma série por analito, cada uma convertida para uma única unidade.
+	Timeline(ctx context.Context, input TimelineInput) (*PatientTimelineOutput, error)
 }
@@ -3,17 +3,37 @@ package labsvc
 
 import (
 	"context"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
 	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
 	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+	applog "github.com/gabrielgcmr/sonnda/internal/kernel/observability"
 
 	"github.com/google/uuid"
 )
 
+// Validade do link de download do documento original.
+const documentURLMinutes = 5
+
+// Limites da timeline: analitos por chamada e pontos por série.
+const (
+	maxTimelineAnalytes = 10
+	maxTimelinePoints   = 500
+)
+
+// Tamanho máximo (em caracteres) do termo da busca textual.
+const maxSearchQueryLength = 200
+
 type service struct {
 	patientRepo repository.Patient
 	labsRepo    repository.Labs
+	jobsRepo    repository.LabJobs
+	storage     domainstorage.FileStorageService
 }
 
 var _ Service = (*service)(nil)
@@ -21,10 +41,14 @@ var _ Service = (*service)(nil)
 func New(
 	patientRepo repository.Patient,
 	labsRepo repository.Labs,
+	jobsRepo repository.LabJobs,
+	storage domainstorage.FileStorageService,
 ) Service {
 	return &service{
 		patientRepo: patientRepo,
 		labsRepo:    labsRepo,
+		jobsRepo:    jobsRepo,
+		storage:     storage,
 	}
 }
 
@@ -115,14 +139,483 @@ func (s *service) ListFull(ctx context.Context, patientID uuid.UUID, limit, offs
 			continue
 		}
 
-		dto := mapDomainReportToOutput(fullReport)
+		dto := ToLabReportOutput(fullReport)
 		out = append(out, dto)
 	}
 
 	return out, nil
 }
 
-func mapDomainReportToOutput(report *labs.LabReport) *LabReportOutput {
+func (s *service) Get(ctx context.Context, patientID, reportID uuid.UUID) (*LabReportOutput, error) {
+	report, err := s.findReport(ctx, patientID, reportID)
+	if err != nil {
+		return nil, err
+	}
+	return ToLabReportOutput(report), nil
+}
+
+func (s *service) Delete(ctx context.Context, patientID, reportID uuid.UUID) error {
+	report, err := s.findReport(ctx, patientID, reportID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.labsRepo.Delete(ctx, report.ID); err != nil {
+		return mapRepoError("labs.delete", err)
+	}
+
+	// O laudo já foi removido; falha no storage só deixa um arquivo órfão,
+	// então não devolvemos erro ao cliente.
+	if report.DocumentURI != nil && s.storage != nil {
+		if err := s.storage.Delete(ctx, *report.DocumentURI); err != nil {
+			applog.FromContext(ctx).Warn("labs: falha ao remover documento do laudo",
+				slog.String("report_id", report.ID.String()),
+				slog.Any("error", err),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) DocumentURL(ctx context.Context, patientID, reportID uuid.UUID) (*DocumentURLOutput, error) {
+	report, err := s.findReport(ctx, patientID, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.DocumentURI == nil || s.storage == nil {
+		return nil, documentNotFound()
+	}
+
+	expiresAt := time.Now().UTC().Add(documentURLMinutes * time.Minute)
+	url, err := s.storage.GetSignedURL(ctx, *report.DocumentURI, documentURLMinutes)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_STORAGE_ERROR,
+			Message: "falha ao gerar link do documento",
+			Cause:   err,
+		}
+	}
+
+	return &DocumentURLOutput{
+		URL:       url,
+		MimeType:  report.MimeType,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// History devolve as revisões do laudo, da mais recente para a mais antiga.
+func (s *service) History(ctx context.Context, patientID, reportID uuid.UUID) (*LabReportHistoryOutput, error) {
+	report, err := s.findReport(ctx, patientID, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.labsRepo.ListRevisions(ctx, report.ID)
+	if err != nil {
+		return nil, mapRepoError("labs.list_revisions", err)
+	}
+
+	output := &LabReportHistoryOutput{
+		ReportID:  report.ID,
+		Status:    string(report.Status),
+		Version:   report.Version,
+		Revisions: make([]RevisionOutput, 0, len(revisions)),
+	}
+	for _, rev := range revisions {
+		output.Revisions = append(output.Revisions, RevisionOutput{
+			Version:         rev.Version,
+			Status:          string(rev.Status),
+			ChangedByUserID: rev.ChangedBy,
+			ChangedAt:       rev.ChangedAt,
+			Reason:          rev.Reason,
+			Changes:         rev.Changes,
+		})
+	}
+	return output, nil
+}
+
+func (s *service) CheckDuplicateDocument(ctx context.Context, patientID uuid.UUID, contentHash string) error {
+	if patientID == uuid.Nil || contentHash == "" {
+		return nil
+	}
+	reportID, err := s.labsRepo.FindIDByContentHash(ctx, patientID, contentHash)
+	if err != nil {
+		return mapRepoError("labs.find_by_content_hash", err)
+	}
+	if reportID != nil {
+		return DuplicateDocumentError(*reportID)
+	}
+	return nil
+}
+
+func (s *service) CheckDocumentInProcessing(ctx context.Context, patientID uuid.UUID, contentHash string) error {
+	if patientID == uuid.Nil || contentHash == "" {
+		return nil
+	}
+	jobID, err := s.jobsRepo.FindActiveIDByContentHash(ctx, patientID, contentHash)
+	if err != nil {
+		return mapRepoError("lab_jobs.find_active_by_content_hash", err)
+	}
+	if jobID != nil {
+		return DocumentInProcessingError(*jobID)
+	}
+	return nil
+}
+
+func (s *service) ListPendingReviews(ctx context.Context, userID uuid.UUID, limit, offset int) ([]ReviewQueueOutput, error) {
+	if userID == uuid.Nil {
+		return nil, apperr.Validation("entrada inválida", apperr.Violation{Field: "user_id", Reason: "required"})
+	}
+
+	entries, err := s.labsRepo.ListPendingReviews(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, mapRepoError("labs.list_pending_reviews", err)
+	}
+
+	out := make([]ReviewQueueOutput, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, ReviewQueueOutput{
+			ReportID:     e.ReportID,
+			PatientID:    e.PatientID,
+			PatientName:  e.PatientName,
+			LabName:      e.LabName,
+			ReportDate:   e.ReportDate,
+			CreatedAt:    e.CreatedAt,
+			PendingItems: e.PendingItems,
+		})
+	}
+	return out, nil
+}
+
+func (s *service) Search(ctx context.Context, patientID uuid.UUID, query string, limit, offset int) ([]LabSearchHitOutput, error) {
+	query = strings.Join(strings.Fields(query), " ")
+
+	var violations []apperr.Violation
+	if patientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	switch {
+	case query == "":
+		violations = append(violations, apperr.Violation{Field: "q", Reason: "required"})
+	case utf8.RuneCountInString(query) > maxSearchQueryLength:
+		violations = append(violations, apperr.Violation{Field: "q", Reason: "too_long"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	p, err := s.patientRepo.FindByID(ctx, patientID)
+	if err != nil {
+		return nil, mapRepoError("patient.find_by_id", err)
+	}
+	if p == nil {
+		return nil, patientNotFound()
+	}
+
+	hits, err := s.labsRepo.SearchRawText(ctx, p.ID, query, limit, offset)
+	if err != nil {
+		return nil, mapRepoError("labs.search_raw_text", err)
+	}
+
+	out := make([]LabSearchHitOutput, 0, len(hits))
+	for _, h := range hits {
+		out = append(out, LabSearchHitOutput{
+			ReportID:   h.ReportID,
+			LabName:    h.LabName,
+			ReportDate: h.ReportDate,
+			Source:     string(h.Source),
+			Status:     string(h.Status),
+			CreatedAt:  h.CreatedAt,
+			Rank:       h.Rank,
+			Snippet:    h.Snippet,
+		})
+	}
+	return out, nil
+}
+
+func (s *service) Compare(ctx context.Context, patientID, reportA, reportB uuid.UUID) (*LabComparisonOutput, error) {
+	if reportA != uuid.Nil && reportA == reportB {
+		return nil, apperr.Validation("entrada inválida", apperr.Violation{Field: "b", Reason: "must_differ_from_a"})
+	}
+
+	a, err := s.findReport(ctx, patientID, reportA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.findReport(ctx, patientID, reportB)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := labs.CompareReports(a, b)
+	out := &LabComparisonOutput{
+		PatientID: patientID,
+		A:         toComparedReportOutput(a),
+		B:         toComparedReportOutput(b),
+		Items:     make([]ItemComparisonOutput, 0, len(rows)),
+	}
+	for _, row := range rows {
+		item := ItemComparisonOutput{
+			Key:              row.Key,
+			AnalyteCode:      row.AnalyteCode,
+			ParameterName:    row.ParameterName,
+			Presence:         string(row.Presence),
+			Unit:             row.Unit,
+			ValueA:           row.ValueA,
+			ValueB:           row.ValueB,
+			AbsoluteDelta:    row.AbsoluteDelta,
+			PercentDelta:     row.PercentDelta,
+			CrossedReference: row.CrossedReference,
+			Highlighted:      row.Highlighted(),
+		}
+		if row.A != nil {
+			itemA := ToTestItemOutput(row.A)
+			item.A = &itemA
+		}
+		if row.B != nil {
+			itemB := ToTestItemOutput(row.B)
+			item.B = &itemB
+		}
+		if item.Highlighted {
+			out.HighlightedCount++
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+func toComparedReportOutput(r *labs.LabReport) ComparedReportOutput {
+	return ComparedReportOutput{
+		ID:         r.ID,
+		LabName:    r.LabName,
+		ReportDate: r.ReportDate,
+		Status:     string(r.Status),
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// findReport carrega o laudo garantindo que ele pertence ao paciente.
+func (s *service) findReport(ctx context.Context, patientID, reportID uuid.UUID) (*labs.LabReport, error) {
+	var violations []apperr.Violation
+	if patientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if reportID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "report_id", Reason: "required"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	report, err := s.labsRepo.FindByID(ctx, reportID)
+	if err != nil {
+		return nil, mapRepoError("labs.find_by_id", err)
+	}
+	// Laudo de outro paciente é tratado como inexistente.
+	if report == nil || report.PatientID != patientID {
+		return nil, reportNotFound()
+	}
+	return report, nil
+}
+
+func (s *service) GetJob(ctx context.Context, patientID, jobID uuid.UUID) (*ProcessingJobOutput, error) {
+	var violations []apperr.Violation
+	if patientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if jobID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "job_id", Reason: "required"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	job, err := s.jobsRepo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, mapRepoError("labs.jobs.find_by_id", err)
+	}
+	// Job de outro paciente é tratado como inexistente.
+	if job == nil || job.PatientID != patientID {
+		return nil, jobNotFound()
+	}
+
+	return ToProcessingJobOutput(job), nil
+}
+
+func (s *service) Timeline(ctx context.Context, input TimelineInput) (*PatientTimelineOutput, error) {
+	analytes := uniqueAnalytes(input.Analytes)
+
+	var violations []apperr.Violation
+	if input.PatientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	switch {
+	case len(analytes) == 0:
+		violations = append(violations, apperr.Violation{Field: "analyte", Reason: "required"})
+	case len(analytes) > maxTimelineAnalytes:
+		violations = append(violations, apperr.Violation{Field: "analyte", Reason: "invalid"})
+	}
+	from, ok := parseTimelineBound(input.From, false)
+	if !ok {
+		violations = append(violations, apperr.Violation{Field: "from", Reason: "invalid_date"})
+	}
+	to, ok := parseTimelineBound(input.To, true)
+	if !ok {
+		violations = append(violations, apperr.Violation{Field: "to", Reason: "invalid_date"})
+	}
+	if from != nil && to != nil && from.After(*to) {
+		violations = append(violations, apperr.Violation{Field: "to", Reason: "invalid"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	limit := input.Limit
+	if limit <= 0 || limit > maxTimelinePoints {
+		limit = maxTimelinePoints
+	}
+
+	p, err := s.patientRepo.FindByID(ctx, input.PatientID)
+	if err != nil {
+		return nil, mapRepoError("patient.find_by_id", err)
+	}
+	if p == nil {
+		return nil, patientNotFound()
+	}
+
+	out := &PatientTimelineOutput{
+		PatientID: p.ID,
+		From:      from,
+		To:        to,
+		Series:    make([]TimelineOutput, 0, len(analytes)),
+	}
+	for _, analyte := range analytes {
+		items, err := s.labsRepo.ListItemsByPatientAndParameter(ctx, p.ID, analyte, from, to, limit, 0)
+		if err != nil {
+			return nil, mapRepoError("labs.list_timeline", err)
+		}
+		out.Series = append(out.Series, *buildTimeline(analyte, items))
+	}
+
+	return out, nil
+}
+
+// uniqueAnalytes descarta vazios e repetidos (mesmo nome normalizado),
+// mantendo a ordem pedida.
+func uniqueAnalytes(raw []string) []string {
+	seen := make(map[string]bool, len(raw))
+	out := make([]string, 0, len(raw))
+	for _, name := range raw {
+		name = strings.TrimSpace(name)
+		key := labs.NormalizeAnalyteName(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, name)
+	}
+	return out
+}
+
+// parseTimelineBound aceita AAAA-MM-DD ou RFC 3339. Uma data sem hora no fim
+// do período inclui o dia inteiro.
+func parseTimelineBound(raw string, endOfDay bool) (*time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, true
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		t = t.UTC()
+		return &t, true
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, false
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, true
+}
+
+// buildTimeline converte os pontos para a unidade comum da série, mantendo
+// o valor e a unidade originais.
+func buildTimeline(parameter string, items []labs.LabResultItemTimeline) *TimelineOutput {
+	out := &TimelineOutput{
+		Parameter: parameter,
+		Points:    make([]TimelinePointOutput, 0, len(items)),
+	}
+
+	for _, it := range items {
+		if out.AnalyteCode == nil && it.AnalyteCode != nil {
+			out.AnalyteCode = it.AnalyteCode
+		}
+		if out.Unit == nil && it.CanonicalUnit != nil {
+			out.Unit = it.CanonicalUnit
+		}
+	}
+	// Itens vêm do mais recente para o mais antigo.
+	if out.Unit == nil {
+		for _, it := range items {
+			if it.NumericValue != nil && it.UCUMUnit != nil {
+				out.Unit = it.UCUMUnit
+				break
+			}
+		}
+	}
+
+	for _, it := range items {
+		point := TimelinePointOutput{
+			ReportID:       it.ReportID,
+			ItemID:         it.ItemID,
+			ReportDate:     it.ReportDate,
+			CollectedAt:    it.CollectedAt,
+			EffectiveAt:    it.EffectiveAt(),
+			TestName:       it.TestName,
+			ParameterName:  it.ParameterName,
+			Interpretation: string(it.Interpretation),
+			Abnormal:       it.Interpretation.IsAbnormal(),
+			OriginalValue:  it.ResultValue,
+			OriginalUnit:   it.ResultUnit,
+			ReferenceText:  it.ReferenceText,
+			Derived:        it.Derived,
+			Formula:        string(it.Formula),
+		}
+		if out.Unit != nil {
+			if v, ok := it.ValueIn(*out.Unit); ok {
+				point.Value = &v
+			}
+			point.ReferenceLow, point.ReferenceHigh = it.ReferenceIn(*out.Unit)
+		}
+		out.Points = append(out.Points, point)
+	}
+
+	return out
+}
+
+// ToProcessingJobOutput converte o job de domínio no DTO de saída.
+func ToProcessingJobOutput(job *labs.ProcessingJob) *ProcessingJobOutput {
+	if job == nil {
+		return nil
+	}
+	return &ProcessingJobOutput{
+		ID:           job.ID,
+		PatientID:    job.PatientID,
+		Status:       string(job.Status),
+		Attempts:     job.Attempts,
+		LabReportID:  job.LabReportID,
+		ErrorCode:    job.ErrorCode,
+		ErrorMessage: job.ErrorMessage,
+		CreatedAt:    job.CreatedAt,
+		UpdatedAt:    job.UpdatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+}
+
+// ToLabReportOutput converte o laudo de domínio no DTO de saída.
+func ToLabReportOutput(report *labs.LabReport) *LabReportOutput {
 	output := &LabReportOutput{
 		ID:                report.ID,
 		PatientID:         report.PatientID,
@@ -136,9 +629,25 @@ func mapDomainReportToOutput(report *labs.LabReport) *LabReportOutput {
 		ReportDate:        report.ReportDate,
 		UploadedByUserID:  report.UploadedBy,
 		Fingerprint:       report.Fingerprint,
+		Source:            string(report.Source),
+		Status:            string(report.Status),
+		Version:           report.Version,
+		HasDocument:       report.DocumentURI != nil,
+		IdentityScore:     report.IdentityScore,
+		ReviewedByUserID:  report.ReviewedBy,
+		ReviewedAt:        report.ReviewedAt,
+		FieldConfidence:   report.FieldConfidence,
 		CreatedAt:         report.CreatedAt,
 		UpdatedAt:         report.UpdatedAt,
 	}
+	if report.IdentityStatus != nil {
+		status := string(*report.IdentityStatus)
+		output.IdentityStatus = &status
+	}
+	if report.ReviewStatus != nil {
+		status := string(*report.ReviewStatus)
+		output.ReviewStatus = &status
+	}
 
 	for _, tr := range report.TestResults {
 		testOutput := TestResultOutput{
@@ -150,14 +659,8 @@ func mapDomainReportToOutput(report *labs.LabReport) *LabReportOutput {
 			ReleaseAt:   tr.ReleaseAt,
 		}
 
-		for _, item := range tr.Items {
-			testOutput.Items = append(testOutput.Items, TestItemOutput{
-				ID:            item.ID,
-				ParameterName: item.ParameterName,
-				ResultValue:   item.ResultValue,
-				ResultUnit:    item.ResultUnit,
-				ReferenceText: item.ReferenceText,
-			})
+		for i := range tr.Items {
+			testOutput.Items = append(testOutput.Items, ToTestItemOutput(&tr.Items[i]))
 		}
 
 		output.TestResults = append(output.TestResults, testOutput)
@@ -165,3 +668,31 @@ func mapDomainReportToOutput(report *labs.LabReport) *LabReportOutput {
 
 	return output
 }
+
+// ToTestItemOutput converte o item de domínio no DTO de saída.
+func ToTestItemOutput(item *labs.LabResultItem) TestItemOutput {
+	return TestItemOutput{
+		ID:            item.ID,
+		ParameterName: item.ParameterName,
+		ResultValue:   item.ResultValue,
+		ResultUnit:    item.ResultUnit,
+		ReferenceText: item.ReferenceText,
+		AnalyteCode:   item.AnalyteCode,
+
+		NumericValue:     item.NumericValue,
+		UCUMUnit:         item.UCUMUnit,
+		Comparator:       string(item.Comparator),
+		QualitativeValue: item.QualitativeValue,
+		ReferenceLow:     item.ReferenceLow,
+		ReferenceHigh:    item.ReferenceHigh,
+		Interpretation:   string(item.Interpretation),
+
+		Confidence:      item.Confidence,
+		FieldConfidence: item.FieldConfidence,
+		Confirmed:       item.Confirmed,
+		NeedsReview:     item.NeedsReview(),
+
+		Derived: item.Derived,
+		Formula: string(item.Formula),
+	}
+}
@@ -4,7 +4,11 @@ package labsvc
 import (
 	"context"
 	"errors"
+	"io"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
@@ -34,6 +38,9 @@ func (r *fakePatientRepo) HardDelete(ctx context.Context, id uuid.UUID) error
 func (r *fakePatientRepo) FindByCPF(ctx context.Context, cpf string) (*patient.Patient, error) {
 	panic("unused")
 }
+func (r *fakePatientRepo) FindByCNS(ctx context.Context, cns string) (*patient.Patient, error) {
+	panic("unused")
+}
 func (r *fakePatientRepo) FindByID(ctx context.Context, id uuid.UUID) (*patient.Patient, error) {
 	return r.findByIDRes, r.findByIDErr
 }
@@ -50,16 +57,61 @@ func (r *fakePatientRepo) SearchByName(ctx context.Context, name string, limit,
 type fakeLabsRepo struct {
 	listRes []labs.LabReport
 	listErr error
+
+	reports map[uuid.UUID]*labs.LabReport
+
+	timelineRes []labs.LabResultItemTimeline
+
+	itemsRes  []labs.LabResultItemTimeline
+	itemsFrom *time.Time
+
+	historyRes []labs.HistoryRow
+
+	parameterNames []string
+	// analyteByName registra os vínculos gravados por Relink.
+	analyteByName map[string]*string
+
+	deleted []uuid.UUID
+
+	revisions map[uuid.UUID][]labs.Revision
+
+	byContentHash map[string]*uuid.UUID
+
+	searchRes   []labs.SearchHit
+	searchQuery string
 }
 
 func (r *fakeLabsRepo) Create(ctx context.Context, report *labs.LabReport) error { panic("unused") }
 func (r *fakeLabsRepo) ExistsBySignature(ctx context.Context, patientID uuid.UUID, fingerprint string) (bool, error) {
 	panic("unused")
 }
-func (r *fakeLabsRepo) Delete(ctx context.Context, id uuid.UUID) error { panic("unused") }
+func (r *fakeLabsRepo) FindIDByContentHash(ctx context.Context, patientID uuid.UUID, contentHash string) (*uuid.UUID, error) {
+	return r.byContentHash[contentHash], nil
+}
+func (r *fakeLabsRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
 func (r *fakeLabsRepo) FindByID(ctx context.Context, reportID uuid.UUID) (*labs.LabReport, error) {
+	return r.reports[reportID], nil
+}
+func (r *fakeLabsRepo) ReplaceDerived(ctx context.Context, reportID uuid.UUID, result *labs.LabResult) error {
+	panic("unused")
+}
+
+func (r *fakeLabsRepo) Amend(ctx context.Context, report *labs.LabReport, revision *labs.Revision) error {
 	panic("unused")
 }
+func (r *fakeLabsRepo) ListRevisions(ctx context.Context, reportID uuid.UUID) ([]labs.Revision, error) {
+	return r.revisions[reportID], nil
+}
+func (r *fakeLabsRepo) ListPendingReviews(ctx context.Context, userID uuid.UUID, limit, offset int) ([]labs.ReviewQueueEntry, error) {
+	panic("unused")
+}
+func (r *fakeLabsRepo) SearchRawText(ctx context.Context, patientID uuid.UUID, query string, limit, offset int) ([]labs.SearchHit, error) {
+	r.searchQuery = query
+	return r.searchRes, nil
+}
 func (r *fakeLabsRepo) ListLabs(ctx context.Context, patientID uuid.UUID, limit, offset int) ([]labs.LabReport, error) {
 	return r.listRes, r.listErr
 }
@@ -67,13 +119,93 @@ func (r *fakeLabsRepo) ListItemsByPatientAndParameter(
 	ctx context.Context,
 	patientID uuid.UUID,
 	parameterName string,
+	from, to *time.Time,
 	limit, offset int,
 ) ([]labs.LabResultItemTimeline, error) {
+	return r.timelineRes, nil
+}
+func (r *fakeLabsRepo) ListItemsByPatient(
+	ctx context.Context,
+	patientID uuid.UUID,
+	from, to *time.Time,
+	limit, offset int,
+) ([]labs.LabResultItemTimeline, error) {
+	r.itemsFrom = from
+	return r.itemsRes, nil
+}
+func (r *fakeLabsRepo) ListHistory(ctx context.Context, patientID uuid.UUID, limit, offset int) ([]labs.HistoryRow, error) {
+	if offset >= len(r.historyRes) {
+		return nil, nil
+	}
+	return r.historyRes[offset:min(offset+limit, len(r.historyRes))], nil
+}
+func (r *fakeLabsRepo) ListDistinctParameterNames(ctx context.Context) ([]string, error) {
+	return r.parameterNames, nil
+}
+func (r *fakeLabsRepo) ListParameterNamesByKey(ctx context.Context, nameKey string) ([]string, error) {
+	var names []string
+	for _, name := range r.parameterNames {
+		if slices.Contains(labs.MatchKeys(name), nameKey) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+func (r *fakeLabsRepo) Relink(ctx context.Context, relinks []labs.AnalyteRelink) (int64, error) {
+	if r.analyteByName == nil {
+		r.analyteByName = make(map[string]*string)
+	}
+	for _, relink := range relinks {
+		r.analyteByName[relink.ParameterName] = relink.AnalyteCode
+	}
+	return int64(len(relinks)), nil
+}
+
+type fakeJobsRepo struct {
+	findByIDRes *labs.ProcessingJob
+	findByIDErr error
+
+	activeByContentHash map[string]*uuid.UUID
+}
+
+func (r *fakeJobsRepo) Create(ctx context.Context, job *labs.ProcessingJob) error { panic("unused") }
+func (r *fakeJobsRepo) FindByID(ctx context.Context, jobID uuid.UUID) (*labs.ProcessingJob, error) {
+	return r.findByIDRes, r.findByIDErr
+}
+func (r *fakeJobsRepo) FindActiveIDByContentHash(ctx context.Context, patientID uuid.UUID, contentHash string) (*uuid.UUID, error) {
+	return r.activeByContentHash[contentHash], nil
+}
+func (r *fakeJobsRepo) ClaimNext(ctx context.Context) (*labs.ProcessingJob, error) { panic("unused") }
+func (r *fakeJobsRepo) MarkSucceeded(ctx context.Context, jobID uuid.UUID, attempt int, reportID uuid.UUID) (bool, error) {
+	panic("unused")
+}
+func (r *fakeJobsRepo) MarkFailed(ctx context.Context, jobID uuid.UUID, attempt int, errorCode, errorMessage string) (bool, error) {
 	panic("unused")
 }
+func (r *fakeJobsRepo) RequeueStale(ctx context.Context, startedBefore time.Time, maxAttempts int) (int64, error) {
+	panic("unused")
+}
+func (r *fakeJobsRepo) FailStale(ctx context.Context, startedBefore time.Time, maxAttempts int, errorCode, errorMessage string) (int64, error) {
+	panic("unused")
+}
+
+type fakeStorage struct {
+	deleted []string
+}
+
+func (s *fakeStorage) Upload(ctx context.Context, file io.Reader, objectName, contentType string) (string, error) {
+	panic("unused")
+}
+func (s *fakeStorage) Delete(ctx context.Context, uri string) error {
+	s.deleted = append(s.deleted, uri)
+	return nil
+}
+func (s *fakeStorage) GetSignedURL(ctx context.Context, uri string, expirationMinutes int) (string, error) {
+	return "https://signed.example/" + uri, nil
+}
 
 func TestList_InvalidPatientID_ReturnsValidationFailed(t *testing.T) {
-	svc := New(&fakePatientRepo{}, &fakeLabsRepo{})
+	svc := New(&fakePatientRepo{}, &fakeLabsRepo{}, &fakeJobsRepo{}, nil)
 
 	_, err := svc.List(context.Background(), uuid.Nil, 10, 0)
 
@@ -87,7 +219,7 @@ func TestList_InvalidPatientID_ReturnsValidationFailed(t *testing.T) {
 }
 
 func TestList_PatientNotFound_ReturnsNotFound(t *testing.T) {
-	svc := New(&fakePatientRepo{findByIDRes: nil}, &fakeLabsRepo{})
+	svc := New(&fakePatientRepo{findByIDRes: nil}, &fakeLabsRepo{}, &fakeJobsRepo{}, nil)
 
 	_, err := svc.List(context.Background(), uuid.Must(uuid.NewV7()), 10, 0)
 
@@ -102,7 +234,7 @@ func TestList_PatientNotFound_ReturnsNotFound(t *testing.T) {
 
 func TestList_PatientRepoError_ReturnsInfraDatabaseError(t *testing.T) {
 	sentinel := errors.New("db down")
-	svc := New(&fakePatientRepo{findByIDErr: errors.Join(repo.ErrRepositoryFailure, sentinel)}, &fakeLabsRepo{})
+	svc := New(&fakePatientRepo{findByIDErr: errors.Join(repo.ErrRepositoryFailure, sentinel)}, &fakeLabsRepo{}, &fakeJobsRepo{}, nil)
 
 	_, err := svc.List(context.Background(), uuid.Must(uuid.NewV7()), 10, 0)
 
@@ -119,6 +251,8 @@ func TestList_LabsRepoError_ReturnsInfraDatabaseError(t *testing.T) {
 	svc := New(
 		&fakePatientRepo{findByIDRes: &patient.Patient{ID: uuid.Must(uuid.NewV7())}},
 		&fakeLabsRepo{listErr: errors.New("db down")},
+		&fakeJobsRepo{},
+		nil,
 	)
 
 	_, err := svc.List(context.Background(), uuid.Must(uuid.NewV7()), 10, 0)
@@ -131,3 +265,380 @@ func TestList_LabsRepoError_ReturnsInfraDatabaseError(t *testing.T) {
 		t.Fatalf("expected INFRA_DATABASE_ERROR, got %s", appErr.Kind)
 	}
 }
+
+func TestGetJob_OtherPatient_ReturnsNotFound(t *testing.T) {
+	job, err := labs.NewProcessingJob(uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), "gs://bucket/a.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := New(&fakePatientRepo{}, &fakeLabsRepo{}, &fakeJobsRepo{findByIDRes: job}, nil)
+
+	_, err = svc.GetJob(context.Background(), uuid.Must(uuid.NewV7()), job.ID)
+
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("expected AppError, got %T", err)
+	}
+	if appErr.Kind != apperr.NOT_FOUND {
+		t.Fatalf("expected NOT_FOUND, got %s", appErr.Kind)
+	}
+}
+
+func TestGetJob_SamePatient_ReturnsStatus(t *testing.T) {
+	job, err := labs.NewProcessingJob(uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), "gs://bucket/a.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := New(&fakePatientRepo{}, &fakeLabsRepo{}, &fakeJobsRepo{findByIDRes: job}, nil)
+
+	out, err := svc.GetJob(context.Background(), job.PatientID, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != string(labs.JobStatusQueued) {
+		t.Fatalf("expected status %s, got %s", labs.JobStatusQueued, out.Status)
+	}
+}
+
+func strPtr(s string) *string     { return &s }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestTimeline_ConvertsToCanonicalUnitKeepingOriginal(t *testing.T) {
+	patientID := uuid.Must(uuid.NewV7())
+	code := "glucose"
+	svc := New(
+		&fakePatientRepo{findByIDRes: &patient.Patient{ID: patientID}},
+		&fakeLabsRepo{timelineRes: []labs.LabResultItemTimeline{
+			{
+				ParameterName: "Glicose", AnalyteCode: &code, CanonicalUnit: strPtr("mg/dL"),
+				ResultValue: strPtr("5,5"), ResultUnit: strPtr("mmol/L"),
+				NumericValue: floatPtr(5.5), UCUMUnit: strPtr("mmol/L"),
+			},
+			{
+				ParameterName: "GLICEMIA", AnalyteCode: &code, CanonicalUnit: strPtr("mg/dL"),
+				ResultValue: strPtr("92"), ResultUnit: strPtr("mg/dL"),
+				NumericValue: floatPtr(92), UCUMUnit: strPtr("mg/dL"),
+			},
+			{
+				ParameterName: "Glicose", AnalyteCode: &code, CanonicalUnit: strPtr("mg/dL"),
+				ResultValue: strPtr("Hemolisado"),
+			},
+		}},
+		&fakeJobsRepo{},
+		nil,
+	)
+
+	timeline, err := svc.Timeline(context.Background(), TimelineInput{PatientID: patientID, Analytes: []string{"glicose"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(timeline.Series) != 1 {
+		t.Fatalf("expected one series, got %d", len(timeline.Series))
+	}
+	out := timeline.Series[0]
+	if out.Unit == nil || *out.Unit != "mg/dL" {
+		t.Fatalf("expected series unit mg/dL, got %v", out.Unit)
+	}
+	if len(out.Points) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(out.Points))
+	}
+
+	first := out.Points[0]
+	if first.Value == nil || *first.Value < 99 || *first.Value > 99.2 {
+		t.Fatalf("expected ~99.1 mg/dL, got %v", first.Value)
+	}
+	if *first.OriginalValue != "5,5" || *first.OriginalUnit != "mmol/L" {
+		t.Fatalf("expected original value to be kept, got %v %v", *first.OriginalValue, *first.OriginalUnit)
+	}
+	if out.Points[1].Value == nil || *out.Points[1].Value != 92 {
+		t.Fatalf("expected 92 mg/dL, got %v", out.Points[1].Value)
+	}
+	if out.Points[2].Value != nil {
+		t.Fatalf("expected qualitative point without value, got %v", *out.Points[2].Value)
+	}
+}
+
+func TestDelete_RemovesReportAndDocument(t *testing.T) {
+	patientID := uuid.Must(uuid.NewV7())
+	uri := "gs://bucket/patients/x/lab-reports/a.pdf"
+	report := &labs.LabReport{ID: uuid.Must(uuid.NewV7()), PatientID: patientID, DocumentURI: &uri}
+	labsRepo := &fakeLabsRepo{reports: map[uuid.UUID]*labs.LabReport{report.ID: report}}
+	storage := &fakeStorage{}
+	svc := New(&fakePatientRepo{}, labsRepo, &fakeJobsRepo{}, storage)
+
+	if err := svc.Delete(context.Background(), patientID, report.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(labsRepo.deleted) != 1 || labsRepo.deleted[0] != report.ID {
+		t.Fatalf("expected report to be deleted, got %v", labsRepo.deleted)
+	}
+	if len(storage.deleted) != 1 || storage.deleted[0] != uri {
+		t.Fatalf("expected document to be deleted, got %v", storage.deleted)
+	}
+}
+
+func TestDelete_OtherPatientReport_ReturnsNotFound(t *testing.T) {
+	report := &labs.LabReport{ID: uuid.Must(uuid.NewV7()), PatientID: uuid.Must(uuid.NewV7())}
+	labsRepo := &fakeLabsRepo{reports: map[uuid.UUID]*labs.LabReport{report.ID: report}}
+	svc := New(&fakePatientRepo{}, labsRepo, &fakeJobsRepo{}, &fakeStorage{})
+
+	err := svc.Delete(context.Background(), uuid.Must(uuid.NewV7()), report.ID)
+
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.NOT_FOUND {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+	if len(labsRepo.deleted) != 0 {
+		t.Fatal("report of another patient must not be deleted")
+	}
+}
+
+func TestDocumentURL(t *testing.T) {
+	patientID := uuid.Must(uuid.NewV7())
+	uri := "gs://bucket/a.pdf"
+	mime := "application/pdf"
+	withDoc := &labs.LabReport{ID: uuid.Must(uuid.NewV7()), PatientID: patientID, DocumentURI: &uri, MimeType: &mime}
+	withoutDoc := &labs.LabReport{ID: uuid.Must(uuid.NewV7()), PatientID: patientID}
+	labsRepo := &fakeLabsRepo{reports: map[uuid.UUID]*labs.LabReport{withDoc.ID: withDoc, withoutDoc.ID: withoutDoc}}
+	svc := New(&fakePatientRepo{}, labsRepo, &fakeJobsRepo{}, &fakeStorage{})
+
+	out, err := svc.DocumentURL(context.Background(), patientID, withDoc.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.URL != "https://signed.example/"+uri || out.MimeType == nil || *out.MimeType != mime {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if !out.ExpiresAt.After(time.Now()) {
+		t.Fatalf("expected expiration in the future, got %v", out.ExpiresAt)
+	}
+
+	_, err = svc.DocumentURL(context.Background(), patientID, withoutDoc.ID)
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.NOT_FOUND {
+		t.Fatalf("expected NOT_FOUND for report without document, got %v", err)
+	}
+}
+
+func TestHistory_ListsRevisions(t *testing.T) {
+	patientID := uuid.Must(uuid.NewV7())
+	report := &labs.LabReport{ID: uuid.Must(uuid.NewV7()), PatientID: patientID, Status: labs.StatusCorrected, Version: 2}
+	old, current := "92", "9,2"
+	revision := labs.Revision{
+		Version:   2,
+		Status:    labs.StatusCorrected,
+		ChangedBy: uuid.Must(uuid.NewV7()),
+		ChangedAt: time.Now(),
+		Changes: []labs.FieldChange{
+			{Target: labs.EditTargetItem, TargetID: uuid.Must(uuid.NewV7()), Field: "result_value", OldValue: &old, NewValue: &current},
+		},
+	}
+	labsRepo := &fakeLabsRepo{
+		reports:   map[uuid.UUID]*labs.LabReport{report.ID: report},
+		revisions: map[uuid.UUID][]labs.Revision{report.ID: {revision}},
+	}
+	svc := New(&fakePatientRepo{}, labsRepo, &fakeJobsRepo{}, &fakeStorage{})
+
+	out, err := svc.History(context.Background(), patientID, report.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != "corrected" || out.Version != 2 || len(out.Revisions) != 1 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if out.Revisions[0].ChangedByUserID != revision.ChangedBy || *out.Revisions[0].Changes[0].OldValue != "92" {
+		t.Fatalf("unexpected revision: %+v", out.Revisions[0])
+	}
+
+	_, err = svc.History(context.Background(), uuid.Must(uuid.NewV7()), report.ID)
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.NOT_FOUND {
+		t.Fatalf("expected NOT_FOUND for another patient, got %v", err)
+	}
+}
+
+func TestCheckDuplicateDocument_ReturnsExistingReport(t *testing.T) {
+	existingID := uuid.Must(uuid.NewV7())
+	hash := strings.Repeat("ab", 32)
+	svc := New(&fakePatientRepo{}, &fakeLabsRepo{byContentHash: map[string]*uuid.UUID{hash: &existingID}}, &fakeJobsRepo{}, &fakeStorage{})
+
+	err := svc.CheckDuplicateDocument(context.Background(), uuid.Must(uuid.NewV7()), hash)
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.RESOURCE_ALREADY_EXISTS {
+		t.Fatalf("expected RESOURCE_ALREADY_EXISTS, got %v", err)
+	}
+	if appErr.ResourceID != existingID.String() {
+		t.Fatalf("expected resource id %s, got %q", existingID, appErr.ResourceID)
+	}
+
+	if err := svc.CheckDuplicateDocument(context.Background(), uuid.Must(uuid.NewV7()), strings.Repeat("cd", 32)); err != nil {
+		t.Fatalf("expected new document to pass, got %v", err)
+	}
+}
+
+func TestCheckDocumentInProcessing_ReturnsActiveJob(t *testing.T) {
+	jobID := uuid.Must(uuid.NewV7())
+	hash := strings.Repeat("ab", 32)
+	svc := New(&fakePatientRepo{}, &fakeLabsRepo{}, &fakeJobsRepo{activeByContentHash: map[string]*uuid.UUID{hash: &jobID}}, &fakeStorage{})
+
+	err := svc.CheckDocumentInProcessing(context.Background(), uuid.Must(uuid.NewV7()), hash)
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.RESOURCE_CONFLICT {
+		t.Fatalf("expected RESOURCE_CONFLICT, got %v", err)
+	}
+	if appErr.ResourceID != jobID.String() {
+		t.Fatalf("expected resource id %s, got %q", jobID, appErr.ResourceID)
+	}
+}
+
+func TestTimeline_ReferenceBandAndFlags(t *testing.T) {
+	patientID := uuid.Must(uuid.NewV7())
+	code := "glucose"
+	collected := time.Date(2025, 3, 10, 10, 30, 0, 0, time.UTC)
+	svc := New(
+		&fakePatientRepo{findByIDRes: &patient.Patient{ID: patientID}},
+		&fakeLabsRepo{timelineRes: []labs.LabResultItemTimeline{
+			{
+				ParameterName: "Glicose", AnalyteCode: &code, CanonicalUnit: strPtr("mg/dL"),
+				CollectedAt: &collected, ResultValue: strPtr("7,0"), ResultUnit: strPtr("mmol/L"),
+				NumericValue: floatPtr(7), UCUMUnit: strPtr("mmol/L"),
+				ReferenceLow: floatPtr(3.9), ReferenceHigh: floatPtr(5.5),
+				Interpretation: labs.InterpretationHigh,
+			},
+		}},
+		&fakeJobsRepo{},
+		nil,
+	)
+
+	out, err := svc.Timeline(context.Background(), TimelineInput{
+		PatientID: patientID,
+		Analytes:  []string{"glicose", " Glicose ", "hba1c"},
+		From:      "2025-01-01",
+		To:        "2025-12-31",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.Series) != 2 {
+		t.Fatalf("expected duplicates to be dropped, got %d series", len(out.Series))
+	}
+	if out.To == nil || !out.To.Equal(time.Date(2025, 12, 31, 23, 59, 59, 999999999, time.UTC)) {
+		t.Fatalf("expected date-only 'to' to include the whole day, got %v", out.To)
+	}
+
+	point := out.Series[0].Points[0]
+	if !point.Abnormal || point.EffectiveAt == nil || !point.EffectiveAt.Equal(collected) {
+		t.Fatalf("unexpected point: %+v", point)
+	}
+	if point.ReferenceLow == nil || *point.ReferenceLow < 70 || *point.ReferenceLow > 70.5 ||
+		point.ReferenceHigh == nil || *point.ReferenceHigh < 99 || *point.ReferenceHigh > 99.2 {
+		t.Fatalf("expected reference band in mg/dL, got %v-%v", point.ReferenceLow, point.ReferenceHigh)
+	}
+
+	_, err = svc.Timeline(context.Background(), TimelineInput{PatientID: patientID, Analytes: []string{"glicose"}, From: "10/03/2025"})
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.VALIDATION_FAILED {
+		t.Fatalf("expected VALIDATION_FAILED for invalid from, got %v", err)
+	}
+}
+
+func TestTimeline_MarksDerivedPoints(t *testing.T) {
+	patientID := uuid.Must(uuid.NewV7())
+	code := "egfr"
+	collected := time.Date(2025, 3, 10, 10, 30, 0, 0, time.UTC)
+	svc := New(
+		&fakePatientRepo{findByIDRes: &patient.Patient{ID: patientID}},
+		&fakeLabsRepo{timelineRes: []labs.LabResultItemTimeline{
+			{
+				ParameterName: "TFG estimada (CKD-EPI 2021)", AnalyteCode: &code, CanonicalUnit: strPtr("mL/min/{1.73_m2}"),
+				CollectedAt: &collected, ResultValue: strPtr("92"), NumericValue: floatPtr(92), UCUMUnit: strPtr("mL/min/{1.73_m2}"),
+				Derived: true, Formula: labs.FormulaCKDEPI2021,
+			},
+		}},
+		&fakeJobsRepo{},
+		nil,
+	)
+
+	out, err := svc.Timeline(context.Background(), TimelineInput{PatientID: patientID, Analytes: []string{"egfr"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	point := out.Series[0].Points[0]
+	if !point.Derived || point.Formula != "ckd-epi-2021" || point.Value == nil || *point.Value != 92 {
+		t.Fatalf("expected derived eGFR point, got %+v", point)
+	}
+}
+
+func TestSearch_NormalizesQuery(t *testing.T) {
+	patientID := uuid.Must(uuid.NewV7())
+	labsRepo := &fakeLabsRepo{searchRes: []labs.SearchHit{{
+		ReportID: uuid.Must(uuid.NewV7()),
+		Source:   labs.SourceDocument,
+		Status:   labs.StatusFinal,
+		Rank:     0.6,
+		Snippet:  "Anti-HBs <mark>Hepatite</mark> <mark>B</mark> &lt;script&gt;",
+	}}}
+	svc := New(&fakePatientRepo{findByIDRes: &patient.Patient{ID: patientID}}, labsRepo, &fakeJobsRepo{}, nil)
+
+	hits, err := svc.Search(context.Background(), patientID, "  hepatite \t B ", 20, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if labsRepo.searchQuery != "hepatite B" {
+		t.Fatalf("expected normalized query, got %q", labsRepo.searchQuery)
+	}
+	want := "Anti-HBs <mark>Hepatite</mark> <mark>B</mark> &lt;script&gt;"
+	if len(hits) != 1 || hits[0].Snippet != want || hits[0].Source != "document" {
+		t.Fatalf("unexpected hits: %+v", hits)
+	}
+
+	_, err = svc.Search(context.Background(), patientID, "   ", 20, 0)
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.VALIDATION_FAILED {
+		t.Fatalf("expected validation error for empty query, got %v", err)
+	}
+}
+
+func TestCompare_AlignsReportsOfSamePatient(t *testing.T) {
+	patientID := uuid.Must(uuid.NewV7())
+	code := "hemoglobin"
+	report := func(value float64, interp labs.Interpretation) *labs.LabReport {
+		return &labs.LabReport{
+			ID:        uuid.Must(uuid.NewV7()),
+			PatientID: patientID,
+			Status:    labs.StatusFinal,
+			TestResults: []labs.LabResult{{Items: []labs.LabResultItem{{
+				ID: uuid.Must(uuid.NewV7()), ParameterName: "Hemoglobina", AnalyteCode: &code,
+				NumericValue: floatPtr(value), UCUMUnit: strPtr("g/dL"), Interpretation: interp,
+			}}}},
+		}
+	}
+	a, b := report(12.5, labs.InterpretationNormal), report(10, labs.InterpretationLow)
+	other := report(11, labs.InterpretationLow)
+	other.PatientID = uuid.Must(uuid.NewV7())
+	labsRepo := &fakeLabsRepo{reports: map[uuid.UUID]*labs.LabReport{a.ID: a, b.ID: b, other.ID: other}}
+	svc := New(&fakePatientRepo{}, labsRepo, &fakeJobsRepo{}, nil)
+
+	out, err := svc.Compare(context.Background(), patientID, a.ID, b.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.Items) != 1 || out.HighlightedCount != 1 {
+		t.Fatalf("unexpected comparison: %+v", out)
+	}
+	item := out.Items[0]
+	if !item.CrossedReference || *item.AbsoluteDelta != -2.5 || *item.PercentDelta != -20 || item.A.Interpretation != "N" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+
+	_, err = svc.Compare(context.Background(), patientID, a.ID, other.ID)
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.NOT_FOUND {
+		t.Fatalf("expected other patient's report to be not found, got %v", err)
+	}
+
+	_, err = svc.Compare(context.Background(), patientID, a.ID, a.ID)
+	if !errors.As(err, &appErr) || appErr.Kind != apperr.VALIDATION_FAILED {
+		t.Fatalf("expected validation error for same report, got %v", err)
+	}
+}
@@ -52,6 +52,9 @@ func (r *fakePatientRepo) HardDelete(ctx context.Context, id uuid.UUID) error
 func (r *fakePatientRepo) FindByCPF(ctx context.Context, cpf string) (*patient.Patient, error) {
 	panic("unused")
 }
+func (r *fakePatientRepo) FindByCNS(ctx context.Context, cns string) (*patient.Patient, error) {
+	panic("unused")
+}
 func (r *fakePatientRepo) FindByID(ctx context.Context, id uuid.UUID) (*patient.Patient, error) {
 	panic("unused")
 }
@@ -87,6 +90,10 @@ func (r *fakeAccessRepo) HasActiveAccess(ctx context.Context, patientID, grantee
 	panic("unused")
 }
 
+func (r *fakeAccessRepo) ListActiveGrantees(ctx context.Context, patientID uuid.UUID, relation patientaccess.RelationshipType) ([]uuid.UUID, error) {
+	panic("unused")
+}
+
 func TestCreate_ProfessionalCreatesAccess(t *testing.T) {
 	patientRepo := &fakePatientRepo{}
 	accessRepo := &fakeAccessRepo{}
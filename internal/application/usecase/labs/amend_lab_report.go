@@ -0,0 +1,257 @@
+package labsuc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+// AmendLabReportUseCase corrige um laudo gravado, registrando uma revisão com
+// quem alterou, quando e o valor anterior de cada campo.
+type AmendLabReportUseCase interface {
+	Execute(ctx context.Context, input AmendLabReportInput) (*labsvc.LabReportOutput, error)
+}
+
+type amendLabReportUseCase struct {
+	patientRepo  repository.Patient
+	labsRepo     repository.Labs
+	analytesRepo repository.Analytes
+	alerter      CriticalValueAlerter
+	calculator   DerivedValueCalculator
+}
+
+var _ AmendLabReportUseCase = (*amendLabReportUseCase)(nil)
+
+func NewAmendLabReport(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	analytesRepo repository.Analytes,
+	alerter CriticalValueAlerter,
+	calculator DerivedValueCalculator,
+) AmendLabReportUseCase {
+	return &amendLabReportUseCase{
+		patientRepo:  patientRepo,
+		labsRepo:     labsRepo,
+		analytesRepo: analytesRepo,
+		alerter:      alerter,
+		calculator:   calculator,
+	}
+}
+
+func (u *amendLabReportUseCase) Execute(ctx context.Context, input AmendLabReportInput) (*labsvc.LabReportOutput, error) {
+	edits, violations := parseFieldEdits(input.Changes)
+	if input.PatientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if input.ReportID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "report_id", Reason: "required"})
+	}
+	if input.ChangedByUserID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "changed_by_user_id", Reason: "required"})
+	}
+	if input.Version == nil {
+		violations = append(violations, apperr.Violation{Field: "version", Reason: "required"})
+	}
+	var status labs.ReportStatus
+	if raw := trimmed(input.Status); raw != "" {
+		status = labs.ReportStatus(strings.ToLower(raw))
+		if !status.IsValid() {
+			violations = append(violations, apperr.Violation{Field: "status", Reason: "invalid_enum"})
+		}
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	report, err := u.labsRepo.FindByID(ctx, input.ReportID)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	// Laudo de outro paciente é tratado como inexistente.
+	if report == nil || report.PatientID != input.PatientID {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "laudo não encontrado",
+		}
+	}
+	if report.Version != *input.Version {
+		return nil, versionConflict(nil)
+	}
+
+	p, err := u.patientRepo.FindByID(ctx, input.PatientID)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	if p == nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "paciente não encontrado",
+		}
+	}
+
+	revision, err := report.Amend(edits, status, input.ChangedByUserID, input.Reason, time.Now())
+	if err != nil {
+		return nil, mapAmendError(err)
+	}
+
+	if err := reparseChangedItems(ctx, u.analytesRepo, report, revision, p.Gender); err != nil {
+		return nil, err
+	}
+
+	if err := u.labsRepo.Amend(ctx, report, revision); err != nil {
+		if errors.Is(err, labs.ErrVersionConflict) {
+			return nil, versionConflict(err)
+		}
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+
+	// Valores corrigidos mudam os cálculos que dependem deles e podem passar
+	// de um limite crítico; itens que já geraram alerta não geram outro.
+	applyDerivedValues(ctx, u.calculator, report, p)
+	checkCriticalValues(ctx, u.alerter, report, p)
+
+	return labsvc.ToLabReportOutput(report), nil
+}
+
+// reparseChangedItems reinterpreta os parâmetros alterados (valor, unidade,
+// referência e vínculo com o catálogo) como numa entrada nova.
+func reparseChangedItems(
+	ctx context.Context,
+	analytesRepo repository.Analytes,
+	report *labs.LabReport,
+	revision *labs.Revision,
+	gender demographics.Gender,
+) error {
+	analytes, err := analytesRepo.List(ctx)
+	if err != nil {
+		return &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	catalog := labs.NewAnalyteCatalog(analytes)
+	for _, change := range revision.Changes {
+		if change.Target != labs.EditTargetItem || change.Field == "confirmed" {
+			continue
+		}
+		if item := report.Item(change.TargetID); item != nil {
+			item.ParseStructuredResult(gender)
+			item.LinkAnalyte(catalog)
+		}
+	}
+	return nil
+}
+
+// parseFieldEdits converte as alterações recebidas, apontando cada campo
+// inválido pelo índice (ex.: changes[1].target_id).
+func parseFieldEdits(changes []LabFieldEditInput) ([]labs.FieldEdit, []apperr.Violation) {
+	var violations []apperr.Violation
+	edits := make([]labs.FieldEdit, 0, len(changes))
+
+	for i, in := range changes {
+		field := fmt.Sprintf("changes[%d]", i)
+		edit := labs.FieldEdit{
+			Target: labs.EditTarget(strings.ToLower(strings.TrimSpace(in.Target))),
+			Field:  strings.ToLower(strings.TrimSpace(in.Field)),
+			Value:  in.Value,
+		}
+
+		switch edit.Target {
+		case labs.EditTargetReport:
+		case labs.EditTargetResult, labs.EditTargetItem:
+			raw := trimmed(in.TargetID)
+			if raw == "" {
+				violations = append(violations, apperr.Violation{Field: field + ".target_id", Reason: "required"})
+				continue
+			}
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				violations = append(violations, apperr.Violation{Field: field + ".target_id", Reason: "invalid"})
+				continue
+			}
+			edit.TargetID = id
+		case "":
+			violations = append(violations, apperr.Violation{Field: field + ".target", Reason: "required"})
+			continue
+		default:
+			violations = append(violations, apperr.Violation{Field: field + ".target", Reason: "invalid_enum"})
+			continue
+		}
+
+		if edit.Field == "" {
+			violations = append(violations, apperr.Violation{Field: field + ".field", Reason: "required"})
+			continue
+		}
+		edits = append(edits, edit)
+	}
+
+	return edits, violations
+}
+
+func mapAmendError(err error) error {
+	if errors.Is(err, labs.ErrNoChanges) {
+		return apperr.Validation("nenhuma alteração no laudo",
+			apperr.Violation{Field: "changes", Reason: "required"})
+	}
+	if errors.Is(err, labs.ErrInReview) {
+		return apperr.DomainRuleViolation("o laudo aguarda revisão; aprove-o em .../review antes de liberá-lo")
+	}
+	if errors.Is(err, labs.ErrInvalidStatus) {
+		return apperr.Validation("entrada inválida",
+			apperr.Violation{Field: "status", Reason: "invalid_enum"})
+	}
+
+	var editErr *labs.FieldEditError
+	if !errors.As(err, &editErr) {
+		return mapLabDomainError(err)
+	}
+
+	field := fmt.Sprintf("changes[%d]", editErr.Index)
+	var violation apperr.Violation
+	switch {
+	case errors.Is(err, labs.ErrUnknownEditTarget):
+		violation = apperr.Violation{Field: field + ".target", Reason: "invalid_enum"}
+	case errors.Is(err, labs.ErrEditTargetNotFound):
+		violation = apperr.Violation{Field: field + ".target_id", Reason: "invalid"}
+	case errors.Is(err, labs.ErrFieldNotEditable):
+		violation = apperr.Violation{Field: field + ".field", Reason: "unsupported"}
+	case errors.Is(err, labs.ErrInvalidDateFormat):
+		violation = apperr.Violation{Field: field + ".value", Reason: "invalid_date"}
+	case errors.Is(err, labs.ErrInvalidTestName), errors.Is(err, labs.ErrInvalidParameterName):
+		violation = apperr.Violation{Field: field + ".value", Reason: "required"}
+	default:
+		return mapLabDomainError(err)
+	}
+	return apperr.Validation("entrada inválida", violation)
+}
+
+func versionConflict(cause error) error {
+	return &apperr.AppError{
+		Kind:    apperr.RESOURCE_CONFLICT,
+		Message: "o laudo foi alterado por outra pessoa; recarregue e tente novamente",
+		Cause:   cause,
+	}
+}
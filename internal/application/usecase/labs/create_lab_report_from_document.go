@@ -13,6 +13,7 @@ import (
 
 	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
 	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
 	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
 	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
@@ -25,9 +26,12 @@ type CreateLabReportFromDocumentUseCase interface {
 }
 
 type createLabReportFromDocumentUseCase struct {
-	patientRepo repository.Patient
-	labsRepo    repository.Labs
-	extractor   domainai.DocumentExtractorService
+	patientRepo  repository.Patient
+	labsRepo     repository.Labs
+	analytesRepo repository.Analytes
+	extractor    domainai.DocumentExtractorService
+	alerter      CriticalValueAlerter
+	calculator   DerivedValueCalculator
 }
 
 var _ CreateLabReportFromDocumentUseCase = (*createLabReportFromDocumentUseCase)(nil)
@@ -35,18 +39,24 @@ var _ CreateLabReportFromDocumentUseCase = (*createLabReportFromDocumentUseCase)
 func NewCreateLabReportFromDocument(
 	patientRepo repository.Patient,
 	labsRepo repository.Labs,
+	analytesRepo repository.Analytes,
 	extractor domainai.DocumentExtractorService,
+	alerter CriticalValueAlerter,
+	calculator DerivedValueCalculator,
 ) CreateLabReportFromDocumentUseCase {
 	return &createLabReportFromDocumentUseCase{
-		patientRepo: patientRepo,
-		labsRepo:    labsRepo,
-		extractor:   extractor,
+		patientRepo:  patientRepo,
+		labsRepo:     labsRepo,
+		analytesRepo: analytesRepo,
+		extractor:    extractor,
+		alerter:      alerter,
+		calculator:   calculator,
 	}
 }
 
 func (u *createLabReportFromDocumentUseCase) Execute(ctx context.Context, input CreateLabReportFromDocumentInput) (*labsvc.LabReportOutput, error) {
 	//Valida o input
-	if err := u.validateInput(input); err != nil {
+	if err := validateDocumentInput(input); err != nil {
 		return nil, err
 	}
 
@@ -65,6 +75,11 @@ func (u *createLabReportFromDocumentUseCase) Execute(ctx context.Context, input
 		}
 	}
 
+	// Arquivo repetido (ex.: dois uploads na fila) não chega a ir para a IA.
+	if err := ensureNewDocument(ctx, u.labsRepo, input.PatientID, input.ContentHash); err != nil {
+		return nil, err
+	}
+
 	extracted, err := u.extractor.ExtractLabReport(ctx, input.DocumentURI, input.MimeType)
 	if err != nil {
 		return nil, &apperr.AppError{
@@ -74,9 +89,24 @@ func (u *createLabReportFromDocumentUseCase) Execute(ctx context.Context, input
 		}
 	}
 
-	report, err := u.mapExtractedToDomain(input.PatientID, input.UploadedByUserID, extracted)
+	analytes, err := u.analytesRepo.List(ctx)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	catalog := labs.NewAnalyteCatalog(analytes)
+
+	report, err := u.mapExtractedToDomain(input.PatientID, input.UploadedByUserID, p.Gender, catalog, extracted)
 	if err != nil {
-		return nil, u.mapDomainError(err)
+		return nil, mapLabDomainError(err)
+	}
+
+	identity := labs.PatientIdentity{FullName: p.FullName, BirthDate: p.BirthDate, CPF: p.CPF}
+	if err := checkIdentity(report, identity, input.IdentityOverride); err != nil {
+		return nil, err
 	}
 
 	fingerprint := generateLabFingerprint(input.PatientID, report)
@@ -96,8 +126,23 @@ func (u *createLabReportFromDocumentUseCase) Execute(ctx context.Context, input
 		}
 	}
 
+	// Guardamos o arquivo no laudo para download e exclusão.
+	documentURI, mimeType := strings.TrimSpace(input.DocumentURI), normalizeMimeType(input.MimeType)
+	report.DocumentURI = &documentURI
+	report.MimeType = &mimeType
+	if input.ContentHash != "" {
+		contentHash := input.ContentHash
+		report.ContentHash = &contentHash
+	}
+
 	report.Fingerprint = &fingerprint
+	// Valores com baixa confiança seguram o laudo como preliminar até revisão.
+	report.FlagForReview()
 	if err := u.labsRepo.Create(ctx, report); err != nil {
+		if errors.Is(err, labs.ErrDuplicateDocument) {
+			// Outro upload do mesmo arquivo gravou primeiro; aponta para ele.
+			return nil, duplicateDocument(ctx, u.labsRepo, input.PatientID, input.ContentHash)
+		}
 		var appErr *apperr.AppError
 		if errors.As(err, &appErr) && appErr != nil {
 			return nil, appErr
@@ -109,10 +154,23 @@ func (u *createLabReportFromDocumentUseCase) Execute(ctx context.Context, input
 		}
 	}
 
-	return toOutput(report), nil
+	applyDerivedValues(ctx, u.calculator, report, p)
+	checkCriticalValues(ctx, u.alerter, report, p)
+
+	return labsvc.ToLabReportOutput(report), nil
+}
+
+// hasSupportedStorageScheme aceita as URIs devolvidas pelos drivers de storage.
+func hasSupportedStorageScheme(uri string) bool {
+	for _, scheme := range []string{"gs://", "s3://", "file://"} {
+		if strings.HasPrefix(uri, scheme) {
+			return true
+		}
+	}
+	return false
 }
 
-func (u *createLabReportFromDocumentUseCase) validateInput(input CreateLabReportFromDocumentInput) error {
+func validateDocumentInput(input CreateLabReportFromDocumentInput) error {
 	var violations []apperr.Violation
 
 	if input.PatientID == uuid.Nil {
@@ -121,8 +179,15 @@ func (u *createLabReportFromDocumentUseCase) validateInput(input CreateLabReport
 	if input.UploadedByUserID == uuid.Nil {
 		violations = append(violations, apperr.Violation{Field: "uploaded_by_user_id", Reason: "required"})
 	}
-	if strings.TrimSpace(input.DocumentURI) == "" {
+	switch documentURI := strings.TrimSpace(input.DocumentURI); {
+	case documentURI == "":
 		violations = append(violations, apperr.Violation{Field: "document_uri", Reason: "required"})
+	case !hasSupportedStorageScheme(documentURI):
+		violations = append(violations, apperr.Violation{Field: "document_uri", Reason: "unsupported_scheme"})
+	}
+
+	if input.ContentHash != "" && !isSHA256Hex(input.ContentHash) {
+		violations = append(violations, apperr.Violation{Field: "content_hash", Reason: "invalid"})
 	}
 
 	switch normalizeMimeType(input.MimeType) {
@@ -138,6 +203,75 @@ func (u *createLabReportFromDocumentUseCase) validateInput(input CreateLabReport
 	return nil
 }
 
+// ensureNewDocument recusa um arquivo que o paciente já tem num laudo.
+func ensureNewDocument(ctx context.Context, labsRepo repository.Labs, patientID uuid.UUID, contentHash string) error {
+	if contentHash == "" {
+		return nil
+	}
+	reportID, err := labsRepo.FindIDByContentHash(ctx, patientID, contentHash)
+	if err != nil {
+		return &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	if reportID != nil {
+		return labsvc.DuplicateDocumentError(*reportID)
+	}
+	return nil
+}
+
+// duplicateDocument monta o erro de arquivo repetido depois que o índice único
+// recusou a gravação.
+func duplicateDocument(ctx context.Context, labsRepo repository.Labs, patientID uuid.UUID, contentHash string) error {
+	if err := ensureNewDocument(ctx, labsRepo, patientID, contentHash); err != nil {
+		return err
+	}
+	return &apperr.AppError{
+		Kind:    apperr.RESOURCE_ALREADY_EXISTS,
+		Message: "este arquivo já foi enviado para o paciente",
+		Cause:   labs.ErrDuplicateDocument,
+	}
+}
+
+// checkIdentity confere nome, nascimento e CPF impressos no laudo com o
+// cadastro do paciente. Divergência só passa com override explícito e fica
+// registrada como "overridden".
+func checkIdentity(report *labs.LabReport, patient labs.PatientIdentity, override bool) error {
+	match := labs.MatchIdentity(report, patient)
+	status := match.Status
+	if status == labs.IdentityMismatch {
+		if !override {
+			return identityMismatchError(match)
+		}
+		status = labs.IdentityOverridden
+	}
+	report.IdentityStatus = &status
+	report.IdentityScore = match.Score
+	return nil
+}
+
+func identityMismatchError(match labs.IdentityMatch) error {
+	var violations []apperr.Violation
+	for _, field := range match.MismatchedFields() {
+		violations = append(violations, apperr.Violation{Field: field, Reason: "mismatch"})
+	}
+	return &apperr.AppError{
+		Kind:       apperr.PATIENT_IDENTITY_MISMATCH,
+		Message:    "os dados do laudo não conferem com o paciente",
+		Violations: violations,
+	}
+}
+
+func isSHA256Hex(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
 func normalizeMimeType(raw string) string {
 	if raw == "" {
 		return ""
@@ -152,6 +286,8 @@ func normalizeMimeType(raw string) string {
 func (u *createLabReportFromDocumentUseCase) mapExtractedToDomain(
 	patientID uuid.UUID,
 	uploadedByUserID uuid.UUID,
+	patientGender demographics.Gender,
+	catalog *labs.AnalyteCatalog,
 	extracted *domainai.ExtractedLabReport,
 ) (*labs.LabReport, error) {
 	if extracted == nil {
@@ -163,6 +299,7 @@ func (u *createLabReportFromDocumentUseCase) mapExtractedToDomain(
 		return nil, err
 	}
 
+	report.Source = labs.SourceDocument
 	report.PatientName = extracted.PatientName
 	report.LabName = extracted.LabName
 	report.LabPhone = extracted.LabPhone
@@ -170,6 +307,7 @@ func (u *createLabReportFromDocumentUseCase) mapExtractedToDomain(
 	report.RequestingDoctor = extracted.RequestingDoctor
 	report.TechnicalManager = extracted.TechnicalManager
 	report.RawText = extracted.RawText
+	report.FieldConfidence = labs.FieldConfidence(extracted.FieldConfidence)
 
 	if extracted.PatientDOB != nil {
 		if t, err := parseDate(*extracted.PatientDOB); err == nil {
@@ -210,7 +348,12 @@ func (u *createLabReportFromDocumentUseCase) mapExtractedToDomain(
 			item.ResultValue = ei.ResultValue
 			item.ResultUnit = ei.ResultUnit
 			item.ReferenceText = ei.ReferenceText
+			item.Confidence = ei.Confidence
+			item.FieldConfidence = labs.FieldConfidence(ei.FieldConfidence)
 			item.Normalize()
+			// Faixas por sexo usam o cadastro do paciente, não o texto do laudo.
+			item.ParseStructuredResult(patientGender)
+			item.LinkAnalyte(catalog)
 			testResult.Items = append(testResult.Items, *item)
 		}
 
@@ -224,7 +367,7 @@ func (u *createLabReportFromDocumentUseCase) mapExtractedToDomain(
 	return report, nil
 }
 
-func (u *createLabReportFromDocumentUseCase) mapDomainError(err error) error {
+func mapLabDomainError(err error) error {
 	if err == nil {
 		return nil
 	}
@@ -363,47 +506,3 @@ func generateLabFingerprint(patientID uuid.UUID, labReport *labs.LabReport) stri
 	hashBytes := hash.Sum(nil)
 	return hex.EncodeToString(hashBytes)
 }
-
-func toOutput(report *labs.LabReport) *labsvc.LabReportOutput {
-	output := &labsvc.LabReportOutput{
-		ID:                report.ID,
-		PatientID:         report.PatientID,
-		PatientName:       report.PatientName,
-		PatientDOB:        report.PatientDOB,
-		LabName:           report.LabName,
-		LabPhone:          report.LabPhone,
-		InsuranceProvider: report.InsuranceProvider,
-		RequestingDoctor:  report.RequestingDoctor,
-		TechnicalManager:  report.TechnicalManager,
-		ReportDate:        report.ReportDate,
-		UploadedByUserID:  report.UploadedBy,
-		Fingerprint:       report.Fingerprint,
-		CreatedAt:         report.CreatedAt,
-		UpdatedAt:         report.UpdatedAt,
-	}
-
-	for _, tr := range report.TestResults {
-		testOutput := labsvc.TestResultOutput{
-			ID:          tr.ID,
-			TestName:    tr.TestName,
-			Material:    tr.Material,
-			Method:      tr.Method,
-			CollectedAt: tr.CollectedAt,
-			ReleaseAt:   tr.ReleaseAt,
-		}
-
-		for _, item := range tr.Items {
-			testOutput.Items = append(testOutput.Items, labsvc.TestItemOutput{
-				ID:            item.ID,
-				ParameterName: item.ParameterName,
-				ResultValue:   item.ResultValue,
-				ResultUnit:    item.ResultUnit,
-				ReferenceText: item.ReferenceText,
-			})
-		}
-
-		output.TestResults = append(output.TestResults, testOutput)
-	}
-
-	return output
-}
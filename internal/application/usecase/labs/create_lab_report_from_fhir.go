@@ -0,0 +1,148 @@
+// internal/application/usecase/labs/create_lab_report_from_fhir.go
+package labsuc
+
+import (
+	"context"
+	"errors"
+
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/diagnostics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+// CreateLabReportFromFHIRUseCase recebe laudos de laboratórios que já falam FHIR.
+type CreateLabReportFromFHIRUseCase interface {
+	Execute(ctx context.Context, input CreateLabReportFromFHIRInput) (*labsvc.LabReportOutput, error)
+}
+
+type createLabReportFromFHIRUseCase struct {
+	patientRepo  repository.Patient
+	labsRepo     repository.Labs
+	analytesRepo repository.Analytes
+	alerter      CriticalValueAlerter
+	calculator   DerivedValueCalculator
+}
+
+var _ CreateLabReportFromFHIRUseCase = (*createLabReportFromFHIRUseCase)(nil)
+
+func NewCreateLabReportFromFHIR(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	analytesRepo repository.Analytes,
+	alerter CriticalValueAlerter,
+	calculator DerivedValueCalculator,
+) CreateLabReportFromFHIRUseCase {
+	return &createLabReportFromFHIRUseCase{
+		patientRepo:  patientRepo,
+		labsRepo:     labsRepo,
+		analytesRepo: analytesRepo,
+		alerter:      alerter,
+		calculator:   calculator,
+	}
+}
+
+func (u *createLabReportFromFHIRUseCase) Execute(ctx context.Context, input CreateLabReportFromFHIRInput) (*labsvc.LabReportOutput, error) {
+	var violations []apperr.Violation
+	if input.PatientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if input.UploadedByUserID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "uploaded_by_user_id", Reason: "required"})
+	}
+	if input.Bundle == nil {
+		violations = append(violations, apperr.Violation{Field: "bundle", Reason: "required"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	p, err := u.patientRepo.FindByID(ctx, input.PatientID)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	if p == nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "paciente não encontrado",
+		}
+	}
+
+	analytes, err := u.analytesRepo.List(ctx)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+
+	report, issues := diagnostics.ToLabReport(input.Bundle, p, input.UploadedByUserID, labs.NewAnalyteCatalog(analytes))
+	if len(issues) > 0 {
+		return nil, invalidBundleError(issues)
+	}
+
+	// Mesmo fingerprint do upload de documento: o laudo que chega em FHIR e
+	// depois em PDF (ou vice-versa) não é duplicado.
+	fingerprint := generateLabFingerprint(input.PatientID, report)
+
+	exists, err := u.labsRepo.ExistsBySignature(ctx, input.PatientID, fingerprint)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	if exists {
+		return nil, &apperr.AppError{
+			Kind:    apperr.RESOURCE_ALREADY_EXISTS,
+			Message: "laudo já existe",
+		}
+	}
+
+	report.Fingerprint = &fingerprint
+	if err := u.labsRepo.Create(ctx, report); err != nil {
+		var appErr *apperr.AppError
+		if errors.As(err, &appErr) && appErr != nil {
+			return nil, appErr
+		}
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+
+	applyDerivedValues(ctx, u.calculator, report, p)
+	checkCriticalValues(ctx, u.alerter, report, p)
+
+	return labsvc.ToLabReportOutput(report), nil
+}
+
+// invalidBundleError expõe as issues como violations e mantém o
+// OperationOutcome completo em Cause para a resposta FHIR.
+func invalidBundleError(issues []diagnostics.OperationOutcomeIssue) error {
+	violations := make([]apperr.Violation, 0, len(issues))
+	for _, issue := range issues {
+		field := "bundle"
+		if len(issue.Expression) > 0 {
+			field = issue.Expression[0]
+		}
+		violations = append(violations, apperr.Violation{Field: field, Reason: issue.Code})
+	}
+
+	return &apperr.AppError{
+		Kind:       apperr.VALIDATION_FAILED,
+		Message:    "Bundle FHIR inválido",
+		Cause:      &diagnostics.OutcomeError{Outcome: diagnostics.NewOperationOutcome(issues...)},
+		Violations: violations,
+	}
+}
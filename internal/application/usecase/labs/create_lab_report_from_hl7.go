@@ -0,0 +1,234 @@
+package labsuc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/hl7v2"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+// CreateLabReportFromHL7UseCase recebe resultados ORU^R01 (HL7 v2) de
+// sistemas de laboratório, via MLLP ou HTTP, e responde com ACK.
+type CreateLabReportFromHL7UseCase interface {
+	Execute(ctx context.Context, input CreateLabReportFromHL7Input) (*CreateLabReportFromHL7Output, error)
+}
+
+type createLabReportFromHL7UseCase struct {
+	patientRepo  repository.Patient
+	labsRepo     repository.Labs
+	analytesRepo repository.Analytes
+	alerter      CriticalValueAlerter
+	calculator   DerivedValueCalculator
+}
+
+var _ CreateLabReportFromHL7UseCase = (*createLabReportFromHL7UseCase)(nil)
+
+func NewCreateLabReportFromHL7(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	analytesRepo repository.Analytes,
+	alerter CriticalValueAlerter,
+	calculator DerivedValueCalculator,
+) CreateLabReportFromHL7UseCase {
+	return &createLabReportFromHL7UseCase{
+		patientRepo:  patientRepo,
+		labsRepo:     labsRepo,
+		analytesRepo: analytesRepo,
+		alerter:      alerter,
+		calculator:   calculator,
+	}
+}
+
+// Execute devolve sempre um ACK: AA quando o laudo foi gravado (ou já
+// existia), AR quando a mensagem não é uma ORU^R01 legível e AE nos demais erros.
+func (u *createLabReportFromHL7UseCase) Execute(ctx context.Context, input CreateLabReportFromHL7Input) (*CreateLabReportFromHL7Output, error) {
+	msg, err := hl7v2.Parse(input.Message)
+	if err != nil {
+		issue := hl7v2.Issue{Segment: "MSH", Code: hl7v2.ErrorSegmentSequence, Message: err.Error()}
+		return nack(nil, hl7v2.AckReject, &apperr.AppError{
+			Kind:       apperr.VALIDATION_FAILED,
+			Message:    "mensagem HL7 inválida",
+			Cause:      err,
+			Violations: hl7Violations(issue),
+		}, issue)
+	}
+
+	if input.UploadedByUserID == uuid.Nil {
+		return nack(msg, hl7v2.AckError, apperr.Validation("entrada inválida",
+			apperr.Violation{Field: "uploaded_by_user_id", Reason: "required"}))
+	}
+
+	if issues := hl7v2.CheckORU(msg); len(issues) > 0 {
+		return nack(msg, hl7v2.AckReject, invalidMessageError(issues), issues...)
+	}
+
+	ids := msg.Identifiers()
+	if ids.IsEmpty() {
+		issue := hl7v2.Issue{Segment: "PID", Sequence: 1, Field: 3, Code: hl7v2.ErrorRequiredFieldMissing,
+			Message: "informe o CPF ou o CNS do paciente em PID-3"}
+		return nack(msg, hl7v2.AckError, invalidMessageError([]hl7v2.Issue{issue}), issue)
+	}
+
+	p, err := u.findPatient(ctx, ids)
+	if err != nil {
+		return nack(msg, hl7v2.AckError, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}, internalIssue())
+	}
+	if input.Authorize != nil {
+		// Paciente inexistente e paciente sem acesso dão a mesma resposta,
+		// para que a rota não revele quais CPF/CNS estão cadastrados.
+		if p == nil {
+			return nack(msg, hl7v2.AckError, patientUnavailableError(nil), patientUnavailableIssue())
+		}
+		if err := input.Authorize(ctx, p.ID); err != nil {
+			if isAccessError(err) {
+				return nack(msg, hl7v2.AckError, patientUnavailableError(err), patientUnavailableIssue())
+			}
+			return nack(msg, hl7v2.AckError, err, internalIssue())
+		}
+	}
+	if p == nil {
+		issue := hl7v2.Issue{Segment: "PID", Sequence: 1, Field: 3, Code: hl7v2.ErrorUnknownKey, Message: "paciente não encontrado"}
+		return nack(msg, hl7v2.AckError, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "paciente não encontrado",
+		}, issue)
+	}
+
+	analytes, err := u.analytesRepo.List(ctx)
+	if err != nil {
+		return nack(msg, hl7v2.AckError, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}, internalIssue())
+	}
+
+	report, issues := hl7v2.ToLabReport(msg, p, input.UploadedByUserID, labs.NewAnalyteCatalog(analytes))
+	if len(issues) > 0 {
+		return nack(msg, hl7v2.AckError, invalidMessageError(issues), issues...)
+	}
+
+	// Mesmo fingerprint do upload de documento e do FHIR.
+	fingerprint := generateLabFingerprint(p.ID, report)
+
+	exists, err := u.labsRepo.ExistsBySignature(ctx, p.ID, fingerprint)
+	if err != nil {
+		return nack(msg, hl7v2.AckError, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}, internalIssue())
+	}
+	if exists {
+		// Reenvio (ex.: timeout do lado do LIS): aceitamos para que o sistema
+		// de origem pare de retransmitir, mas não duplicamos o laudo.
+		return nack(msg, hl7v2.AckAccept, &apperr.AppError{
+			Kind:    apperr.RESOURCE_ALREADY_EXISTS,
+			Message: "laudo já existe",
+		})
+	}
+
+	report.Fingerprint = &fingerprint
+	if err := u.labsRepo.Create(ctx, report); err != nil {
+		var appErr *apperr.AppError
+		if !errors.As(err, &appErr) || appErr == nil {
+			appErr = &apperr.AppError{
+				Kind:    apperr.INFRA_DATABASE_ERROR,
+				Message: "falha técnica",
+				Cause:   err,
+			}
+		}
+		return nack(msg, hl7v2.AckError, appErr, internalIssue())
+	}
+
+	applyDerivedValues(ctx, u.calculator, report, p)
+	checkCriticalValues(ctx, u.alerter, report, p)
+
+	return &CreateLabReportFromHL7Output{
+		ACK:    hl7v2.NewACK(msg, hl7v2.AckAccept, "laudo recebido", nil, time.Now()),
+		Report: labsvc.ToLabReportOutput(report),
+	}, nil
+}
+
+// findPatient procura pelo CPF e, sem resultado, pelo CNS.
+func (u *createLabReportFromHL7UseCase) findPatient(ctx context.Context, ids hl7v2.PatientIdentifiers) (*patient.Patient, error) {
+	if ids.CPF != "" {
+		p, err := u.patientRepo.FindByCPF(ctx, ids.CPF)
+		if err != nil || p != nil {
+			return p, err
+		}
+	}
+	if ids.CNS != "" {
+		return u.patientRepo.FindByCNS(ctx, ids.CNS)
+	}
+	return nil, nil
+}
+
+// nack monta a saída de erro: o ACK com o código e as issues, e o erro para quem chamou.
+func nack(msg *hl7v2.Message, code hl7v2.AckCode, err error, issues ...hl7v2.Issue) (*CreateLabReportFromHL7Output, error) {
+	text := "mensagem rejeitada"
+	var appErr *apperr.AppError
+	if errors.As(err, &appErr) && appErr != nil {
+		text = appErr.Message
+	}
+	return &CreateLabReportFromHL7Output{ACK: hl7v2.NewACK(msg, code, text, issues, time.Now())}, err
+}
+
+// patientUnavailableError é a resposta única para paciente não encontrado
+// ou sem permissão de upload.
+func patientUnavailableError(cause error) error {
+	return &apperr.AppError{
+		Kind:    apperr.ACCESS_DENIED,
+		Message: "acesso negado",
+		Cause:   cause,
+	}
+}
+
+func patientUnavailableIssue() hl7v2.Issue {
+	return hl7v2.Issue{Segment: "PID", Sequence: 1, Field: 3, Code: hl7v2.ErrorApplicationInternal, Message: "acesso negado"}
+}
+
+func isAccessError(err error) bool {
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr == nil {
+		return false
+	}
+	return appErr.Kind == apperr.ACCESS_DENIED || appErr.Kind == apperr.ACTION_NOT_ALLOWED
+}
+
+func internalIssue() hl7v2.Issue {
+	return hl7v2.Issue{Code: hl7v2.ErrorApplicationInternal, Message: "falha técnica; reenvie a mensagem"}
+}
+
+func invalidMessageError(issues []hl7v2.Issue) error {
+	return &apperr.AppError{
+		Kind:       apperr.VALIDATION_FAILED,
+		Message:    "mensagem HL7 inválida",
+		Violations: hl7Violations(issues...),
+	}
+}
+
+// hl7Violations usa a localização HL7 (ex.: "OBX[2]-5") como campo.
+func hl7Violations(issues ...hl7v2.Issue) []apperr.Violation {
+	violations := make([]apperr.Violation, 0, len(issues))
+	for _, issue := range issues {
+		field := issue.Location()
+		if field == "" {
+			field = "message"
+		}
+		violations = append(violations, apperr.Violation{Field: field, Reason: string(issue.Code)})
+	}
+	return violations
+}
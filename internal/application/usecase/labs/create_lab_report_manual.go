@@ -0,0 +1,246 @@
+package labsuc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+// CreateLabReportManualUseCase grava um laudo digitado pelo profissional.
+// Passa pela mesma interpretação estruturada e deduplicação do upload.
+type CreateLabReportManualUseCase interface {
+	Execute(ctx context.Context, input CreateLabReportManualInput) (*labsvc.LabReportOutput, error)
+}
+
+type createLabReportManualUseCase struct {
+	patientRepo  repository.Patient
+	labsRepo     repository.Labs
+	analytesRepo repository.Analytes
+	alerter      CriticalValueAlerter
+	calculator   DerivedValueCalculator
+}
+
+var _ CreateLabReportManualUseCase = (*createLabReportManualUseCase)(nil)
+
+func NewCreateLabReportManual(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	analytesRepo repository.Analytes,
+	alerter CriticalValueAlerter,
+	calculator DerivedValueCalculator,
+) CreateLabReportManualUseCase {
+	return &createLabReportManualUseCase{
+		patientRepo:  patientRepo,
+		labsRepo:     labsRepo,
+		analytesRepo: analytesRepo,
+		alerter:      alerter,
+		calculator:   calculator,
+	}
+}
+
+func (u *createLabReportManualUseCase) Execute(ctx context.Context, input CreateLabReportManualInput) (*labsvc.LabReportOutput, error) {
+	var violations []apperr.Violation
+	if input.PatientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if input.UploadedByUserID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "uploaded_by_user_id", Reason: "required"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	p, err := u.patientRepo.FindByID(ctx, input.PatientID)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	if p == nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "paciente não encontrado",
+		}
+	}
+
+	analytes, err := u.analytesRepo.List(ctx)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+
+	report, err := buildManualReport(input, p.Gender, labs.NewAnalyteCatalog(analytes))
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := generateLabFingerprint(input.PatientID, report)
+
+	exists, err := u.labsRepo.ExistsBySignature(ctx, input.PatientID, fingerprint)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	if exists {
+		return nil, &apperr.AppError{
+			Kind:    apperr.RESOURCE_ALREADY_EXISTS,
+			Message: "laudo já existe",
+		}
+	}
+
+	report.Fingerprint = &fingerprint
+	if err := u.labsRepo.Create(ctx, report); err != nil {
+		var appErr *apperr.AppError
+		if errors.As(err, &appErr) && appErr != nil {
+			return nil, appErr
+		}
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+
+	applyDerivedValues(ctx, u.calculator, report, p)
+	checkCriticalValues(ctx, u.alerter, report, p)
+
+	return labsvc.ToLabReportOutput(report), nil
+}
+
+// buildManualReport monta o laudo com os construtores do domínio e acumula
+// todas as violações, com o caminho do campo (ex.: tests[0].items[2].parameter_name),
+// para o usuário corrigir o formulário de uma vez.
+func buildManualReport(
+	input CreateLabReportManualInput,
+	patientGender demographics.Gender,
+	catalog *labs.AnalyteCatalog,
+) (*labs.LabReport, error) {
+	report, err := labs.NewLabReport(input.PatientID.String(), input.UploadedByUserID.String())
+	if err != nil {
+		return nil, mapLabDomainError(err)
+	}
+
+	report.Source = labs.SourceManual
+	report.LabName = input.LabName
+	report.RequestingDoctor = input.RequestingDoctor
+
+	var violations []apperr.Violation
+	invalidDate := func(field string) {
+		violations = append(violations, apperr.Violation{Field: field, Reason: "invalid_date"})
+	}
+
+	if raw := trimmed(input.ReportDate); raw != "" {
+		if t, err := parseDate(raw); err == nil {
+			report.ReportDate = &t
+		} else {
+			invalidDate("report_date")
+		}
+	}
+
+	if len(input.Tests) == 0 {
+		violations = append(violations, apperr.Violation{Field: "tests", Reason: "required"})
+	}
+
+	for i, in := range input.Tests {
+		field := fmt.Sprintf("tests[%d]", i)
+
+		testResult, err := labs.NewLabResult(report.ID.String(), in.TestName)
+		if err != nil {
+			violations = append(violations, apperr.Violation{Field: field + ".test_name", Reason: "required"})
+			continue
+		}
+		testResult.Material = in.Material
+		testResult.Method = in.Method
+
+		if raw := trimmed(in.CollectedAt); raw != "" {
+			if t, err := parseDateTime(raw); err == nil {
+				testResult.CollectedAt = &t
+			} else {
+				invalidDate(field + ".collected_at")
+			}
+		}
+		if raw := trimmed(in.ReleaseAt); raw != "" {
+			if t, err := parseDateTime(raw); err == nil {
+				testResult.ReleaseAt = &t
+			} else {
+				invalidDate(field + ".release_at")
+			}
+		}
+
+		if len(in.Items) == 0 {
+			violations = append(violations, apperr.Violation{Field: field + ".items", Reason: "required"})
+		}
+
+		for j, ii := range in.Items {
+			itemField := fmt.Sprintf("%s.items[%d]", field, j)
+
+			item, err := labs.NewLabResultItem(testResult.ID.String(), ii.ParameterName)
+			if err != nil {
+				violations = append(violations, apperr.Violation{Field: itemField + ".parameter_name", Reason: "required"})
+				continue
+			}
+			item.ResultValue = ii.ResultValue
+			item.ResultUnit = ii.ResultUnit
+			item.ReferenceText = ii.ReferenceText
+			item.Normalize()
+			if item.ResultValue == nil {
+				violations = append(violations, apperr.Violation{Field: itemField + ".result_value", Reason: "required"})
+				continue
+			}
+
+			item.ParseStructuredResult(patientGender)
+			item.LinkAnalyte(catalog)
+			testResult.Items = append(testResult.Items, *item)
+		}
+
+		testResult.Normalize()
+		report.TestResults = append(report.TestResults, *testResult)
+	}
+
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	// Sem data do laudo, usamos a primeira coleta (mesma regra do HL7 v2).
+	if report.ReportDate == nil {
+		for _, tr := range report.TestResults {
+			if tr.CollectedAt == nil {
+				continue
+			}
+			day := time.Date(tr.CollectedAt.Year(), tr.CollectedAt.Month(), tr.CollectedAt.Day(), 0, 0, 0, 0, time.UTC)
+			if report.ReportDate == nil || day.Before(*report.ReportDate) {
+				report.ReportDate = &day
+			}
+		}
+	}
+
+	report.Normalize()
+	report.UpdatedAt = time.Now().UTC()
+
+	return report, nil
+}
+
+func trimmed(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
@@ -0,0 +1,142 @@
+package labsuc
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patientaccess"
+	"github.com/gabrielgcmr/sonnda/internal/domain/notification"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	applog "github.com/gabrielgcmr/sonnda/internal/kernel/observability"
+)
+
+// CriticalValueAlerter confere um laudo recém-gravado (ou corrigido) contra as
+// regras de valores críticos e avisa os profissionais com acesso ativo ao
+// paciente. Cada item gera no máximo um alerta. Roda depois da gravação:
+// falhas são registradas no log e não desfazem o laudo.
+type CriticalValueAlerter interface {
+	Check(ctx context.Context, report *labs.LabReport, p *patient.Patient) []labs.CriticalAlert
+}
+
+type criticalValueAlerter struct {
+	alertsRepo repository.CriticalAlerts
+	accessRepo repository.PatientAccessRepo
+	notifier   notification.CriticalValueNotifier
+	now        func() time.Time
+}
+
+var _ CriticalValueAlerter = (*criticalValueAlerter)(nil)
+
+func NewCriticalValueAlerter(
+	alertsRepo repository.CriticalAlerts,
+	accessRepo repository.PatientAccessRepo,
+	notifier notification.CriticalValueNotifier,
+) CriticalValueAlerter {
+	return &criticalValueAlerter{
+		alertsRepo: alertsRepo,
+		accessRepo: accessRepo,
+		notifier:   notifier,
+		now:        time.Now,
+	}
+}
+
+func (a *criticalValueAlerter) Check(ctx context.Context, report *labs.LabReport, p *patient.Patient) []labs.CriticalAlert {
+	logger := applog.FromContext(ctx).With(slog.String("report_id", report.ID.String()))
+
+	rules, err := a.alertsRepo.ListRules(ctx)
+	if err != nil {
+		logger.Error("labs: falha ao carregar regras de valores críticos", slog.Any("error", err))
+		return nil
+	}
+
+	now := a.now().UTC()
+	alerts := labs.NewCriticalRuleSet(rules).EvaluateCritical(report, p.Gender, p.BirthDate, now)
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	// LL/HH no item vem só das regras, para bater com os alertas. Vale também
+	// para itens já alertados, cuja interpretação a correção recalculou.
+	if err := a.alertsRepo.FlagItems(ctx, alerts); err != nil {
+		logger.Error("labs: falha ao marcar itens com valor crítico", slog.Any("error", err))
+	} else {
+		report.FlagCritical(alerts)
+	}
+
+	alerted, err := a.alertsRepo.ListAlertedItemIDs(ctx, report.ID)
+	if err != nil {
+		logger.Error("labs: falha ao listar alertas do laudo", slog.Any("error", err))
+		return nil
+	}
+	alerts = slices.DeleteFunc(alerts, func(alert labs.CriticalAlert) bool {
+		return slices.Contains(alerted, alert.ItemID)
+	})
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	grantees, err := a.accessRepo.ListActiveGrantees(ctx, p.ID, patientaccess.RelationshipTypeProfessional)
+	if err != nil {
+		logger.Error("labs: falha ao listar profissionais do paciente", slog.Any("error", err))
+		return nil
+	}
+
+	recipients := make([]labs.AlertRecipient, 0, len(grantees))
+	for _, userID := range grantees {
+		recipients = append(recipients, labs.AlertRecipient{UserID: userID})
+	}
+	for i := range alerts {
+		alerts[i].Recipients = append([]labs.AlertRecipient(nil), recipients...)
+	}
+
+	if err := a.alertsRepo.CreateAlerts(ctx, alerts); err != nil {
+		logger.Error("labs: falha ao gravar alertas de valor crítico", slog.Any("error", err))
+		return nil
+	}
+	if len(grantees) == 0 {
+		logger.Warn("labs: valor crítico sem profissional com acesso ao paciente",
+			slog.Int("alerts", len(alerts)),
+		)
+		return alerts
+	}
+
+	for i := range alerts {
+		for j := range alerts[i].Recipients {
+			r := &alerts[i].Recipients[j]
+			delivered, err := a.notifier.NotifyCriticalValue(ctx, r.UserID, alerts[i])
+			if err != nil {
+				logger.Warn("labs: falha ao notificar valor crítico",
+					slog.String("alert_id", alerts[i].ID.String()),
+					slog.String("user_id", r.UserID.String()),
+					slog.Any("error", err),
+				)
+				continue
+			}
+			if !delivered {
+				// Sem canal de entrega: notified_at fica nulo e o alerta
+				// segue só na caixa de entrada.
+				continue
+			}
+			notifiedAt := a.now().UTC()
+			if err := a.alertsRepo.MarkNotified(ctx, alerts[i].ID, r.UserID, notifiedAt); err != nil {
+				logger.Warn("labs: falha ao marcar alerta como notificado", slog.Any("error", err))
+				continue
+			}
+			r.NotifiedAt = &notifiedAt
+		}
+	}
+	return alerts
+}
+
+// checkCriticalValues tolera alerter nil (ex.: testes e ferramentas que não
+// configuram as notificações).
+func checkCriticalValues(ctx context.Context, alerter CriticalValueAlerter, report *labs.LabReport, p *patient.Patient) {
+	if alerter == nil || p == nil {
+		return
+	}
+	alerter.Check(ctx, report, p)
+}
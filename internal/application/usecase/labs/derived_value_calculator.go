@@ -0,0 +1,60 @@
+package labsuc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	applog "github.com/gabrielgcmr/sonnda/internal/kernel/observability"
+)
+
+// DerivedValueCalculator calcula os valores derivados de um laudo gravado
+// (TFG pela CKD-EPI 2021, LDL por Friedewald e Martin-Hopkins, colesterol
+// não-HDL e HOMA-IR) com a idade e o sexo do paciente, e grava no lugar dos
+// anteriores. Roda depois da gravação: falhas são registradas no log e não
+// desfazem o laudo.
+type DerivedValueCalculator interface {
+	Apply(ctx context.Context, report *labs.LabReport, p *patient.Patient)
+}
+
+type derivedValueCalculator struct {
+	labsRepo repository.Labs
+	now      func() time.Time
+}
+
+var _ DerivedValueCalculator = (*derivedValueCalculator)(nil)
+
+func NewDerivedValueCalculator(labsRepo repository.Labs) DerivedValueCalculator {
+	return &derivedValueCalculator{
+		labsRepo: labsRepo,
+		now:      time.Now,
+	}
+}
+
+func (c *derivedValueCalculator) Apply(ctx context.Context, report *labs.LabReport, p *patient.Patient) {
+	result := labs.DeriveValues(report, p.Gender, p.BirthDate, c.now().UTC())
+	if !report.DerivedChanged(result) {
+		return
+	}
+
+	if err := c.labsRepo.ReplaceDerived(ctx, report.ID, result); err != nil {
+		applog.FromContext(ctx).Error("labs: falha ao gravar valores derivados",
+			slog.String("report_id", report.ID.String()),
+			slog.Any("error", err),
+		)
+		return
+	}
+	report.SetDerived(result)
+}
+
+// applyDerivedValues tolera calculator nil (ex.: testes e ferramentas que
+// não calculam valores derivados).
+func applyDerivedValues(ctx context.Context, calculator DerivedValueCalculator, report *labs.LabReport, p *patient.Patient) {
+	if calculator == nil || p == nil {
+		return
+	}
+	calculator.Apply(ctx, report, p)
+}
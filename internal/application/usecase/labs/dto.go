@@ -1,10 +1,112 @@
 package labsuc
 
-import "github.com/google/uuid"
+import (
+	"context"
+
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/diagnostics"
+
+	"github.com/google/uuid"
+)
 
 type CreateLabReportFromDocumentInput struct {
-	PatientID        uuid.UUID
+	PatientID uuid.UUID
+	// URI devolvida pelo storage: gs://, s3:// ou file://.
 	DocumentURI      string
 	MimeType         string
 	UploadedByUserID uuid.UUID
+	// ContentHash é o SHA-256 (hex) do arquivo; vazio quando desconhecido.
+	ContentHash string
+	// IdentityOverride aceita o laudo mesmo que nome, nascimento ou CPF
+	// impressos não confiram com o paciente. Quem chama já checou a permissão.
+	IdentityOverride bool
+}
+
+type CreateLabReportFromFHIRInput struct {
+	PatientID        uuid.UUID
+	UploadedByUserID uuid.UUID
+	// Bundle transaction (R5 ou R4) com DiagnosticReport e Observations.
+	Bundle *diagnostics.Bundle
+}
+
+// CreateLabReportManualInput é um laudo digitado pelo profissional, sem arquivo
+// (resultado recebido por telefone, lido de outro sistema etc.).
+type CreateLabReportManualInput struct {
+	PatientID        uuid.UUID
+	UploadedByUserID uuid.UUID
+	LabName          *string
+	RequestingDoctor *string
+	// Datas aceitam ISO (2006-01-02, RFC 3339) ou o formato brasileiro.
+	ReportDate *string
+	Tests      []ManualLabTestInput
+}
+
+type ManualLabTestInput struct {
+	TestName    string
+	Material    *string
+	Method      *string
+	CollectedAt *string
+	ReleaseAt   *string
+	Items       []ManualLabItemInput
+}
+
+type ManualLabItemInput struct {
+	ParameterName string
+	ResultValue   *string
+	ResultUnit    *string
+	ReferenceText *string
+}
+
+type CreateLabReportFromHL7Input struct {
+	// Mensagem ORU^R01 em ER7 (sem o envelope MLLP).
+	Message          []byte
+	UploadedByUserID uuid.UUID
+	// Authorize, quando informado, recebe o paciente encontrado no PID antes
+	// de gravar (a rota HTTP só conhece o paciente depois de ler a mensagem).
+	Authorize func(ctx context.Context, patientID uuid.UUID) error
+}
+
+type CreateLabReportFromHL7Output struct {
+	// ACK sempre vem preenchido, inclusive quando Execute devolve erro.
+	ACK    string
+	Report *labsvc.LabReportOutput
+}
+
+// AmendLabReportInput corrige campos de um laudo já gravado. Version é a
+// versão lida pelo cliente; se outra correção tiver sido gravada antes, a
+// requisição é recusada com conflito.
+type AmendLabReportInput struct {
+	PatientID       uuid.UUID
+	ReportID        uuid.UUID
+	ChangedByUserID uuid.UUID
+	Version         *int
+	// Status opcional (preliminary, final, amended, corrected). Vazio: laudo
+	// preliminar continua preliminar e os demais passam a corrected.
+	Status  *string
+	Reason  *string
+	Changes []LabFieldEditInput
+}
+
+// LabFieldEditInput altera um campo do laudo (target report), de um exame
+// (result) ou de um parâmetro (item); Value nil apaga o campo.
+type LabFieldEditInput struct {
+	Target   string
+	TargetID *string
+	Field    string
+	Value    *string
+}
+
+// ReviewLabReportInput confirma ou corrige os valores de baixa confiança de
+// um laudo em revisão. Itens corrigidos contam como confirmados; Approve
+// confirma os pendentes e libera o laudo como final.
+type ReviewLabReportInput struct {
+	PatientID        uuid.UUID
+	ReportID         uuid.UUID
+	ReviewedByUserID uuid.UUID
+	Version          *int
+	Changes          []LabFieldEditInput
+	// Confirm lista os IDs dos itens conferidos sem alteração.
+	Confirm []string
+	Approve bool
+	Reason  *string
 }
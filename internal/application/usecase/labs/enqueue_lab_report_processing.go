@@ -0,0 +1,86 @@
+package labsuc
+
+import (
+	"context"
+
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+// EnqueueLabReportProcessingUseCase registra o documento enviado para
+// extração assíncrona. O processamento em si roda no LabJobWorker.
+type EnqueueLabReportProcessingUseCase interface {
+	Execute(ctx context.Context, input CreateLabReportFromDocumentInput) (*labsvc.ProcessingJobOutput, error)
+}
+
+type enqueueLabReportProcessingUseCase struct {
+	patientRepo repository.Patient
+	labsRepo    repository.Labs
+	jobsRepo    repository.LabJobs
+}
+
+var _ EnqueueLabReportProcessingUseCase = (*enqueueLabReportProcessingUseCase)(nil)
+
+func NewEnqueueLabReportProcessing(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	jobsRepo repository.LabJobs,
+) EnqueueLabReportProcessingUseCase {
+	return &enqueueLabReportProcessingUseCase{
+		patientRepo: patientRepo,
+		labsRepo:    labsRepo,
+		jobsRepo:    jobsRepo,
+	}
+}
+
+func (u *enqueueLabReportProcessingUseCase) Execute(ctx context.Context, input CreateLabReportFromDocumentInput) (*labsvc.ProcessingJobOutput, error) {
+	if err := validateDocumentInput(input); err != nil {
+		return nil, err
+	}
+
+	p, err := u.patientRepo.FindByID(ctx, input.PatientID)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	if p == nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "paciente não encontrado",
+		}
+	}
+
+	if err := ensureNewDocument(ctx, u.labsRepo, input.PatientID, input.ContentHash); err != nil {
+		return nil, err
+	}
+
+	job, err := labs.NewProcessingJob(
+		input.PatientID,
+		input.UploadedByUserID,
+		input.DocumentURI,
+		normalizeMimeType(input.MimeType),
+	)
+	if err != nil {
+		return nil, mapLabDomainError(err)
+	}
+	if input.ContentHash != "" {
+		contentHash := input.ContentHash
+		job.ContentHash = &contentHash
+	}
+	job.IdentityOverride = input.IdentityOverride
+
+	if err := u.jobsRepo.Create(ctx, job); err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+
+	return labsvc.ToProcessingJobOutput(job), nil
+}
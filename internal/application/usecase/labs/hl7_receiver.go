@@ -0,0 +1,51 @@
+package labsuc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+	applog "github.com/gabrielgcmr/sonnda/internal/kernel/observability"
+
+	"github.com/google/uuid"
+)
+
+// HL7Receiver atende o listener MLLP: cada mensagem vira um laudo gravado em
+// nome de uploadedBy (o usuário de integração configurado) e recebe um ACK.
+type HL7Receiver struct {
+	createUC   CreateLabReportFromHL7UseCase
+	uploadedBy uuid.UUID
+}
+
+func NewHL7Receiver(createUC CreateLabReportFromHL7UseCase, uploadedBy uuid.UUID) *HL7Receiver {
+	return &HL7Receiver{createUC: createUC, uploadedBy: uploadedBy}
+}
+
+// HandleMessage implementa mllp.Handler.
+func (r *HL7Receiver) HandleMessage(ctx context.Context, message []byte) []byte {
+	logger := applog.FromContext(ctx)
+
+	out, err := r.createUC.Execute(ctx, CreateLabReportFromHL7Input{
+		Message:          message,
+		UploadedByUserID: r.uploadedBy,
+	})
+
+	var appErr *apperr.AppError
+	switch {
+	case err == nil:
+		logger.Info("hl7: laudo recebido",
+			slog.String("report_id", out.Report.ID.String()),
+			slog.String("patient_id", out.Report.PatientID.String()),
+		)
+	case errors.As(err, &appErr) && appErr.Kind == apperr.RESOURCE_ALREADY_EXISTS:
+		logger.Info("hl7: laudo já recebido anteriormente")
+	default:
+		logger.Warn("hl7: mensagem não gravada", slog.Any("error", err))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return []byte(out.ACK)
+}
@@ -0,0 +1,256 @@
+package labsuc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+	applog "github.com/gabrielgcmr/sonnda/internal/kernel/observability"
+
+	"github.com/google/uuid"
+)
+
+type LabJobWorkerConfig struct {
+	// Quantidade de jobs processados em paralelo.
+	Concurrency int
+	// Intervalo entre consultas quando a fila está vazia.
+	PollInterval time.Duration
+	// Tempo máximo de um job (inclui a chamada ao extractor).
+	JobTimeout time.Duration
+	// Intervalo entre varreduras de jobs presos em running.
+	RequeueInterval time.Duration
+	// Tentativas antes de um job preso ser encerrado como failed em vez de
+	// voltar para a fila.
+	MaxAttempts int
+}
+
+// staleJobGrace é a folga, além de JobTimeout, antes de um job em running ser
+// considerado preso: cobre o cancelamento do extractor e a gravação do
+// resultado, que ainda pertencem à execução original.
+const staleJobGrace = time.Minute
+
+// LabJobWorker consome a fila lab_processing_jobs e executa a extração
+// através do CreateLabReportFromDocumentUseCase.
+type LabJobWorker struct {
+	jobsRepo repository.LabJobs
+	createUC CreateLabReportFromDocumentUseCase
+	cfg      LabJobWorkerConfig
+}
+
+func NewLabJobWorker(
+	jobsRepo repository.LabJobs,
+	createUC CreateLabReportFromDocumentUseCase,
+	cfg LabJobWorkerConfig,
+) *LabJobWorker {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.JobTimeout <= 0 {
+		cfg.JobTimeout = 5 * time.Minute
+	}
+	if cfg.RequeueInterval <= 0 {
+		cfg.RequeueInterval = time.Minute
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+
+	return &LabJobWorker{
+		jobsRepo: jobsRepo,
+		createUC: createUC,
+		cfg:      cfg,
+	}
+}
+
+// Run bloqueia até ctx ser cancelado. A cada RequeueInterval, jobs que
+// ficaram em running por mais tempo que JobTimeout mais staleJobGrace (ex.:
+// processo ou réplica derrubada) voltam para a fila, ou falham depois de
+// MaxAttempts tentativas.
+func (w *LabJobWorker) Run(ctx context.Context) {
+	log := applog.FromContext(ctx).With(slog.String("component", "lab_job_worker"))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.requeueLoop(ctx, log)
+	}()
+	for i := 0; i < w.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func(slot int) {
+			defer wg.Done()
+			w.loop(ctx, log.With(slog.Int("slot", slot)))
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (w *LabJobWorker) requeueLoop(ctx context.Context, log *slog.Logger) {
+	ticker := time.NewTicker(w.cfg.RequeueInterval)
+	defer ticker.Stop()
+
+	for {
+		w.requeueStale(ctx, log)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *LabJobWorker) requeueStale(ctx context.Context, log *slog.Logger) {
+	startedBefore := time.Now().UTC().Add(-(w.cfg.JobTimeout + staleJobGrace))
+
+	failed, err := w.jobsRepo.FailStale(ctx, startedBefore, w.cfg.MaxAttempts,
+		string(apperr.INTERNAL_ERROR), "processamento interrompido após várias tentativas")
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error("lab_job_requeue_failed", slog.Any("err", err))
+		}
+		return
+	}
+	if failed > 0 {
+		log.Warn("lab_jobs_exhausted", slog.Int64("count", failed))
+	}
+
+	requeued, err := w.jobsRepo.RequeueStale(ctx, startedBefore, w.cfg.MaxAttempts)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error("lab_job_requeue_failed", slog.Any("err", err))
+		}
+		return
+	}
+	if requeued > 0 {
+		log.Warn("lab_jobs_requeued", slog.Int64("count", requeued))
+	}
+}
+
+func (w *LabJobWorker) loop(ctx context.Context, log *slog.Logger) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		processed, err := w.ProcessNext(ctx)
+		if err != nil {
+			log.Error("lab_job_poll_failed", slog.Any("err", err))
+		}
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.cfg.PollInterval):
+		}
+	}
+}
+
+// ProcessNext processa um único job da fila. Retorna false quando não havia job.
+func (w *LabJobWorker) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := w.jobsRepo.ClaimNext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("claim next lab job: %w", err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	log := applog.FromContext(ctx).With(
+		slog.String("job_id", job.ID.String()),
+		slog.String("patient_id", job.PatientID.String()),
+	)
+
+	reportID, runErr := w.run(ctx, job)
+
+	// O resultado é gravado mesmo que ctx tenha sido cancelado no meio do job.
+	persistCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if runErr != nil {
+		code, message := jobErrorDetails(runErr)
+		log.Warn("lab_job_failed", slog.String("error_code", code), slog.Any("err", runErr))
+		owned, err := w.jobsRepo.MarkFailed(persistCtx, job.ID, job.Attempts, code, message)
+		if err != nil {
+			return true, fmt.Errorf("mark lab job %s failed: %w", job.ID, err)
+		}
+		if !owned {
+			log.Warn("lab_job_ownership_lost", slog.Int("attempt", job.Attempts))
+		}
+		return true, nil
+	}
+
+	owned, err := w.jobsRepo.MarkSucceeded(persistCtx, job.ID, job.Attempts, reportID)
+	if err != nil {
+		return true, fmt.Errorf("mark lab job %s succeeded: %w", job.ID, err)
+	}
+	if !owned {
+		// Outra execução assumiu o job; o laudo gravado aqui fica, e a outra
+		// execução esbarra no content_sha256 repetido.
+		log.Warn("lab_job_ownership_lost", slog.Int("attempt", job.Attempts),
+			slog.String("lab_report_id", reportID.String()))
+		return true, nil
+	}
+	log.Info("lab_job_succeeded", slog.String("lab_report_id", reportID.String()))
+	return true, nil
+}
+
+func (w *LabJobWorker) run(ctx context.Context, job *labs.ProcessingJob) (reportID uuid.UUID, err error) {
+	jobCtx, cancel := context.WithTimeout(ctx, w.cfg.JobTimeout)
+	defer cancel()
+
+	if event := applog.CapturePanic(func() {
+		out, execErr := w.createUC.Execute(jobCtx, CreateLabReportFromDocumentInput{
+			PatientID:        job.PatientID,
+			DocumentURI:      job.DocumentURI,
+			MimeType:         job.MimeType,
+			UploadedByUserID: job.UploadedBy,
+			ContentHash:      jobContentHash(job),
+			IdentityOverride: job.IdentityOverride,
+		})
+		if execErr != nil {
+			err = execErr
+			return
+		}
+		reportID = out.ID
+	}); event != nil {
+		return reportID, apperr.Internal("erro inesperado", fmt.Errorf("panic: %v", event.Value))
+	}
+
+	if err != nil && errors.Is(jobCtx.Err(), context.DeadlineExceeded) {
+		return reportID, &apperr.AppError{
+			Kind:    apperr.INFRA_TIMEOUT,
+			Message: "tempo limite excedido ao processar documento",
+			Cause:   err,
+		}
+	}
+	return reportID, err
+}
+
+// jobErrorDetails extrai o código estável e a mensagem pública do erro.
+func jobErrorDetails(err error) (string, string) {
+	var appErr *apperr.AppError
+	if errors.As(err, &appErr) && appErr != nil {
+		return string(appErr.Kind), appErr.Message
+	}
+	return string(apperr.INTERNAL_ERROR), "erro inesperado"
+}
+
+func jobContentHash(job *labs.ProcessingJob) string {
+	if job.ContentHash == nil {
+		return ""
+	}
+	return *job.ContentHash
+}
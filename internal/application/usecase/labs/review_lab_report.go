@@ -0,0 +1,155 @@
+package labsuc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	labsvc "github.com/gabrielgcmr/sonnda/internal/application/services/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/google/uuid"
+)
+
+// ReviewLabReportUseCase registra a revisão de um laudo extraído com baixa
+// confiança: confirmações e correções viram uma revisão do histórico, e a
+// aprovação libera o laudo como final.
+type ReviewLabReportUseCase interface {
+	Execute(ctx context.Context, input ReviewLabReportInput) (*labsvc.LabReportOutput, error)
+}
+
+type reviewLabReportUseCase struct {
+	patientRepo  repository.Patient
+	labsRepo     repository.Labs
+	analytesRepo repository.Analytes
+	alerter      CriticalValueAlerter
+	calculator   DerivedValueCalculator
+}
+
+var _ ReviewLabReportUseCase = (*reviewLabReportUseCase)(nil)
+
+func NewReviewLabReport(
+	patientRepo repository.Patient,
+	labsRepo repository.Labs,
+	analytesRepo repository.Analytes,
+	alerter CriticalValueAlerter,
+	calculator DerivedValueCalculator,
+) ReviewLabReportUseCase {
+	return &reviewLabReportUseCase{
+		patientRepo:  patientRepo,
+		labsRepo:     labsRepo,
+		analytesRepo: analytesRepo,
+		alerter:      alerter,
+		calculator:   calculator,
+	}
+}
+
+func (u *reviewLabReportUseCase) Execute(ctx context.Context, input ReviewLabReportInput) (*labsvc.LabReportOutput, error) {
+	edits, violations := parseFieldEdits(input.Changes)
+	confirm := make([]uuid.UUID, 0, len(input.Confirm))
+	for i, raw := range input.Confirm {
+		id, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			violations = append(violations, apperr.Violation{Field: fmt.Sprintf("confirm[%d]", i), Reason: "invalid"})
+			continue
+		}
+		confirm = append(confirm, id)
+	}
+	if input.PatientID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "patient_id", Reason: "required"})
+	}
+	if input.ReportID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "report_id", Reason: "required"})
+	}
+	if input.ReviewedByUserID == uuid.Nil {
+		violations = append(violations, apperr.Violation{Field: "reviewed_by_user_id", Reason: "required"})
+	}
+	if input.Version == nil {
+		violations = append(violations, apperr.Violation{Field: "version", Reason: "required"})
+	}
+	if len(violations) > 0 {
+		return nil, apperr.Validation("entrada inválida", violations...)
+	}
+
+	report, err := u.labsRepo.FindByID(ctx, input.ReportID)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	// Laudo de outro paciente é tratado como inexistente.
+	if report == nil || report.PatientID != input.PatientID {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "laudo não encontrado",
+		}
+	}
+	if report.Version != *input.Version {
+		return nil, versionConflict(nil)
+	}
+
+	p, err := u.patientRepo.FindByID(ctx, input.PatientID)
+	if err != nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+	if p == nil {
+		return nil, &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "paciente não encontrado",
+		}
+	}
+
+	revision, err := report.Review(edits, confirm, input.Approve, input.ReviewedByUserID, input.Reason, time.Now())
+	if err != nil {
+		return nil, mapReviewError(err)
+	}
+
+	if err := reparseChangedItems(ctx, u.analytesRepo, report, revision, p.Gender); err != nil {
+		return nil, err
+	}
+
+	if err := u.labsRepo.Amend(ctx, report, revision); err != nil {
+		if errors.Is(err, labs.ErrVersionConflict) {
+			return nil, versionConflict(err)
+		}
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_DATABASE_ERROR,
+			Message: "falha técnica",
+			Cause:   err,
+		}
+	}
+
+	// Valores corrigidos mudam os cálculos que dependem deles e podem passar
+	// de um limite crítico; itens que já geraram alerta não geram outro.
+	applyDerivedValues(ctx, u.calculator, report, p)
+	checkCriticalValues(ctx, u.alerter, report, p)
+
+	return labsvc.ToLabReportOutput(report), nil
+}
+
+func mapReviewError(err error) error {
+	var editErr *labs.FieldEditError
+	switch {
+	case errors.Is(err, labs.ErrNotInReview):
+		return apperr.DomainRuleViolation("o laudo não está aguardando revisão")
+	case errors.Is(err, labs.ErrNoChanges):
+		return apperr.Validation("nenhuma alteração na revisão",
+			apperr.Violation{Field: "confirm", Reason: "required"})
+	// Erros fora das alterações vêm da lista de confirmação.
+	case errors.Is(err, labs.ErrEditTargetNotFound) && !errors.As(err, &editErr):
+		return apperr.Validation("entrada inválida",
+			apperr.Violation{Field: "confirm", Reason: "invalid"})
+	default:
+		return mapAmendError(err)
+	}
+}
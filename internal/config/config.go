@@ -2,10 +2,13 @@
 package config
 
 type Config struct {
-	App      AppConfig
-	HTTP     HTTPConfig
-	Database DatabaseConfig
-	Auth     AuthConfig
-	Storage  StorageConfig
-	CORS     CORSConfig
+	App       AppConfig
+	HTTP      HTTPConfig
+	Database  DatabaseConfig
+	Auth      AuthConfig
+	Storage   StorageConfig
+	Extractor ExtractorConfig
+	CORS      CORSConfig
+	Worker    WorkerConfig
+	HL7       HL7Config
 }
@@ -0,0 +1,58 @@
+// internal/config/extractor.go
+package config
+
+import "strings"
+
+const (
+	envLabExtractor      = "LAB_EXTRACTOR"
+	envDocAIFixturesMode = "DOCAI_FIXTURES_MODE"
+	envDocAIFixturesDir  = "DOCAI_FIXTURES_DIR"
+)
+
+const (
+	// Camada de texto do PDF localmente, com Document AI para escaneados.
+	LabExtractorAuto = "auto"
+	// Sempre Document AI.
+	LabExtractorDocumentAI = "documentai"
+	// Apenas camada de texto; não usa GCP (PDFs escaneados falham).
+	LabExtractorPDFText = "pdftext"
+)
+
+var allowedLabExtractors = map[string]struct{}{
+	LabExtractorAuto:       {},
+	LabExtractorDocumentAI: {},
+	LabExtractorPDFText:    {},
+}
+
+const (
+	// Respostas gravadas do Document AI no lugar da API (testes e demos).
+	DocAIFixturesReplay = "replay"
+	// Chama o Document AI e grava cada resposta como fixture.
+	DocAIFixturesRecord = "record"
+)
+
+var allowedDocAIFixturesModes = map[string]struct{}{
+	DocAIFixturesReplay: {},
+	DocAIFixturesRecord: {},
+}
+
+type ExtractorConfig struct {
+	Driver string
+
+	// Vazio desliga as fixtures.
+	FixturesMode string
+	FixturesDir  string
+}
+
+// UsesDocumentAI indica se o Document AI precisa ser configurado.
+func (c ExtractorConfig) UsesDocumentAI() bool {
+	return c.Driver != LabExtractorPDFText && c.FixturesMode != DocAIFixturesReplay
+}
+
+func loadExtractorConfig() ExtractorConfig {
+	return ExtractorConfig{
+		Driver:       strings.ToLower(getEnvOrDefault(envLabExtractor, LabExtractorAuto)),
+		FixturesMode: strings.ToLower(getEnv(envDocAIFixturesMode)),
+		FixturesDir:  getEnvOrDefault(envDocAIFixturesDir, "./data/docai-fixtures"),
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	envHL7MLLPAddr         = "HL7_MLLP_ADDR"
+	envHL7MLLPUploaderID   = "HL7_MLLP_UPLOADER_USER_ID"
+	envHL7MLLPReadTimeout  = "HL7_MLLP_READ_TIMEOUT"
+	envHL7MLLPMaxMsgSizeKB = "HL7_MLLP_MAX_MESSAGE_KB"
+	envHL7MLLPAllowedCIDRs = "HL7_MLLP_ALLOWED_CIDRS"
+	envHL7MLLPMaxConns     = "HL7_MLLP_MAX_CONNECTIONS"
+)
+
+// HL7Config controla o listener MLLP (HL7 v2) dos sistemas de laboratório.
+type HL7Config struct {
+	// Endereço TCP (ex.: ":2575"); vazio desliga o listener.
+	MLLPAddr string
+	// Usuário gravado como uploaded_by dos laudos recebidos por MLLP.
+	MLLPUploaderUserID string
+	// Tempo máximo sem receber dados numa conexão aberta.
+	MLLPReadTimeout  time.Duration
+	MLLPMaxMessageKB int
+	// Redes (CIDR, separadas por vírgula) autorizadas a conectar; obrigatório
+	// com o listener ligado.
+	MLLPAllowedCIDRs []string
+	// Conexões simultâneas; as excedentes são recusadas.
+	MLLPMaxConnections int
+}
+
+func (c HL7Config) MLLPEnabled() bool {
+	return c.MLLPAddr != ""
+}
+
+func loadHL7Config() HL7Config {
+	return HL7Config{
+		MLLPAddr:           getEnv(envHL7MLLPAddr),
+		MLLPUploaderUserID: getEnv(envHL7MLLPUploaderID),
+		MLLPReadTimeout:    getEnvDurationOrDefault(envHL7MLLPReadTimeout, 5*time.Minute),
+		MLLPMaxMessageKB:   getEnvIntOrDefault(envHL7MLLPMaxMsgSizeKB, 1024),
+		MLLPAllowedCIDRs:   parseCommaSeparatedList(getEnv(envHL7MLLPAllowedCIDRs)),
+		MLLPMaxConnections: getEnvIntOrDefault(envHL7MLLPMaxConns, 16),
+	}
+}
+
+// MLLPAllowedPrefixes converte MLLPAllowedCIDRs; um IP sem máscara vale como
+// /32 (ou /128).
+func (c HL7Config) MLLPAllowedPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.MLLPAllowedCIDRs))
+	for _, v := range c.MLLPAllowedCIDRs {
+		if addr, err := netip.ParseAddr(v); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func isUUID(v string) bool {
+	_, err := uuid.Parse(v)
+	return err == nil
+}
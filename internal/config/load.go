@@ -12,34 +12,76 @@ func Load() (*Config, error) {
 	_ = godotenv.Load()
 
 	appCfg := loadAppConfig()
+	httpCfg := loadHTTPConfig()
 
 	cfg := &Config{
-		App:      appCfg,
-		HTTP:     loadHTTPConfig(),
-		Database: loadDatabaseConfig(),
-		Auth:     loadAuthConfig(),
-		Storage:  loadStorageConfig(),
-		CORS:     loadCORSConfig(appCfg.Env),
+		App:       appCfg,
+		HTTP:      httpCfg,
+		Database:  loadDatabaseConfig(),
+		Auth:      loadAuthConfig(),
+		Storage:   loadStorageConfig(httpCfg),
+		Extractor: loadExtractorConfig(),
+		CORS:      loadCORSConfig(appCfg.Env),
+		Worker:    loadWorkerConfig(),
+		HL7:       loadHL7Config(),
 	}
 
 	var violations []apperr.Violation
 
 	appendRequired(&violations, envDatabaseURL, cfg.Database.URL)
 	appendRequired(&violations, envSupabaseProjectURL, cfg.Auth.SupabaseProjectURL)
-	appendRequired(&violations, envGCPProjectID, cfg.Storage.GCPProjectID)
-	appendRequired(&violations, envGCSBucket, cfg.Storage.GCSBucket)
-	appendRequired(&violations, envGCPLocation, cfg.Storage.GCPLocation)
-	appendRequired(&violations, envGCPExtractLabsProcessorID, cfg.Storage.GCPExtractLabsProcessorID)
-	// Exigir pelo menos uma forma de credenciais do Google Cloud
-	if cfg.Storage.GoogleApplicationCredentials == "" && cfg.Storage.GoogleApplicationCredentialsJSON == "" {
-		violations = append(violations, apperr.Violation{
-			Field:  "GOOGLE_CREDENTIALS",
-			Reason: "either GOOGLE_APPLICATION_CREDENTIALS or GOOGLE_APPLICATION_CREDENTIALS_JSON is required",
-		})
+	validateEnum(&violations, envStorageDriver, cfg.Storage.Driver, allowedStorageDrivers)
+	validateEnum(&violations, envLabExtractor, cfg.Extractor.Driver, allowedLabExtractors)
+	validateEnum(&violations, envDocAIFixturesMode, cfg.Extractor.FixturesMode, allowedDocAIFixturesModes)
+	switch cfg.Storage.Driver {
+	case StorageDriverGCS:
+		appendRequired(&violations, envGCSBucket, cfg.Storage.GCSBucket)
+	case StorageDriverLocal:
+		appendRequired(&violations, envLocalStorageDir, cfg.Storage.LocalDir)
+		appendRequired(&violations, envLocalStorageSigningKey, cfg.Storage.LocalSigningKey)
+	case StorageDriverS3:
+		appendRequired(&violations, envS3Bucket, cfg.Storage.S3Bucket)
+		appendRequired(&violations, envS3AccessKeyID, cfg.Storage.S3AccessKeyID)
+		appendRequired(&violations, envS3SecretAccessKey, cfg.Storage.S3SecretAccessKey)
+	}
+	// GCP só é obrigatório quando usado (GCS ou Document AI).
+	if cfg.Storage.Driver == StorageDriverGCS || cfg.Extractor.UsesDocumentAI() {
+		appendRequired(&violations, envGCPProjectID, cfg.Storage.GCPProjectID)
+		// Exigir pelo menos uma forma de credenciais do Google Cloud
+		if cfg.Storage.GoogleApplicationCredentials == "" && cfg.Storage.GoogleApplicationCredentialsJSON == "" {
+			violations = append(violations, apperr.Violation{
+				Field:  "GOOGLE_CREDENTIALS",
+				Reason: "either GOOGLE_APPLICATION_CREDENTIALS or GOOGLE_APPLICATION_CREDENTIALS_JSON is required",
+			})
+		}
+	}
+	if cfg.Extractor.UsesDocumentAI() {
+		appendRequired(&violations, envGCPLocation, cfg.Storage.GCPLocation)
+		appendRequired(&violations, envGCPExtractLabsProcessorID, cfg.Storage.GCPExtractLabsProcessorID)
 	}
 	validateEnum(&violations, envAppEnv, cfg.App.Env, allowedEnvs)
 	validateEnum(&violations, envLogLevel, cfg.App.LogLevel, allowedLogLevels)
 	validateEnum(&violations, envLogFormat, cfg.App.LogFormat, allowedLogFormats)
+	if cfg.Worker.LabConcurrency <= 0 {
+		violations = append(violations, apperr.Violation{Field: envLabWorkerConcurrency, Reason: "must be > 0"})
+	}
+	if cfg.HL7.MLLPEnabled() {
+		appendRequired(&violations, envHL7MLLPUploaderID, cfg.HL7.MLLPUploaderUserID)
+		if cfg.HL7.MLLPUploaderUserID != "" && !isUUID(cfg.HL7.MLLPUploaderUserID) {
+			violations = append(violations, apperr.Violation{Field: envHL7MLLPUploaderID, Reason: "must be a uuid"})
+		}
+		if cfg.HL7.MLLPMaxMessageKB <= 0 {
+			violations = append(violations, apperr.Violation{Field: envHL7MLLPMaxMsgSizeKB, Reason: "must be > 0"})
+		}
+		if cfg.HL7.MLLPMaxConnections <= 0 {
+			violations = append(violations, apperr.Violation{Field: envHL7MLLPMaxConns, Reason: "must be > 0"})
+		}
+		if len(cfg.HL7.MLLPAllowedCIDRs) == 0 {
+			violations = append(violations, apperr.Violation{Field: envHL7MLLPAllowedCIDRs, Reason: "required"})
+		} else if _, err := cfg.HL7.MLLPAllowedPrefixes(); err != nil {
+			violations = append(violations, apperr.Violation{Field: envHL7MLLPAllowedCIDRs, Reason: "must be a list of CIDRs"})
+		}
+	}
 
 	if len(violations) > 0 {
 		return nil, apperr.Validation("invalid configuration", violations...)
@@ -1,6 +1,8 @@
 // internal/config/storage.go
 package config
 
+import "strings"
+
 const (
 	envGoogleApplicationCredentials     = "GOOGLE_APPLICATION_CREDENTIALS"
 	envGoogleApplicationCredentialsJSON = "GOOGLE_APPLICATION_CREDENTIALS_JSON"
@@ -9,8 +11,27 @@ const (
 	envGCSBucket                        = "GCS_BUCKET"
 	envGCPLocation                      = "GCP_LOCATION"
 	envGCPExtractLabsProcessorID        = "GCP_EXTRACT_LABS_PROCESSOR_ID"
+	envStorageDriver                    = "STORAGE_DRIVER"
+	envLocalStorageDir                  = "LOCAL_STORAGE_DIR"
+	envLocalStoragePublicURL            = "LOCAL_STORAGE_PUBLIC_URL"
+	envLocalStorageSigningKey           = "LOCAL_STORAGE_SIGNING_KEY"
+	envS3Endpoint                       = "S3_ENDPOINT"
+	envS3Region                         = "S3_REGION"
+	envS3Bucket                         = "S3_BUCKET"
+	envS3AccessKeyID                    = "S3_ACCESS_KEY_ID"
+	envS3SecretAccessKey                = "S3_SECRET_ACCESS_KEY"
+	envS3UseSSL                         = "S3_USE_SSL"
+	envS3ForcePathStyle                 = "S3_FORCE_PATH_STYLE"
 )
 
+const (
+	StorageDriverGCS   = "gcs"
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+var allowedStorageDrivers = map[string]struct{}{StorageDriverGCS: {}, StorageDriverLocal: {}, StorageDriverS3: {}}
+
 type StorageConfig struct {
 	GoogleApplicationCredentials     string
 	GoogleApplicationCredentialsJSON string
@@ -19,9 +40,27 @@ type StorageConfig struct {
 	GCSBucket                        string
 	GCPLocation                      string
 	GCPExtractLabsProcessorID        string
+
+	// Driver escolhe a implementação de FileStorageService: "gcs" (padrão), "local" ou "s3".
+	Driver string
+	// Diretório raiz dos arquivos quando Driver = "local".
+	LocalDir string
+	// URL base usada nos links assinados do driver local (ex.: http://localhost:8080).
+	LocalPublicURL string
+	// Chave HMAC dos links assinados do driver local.
+	LocalSigningKey string
+
+	// S3 ou compatível (MinIO) quando Driver = "s3".
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UseSSL          bool
+	S3ForcePathStyle  bool
 }
 
-func loadStorageConfig() StorageConfig {
+func loadStorageConfig(httpCfg HTTPConfig) StorageConfig {
 	return StorageConfig{
 		GoogleApplicationCredentials:     getEnv(envGoogleApplicationCredentials),
 		GoogleApplicationCredentialsJSON: getEnv(envGoogleApplicationCredentialsJSON),
@@ -30,5 +69,20 @@ func loadStorageConfig() StorageConfig {
 		GCSBucket:                        getEnv(envGCSBucket),
 		GCPLocation:                      getEnv(envGCPLocation),
 		GCPExtractLabsProcessorID:        getEnv(envGCPExtractLabsProcessorID),
+		Driver:                           strings.ToLower(getEnvOrDefault(envStorageDriver, StorageDriverGCS)),
+		LocalDir:                         getEnvOrDefault(envLocalStorageDir, "./data/storage"),
+		LocalPublicURL:                   getEnvOrDefault(envLocalStoragePublicURL, "http://localhost:"+httpCfg.Port),
+		LocalSigningKey:                  getEnv(envLocalStorageSigningKey),
+		S3Endpoint:                       getEnvOrDefault(envS3Endpoint, "s3.amazonaws.com"),
+		S3Region:                         getEnv(envS3Region),
+		S3Bucket:                         getEnv(envS3Bucket),
+		S3AccessKeyID:                    getEnv(envS3AccessKeyID),
+		S3SecretAccessKey:                getEnv(envS3SecretAccessKey),
+		S3UseSSL:                         isTruthy(getEnvOrDefault(envS3UseSSL, "true")),
+		S3ForcePathStyle:                 isTruthy(getEnvOrDefault(envS3ForcePathStyle, "false")),
 	}
 }
+
+func isTruthy(v string) bool {
+	return v != "false" && v != "0"
+}
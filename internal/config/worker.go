@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	envLabWorkerConcurrency  = "LAB_WORKER_CONCURRENCY"
+	envLabWorkerPollInterval = "LAB_WORKER_POLL_INTERVAL"
+	envLabWorkerJobTimeout   = "LAB_WORKER_JOB_TIMEOUT"
+	envLabWorkerRequeue      = "LAB_WORKER_REQUEUE_INTERVAL"
+	envLabWorkerMaxAttempts  = "LAB_WORKER_MAX_ATTEMPTS"
+)
+
+// WorkerConfig controla o processamento assíncrono de laudos.
+type WorkerConfig struct {
+	LabConcurrency  int
+	LabPollInterval time.Duration
+	LabJobTimeout   time.Duration
+	// Intervalo da varredura que devolve à fila jobs presos em running.
+	LabRequeueInterval time.Duration
+	// Tentativas antes de um job preso ser encerrado como failed.
+	LabMaxAttempts int
+}
+
+func loadWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		LabConcurrency:     getEnvIntOrDefault(envLabWorkerConcurrency, 2),
+		LabPollInterval:    getEnvDurationOrDefault(envLabWorkerPollInterval, 2*time.Second),
+		LabJobTimeout:      getEnvDurationOrDefault(envLabWorkerJobTimeout, 5*time.Minute),
+		LabRequeueInterval: getEnvDurationOrDefault(envLabWorkerRequeue, time.Minute),
+		LabMaxAttempts:     getEnvIntOrDefault(envLabWorkerMaxAttempts, 3),
+	}
+}
+
+func getEnvIntOrDefault(key string, def int) int {
+	v := getEnv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// getEnvDurationOrDefault aceita o formato de time.ParseDuration (ex.: "2s", "5m").
+func getEnvDurationOrDefault(key string, def time.Duration) time.Duration {
+	v := getEnv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -7,6 +7,11 @@ type ExtractedTestItem struct {
 	ResultValue   *string // test_item.result_value
 	ResultUnit    *string // test_item.unit
 	ReferenceText *string // test_item.reference_text
+
+	// Confiança (0 a 1) do test_item e de cada propriedade, pelo nome do campo
+	// (parameter_name, result_value, result_unit, reference_text).
+	Confidence      *float64
+	FieldConfidence map[string]float64
 }
 
 // ExtractedTestResult representa um test_result vindo do Document AI.
@@ -31,5 +36,8 @@ type ExtractedLabReport struct {
 	ReportDate        *string
 	RawText           *string
 
+	// FieldConfidence guarda a confiança (0 a 1) de cada campo do cabeçalho.
+	FieldConfidence map[string]float64
+
 	Tests []ExtractedTestResult
 }
@@ -0,0 +1,27 @@
+// internal/domain/document/renderer.go
+package document
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+)
+
+// CumulativeReport reúne o que vai no relatório cumulativo de exames.
+// From e To são o período pedido; nil quando aberto.
+type CumulativeReport struct {
+	Patient     *patient.Patient
+	Table       labs.CumulativeTable
+	From        *time.Time
+	To          *time.Time
+	GeneratedAt time.Time
+}
+
+// CumulativeReportRenderer gera o arquivo do relatório cumulativo.
+type CumulativeReportRenderer interface {
+	// ContentType é o tipo MIME do arquivo gerado (ex.: application/pdf).
+	ContentType() string
+	RenderCumulative(ctx context.Context, report CumulativeReport) ([]byte, error)
+}
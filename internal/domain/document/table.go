@@ -0,0 +1,26 @@
+// internal/domain/document/table.go
+package document
+
+import "io"
+
+// Cell é o valor de uma célula: string, float64, time.Time ou nil (vazia).
+type Cell = any
+
+// TableFormat cria planilhas num formato de arquivo (CSV, XLSX).
+type TableFormat interface {
+	// ContentType é o tipo MIME do arquivo; Extension vem sem o ponto.
+	ContentType() string
+	Extension() string
+	// NewTableWriter começa a planilha em w; sheet nomeia a aba quando o
+	// formato tem abas.
+	NewTableWriter(w io.Writer, sheet string) (TableWriter, error)
+}
+
+// TableWriter grava a planilha linha a linha, sem montar o arquivo inteiro
+// em memória.
+type TableWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(cells []Cell) error
+	// Close termina o arquivo; sem ele o conteúdo fica incompleto.
+	Close() error
+}
@@ -0,0 +1,112 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bundle agrupa recursos FHIR (laudo + observações + paciente).
+type Bundle struct {
+	ResourceType string        `json:"resourceType"` // sempre "Bundle"
+	ID           string        `json:"id,omitempty"`
+	Meta         *Meta         `json:"meta,omitempty"`
+	Identifier   *Identifier   `json:"identifier,omitempty"`
+	Type         string        `json:"type"`
+	Timestamp    string        `json:"timestamp,omitempty"`
+	Total        *int          `json:"total,omitempty"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+type BundleEntry struct {
+	FullURL  string              `json:"fullUrl,omitempty"`
+	Resource any                 `json:"resource,omitempty"`
+	Search   *BundleEntrySearch  `json:"search,omitempty"`
+	Request  *BundleEntryRequest `json:"request,omitempty"`
+}
+
+type BundleEntrySearch struct {
+	Mode string `json:"mode,omitempty"` // match, include
+}
+
+type BundleEntryRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+const (
+	ResourceBundle = "Bundle"
+
+	BundleTypeCollection  = "collection"
+	BundleTypeSearchset   = "searchset"
+	BundleTypeTransaction = "transaction"
+)
+
+// URNUUID monta a fullUrl/referência usada dentro dos Bundles que geramos.
+func URNUUID(id string) string {
+	return "urn:uuid:" + id
+}
+
+// NewBundle cria um Bundle vazio com id e timestamp.
+func NewBundle(bundleType string, now time.Time) *Bundle {
+	return &Bundle{
+		ResourceType: ResourceBundle,
+		ID:           uuid.NewString(),
+		Type:         bundleType,
+		Timestamp:    formatInstant(now),
+	}
+}
+
+// Add inclui o recurso com fullUrl urn:uuid; searchMode só vale para searchset.
+func (b *Bundle) Add(id string, resource any, searchMode string) {
+	entry := BundleEntry{FullURL: URNUUID(id), Resource: resource}
+	if searchMode != "" {
+		entry.Search = &BundleEntrySearch{Mode: searchMode}
+	}
+	b.Entry = append(b.Entry, entry)
+}
+
+// UnmarshalJSON decodifica resource no tipo concreto indicado por resourceType;
+// recursos que não modelamos ficam como json.RawMessage.
+func (e *BundleEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		FullURL  string              `json:"fullUrl"`
+		Resource json.RawMessage     `json:"resource"`
+		Search   *BundleEntrySearch  `json:"search"`
+		Request  *BundleEntryRequest `json:"request"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.FullURL, e.Search, e.Request, e.Resource = raw.FullURL, raw.Search, raw.Request, nil
+	if len(raw.Resource) == 0 {
+		return nil
+	}
+
+	var header struct {
+		ResourceType string `json:"resourceType"`
+	}
+	if err := json.Unmarshal(raw.Resource, &header); err != nil {
+		return err
+	}
+
+	var resource any
+	switch header.ResourceType {
+	case ResourceDiagnosticReport:
+		resource = &DiagnosticReport{}
+	case ResourceObservation:
+		resource = &Observation{}
+	case ResourcePatient:
+		resource = &Patient{}
+	default:
+		e.Resource = raw.Resource
+		return nil
+	}
+	if err := json.Unmarshal(raw.Resource, resource); err != nil {
+		return err
+	}
+	e.Resource = resource
+	return nil
+}
@@ -0,0 +1,77 @@
+// internal/domain/entity/diagnostics/datatypes.go
+package diagnostics
+
+// Tipos de dados FHIR R5 usados pelos recursos de laboratório.
+// Só os elementos que produzimos/consumimos estão modelados.
+
+const (
+	FHIRVersion = "5.0.0"
+
+	SystemLOINC          = "http://loinc.org"
+	SystemUCUM           = "http://unitsofmeasure.org"
+	SystemV2_0074        = "http://terminology.hl7.org/CodeSystem/v2-0074"
+	SystemObsCategory    = "http://terminology.hl7.org/CodeSystem/observation-category"
+	SystemInterpretation = "http://terminology.hl7.org/CodeSystem/v3-ObservationInterpretation"
+	SystemDataAbsent     = "http://terminology.hl7.org/CodeSystem/data-absent-reason"
+	SystemAdminGender    = "http://hl7.org/fhir/administrative-gender"
+
+	// Identificadores brasileiros (RNDS).
+	SystemCPF = "http://rnds.saude.gov.br/fhir/r4/NamingSystem/cpf"
+	SystemCNS = "http://rnds.saude.gov.br/fhir/r4/NamingSystem/cns"
+
+	// Sistemas locais da Sonnda.
+	SystemLabReportFingerprint = "urn:sonnda:lab-report:fingerprint"
+	SystemLabAnalyte           = "urn:sonnda:lab-analyte"
+)
+
+type Meta struct {
+	LastUpdated string   `json:"lastUpdated,omitempty"`
+	Profile     []string `json:"profile,omitempty"`
+}
+
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Reference aponta para outro recurso; dentro de um Bundle usamos "urn:uuid:<id>".
+type Reference struct {
+	Reference  string      `json:"reference,omitempty"`
+	Type       string      `json:"type,omitempty"`
+	Identifier *Identifier `json:"identifier,omitempty"`
+	Display    string      `json:"display,omitempty"`
+}
+
+type Identifier struct {
+	Use    string `json:"use,omitempty"`
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// Quantity segue o datatype FHIR: Unit é o texto exibido, Code o código UCUM.
+type Quantity struct {
+	Value      *float64 `json:"value,omitempty"`
+	Comparator string   `json:"comparator,omitempty"`
+	Unit       string   `json:"unit,omitempty"`
+	System     string   `json:"system,omitempty"`
+	Code       string   `json:"code,omitempty"`
+}
+
+type Attachment struct {
+	ContentType string `json:"contentType,omitempty"`
+	Data        string `json:"data,omitempty"` // base64
+	URL         string `json:"url,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Creation    string `json:"creation,omitempty"`
+}
+
+type HumanName struct {
+	Use  string `json:"use,omitempty"`
+	Text string `json:"text,omitempty"`
+}
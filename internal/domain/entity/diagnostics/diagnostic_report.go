@@ -1,34 +1,57 @@
 package diagnostics
 
-import (
-	"time"
-
-	"github.com/google/uuid"
-)
-
+// DiagnosticReport representa o laudo no formato FHIR R5.
 type DiagnosticReport struct {
-	ID        uuid.UUID `json:"id"`
-	PatientID uuid.UUID `json:"patient_id"`
+	ResourceType string       `json:"resourceType"` // sempre "DiagnosticReport"
+	ID           string       `json:"id,omitempty"`
+	Meta         *Meta        `json:"meta,omitempty"`
+	Identifier   []Identifier `json:"identifier,omitempty"`
 
 	// Status é vital no FHIR: preliminary, final, amended, corrected
 	Status string `json:"status"`
 
 	// Category: No seu caso, "LAB" (Laboratory)
-	Category string `json:"category"`
+	Category []CodeableConcept `json:"category,omitempty"`
 
 	// O código do relatório (ex: LOINC do painel completo)
-	Code string `json:"code"`
+	Code CodeableConcept `json:"code"`
 
-	// Metadados do emissor (Laboratório)
-	PerformerName string `json:"performer_name"`
+	Subject *Reference `json:"subject,omitempty"`
 
 	// Datas clínicas
-	EffectiveDateTime time.Time `json:"effective_datetime"` // Quando a amostra foi colhida
-	Issued            time.Time `json:"issued"`             // Quando o laudo foi liberado
+	EffectiveDateTime string `json:"effectiveDateTime,omitempty"` // Quando a amostra foi colhida
+	Issued            string `json:"issued,omitempty"`            // Quando o laudo foi liberado
+
+	// Metadados do emissor (Laboratório e responsável técnico)
+	Performer          []Reference `json:"performer,omitempty"`
+	ResultsInterpreter []Reference `json:"resultsInterpreter,omitempty"`
 
 	// No FHIR, o relatório contém referências para Observações
-	Observations []Observation `json:"observations"`
+	Result []Reference `json:"result,omitempty"`
+
+	Conclusion string `json:"conclusion,omitempty"`
+
+	// Documento original (PDF/imagem) ou, na falta dele, o texto extraído
+	PresentedForm []Attachment `json:"presentedForm,omitempty"`
+}
+
+const (
+	ResourceDiagnosticReport = "DiagnosticReport"
+
+	StatusRegistered  = "registered"
+	StatusPartial     = "partial"
+	StatusPreliminary = "preliminary"
+	StatusFinal       = "final"
+	StatusAmended     = "amended"
+	StatusCorrected   = "corrected"
+	StatusCancelled   = "cancelled"
+	StatusEnteredErr  = "entered-in-error"
+
+	// LOINC genérico para laudos laboratoriais sem painel único.
+	LOINCLabReport = "11502-2"
+)
 
-	// Campo para IA salvar o texto bruto se necessário
-	Conclusion *string `json:"conclusion,omitempty"`
+// LabCategory é a categoria "LAB" da tabela HL7 v2-0074.
+func LabCategory() CodeableConcept {
+	return CodeableConcept{Coding: []Coding{{System: SystemV2_0074, Code: "LAB", Display: "Laboratory"}}}
 }
@@ -0,0 +1,557 @@
+package diagnostics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+
+	"github.com/google/uuid"
+)
+
+// Regras de importação FHIR (R5 ou R4) -> labs:
+//   - O Bundle deve ser transaction (ou collection) com exatamente um DiagnosticReport.
+//   - Observations com hasMember viram um LabResult (painel); as demais viram
+//     um LabResult com um único item.
+//   - subject, quando informado, precisa identificar o paciente da rota
+//     (Patient/<id>, CPF ou CNS).
+//   - Valores tipados (valueQuantity, referenceRange.low/high, interpretation)
+//     prevalecem sobre o que seria inferido do texto.
+
+// acceptedReportStatuses são os status com resultado utilizável (R4 e R5).
+var acceptedReportStatuses = map[string]bool{
+	StatusPartial: true, StatusPreliminary: true, StatusFinal: true,
+	StatusAmended: true, StatusCorrected: true, "appended": true, "modified": true,
+}
+
+var acceptedObservationStatuses = map[string]bool{
+	StatusPreliminary: true, StatusFinal: true, StatusAmended: true, StatusCorrected: true,
+}
+
+type importEntry struct {
+	index int
+	obs   *Observation
+}
+
+type labImport struct {
+	bundle  *Bundle
+	patient *patient.Patient
+	catalog *labs.AnalyteCatalog
+	issues  []OperationOutcomeIssue
+
+	observations map[string]importEntry // por fullUrl e Observation/<id>
+	patients     map[string]*Patient    // por fullUrl e Patient/<id>
+}
+
+// ToLabReport valida o Bundle e o converte em laudo do paciente. Quando há
+// issues, o laudo é nil e as issues descrevem todos os problemas encontrados.
+func ToLabReport(
+	bundle *Bundle,
+	p *patient.Patient,
+	uploadedBy uuid.UUID,
+	catalog *labs.AnalyteCatalog,
+) (*labs.LabReport, []OperationOutcomeIssue) {
+	if bundle == nil || bundle.ResourceType != ResourceBundle {
+		return nil, []OperationOutcomeIssue{NewIssue(IssueInvalid, "Bundle.resourceType", "o corpo deve ser um Bundle")}
+	}
+	if p == nil {
+		return nil, []OperationOutcomeIssue{NewIssue(IssueNotFound, "", "paciente não encontrado")}
+	}
+
+	imp := &labImport{
+		bundle:       bundle,
+		patient:      p,
+		catalog:      catalog,
+		observations: make(map[string]importEntry),
+		patients:     make(map[string]*Patient),
+	}
+
+	if bundle.Type != BundleTypeTransaction && bundle.Type != BundleTypeCollection {
+		imp.issue(IssueValue, "Bundle.type", "tipo de Bundle não suportado: use transaction")
+	}
+
+	dr, drIndex := imp.indexEntries()
+	if dr == nil {
+		return nil, imp.issues
+	}
+
+	report, err := labs.NewLabReport(p.ID.String(), uploadedBy.String())
+	if err != nil {
+		imp.issue(IssueInvalid, "", err.Error())
+		return nil, imp.issues
+	}
+	report.Source = labs.SourceFHIR
+
+	imp.fillHeader(report, dr, drIndex)
+	imp.fillResults(report, dr, drIndex)
+
+	if len(imp.issues) > 0 {
+		return nil, imp.issues
+	}
+	if len(report.TestResults) == 0 {
+		return nil, []OperationOutcomeIssue{NewIssue(IssueRequired, "Bundle.entry", "nenhuma Observation com resultado")}
+	}
+
+	report.Normalize()
+	report.UpdatedAt = time.Now().UTC()
+	return report, nil
+}
+
+func (imp *labImport) issue(code, expression, diagnostics string) {
+	imp.issues = append(imp.issues, NewIssue(code, expression, diagnostics))
+}
+
+// indexEntries registra Observations e Patients e devolve o único DiagnosticReport.
+func (imp *labImport) indexEntries() (*DiagnosticReport, int) {
+	var (
+		dr      *DiagnosticReport
+		drIndex = -1
+	)
+
+	for i, entry := range imp.bundle.Entry {
+		path := fmt.Sprintf("Bundle.entry[%d]", i)
+		if entry.Request != nil && entry.Request.Method != "POST" && entry.Request.Method != "PUT" {
+			imp.issue(IssueNotSupported, path+".request.method", "somente POST e PUT são aceitos")
+		}
+
+		switch res := entry.Resource.(type) {
+		case *DiagnosticReport:
+			if dr != nil {
+				imp.issue(IssueNotSupported, path, "envie um DiagnosticReport por Bundle")
+				continue
+			}
+			dr, drIndex = res, i
+		case *Observation:
+			for _, key := range entryKeys(entry.FullURL, ResourceObservation, res.ID) {
+				imp.observations[key] = importEntry{index: i, obs: res}
+			}
+		case *Patient:
+			for _, key := range entryKeys(entry.FullURL, ResourcePatient, res.ID) {
+				imp.patients[key] = res
+			}
+		}
+	}
+
+	if dr == nil {
+		imp.issue(IssueRequired, "Bundle.entry", "o Bundle deve conter um DiagnosticReport")
+	}
+	return dr, drIndex
+}
+
+func entryKeys(fullURL, resourceType, id string) []string {
+	var keys []string
+	if fullURL != "" {
+		keys = append(keys, fullURL)
+	}
+	if id != "" {
+		keys = append(keys, resourceType+"/"+id)
+	}
+	return keys
+}
+
+func (imp *labImport) fillHeader(report *labs.LabReport, dr *DiagnosticReport, index int) {
+	path := fmt.Sprintf("Bundle.entry[%d].resource", index)
+
+	if !acceptedReportStatuses[dr.Status] {
+		imp.issue(IssueValue, path+".status", fmt.Sprintf("status %q não aceito", dr.Status))
+	}
+	report.Status = labReportStatus(dr.Status)
+	if conceptName(dr.Code) == "" {
+		imp.issue(IssueRequired, path+".code", "code é obrigatório")
+	}
+	imp.checkSubject(dr.Subject, path+".subject")
+
+	if t, ok := imp.parseDateTime(dr.EffectiveDateTime, path+".effectiveDateTime"); ok {
+		report.ReportDate = &t
+	} else if t, ok := imp.parseDateTime(dr.Issued, path+".issued"); ok {
+		report.ReportDate = &t
+	}
+	if report.ReportDate != nil {
+		day := report.ReportDate.UTC().Truncate(24 * time.Hour)
+		report.ReportDate = &day
+	}
+
+	if len(dr.Performer) > 0 && dr.Performer[0].Display != "" {
+		report.LabName = &dr.Performer[0].Display
+	}
+	if len(dr.ResultsInterpreter) > 0 && dr.ResultsInterpreter[0].Display != "" {
+		report.TechnicalManager = &dr.ResultsInterpreter[0].Display
+	}
+	if dr.Conclusion != "" {
+		report.RawText = &dr.Conclusion
+	}
+
+	if subject := imp.resolvePatient(dr.Subject); subject != nil {
+		if len(subject.Name) > 0 && subject.Name[0].Text != "" {
+			report.PatientName = &subject.Name[0].Text
+		}
+		if t, err := time.Parse(time.DateOnly, subject.BirthDate); err == nil {
+			report.PatientDOB = &t
+		}
+	}
+}
+
+// fillResults percorre DiagnosticReport.result e depois as Observations soltas.
+func (imp *labImport) fillResults(report *labs.LabReport, dr *DiagnosticReport, drIndex int) {
+	used := make(map[*Observation]bool)
+
+	for j, ref := range dr.Result {
+		entry, ok := imp.observations[ref.Reference]
+		if !ok {
+			imp.issue(IssueNotFound, fmt.Sprintf("Bundle.entry[%d].resource.result[%d]", drIndex, j),
+				fmt.Sprintf("referência %q não encontrada no Bundle", ref.Reference))
+			continue
+		}
+		imp.addResult(report, entry, used)
+	}
+
+	// Observations não referenciadas pelo laudo também são importadas.
+	for i, entry := range imp.bundle.Entry {
+		obs, ok := entry.Resource.(*Observation)
+		if !ok || used[obs] || imp.isMember(obs) {
+			continue
+		}
+		imp.addResult(report, importEntry{index: i, obs: obs}, used)
+	}
+}
+
+func (imp *labImport) isMember(obs *Observation) bool {
+	for _, entry := range imp.bundle.Entry {
+		panel, ok := entry.Resource.(*Observation)
+		if !ok {
+			continue
+		}
+		for _, ref := range panel.HasMember {
+			if member, ok := imp.observations[ref.Reference]; ok && member.obs == obs {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (imp *labImport) addResult(report *labs.LabReport, entry importEntry, used map[*Observation]bool) {
+	if used[entry.obs] {
+		return
+	}
+	used[entry.obs] = true
+
+	path := fmt.Sprintf("Bundle.entry[%d].resource", entry.index)
+	if !imp.checkObservation(entry.obs, path) {
+		return
+	}
+
+	result, err := labs.NewLabResult(report.ID.String(), conceptName(entry.obs.Code))
+	if err != nil {
+		imp.issue(IssueInvalid, path+".code", err.Error())
+		return
+	}
+	imp.fillResultMeta(result, entry.obs, path)
+
+	members := []importEntry{entry}
+	if len(entry.obs.HasMember) > 0 {
+		members = members[:0]
+		for k, ref := range entry.obs.HasMember {
+			member, ok := imp.observations[ref.Reference]
+			if !ok {
+				imp.issue(IssueNotFound, fmt.Sprintf("%s.hasMember[%d]", path, k),
+					fmt.Sprintf("referência %q não encontrada no Bundle", ref.Reference))
+				continue
+			}
+			used[member.obs] = true
+			memberPath := fmt.Sprintf("Bundle.entry[%d].resource", member.index)
+			if imp.checkObservation(member.obs, memberPath) {
+				members = append(members, member)
+			}
+		}
+	}
+
+	for _, member := range members {
+		memberPath := fmt.Sprintf("Bundle.entry[%d].resource", member.index)
+		item, err := labs.NewLabResultItem(result.ID.String(), conceptName(member.obs.Code))
+		if err != nil {
+			imp.issue(IssueInvalid, memberPath+".code", err.Error())
+			continue
+		}
+		imp.fillItem(item, member.obs, memberPath)
+		if result.CollectedAt == nil {
+			if t, ok := imp.parseDateTime(member.obs.EffectiveDateTime, memberPath+".effectiveDateTime"); ok {
+				result.CollectedAt = &t
+			}
+		}
+		result.Items = append(result.Items, *item)
+	}
+
+	result.Normalize()
+	report.TestResults = append(report.TestResults, *result)
+}
+
+func (imp *labImport) checkObservation(obs *Observation, path string) bool {
+	before := len(imp.issues)
+	if !acceptedObservationStatuses[obs.Status] {
+		imp.issue(IssueValue, path+".status", fmt.Sprintf("status %q não aceito", obs.Status))
+	}
+	if conceptName(obs.Code) == "" {
+		imp.issue(IssueRequired, path+".code", "code é obrigatório")
+	}
+	if q := obs.ValueQuantity; q != nil {
+		if q.Value == nil {
+			imp.issue(IssueRequired, path+".valueQuantity.value", "valueQuantity sem value")
+		}
+		if !labs.Comparator(q.Comparator).IsValid() {
+			imp.issue(IssueValue, path+".valueQuantity.comparator", fmt.Sprintf("comparator %q não suportado", q.Comparator))
+		}
+	}
+	imp.checkSubject(obs.Subject, path+".subject")
+	return len(imp.issues) == before
+}
+
+func (imp *labImport) fillResultMeta(result *labs.LabResult, obs *Observation, path string) {
+	if t, ok := imp.parseDateTime(obs.EffectiveDateTime, path+".effectiveDateTime"); ok {
+		result.CollectedAt = &t
+	}
+	if t, ok := imp.parseDateTime(obs.Issued, path+".issued"); ok {
+		result.ReleaseAt = &t
+	}
+	if obs.Method != nil {
+		if name := conceptName(*obs.Method); name != "" {
+			result.Method = &name
+		}
+	}
+	for _, note := range obs.Note {
+		if material, ok := strings.CutPrefix(note.Text, "Material: "); ok {
+			result.Material = &material
+			break
+		}
+	}
+}
+
+func (imp *labImport) fillItem(item *labs.LabResultItem, obs *Observation, path string) {
+	sex := imp.patient.Gender
+	if sex == "" {
+		sex = demographics.GenderUnknown
+	}
+
+	q := obs.ValueQuantity
+	switch {
+	case q != nil && q.Value != nil:
+		value := q.Comparator + " " + formatNumberPTBR(*q.Value)
+		item.ResultValue = &value
+		if unit := firstNonEmpty(q.Unit, q.Code); unit != "" {
+			item.ResultUnit = &unit
+		}
+	case obs.ValueString != nil:
+		item.ResultValue = obs.ValueString
+	case obs.ValueCodeableConcept != nil:
+		if name := conceptName(*obs.ValueCodeableConcept); name != "" {
+			item.ResultValue = &name
+		}
+	case obs.ValueInteger != nil:
+		value := strconv.FormatInt(*obs.ValueInteger, 10)
+		item.ResultValue = &value
+	}
+
+	var rr *ObservationReferenceRange
+	if len(obs.ReferenceRange) > 0 {
+		rr = &obs.ReferenceRange[0]
+		if text := referenceText(rr); text != "" {
+			item.ReferenceText = &text
+		}
+	}
+
+	item.Normalize()
+	item.ParseStructuredResult(sex)
+
+	// Dados tipados do FHIR prevalecem sobre o texto.
+	if q != nil && q.Value != nil {
+		v := *q.Value
+		item.NumericValue = &v
+		item.QualitativeValue = nil
+		item.Comparator = labs.Comparator(q.Comparator)
+		if q.System == SystemUCUM && q.Code != "" {
+			if code, ok := labs.ParseUCUM(q.Code); ok {
+				item.UCUMUnit = &code
+			}
+		}
+	}
+	if rr != nil && (rr.Low != nil || rr.High != nil) {
+		ref := labs.ReferenceRange{LowInclusive: true, HighInclusive: true}
+		if rr.Low != nil {
+			ref.Low = rr.Low.Value
+		}
+		if rr.High != nil {
+			ref.High = rr.High.Value
+		}
+		item.ReferenceLow, item.ReferenceHigh = ref.Low, ref.High
+		item.Interpretation = labs.Interpret(item.NumericValue, item.QualitativeValue, ref)
+	}
+	if interp, ok := interpretationCode(obs.Interpretation); ok {
+		item.Interpretation = interp
+	}
+
+	imp.linkAnalyte(item, obs.Code)
+}
+
+// linkAnalyte prioriza LOINC e o código local; o nome é o último recurso.
+func (imp *labImport) linkAnalyte(item *labs.LabResultItem, code CodeableConcept) {
+	for _, coding := range code.Coding {
+		var a *labs.Analyte
+		switch coding.System {
+		case SystemLOINC:
+			a = imp.catalog.GetByLOINC(coding.Code)
+		case SystemLabAnalyte:
+			a = imp.catalog.Get(coding.Code)
+		}
+		if a != nil {
+			item.LinkAnalyteCode(a.Code)
+			return
+		}
+	}
+	item.LinkAnalyte(imp.catalog)
+}
+
+// checkSubject confere que a referência aponta para o paciente da rota.
+func (imp *labImport) checkSubject(ref *Reference, path string) {
+	if ref == nil || imp.subjectMatches(ref) {
+		return
+	}
+	imp.issue(IssueInvalid, path, "subject não corresponde ao paciente")
+}
+
+func (imp *labImport) subjectMatches(ref *Reference) bool {
+	p := imp.patient
+	if ref.Reference == ResourcePatient+"/"+p.ID.String() || ref.Reference == URNUUID(p.ID.String()) {
+		return true
+	}
+	if ref.Identifier != nil && imp.identifierMatches(*ref.Identifier) {
+		return true
+	}
+	if res := imp.resolvePatient(ref); res != nil {
+		if res.ID == p.ID.String() {
+			return true
+		}
+		for _, id := range res.Identifier {
+			if imp.identifierMatches(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (imp *labImport) identifierMatches(id Identifier) bool {
+	p := imp.patient
+	switch id.System {
+	case SystemCPF:
+		return p.CPF != "" && demographics.CleanDigits(id.Value) == p.CPF
+	case SystemCNS:
+		return p.CNS != nil && demographics.CleanDigits(id.Value) == demographics.CleanDigits(*p.CNS)
+	default:
+		return false
+	}
+}
+
+func (imp *labImport) resolvePatient(ref *Reference) *Patient {
+	if ref == nil || ref.Reference == "" {
+		return nil
+	}
+	return imp.patients[ref.Reference]
+}
+
+// parseDateTime aceita as precisões do tipo dateTime FHIR; vazio não gera issue.
+func (imp *labImport) parseDateTime(raw, path string) (time.Time, bool) {
+	if raw == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", time.DateOnly, "2006-01", "2006"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.UTC(), true
+		}
+	}
+	imp.issue(IssueValue, path, fmt.Sprintf("data inválida: %q", raw))
+	return time.Time{}, false
+}
+
+// conceptName devolve text, senão display/código da primeira coding.
+func conceptName(cc CodeableConcept) string {
+	if text := strings.TrimSpace(cc.Text); text != "" {
+		return text
+	}
+	for _, coding := range cc.Coding {
+		if name := firstNonEmpty(strings.TrimSpace(coding.Display), strings.TrimSpace(coding.Code)); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+func interpretationCode(concepts []CodeableConcept) (labs.Interpretation, bool) {
+	for _, cc := range concepts {
+		for _, coding := range cc.Coding {
+			if coding.System != "" && coding.System != SystemInterpretation {
+				continue
+			}
+			interp := labs.Interpretation(coding.Code)
+			if interp != labs.InterpretationUnknown && interp.IsValid() {
+				return interp, true
+			}
+		}
+	}
+	return labs.InterpretationUnknown, false
+}
+
+// referenceText usa o texto da faixa ou o monta a partir de low/high.
+func referenceText(rr *ObservationReferenceRange) string {
+	if rr.Text != "" {
+		return rr.Text
+	}
+	var low, high string
+	if rr.Low != nil && rr.Low.Value != nil {
+		low = formatNumberPTBR(*rr.Low.Value)
+	}
+	if rr.High != nil && rr.High.Value != nil {
+		high = formatNumberPTBR(*rr.High.Value)
+	}
+	switch {
+	case low != "" && high != "":
+		return low + " a " + high
+	case high != "":
+		return "Até " + high
+	case low != "":
+		return "Superior a " + low
+	default:
+		return ""
+	}
+}
+
+// formatNumberPTBR escreve com vírgula decimal, como nos laudos ("14,2").
+func formatNumberPTBR(v float64) string {
+	return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", ",", 1)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// labReportStatus reduz o DiagnosticReport.status aos status guardados no laudo.
+func labReportStatus(status string) labs.ReportStatus {
+	switch status {
+	case StatusPartial, StatusPreliminary:
+		return labs.StatusPreliminary
+	case StatusAmended, "appended":
+		return labs.StatusAmended
+	case StatusCorrected, "modified":
+		return labs.StatusCorrected
+	default:
+		return labs.StatusFinal
+	}
+}
@@ -0,0 +1,185 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+
+	"github.com/google/uuid"
+)
+
+const transactionBundle = `{
+  "resourceType": "Bundle",
+  "type": "transaction",
+  "entry": [
+    {
+      "fullUrl": "urn:uuid:p1",
+      "resource": {
+        "resourceType": "Patient",
+        "identifier": [{"system": "http://rnds.saude.gov.br/fhir/r4/NamingSystem/cpf", "value": "123.456.789-09"}]
+      },
+      "request": {"method": "POST", "url": "Patient"}
+    },
+    {
+      "fullUrl": "urn:uuid:dr1",
+      "resource": {
+        "resourceType": "DiagnosticReport",
+        "status": "final",
+        "code": {"text": "Bioquímica"},
+        "subject": {"reference": "urn:uuid:p1"},
+        "effectiveDateTime": "2025-02-01T08:30:00-03:00",
+        "performer": [{"display": "Laboratório Central"}],
+        "result": [{"reference": "urn:uuid:o1"}, {"reference": "urn:uuid:o2"}]
+      },
+      "request": {"method": "POST", "url": "DiagnosticReport"}
+    },
+    {
+      "fullUrl": "urn:uuid:o1",
+      "resource": {
+        "resourceType": "Observation",
+        "status": "final",
+        "code": {"coding": [{"system": "http://loinc.org", "code": "2345-7", "display": "Glucose"}], "text": "Glicose"},
+        "subject": {"reference": "urn:uuid:p1"},
+        "valueQuantity": {"value": 126, "unit": "mg/dL", "system": "http://unitsofmeasure.org", "code": "mg/dL"},
+        "referenceRange": [{"low": {"value": 70}, "high": {"value": 99}}]
+      },
+      "request": {"method": "POST", "url": "Observation"}
+    },
+    {
+      "fullUrl": "urn:uuid:o2",
+      "resource": {
+        "resourceType": "Observation",
+        "status": "final",
+        "code": {"text": "Anti-HIV"},
+        "valueString": "Não reagente",
+        "referenceRange": [{"text": "Não reagente"}]
+      },
+      "request": {"method": "POST", "url": "Observation"}
+    }
+  ]
+}`
+
+func TestToLabReport(t *testing.T) {
+	var bundle Bundle
+	if err := json.Unmarshal([]byte(transactionBundle), &bundle); err != nil {
+		t.Fatal(err)
+	}
+	p := &patient.Patient{ID: uuid.New(), CPF: "12345678909", Gender: demographics.GenderFemale}
+	loinc := "2345-7"
+	catalog := labs.NewAnalyteCatalog([]labs.Analyte{{Code: "glucose", LOINC: &loinc, DisplayName: "Glicose"}})
+
+	report, issues := ToLabReport(&bundle, p, uuid.New(), catalog)
+	if len(issues) > 0 {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+
+	if report.PatientID != p.ID || report.LabName == nil || *report.LabName != "Laboratório Central" {
+		t.Fatalf("unexpected header: %+v", report)
+	}
+	if report.ReportDate == nil || report.ReportDate.Format("2006-01-02") != "2025-02-01" {
+		t.Fatalf("unexpected report date: %v", report.ReportDate)
+	}
+	if len(report.TestResults) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(report.TestResults))
+	}
+
+	glucose := report.TestResults[0].Items[0]
+	if glucose.NumericValue == nil || *glucose.NumericValue != 126 || glucose.UCUMUnit == nil || *glucose.UCUMUnit != "mg/dL" {
+		t.Fatalf("unexpected glucose value: %+v", glucose)
+	}
+	if glucose.AnalyteCode == nil || *glucose.AnalyteCode != "glucose" || glucose.AnalyteLink != labs.AnalyteLinkCode {
+		t.Fatalf("LOINC coding should link the analyte by code, got %v (%q)", glucose.AnalyteCode, glucose.AnalyteLink)
+	}
+	if glucose.Interpretation != labs.InterpretationHigh {
+		t.Fatalf("interpretation = %q, want H", glucose.Interpretation)
+	}
+
+	hiv := report.TestResults[1].Items[0]
+	if hiv.QualitativeValue == nil || hiv.Interpretation != labs.InterpretationNormal {
+		t.Fatalf("unexpected qualitative item: %+v", hiv)
+	}
+}
+
+func TestToLabReport_Issues(t *testing.T) {
+	var bundle Bundle
+	if err := json.Unmarshal([]byte(transactionBundle), &bundle); err != nil {
+		t.Fatal(err)
+	}
+	dr := bundle.Entry[1].Resource.(*DiagnosticReport)
+	dr.Status = "cancelled"
+	dr.Result = append(dr.Result, Reference{Reference: "urn:uuid:missing"})
+
+	// CPF diferente do paciente da rota.
+	p := &patient.Patient{ID: uuid.New(), CPF: "98765432100"}
+
+	report, issues := ToLabReport(&bundle, p, uuid.New(), nil)
+	if report != nil {
+		t.Fatalf("expected no report when there are issues")
+	}
+
+	want := map[string]string{
+		"Bundle.entry[1].resource.status":    IssueValue,
+		"Bundle.entry[1].resource.subject":   IssueInvalid,
+		"Bundle.entry[1].resource.result[2]": IssueNotFound,
+	}
+	got := make(map[string]string)
+	for _, issue := range issues {
+		for _, expr := range issue.Expression {
+			got[expr] = issue.Code
+		}
+	}
+	for expr, code := range want {
+		if got[expr] != code {
+			t.Errorf("issue at %s = %q, want %q (all: %+v)", expr, got[expr], code, issues)
+		}
+	}
+}
+
+func TestToLabReport_RoundTrip(t *testing.T) {
+	unit, ucum := "mg/dL", "mg/dL"
+	value := 92.0
+	text := "92"
+	p := &patient.Patient{ID: uuid.New(), CPF: "12345678909"}
+	source := &labs.LabReport{
+		ID:        uuid.New(),
+		PatientID: p.ID,
+		TestResults: []labs.LabResult{{
+			ID:       uuid.New(),
+			TestName: "Glicose",
+			Items: []labs.LabResultItem{{
+				ID: uuid.New(), ParameterName: "Glicose",
+				ResultValue: &text, ResultUnit: &unit, NumericValue: &value, UCUMUnit: &ucum,
+			}},
+		}},
+	}
+
+	fhirPatient := FromPatient(p)
+	dr, observations := FromLabReport(source, PatientReference(fhirPatient), nil, nil)
+	bundle := NewBundle(BundleTypeCollection, source.CreatedAt)
+	bundle.Add(fhirPatient.ID, fhirPatient, "")
+	bundle.Add(dr.ID, dr, "")
+	for i := range observations {
+		bundle.Add(observations[i].ID, &observations[i], "")
+	}
+
+	raw, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Bundle
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	report, issues := ToLabReport(&decoded, p, uuid.New(), nil)
+	if len(issues) > 0 {
+		t.Fatalf("exported bundle should import cleanly: %+v", issues)
+	}
+	item := report.TestResults[0].Items[0]
+	if item.ParameterName != "Glicose" || item.ResultValue == nil || *item.ResultValue != "92" {
+		t.Fatalf("unexpected round-trip item: %+v", item)
+	}
+}
@@ -0,0 +1,282 @@
+package diagnostics
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+)
+
+// Regras de mapeamento labs -> FHIR R5:
+//   - LabReport vira DiagnosticReport; o fingerprint vira identifier.
+//   - Cada LabResultItem vira uma Observation; exames com mais de um item
+//     ganham uma Observation "painel" (hasMember) referenciada em result.
+//   - effectiveDateTime: collected_at do exame, senão report_date.
+//   - issued: maior release_at, senão created_at do laudo.
+//   - presentedForm: documento original; sem ele, o texto extraído (text/plain).
+
+// FromLabReport converte o laudo e devolve o DiagnosticReport e todas as
+// Observations referenciadas (painéis e itens).
+func FromLabReport(
+	report *labs.LabReport,
+	subject *Reference,
+	catalog *labs.AnalyteCatalog,
+	document *Attachment,
+) (*DiagnosticReport, []Observation) {
+	if report == nil {
+		return nil, nil
+	}
+
+	dr := &DiagnosticReport{
+		ResourceType: ResourceDiagnosticReport,
+		ID:           report.ID.String(),
+		Meta:         &Meta{LastUpdated: formatInstant(report.UpdatedAt)},
+		Status:       reportStatus(report.Status),
+		Category:     []CodeableConcept{LabCategory()},
+		Code: CodeableConcept{
+			Coding: []Coding{{System: SystemLOINC, Code: LOINCLabReport, Display: "Laboratory report"}},
+			Text:   "Laudo laboratorial",
+		},
+		Subject: subject,
+	}
+
+	if report.Fingerprint != nil {
+		dr.Identifier = []Identifier{{Use: "official", System: SystemLabReportFingerprint, Value: *report.Fingerprint}}
+	}
+	if report.LabName != nil {
+		dr.Performer = []Reference{{Type: "Organization", Display: *report.LabName}}
+	}
+	if report.TechnicalManager != nil {
+		dr.ResultsInterpreter = []Reference{{Type: "Practitioner", Display: *report.TechnicalManager}}
+	}
+
+	switch {
+	case document != nil:
+		dr.PresentedForm = []Attachment{*document}
+	case report.RawText != nil:
+		dr.PresentedForm = []Attachment{{
+			ContentType: "text/plain; charset=utf-8",
+			Data:        base64.StdEncoding.EncodeToString([]byte(*report.RawText)),
+			Title:       "Texto extraído do laudo",
+		}}
+	}
+
+	var (
+		effective    *time.Time
+		issued       *time.Time
+		observations []Observation
+	)
+
+	for _, result := range report.TestResults {
+		resultEffective := firstTime(result.CollectedAt, report.ReportDate)
+		if result.CollectedAt != nil && (effective == nil || result.CollectedAt.Before(*effective)) {
+			effective = result.CollectedAt
+		}
+		if result.ReleaseAt != nil && (issued == nil || result.ReleaseAt.After(*issued)) {
+			issued = result.ReleaseAt
+		}
+
+		members := make([]Observation, 0, len(result.Items))
+		for idx := range result.Items {
+			obs := itemObservation(&result.Items[idx], &result, subject, catalog, resultEffective)
+			members = append(members, obs)
+		}
+
+		if len(members) == 0 {
+			continue
+		}
+		if len(members) == 1 {
+			dr.Result = append(dr.Result, observationReference(&members[0]))
+			observations = append(observations, members[0])
+			continue
+		}
+
+		panel := Observation{
+			ResourceType:      ResourceObservation,
+			ID:                result.ID.String(),
+			Status:            StatusFinal,
+			Category:          []CodeableConcept{LaboratoryCategory()},
+			Code:              CodeableConcept{Text: result.TestName},
+			Subject:           subject,
+			EffectiveDateTime: formatDateTime(resultEffective),
+			Issued:            formatOptionalInstant(result.ReleaseAt),
+			Method:            textConcept(result.Method),
+			Note:              materialNote(result.Material),
+		}
+		for i := range members {
+			panel.HasMember = append(panel.HasMember, observationReference(&members[i]))
+		}
+
+		dr.Result = append(dr.Result, observationReference(&panel))
+		observations = append(observations, panel)
+		observations = append(observations, members...)
+	}
+
+	// Resultados de laudo preliminar também são preliminares.
+	if dr.Status == StatusPreliminary {
+		for i := range observations {
+			observations[i].Status = StatusPreliminary
+		}
+	}
+
+	dr.EffectiveDateTime = formatDateTime(firstTime(effective, report.ReportDate))
+	if issued != nil {
+		dr.Issued = formatInstant(*issued)
+	} else {
+		dr.Issued = formatInstant(report.CreatedAt)
+	}
+
+	return dr, observations
+}
+
+func itemObservation(
+	item *labs.LabResultItem,
+	result *labs.LabResult,
+	subject *Reference,
+	catalog *labs.AnalyteCatalog,
+	effective *time.Time,
+) Observation {
+	obs := Observation{
+		ResourceType:      ResourceObservation,
+		ID:                item.ID.String(),
+		Status:            StatusFinal,
+		Category:          []CodeableConcept{LaboratoryCategory()},
+		Code:              itemCode(item, catalog),
+		Subject:           subject,
+		EffectiveDateTime: formatDateTime(effective),
+		Issued:            formatOptionalInstant(result.ReleaseAt),
+		Method:            textConcept(result.Method),
+		Note:              materialNote(result.Material),
+	}
+
+	switch {
+	case item.NumericValue != nil:
+		obs.ValueQuantity = quantity(item.NumericValue, item.ResultUnit, item.UCUMUnit)
+		obs.ValueQuantity.Comparator = string(item.Comparator)
+	case item.QualitativeValue != nil:
+		obs.ValueString = item.QualitativeValue
+	case item.ResultValue != nil:
+		obs.ValueString = item.ResultValue
+	default:
+		obs.DataAbsentReason = &CodeableConcept{Coding: []Coding{{System: SystemDataAbsent, Code: "unknown", Display: "Unknown"}}}
+	}
+
+	if item.Interpretation != labs.InterpretationUnknown {
+		obs.Interpretation = []CodeableConcept{{Coding: []Coding{{
+			System: SystemInterpretation,
+			Code:   string(item.Interpretation),
+		}}}}
+	}
+
+	if item.ReferenceLow != nil || item.ReferenceHigh != nil || item.ReferenceText != nil {
+		rr := ObservationReferenceRange{}
+		if item.ReferenceLow != nil {
+			rr.Low = quantity(item.ReferenceLow, item.ResultUnit, item.UCUMUnit)
+		}
+		if item.ReferenceHigh != nil {
+			rr.High = quantity(item.ReferenceHigh, item.ResultUnit, item.UCUMUnit)
+		}
+		if item.ReferenceText != nil {
+			rr.Text = *item.ReferenceText
+		}
+		obs.ReferenceRange = []ObservationReferenceRange{rr}
+	}
+
+	return obs
+}
+
+// itemCode usa o LOINC do analito quando conhecido e sempre mantém o nome impresso em text.
+func itemCode(item *labs.LabResultItem, catalog *labs.AnalyteCatalog) CodeableConcept {
+	cc := CodeableConcept{Text: item.ParameterName}
+	if item.AnalyteCode == nil {
+		return cc
+	}
+
+	display := ""
+	if a := catalog.Get(*item.AnalyteCode); a != nil {
+		display = a.DisplayName
+		if a.LOINC != nil {
+			cc.Coding = append(cc.Coding, Coding{System: SystemLOINC, Code: *a.LOINC, Display: a.DisplayName})
+		}
+	}
+	cc.Coding = append(cc.Coding, Coding{System: SystemLabAnalyte, Code: *item.AnalyteCode, Display: display})
+	return cc
+}
+
+func quantity(value *float64, unit, ucum *string) *Quantity {
+	v := *value
+	q := &Quantity{Value: &v}
+	if unit != nil {
+		q.Unit = *unit
+	}
+	if ucum != nil {
+		q.System = SystemUCUM
+		q.Code = *ucum
+		if q.Unit == "" {
+			q.Unit = *ucum
+		}
+	}
+	return q
+}
+
+func observationReference(obs *Observation) Reference {
+	return Reference{Reference: URNUUID(obs.ID), Type: ResourceObservation}
+}
+
+func textConcept(text *string) *CodeableConcept {
+	if text == nil {
+		return nil
+	}
+	return &CodeableConcept{Text: *text}
+}
+
+func materialNote(material *string) []Annotation {
+	if material == nil {
+		return nil
+	}
+	return []Annotation{{Text: "Material: " + *material}}
+}
+
+func firstTime(values ...*time.Time) *time.Time {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func formatDate(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
+
+// formatDateTime omite o horário quando só a data é conhecida (00:00 UTC).
+func formatDateTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	u := t.UTC()
+	if u.Hour() == 0 && u.Minute() == 0 && u.Second() == 0 && u.Nanosecond() == 0 {
+		return formatDate(u)
+	}
+	return u.Format(time.RFC3339)
+}
+
+func formatInstant(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatOptionalInstant(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatInstant(*t)
+}
+
+// reportStatus usa o status do laudo; laudos sem status são finais.
+func reportStatus(status labs.ReportStatus) string {
+	if status == "" {
+		return StatusFinal
+	}
+	return string(status)
+}
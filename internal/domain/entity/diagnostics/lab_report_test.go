@@ -0,0 +1,121 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+
+	"github.com/google/uuid"
+)
+
+func TestFromLabReport(t *testing.T) {
+	reportDate := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	fingerprint := "abc123"
+	loinc := "718-7"
+	unit, ucum := "g/dL", "g/dL"
+	hb, low, high := 11.2, 12.0, 16.0
+	analyte := "hemoglobin"
+	hivText := "Não reagente"
+
+	report := &labs.LabReport{
+		ID:          uuid.New(),
+		PatientID:   uuid.New(),
+		ReportDate:  &reportDate,
+		Fingerprint: &fingerprint,
+		CreatedAt:   reportDate,
+		UpdatedAt:   reportDate,
+		TestResults: []labs.LabResult{
+			{
+				ID:       uuid.New(),
+				TestName: "Hemograma",
+				Items: []labs.LabResultItem{
+					{
+						ID: uuid.New(), ParameterName: "Hemoglobina", AnalyteCode: &analyte,
+						NumericValue: &hb, ResultUnit: &unit, UCUMUnit: &ucum,
+						ReferenceLow: &low, ReferenceHigh: &high,
+						Interpretation: labs.InterpretationLow,
+					},
+					{ID: uuid.New(), ParameterName: "Plaquetas"},
+				},
+			},
+			{
+				ID:       uuid.New(),
+				TestName: "HIV",
+				Items: []labs.LabResultItem{
+					{ID: uuid.New(), ParameterName: "Anti-HIV", QualitativeValue: &hivText},
+				},
+			},
+		},
+	}
+	catalog := labs.NewAnalyteCatalog([]labs.Analyte{{Code: analyte, LOINC: &loinc, DisplayName: "Hemoglobina"}})
+	subject := &Reference{Reference: URNUUID(report.PatientID.String()), Type: ResourcePatient}
+	document := &Attachment{ContentType: "application/pdf", URL: "https://files/laudo.pdf"}
+
+	dr, observations := FromLabReport(report, subject, catalog, document)
+
+	if dr.Status != StatusFinal || dr.Subject != subject {
+		t.Fatalf("unexpected report header: %+v", dr)
+	}
+	if len(dr.Identifier) != 1 || dr.Identifier[0].System != SystemLabReportFingerprint || dr.Identifier[0].Value != fingerprint {
+		t.Fatalf("fingerprint identifier missing: %+v", dr.Identifier)
+	}
+	if len(dr.PresentedForm) != 1 || dr.PresentedForm[0].URL != document.URL {
+		t.Fatalf("presentedForm should point to the original document: %+v", dr.PresentedForm)
+	}
+	if dr.EffectiveDateTime != "2025-03-10" {
+		t.Fatalf("effectiveDateTime = %q", dr.EffectiveDateTime)
+	}
+
+	// Hemograma vira painel (2 membros); HIV referencia a observação direto.
+	if len(dr.Result) != 2 || len(observations) != 4 {
+		t.Fatalf("got %d results and %d observations", len(dr.Result), len(observations))
+	}
+	panel := observations[0]
+	if panel.Code.Text != "Hemograma" || len(panel.HasMember) != 2 {
+		t.Fatalf("unexpected panel: %+v", panel)
+	}
+
+	hemoglobin := observations[1]
+	if len(hemoglobin.Code.Coding) == 0 || hemoglobin.Code.Coding[0].System != SystemLOINC || hemoglobin.Code.Coding[0].Code != loinc {
+		t.Fatalf("expected LOINC coding, got %+v", hemoglobin.Code)
+	}
+	if q := hemoglobin.ValueQuantity; q == nil || *q.Value != hb || q.System != SystemUCUM || q.Code != ucum {
+		t.Fatalf("unexpected valueQuantity: %+v", q)
+	}
+	if len(hemoglobin.Interpretation) != 1 || hemoglobin.Interpretation[0].Coding[0].Code != "L" {
+		t.Fatalf("unexpected interpretation: %+v", hemoglobin.Interpretation)
+	}
+	if rr := hemoglobin.ReferenceRange; len(rr) != 1 || *rr[0].Low.Value != low || *rr[0].High.Value != high {
+		t.Fatalf("unexpected referenceRange: %+v", rr)
+	}
+
+	if observations[2].DataAbsentReason == nil {
+		t.Fatalf("item without value should carry dataAbsentReason")
+	}
+	if v := observations[3].ValueString; v == nil || *v != hivText {
+		t.Fatalf("qualitative value should map to valueString, got %v", v)
+	}
+
+	raw, err := json.Marshal(dr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	_ = json.Unmarshal(raw, &decoded)
+	if decoded["resourceType"] != ResourceDiagnosticReport {
+		t.Fatalf("resourceType missing from JSON: %s", raw)
+	}
+}
+
+func TestFromLabReport_RawTextFallback(t *testing.T) {
+	text := "GLICOSE 92 mg/dL"
+	report := &labs.LabReport{ID: uuid.New(), RawText: &text}
+
+	dr, _ := FromLabReport(report, nil, nil, nil)
+
+	if len(dr.PresentedForm) != 1 || dr.PresentedForm[0].Data == "" {
+		t.Fatalf("expected raw text attachment, got %+v", dr.PresentedForm)
+	}
+}
@@ -1,31 +1,54 @@
 package diagnostics
 
-import "github.com/google/uuid"
-
-// Observation representa cada resultado individual (FHIR Observation)
+// Observation representa cada resultado individual (FHIR Observation).
+// Exames com vários parâmetros viram uma Observation "painel" com hasMember.
 type Observation struct {
-	ID       uuid.UUID `json:"id"`
-	ReportID uuid.UUID `json:"report_id"`
+	ResourceType string       `json:"resourceType"` // sempre "Observation"
+	ID           string       `json:"id,omitempty"`
+	Meta         *Meta        `json:"meta,omitempty"`
+	Identifier   []Identifier `json:"identifier,omitempty"`
 
-	Status string `json:"status"` // final, preliminary
+	Status   string            `json:"status"` // final, preliminary
+	Category []CodeableConcept `json:"category,omitempty"`
 
 	// Code: O coração da interoperabilidade (LOINC Code)
 	// Ex: 718-7 para Hemoglobina
-	Code    string `json:"code"`
-	Display string `json:"display"` // Nome legível: "Hemoglobina"
+	Code CodeableConcept `json:"code"`
+
+	Subject           *Reference `json:"subject,omitempty"`
+	EffectiveDateTime string     `json:"effectiveDateTime,omitempty"`
+	Issued            string     `json:"issued,omitempty"`
 
-	// Value: FHIR separa valor, unidade e sistema
-	ValueQuantity ObservationValue `json:"value_quantity"`
+	// Value[x]: numérico com UCUM ou texto ("Não reagente")
+	ValueQuantity        *Quantity        `json:"valueQuantity,omitempty"`
+	ValueString          *string          `json:"valueString,omitempty"`
+	ValueCodeableConcept *CodeableConcept `json:"valueCodeableConcept,omitempty"`
+	ValueInteger         *int64           `json:"valueInteger,omitempty"`
+	DataAbsentReason     *CodeableConcept `json:"dataAbsentReason,omitempty"`
 
 	// Interpretation: H (High), L (Low), N (Normal)
-	Interpretation string `json:"interpretation,omitempty"`
+	Interpretation []CodeableConcept `json:"interpretation,omitempty"`
+
+	Note   []Annotation     `json:"note,omitempty"`
+	Method *CodeableConcept `json:"method,omitempty"`
+
+	ReferenceRange []ObservationReferenceRange `json:"referenceRange,omitempty"`
+	HasMember      []Reference                 `json:"hasMember,omitempty"`
+}
+
+type ObservationReferenceRange struct {
+	Low  *Quantity `json:"low,omitempty"`
+	High *Quantity `json:"high,omitempty"`
+	Text string    `json:"text,omitempty"`
+}
 
-	// ReferenceRange: Onde a IA salva os valores de referência extraídos
-	ReferenceRange string `json:"reference_range,omitempty"`
+type Annotation struct {
+	Text string `json:"text"`
 }
 
-type ObservationValue struct {
-	Value  string `json:"value"`  // Mantemos string para aceitar "< 0.01" ou "Positivo"
-	Unit   string `json:"unit"`   // Ex: "mg/dL"
-	System string `json:"system"` // Geralmente "http://unitsofmeasure.org" (UCUM)
+const ResourceObservation = "Observation"
+
+// LaboratoryCategory é a categoria "laboratory" de observation-category.
+func LaboratoryCategory() CodeableConcept {
+	return CodeableConcept{Coding: []Coding{{System: SystemObsCategory, Code: "laboratory", Display: "Laboratory"}}}
 }
@@ -0,0 +1,60 @@
+package diagnostics
+
+import "fmt"
+
+// OperationOutcome descreve por que uma requisição FHIR foi recusada.
+type OperationOutcome struct {
+	ResourceType string                  `json:"resourceType"` // sempre "OperationOutcome"
+	Issue        []OperationOutcomeIssue `json:"issue"`
+}
+
+type OperationOutcomeIssue struct {
+	Severity    string   `json:"severity"` // fatal, error, warning, information
+	Code        string   `json:"code"`     // tabela issue-type: invalid, required, value, ...
+	Diagnostics string   `json:"diagnostics,omitempty"`
+	Expression  []string `json:"expression,omitempty"` // FHIRPath do elemento com problema
+}
+
+const (
+	ResourceOperationOutcome = "OperationOutcome"
+
+	IssueSeverityError = "error"
+
+	IssueInvalid      = "invalid"
+	IssueRequired     = "required"
+	IssueValue        = "value"
+	IssueNotSupported = "not-supported"
+	IssueNotFound     = "not-found"
+	IssueDuplicate    = "duplicate"
+	IssueForbidden    = "forbidden"
+	IssueException    = "exception"
+	IssueProcessing   = "processing"
+)
+
+// NewOperationOutcome monta o recurso a partir das issues.
+func NewOperationOutcome(issues ...OperationOutcomeIssue) *OperationOutcome {
+	return &OperationOutcome{ResourceType: ResourceOperationOutcome, Issue: issues}
+}
+
+// NewIssue cria uma issue de severidade error apontando para expression.
+func NewIssue(code, expression, diagnostics string) OperationOutcomeIssue {
+	issue := OperationOutcomeIssue{Severity: IssueSeverityError, Code: code, Diagnostics: diagnostics}
+	if expression != "" {
+		issue.Expression = []string{expression}
+	}
+	return issue
+}
+
+// OutcomeError carrega o OperationOutcome de uma validação que falhou, para
+// que a camada HTTP responda no formato FHIR.
+type OutcomeError struct {
+	Outcome *OperationOutcome
+}
+
+func (e *OutcomeError) Error() string {
+	if e == nil || e.Outcome == nil || len(e.Outcome.Issue) == 0 {
+		return "fhir: operation outcome"
+	}
+	first := e.Outcome.Issue[0]
+	return fmt.Sprintf("fhir: %d issue(s), first: %s %v %s", len(e.Outcome.Issue), first.Code, first.Expression, first.Diagnostics)
+}
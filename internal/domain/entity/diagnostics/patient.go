@@ -0,0 +1,62 @@
+package diagnostics
+
+import (
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+)
+
+// Patient é o recurso FHIR mínimo para que subject resolva dentro do Bundle.
+type Patient struct {
+	ResourceType string       `json:"resourceType"` // sempre "Patient"
+	ID           string       `json:"id,omitempty"`
+	Identifier   []Identifier `json:"identifier,omitempty"`
+	Name         []HumanName  `json:"name,omitempty"`
+	Gender       string       `json:"gender,omitempty"`
+	BirthDate    string       `json:"birthDate,omitempty"`
+}
+
+const ResourcePatient = "Patient"
+
+// FromPatient identifica o paciente por CPF e, quando houver, CNS.
+func FromPatient(p *patient.Patient) *Patient {
+	if p == nil {
+		return nil
+	}
+
+	out := &Patient{
+		ResourceType: ResourcePatient,
+		ID:           p.ID.String(),
+		Gender:       fhirGender(p.Gender),
+	}
+	if p.CPF != "" {
+		out.Identifier = append(out.Identifier, Identifier{Use: "official", System: SystemCPF, Value: p.CPF})
+	}
+	if p.CNS != nil && *p.CNS != "" {
+		out.Identifier = append(out.Identifier, Identifier{Use: "official", System: SystemCNS, Value: *p.CNS})
+	}
+	if p.FullName != "" {
+		out.Name = []HumanName{{Use: "official", Text: p.FullName}}
+	}
+	if !p.BirthDate.IsZero() {
+		out.BirthDate = formatDate(p.BirthDate)
+	}
+	return out
+}
+
+// PatientReference referencia o paciente dentro de um Bundle.
+func PatientReference(p *Patient) *Reference {
+	return &Reference{Reference: URNUUID(p.ID), Type: ResourcePatient}
+}
+
+func fhirGender(g demographics.Gender) string {
+	switch g {
+	case demographics.GenderMale:
+		return "male"
+	case demographics.GenderFemale:
+		return "female"
+	case demographics.GenderOther:
+		return "other"
+	default:
+		return "unknown"
+	}
+}
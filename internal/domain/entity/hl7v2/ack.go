@@ -0,0 +1,152 @@
+package hl7v2
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AckCode é o MSA-1 (modo de reconhecimento original).
+type AckCode string
+
+const (
+	// AckAccept: mensagem processada e gravada.
+	AckAccept AckCode = "AA"
+	// AckError: mensagem entendida, mas o conteúdo não pôde ser gravado
+	// (paciente desconhecido, resultado inválido, falha interna).
+	AckError AckCode = "AE"
+	// AckReject: mensagem que não processamos (tipo, versão ou estrutura).
+	AckReject AckCode = "AR"
+)
+
+// ErrorCode segue a tabela HL7 0357 (ERR-3).
+type ErrorCode string
+
+const (
+	ErrorSegmentSequence        ErrorCode = "100"
+	ErrorRequiredFieldMissing   ErrorCode = "101"
+	ErrorDataType               ErrorCode = "102"
+	ErrorTableValueNotFound     ErrorCode = "103"
+	ErrorUnsupportedMessageType ErrorCode = "200"
+	ErrorUnsupportedEventCode   ErrorCode = "201"
+	ErrorUnsupportedVersion     ErrorCode = "203"
+	ErrorUnknownKey             ErrorCode = "204"
+	ErrorDuplicateKey           ErrorCode = "205"
+	ErrorApplicationInternal    ErrorCode = "207"
+)
+
+var errorCodeText = map[ErrorCode]string{
+	ErrorSegmentSequence:        "Segment sequence error",
+	ErrorRequiredFieldMissing:   "Required field missing",
+	ErrorDataType:               "Data type error",
+	ErrorTableValueNotFound:     "Table value not found",
+	ErrorUnsupportedMessageType: "Unsupported message type",
+	ErrorUnsupportedEventCode:   "Unsupported event code",
+	ErrorUnsupportedVersion:     "Unsupported version id",
+	ErrorUnknownKey:             "Unknown key identifier",
+	ErrorDuplicateKey:           "Duplicate key identifier",
+	ErrorApplicationInternal:    "Application internal error",
+}
+
+// Issue é um problema encontrado na mensagem; vira um segmento ERR no ACK.
+type Issue struct {
+	Segment  string // ex.: "OBX"
+	Sequence int    // ocorrência do segmento (1-based); 0 quando não se aplica
+	Field    int    // número do campo; 0 quando o problema é no segmento todo
+	Code     ErrorCode
+	Message  string
+}
+
+// Location descreve onde está o problema (ex.: "OBX[2]-5"); vazio quando é na mensagem toda.
+func (i Issue) Location() string {
+	if i.Segment == "" {
+		return ""
+	}
+	loc := i.Segment
+	if i.Sequence > 0 {
+		loc += "[" + strconv.Itoa(i.Sequence) + "]"
+	}
+	if i.Field > 0 {
+		loc += "-" + strconv.Itoa(i.Field)
+	}
+	return loc
+}
+
+func (i Issue) Error() string {
+	if loc := i.Location(); loc != "" {
+		return loc + ": " + i.Message
+	}
+	return i.Message
+}
+
+// NewACK monta a resposta à mensagem original. orig pode ser nil quando nem o
+// MSH pôde ser lido; nesse caso o ACK sai sem MSA-2.
+func NewACK(orig *Message, code AckCode, text string, issues []Issue, now time.Time) string {
+	msh := orig.MSH()
+
+	event := msh.Get(9, 2)
+	messageType := "ACK"
+	if event != "" {
+		messageType += "^" + Escape(event) + "^ACK"
+	}
+	processingID := firstNonEmpty(msh.Field(11), "P")
+	version := firstNonEmpty(msh.Field(12), "2.5.1")
+
+	segments := []Segment{
+		NewSegment("MSH", "|", `^~\&`,
+			msh.Field(5), msh.Field(6), // quem recebeu responde como remetente
+			msh.Field(3), msh.Field(4),
+			formatTS(now), "",
+			messageType,
+			controlID(now),
+			processingID,
+			version,
+		),
+		NewSegment("MSA", string(code), Escape(orig.ControlID()), Escape(text)),
+	}
+	for _, issue := range issues {
+		segments = append(segments, issue.segment())
+	}
+
+	lines := make([]string, len(segments))
+	for i, seg := range segments {
+		lines[i] = seg.String()
+	}
+	return strings.Join(lines, "\r") + "\r"
+}
+
+// segment monta ERR no layout da v2.5: ERR-2 localização, ERR-3 código,
+// ERR-4 severidade e ERR-8 mensagem para o usuário.
+func (i Issue) segment() Segment {
+	location := ""
+	if i.Segment != "" {
+		location = i.Segment + "^" + positiveOrEmpty(i.Sequence) + "^" + positiveOrEmpty(i.Field)
+	}
+	code := string(i.Code) + "^" + errorCodeText[i.Code] + "^HL70357"
+	return NewSegment("ERR", "", location, code, "E", "", "", "", Escape(i.Message))
+}
+
+func positiveOrEmpty(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// controlID gera o MSH-10 do ACK (até 20 caracteres, como pede o tipo ST).
+func controlID(now time.Time) string {
+	return "ACK" + strings.ToUpper(strconv.FormatInt(now.UnixNano(), 36))
+}
+
+func formatTS(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "+0000"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
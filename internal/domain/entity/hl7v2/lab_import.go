@@ -0,0 +1,634 @@
+package hl7v2
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+
+	"github.com/google/uuid"
+)
+
+// Regras de importação ORU^R01 -> labs:
+//   - Uma mensagem traz um paciente (PID); o paciente é localizado por CPF/CNS.
+//   - Cada OBR vira um LabResult e cada OBX seguinte, um LabResultItem.
+//   - OBX com status D, W, X ou N (apagado, errado, não obtido, não pedido) é ignorado.
+//   - Tipos NM/SN, unidade UCUM (OBX-6), faixa (OBX-7) e flags (OBX-8)
+//     prevalecem sobre o que seria inferido do texto.
+
+// Sistemas de codificação usados em CE/CWE (tabela HL7 0396).
+const (
+	CodingSystemLOINC = "LN"
+	CodingSystemUCUM  = "UCUM"
+)
+
+// SupportedVersions são as versões de MSH-12 que sabemos ler.
+var SupportedVersions = map[string]bool{
+	"2.3": true, "2.3.1": true, "2.4": true, "2.5": true, "2.5.1": true, "2.6": true, "2.7": true, "2.7.1": true, "2.8": true,
+}
+
+// OBX-11 com resultado utilizável: final, corrigido, preliminar, registrado e parcial.
+var acceptedObservationStatuses = map[string]bool{
+	"": true, "F": true, "C": true, "P": true, "R": true, "S": true, "A": true,
+}
+
+// reRange reconhece OBX-7 numérico: "12.0-16.0", "<5", ">=1.5".
+var reRange = regexp.MustCompile(`^\s*(?:(-?\d+(?:\.\d+)?)\s*-\s*(-?\d+(?:\.\d+)?)|([<>]=?)\s*(-?\d+(?:\.\d+)?))\s*$`)
+
+// PatientIdentifiers são os documentos encontrados no PID.
+type PatientIdentifiers struct {
+	CPF string
+	CNS string
+}
+
+func (p PatientIdentifiers) IsEmpty() bool {
+	return p.CPF == "" && p.CNS == ""
+}
+
+// CheckORU confere tipo, versão e estrutura antes de qualquer consulta ao banco.
+// Os problemas aqui levam a um ACK AR.
+func CheckORU(msg *Message) []Issue {
+	code, event := msg.Type()
+	if code != "ORU" {
+		return []Issue{{Segment: "MSH", Sequence: 1, Field: 9, Code: ErrorUnsupportedMessageType,
+			Message: fmt.Sprintf("tipo de mensagem %q não suportado: envie ORU^R01", code)}}
+	}
+	if event != "R01" {
+		return []Issue{{Segment: "MSH", Sequence: 1, Field: 9, Code: ErrorUnsupportedEventCode,
+			Message: fmt.Sprintf("evento %q não suportado: envie ORU^R01", event)}}
+	}
+	if v := msg.Version(); v != "" && !SupportedVersions[v] {
+		return []Issue{{Segment: "MSH", Sequence: 1, Field: 12, Code: ErrorUnsupportedVersion,
+			Message: fmt.Sprintf("versão %q não suportada", v)}}
+	}
+
+	var issues []Issue
+	if n := msg.count("PID"); n == 0 {
+		issues = append(issues, Issue{Segment: "PID", Code: ErrorSegmentSequence, Message: "a mensagem deve conter um PID"})
+	} else if n > 1 {
+		issues = append(issues, Issue{Segment: "PID", Sequence: 2, Code: ErrorSegmentSequence, Message: "envie um paciente por mensagem"})
+	}
+	if msg.count("OBR") == 0 {
+		issues = append(issues, Issue{Segment: "OBR", Code: ErrorSegmentSequence, Message: "a mensagem deve conter ao menos um OBR"})
+	}
+	return issues
+}
+
+// Identifiers lê CPF e CNS de PID-3 (pelo tipo em CX-5 ou pela autoridade em
+// CX-4) e, na falta deles, o CPF de PID-19, onde alguns sistemas o colocam.
+func (m *Message) Identifiers() PatientIdentifiers {
+	var ids PatientIdentifiers
+	pid := m.first("PID")
+	if pid == nil {
+		return ids
+	}
+
+	for _, cx := range pid.Repetitions(3) {
+		value := demographics.CleanDigits(component(cx, 1))
+		if value == "" {
+			continue
+		}
+		kind := strings.ToUpper(component(cx, 5) + " " + component(cx, 4))
+		switch {
+		case strings.Contains(kind, "CPF") && ids.CPF == "":
+			ids.CPF = value
+		case strings.Contains(kind, "CNS") && ids.CNS == "":
+			ids.CNS = value
+		}
+	}
+	if ids.CPF == "" {
+		if cpf := demographics.CleanDigits(pid.Get(19, 1)); len(cpf) == 11 {
+			ids.CPF = cpf
+		}
+	}
+	return ids
+}
+
+type labImport struct {
+	msg     *Message
+	patient *patient.Patient
+	catalog *labs.AnalyteCatalog
+	issues  []Issue
+}
+
+// ToLabReport converte a ORU^R01 no laudo do paciente. Quando há issues, o
+// laudo é nil e as issues descrevem todos os problemas encontrados.
+func ToLabReport(
+	msg *Message,
+	p *patient.Patient,
+	uploadedBy uuid.UUID,
+	catalog *labs.AnalyteCatalog,
+) (*labs.LabReport, []Issue) {
+	if issues := CheckORU(msg); len(issues) > 0 {
+		return nil, issues
+	}
+	if p == nil {
+		return nil, []Issue{{Segment: "PID", Sequence: 1, Field: 3, Code: ErrorUnknownKey, Message: "paciente não encontrado"}}
+	}
+
+	report, err := labs.NewLabReport(p.ID.String(), uploadedBy.String())
+	if err != nil {
+		return nil, []Issue{{Code: ErrorApplicationInternal, Message: err.Error()}}
+	}
+	report.Source = labs.SourceHL7v2
+
+	imp := &labImport{msg: msg, patient: p, catalog: catalog}
+	imp.fillHeader(report)
+	imp.fillResults(report)
+
+	if len(imp.issues) > 0 {
+		return nil, imp.issues
+	}
+	if len(report.TestResults) == 0 {
+		return nil, []Issue{{Segment: "OBX", Code: ErrorRequiredFieldMissing, Message: "nenhum OBX com resultado"}}
+	}
+
+	report.Normalize()
+	report.UpdatedAt = time.Now().UTC()
+	return report, nil
+}
+
+func (imp *labImport) issue(segment string, seq, field int, code ErrorCode, format string, args ...any) {
+	imp.issues = append(imp.issues, Issue{
+		Segment: segment, Sequence: seq, Field: field, Code: code,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (imp *labImport) fillHeader(report *labs.LabReport) {
+	msh := imp.msg.MSH()
+	if lab := msh.Get(4, 1); lab != "" {
+		report.LabName = &lab
+	}
+
+	pid := imp.msg.first("PID")
+	if name := personName(pid.Repetitions(5), 1, imp.msg.Delimiters.Subcomponent); name != "" {
+		report.PatientName = &name
+	}
+	if t, ok := imp.parseTS(pid, 1, 7); ok {
+		dob := t.Truncate(24 * time.Hour)
+		report.PatientDOB = &dob
+	}
+}
+
+// fillResults percorre os segmentos em ordem: OBR abre um exame, OBX e SPM
+// seguintes pertencem a ele.
+func (imp *labImport) fillResults(report *labs.LabReport) {
+	var (
+		result   *labs.LabResult
+		obrSeq   int
+		obxSeq   int
+		earliest *time.Time
+	)
+
+	flush := func() {
+		if result == nil || len(result.Items) == 0 {
+			return
+		}
+		if result.CollectedAt != nil && (earliest == nil || result.CollectedAt.Before(*earliest)) {
+			earliest = result.CollectedAt
+		}
+		result.Normalize()
+		report.TestResults = append(report.TestResults, *result)
+	}
+
+	for i := range imp.msg.Segments {
+		seg := &imp.msg.Segments[i]
+		switch seg.Name {
+		case "OBR":
+			flush()
+			obrSeq++
+			result = imp.newResult(report, seg, obrSeq)
+		case "OBX":
+			obxSeq++
+			if result == nil {
+				// OBR inválido já gerou issue; só reclamamos do OBX sem OBR.
+				if obrSeq == 0 {
+					imp.issue("OBX", obxSeq, 0, ErrorSegmentSequence, "OBX antes de qualquer OBR")
+				}
+				continue
+			}
+			if item := imp.newItem(result, seg, obxSeq); item != nil {
+				result.Items = append(result.Items, *item)
+			}
+		case "SPM":
+			// v2.5+: o material fica em SPM-4 em vez de OBR-15.
+			if result != nil && result.Material == nil {
+				if material := codedText(seg.Repetitions(4)); material != "" {
+					result.Material = &material
+				}
+			}
+		}
+	}
+	flush()
+
+	// Data do laudo: coleta mais antiga; sem ela, a data da mensagem.
+	if earliest == nil {
+		if t, ok := imp.parseTS(imp.msg.MSH(), 1, 7); ok {
+			earliest = &t
+		}
+	}
+	if earliest != nil {
+		day := earliest.UTC().Truncate(24 * time.Hour)
+		report.ReportDate = &day
+	}
+}
+
+func (imp *labImport) newResult(report *labs.LabReport, obr *Segment, seq int) *labs.LabResult {
+	name := codedText(obr.Repetitions(4))
+	if name == "" {
+		imp.issue("OBR", seq, 4, ErrorRequiredFieldMissing, "OBR-4 (exame) é obrigatório")
+		return nil
+	}
+
+	result, err := labs.NewLabResult(report.ID.String(), name)
+	if err != nil {
+		imp.issue("OBR", seq, 4, ErrorRequiredFieldMissing, "%s", err.Error())
+		return nil
+	}
+
+	// Um OBR preliminar deixa o laudo inteiro preliminar.
+	switch status := resultStatus(obr.Get(25, 1)); {
+	case status == labs.StatusPreliminary:
+		report.Status = status
+	case status == labs.StatusCorrected && report.Status != labs.StatusPreliminary:
+		report.Status = status
+	}
+
+	if t, ok := imp.parseTS(obr, seq, 7); ok {
+		result.CollectedAt = &t
+	}
+	if t, ok := imp.parseTS(obr, seq, 22); ok {
+		result.ReleaseAt = &t
+	}
+	if material := specimenSource(obr.Get(15, 1), imp.msg.Delimiters.Subcomponent); material != "" {
+		result.Material = &material
+	}
+
+	if report.RequestingDoctor == nil {
+		if doctor := personName(obr.Repetitions(16), 2, imp.msg.Delimiters.Subcomponent); doctor != "" {
+			report.RequestingDoctor = &doctor
+		}
+	}
+	if report.TechnicalManager == nil {
+		if manager := interpreterName(obr.Get(32, 1), imp.msg.Delimiters.Subcomponent); manager != "" {
+			report.TechnicalManager = &manager
+		}
+	}
+	return result
+}
+
+func (imp *labImport) newItem(result *labs.LabResult, obx *Segment, seq int) *labs.LabResultItem {
+	if !acceptedObservationStatuses[obx.Get(11, 1)] {
+		return nil
+	}
+
+	code := obx.Repetitions(3)
+	name := codedText(code)
+	if name == "" {
+		imp.issue("OBX", seq, 3, ErrorRequiredFieldMissing, "OBX-3 (parâmetro) é obrigatório")
+		return nil
+	}
+
+	item, err := labs.NewLabResultItem(result.ID.String(), name)
+	if err != nil {
+		imp.issue("OBX", seq, 3, ErrorRequiredFieldMissing, "%s", err.Error())
+		return nil
+	}
+
+	valueType := obx.Get(2, 1)
+	numeric, comparator, ok := imp.parseValue(obx, seq, valueType)
+	if !ok {
+		return nil
+	}
+	value := observationValue(obx, valueType)
+	if numeric != nil {
+		// Texto no padrão dos laudos ("14,2"), como no upload de documento.
+		value = strings.TrimSpace(string(comparator) + " " + formatNumberPTBR(*numeric))
+	}
+	if value != "" {
+		item.ResultValue = &value
+	}
+
+	unitCode, unitText, unitSystem := obx.Get(6, 1), obx.Get(6, 2), obx.Get(6, 3)
+	if unit := firstNonEmpty(unitText, unitCode); unit != "" {
+		item.ResultUnit = &unit
+	}
+	ref, hasRange := parseRange(obx.Get(7, 1))
+	if ref.text != "" {
+		item.ReferenceText = &ref.text
+	}
+
+	sex := imp.patient.Gender
+	if sex == "" {
+		sex = demographics.GenderUnknown
+	}
+	item.Normalize()
+	item.ParseStructuredResult(sex)
+
+	// Dados tipados do HL7 prevalecem sobre o texto.
+	if numeric != nil {
+		item.NumericValue = numeric
+		item.QualitativeValue = nil
+		item.Comparator = comparator
+	}
+	if unitCode != "" && (unitSystem == CodingSystemUCUM || unitSystem == "") {
+		if ucum, ok := labs.ParseUCUM(unitCode); ok {
+			item.UCUMUnit = &ucum
+		}
+	}
+	if hasRange {
+		item.ReferenceLow, item.ReferenceHigh = ref.Low, ref.High
+		item.Interpretation = labs.Interpret(item.NumericValue, item.QualitativeValue, ref.ReferenceRange)
+	} else if numeric != nil && item.ReferenceText != nil {
+		item.Interpretation = labs.Interpret(item.NumericValue, nil, labs.ParseReferenceRange(*item.ReferenceText, sex))
+	}
+	if interp, ok := abnormalFlag(obx.Repetitions(8)); ok {
+		item.Interpretation = interp
+	}
+
+	if method := codedText(obx.Repetitions(17)); method != "" && result.Method == nil {
+		result.Method = &method
+	}
+	if result.CollectedAt == nil {
+		if t, ok := imp.parseTS(obx, seq, 14); ok {
+			result.CollectedAt = &t
+		}
+	}
+
+	imp.linkAnalyte(item, code)
+	return item
+}
+
+// parseValue lê OBX-5 dos tipos numéricos (NM e SN). Outros tipos seguem como texto.
+func (imp *labImport) parseValue(obx *Segment, seq int, valueType string) (*float64, labs.Comparator, bool) {
+	switch valueType {
+	case "NM":
+		raw := obx.Get(5, 1)
+		if raw == "" {
+			return nil, labs.ComparatorNone, true
+		}
+		v, ok := parseNM(raw)
+		if !ok {
+			imp.issue("OBX", seq, 5, ErrorDataType, "valor NM inválido: %q", raw)
+			return nil, labs.ComparatorNone, false
+		}
+		return &v, labs.ComparatorNone, true
+	case "SN":
+		// SN: comparador ^ número [^ separador ^ número]; só o primeiro número é usado.
+		comparator := labs.Comparator(obx.Get(5, 1))
+		if !comparator.IsValid() {
+			imp.issue("OBX", seq, 5, ErrorDataType, "comparador SN não suportado: %q", comparator)
+			return nil, labs.ComparatorNone, false
+		}
+		raw := obx.Get(5, 2)
+		v, ok := parseNM(raw)
+		if !ok {
+			imp.issue("OBX", seq, 5, ErrorDataType, "valor SN inválido: %q", raw)
+			return nil, labs.ComparatorNone, false
+		}
+		return &v, comparator, true
+	default:
+		return nil, labs.ComparatorNone, true
+	}
+}
+
+// linkAnalyte prioriza o LOINC de OBX-3 (identificador ou alternativo); o nome é o último recurso.
+func (imp *labImport) linkAnalyte(item *labs.LabResultItem, code [][]string) {
+	if len(code) > 0 {
+		cwe := code[0]
+		for _, pair := range [][2]int{{1, 3}, {4, 6}} {
+			if component(cwe, pair[1]) != CodingSystemLOINC {
+				continue
+			}
+			if a := imp.catalog.GetByLOINC(component(cwe, pair[0])); a != nil {
+				item.LinkAnalyteCode(a.Code)
+				return
+			}
+		}
+	}
+	item.LinkAnalyte(imp.catalog)
+}
+
+// parseTS lê um campo TS/DTM (YYYY[MM[DD[HH[MM[SS[.S]]]]]][+/-ZZZZ]); vazio não gera issue.
+func (imp *labImport) parseTS(seg *Segment, seq, field int) (time.Time, bool) {
+	raw := seg.Get(field, 1)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	t, ok := ParseTS(raw)
+	if !ok {
+		imp.issue(seg.Name, seq, field, ErrorDataType, "data inválida: %q", raw)
+	}
+	return t, ok
+}
+
+// ParseTS converte datas HL7; sem fuso, assume UTC.
+func ParseTS(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	zone := ""
+	if i := strings.IndexAny(raw, "+-"); i > 0 {
+		raw, zone = raw[:i], raw[i:]
+	}
+	if i := strings.IndexByte(raw, '.'); i > 0 {
+		raw = raw[:i]
+	}
+
+	layouts := map[int]string{4: "2006", 6: "200601", 8: "20060102", 10: "2006010215", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[len(raw)]
+	if !ok {
+		return time.Time{}, false
+	}
+	if zone != "" {
+		t, err := time.Parse(layout+"-0700", raw+zone)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t.UTC(), true
+	}
+	t, err := time.Parse(layout, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.UTC(), true
+}
+
+// parseNM lê o tipo NM: sinal opcional e ponto decimal ("6.500" é 6,5).
+func parseNM(raw string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+type hl7Range struct {
+	labs.ReferenceRange
+	text string
+}
+
+// parseRange converte OBX-7 numérico para a faixa estruturada e para o texto
+// no padrão dos laudos; faixas em texto livre seguem como vieram.
+func parseRange(raw string) (hl7Range, bool) {
+	raw = strings.TrimSpace(raw)
+	m := reRange.FindStringSubmatch(raw)
+	if m == nil {
+		return hl7Range{text: raw}, false
+	}
+
+	r := hl7Range{ReferenceRange: labs.ReferenceRange{LowInclusive: true, HighInclusive: true}}
+	if m[1] != "" {
+		low, _ := parseNM(m[1])
+		high, _ := parseNM(m[2])
+		r.Low, r.High = &low, &high
+		r.text = formatNumberPTBR(low) + " a " + formatNumberPTBR(high)
+		return r, true
+	}
+
+	v, _ := parseNM(m[4])
+	switch m[3] {
+	case "<":
+		r.High, r.HighInclusive = &v, false
+		r.text = "Inferior a " + formatNumberPTBR(v)
+	case "<=":
+		r.High = &v
+		r.text = "Até " + formatNumberPTBR(v)
+	case ">":
+		r.Low, r.LowInclusive = &v, false
+		r.text = "Superior a " + formatNumberPTBR(v)
+	case ">=":
+		r.Low = &v
+		r.text = "Maior ou igual a " + formatNumberPTBR(v)
+	}
+	return r, true
+}
+
+// formatNumberPTBR escreve com vírgula decimal, como nos laudos ("14,2").
+func formatNumberPTBR(v float64) string {
+	return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", ",", 1)
+}
+
+func (m *Message) first(name string) *Segment {
+	for i := range m.Segments {
+		if m.Segments[i].Name == name {
+			return &m.Segments[i]
+		}
+	}
+	return nil
+}
+
+func (m *Message) count(name string) int {
+	n := 0
+	for _, seg := range m.Segments {
+		if seg.Name == name {
+			n++
+		}
+	}
+	return n
+}
+
+// observationValue devolve OBX-5 como texto; TX/FT com repetições viram linhas.
+func observationValue(obx *Segment, valueType string) string {
+	reps := obx.Repetitions(5)
+	switch valueType {
+	case "CE", "CWE", "CNE":
+		return codedText(reps)
+	case "SN":
+		if len(reps) == 0 {
+			return ""
+		}
+		return strings.TrimSpace(component(reps[0], 1) + " " + component(reps[0], 2))
+	}
+
+	lines := make([]string, 0, len(reps))
+	for _, rep := range reps {
+		lines = append(lines, strings.Join(rep, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codedText devolve o texto de um CE/CWE (componente 2), senão o código.
+func codedText(reps [][]string) string {
+	if len(reps) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(firstNonEmpty(component(reps[0], 2), component(reps[0], 1)))
+}
+
+// personName monta "Nome Sobrenome" a partir de XPN (familyAt=1) ou XCN (familyAt=2).
+func personName(reps [][]string, familyAt int, sub byte) string {
+	if len(reps) == 0 {
+		return ""
+	}
+	xpn := reps[0]
+	family := component(xpn, familyAt)
+	// Sobrenome pode vir como subcomponentes (sobrenome&prefixo...).
+	if i := strings.IndexByte(family, sub); i >= 0 {
+		family = family[:i]
+	}
+	parts := []string{component(xpn, familyAt+1), component(xpn, familyAt+2), family}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// specimenSource lê OBR-15.1 (código&texto do material).
+func specimenSource(raw string, sub byte) string {
+	parts := strings.Split(raw, string(sub))
+	if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
+		return strings.TrimSpace(parts[1])
+	}
+	return strings.TrimSpace(parts[0])
+}
+
+// interpreterName lê OBR-32.1 (CNN: id&sobrenome&nome).
+func interpreterName(raw string, sub byte) string {
+	parts := strings.Split(raw, string(sub))
+	if len(parts) < 2 {
+		return ""
+	}
+	given := ""
+	if len(parts) > 2 {
+		given = parts[2]
+	}
+	return strings.Join(strings.Fields(given+" "+parts[1]), " ")
+}
+
+// abnormalFlag usa a primeira flag de OBX-8 que conhecemos.
+func abnormalFlag(reps [][]string) (labs.Interpretation, bool) {
+	for _, rep := range reps {
+		flag := strings.ToUpper(component(rep, 1))
+		if flag == "AA" {
+			flag = string(labs.InterpretationAbnormal)
+		}
+		interp := labs.Interpretation(flag)
+		if interp != labs.InterpretationUnknown && interp.IsValid() {
+			return interp, true
+		}
+	}
+	return labs.InterpretationUnknown, false
+}
+
+func component(values []string, n int) string {
+	if n <= 0 || n > len(values) {
+		return ""
+	}
+	return strings.TrimSpace(values[n-1])
+}
+
+// resultStatus traduz o OBR-25 (tabela 0123): P, A e R ainda não estão
+// liberados; C é correção; o restante (F em geral) é tratado como final.
+func resultStatus(code string) labs.ReportStatus {
+	switch strings.ToUpper(strings.TrimSpace(code)) {
+	case "P", "A", "R":
+		return labs.StatusPreliminary
+	case "C":
+		return labs.StatusCorrected
+	default:
+		return labs.StatusFinal
+	}
+}
@@ -0,0 +1,183 @@
+package hl7v2
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+
+	"github.com/google/uuid"
+)
+
+const sampleORU = "MSH|^~\\&|LIS|LAB CENTRAL|SONNDA|SONNDA|20250311101500-0300||ORU^R01^ORU_R01|MSG0001|P|2.5.1\r" +
+	"PID|1||12345678909^^^RFB^CPF~898001160660005^^^MS^CNS||SILVA^MARIA||19800102|F\r" +
+	"ORC|RE|PED123\r" +
+	"OBR|1|PED123|LAB456|58410-2^Hemograma^LN|||20250310073000-0300||||||||SANGUE&Sangue total|CRM123^SOUZA^JOAO||||||20250310160000-0300|||F|||||||99&OLIVEIRA&ANA\r" +
+	"OBX|1|NM|718-7^Hemoglobina^LN||11.2|g/dL^g/dL^UCUM|12.0-16.0|L|||F\r" +
+	"OBX|2|NM|777-3^Plaquetas^LN||250000|/uL^/uL^UCUM|150000-450000|N|||F\r" +
+	"OBX|3|ST|5000-0^Observação^L||amostra lipêmica||||||X\r" +
+	"OBR|2|PED123|LAB457|2345-7^Glicose^LN|||20250310073000-0300\r" +
+	"OBX|1|SN|2345-7^Glicose^LN||<^40|mg/dL^mg/dL^UCUM|<100||||F\r" +
+	"SPM|1|||SER^Soro\r" +
+	"OBR|3|PED123|LAB458|5196-1^HBsAg^LN\r" +
+	"OBX|1|CWE|5196-1^HBsAg^LN||260415000^Não reagente^SCT|||N|||F\r"
+
+func samplePatient() *patient.Patient {
+	cns := "898001160660005"
+	return &patient.Patient{ID: uuid.New(), CPF: "12345678909", CNS: &cns, Gender: demographics.GenderFemale}
+}
+
+func TestIdentifiers(t *testing.T) {
+	msg, err := Parse([]byte(sampleORU))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := msg.Identifiers()
+	if ids.CPF != "12345678909" || ids.CNS != "898001160660005" {
+		t.Fatalf("identifiers = %+v", ids)
+	}
+
+	// CPF em PID-19 quando PID-3 só traz o prontuário do laboratório.
+	msg, _ = Parse([]byte("MSH|^~\\&|LIS|LAB|||20250311||ORU^R01|1|P|2.3\rPID|1||LAB-998^^^LAB^MR||SILVA^MARIA||||||||||||||123.456.789-09\r"))
+	if ids := msg.Identifiers(); ids.CPF != "12345678909" || ids.CNS != "" {
+		t.Fatalf("identifiers = %+v", ids)
+	}
+}
+
+func TestToLabReport(t *testing.T) {
+	msg, err := Parse([]byte(sampleORU))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := samplePatient()
+	hbLOINC := "718-7"
+	catalog := labs.NewAnalyteCatalog([]labs.Analyte{{Code: "hemoglobin", LOINC: &hbLOINC, DisplayName: "Hemoglobina"}})
+	uploader := uuid.New()
+
+	report, issues := ToLabReport(msg, p, uploader, catalog)
+	if len(issues) > 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+
+	if report.PatientID != p.ID || report.UploadedBy != uploader {
+		t.Fatalf("unexpected ownership: %+v", report)
+	}
+	if report.LabName == nil || *report.LabName != "LAB CENTRAL" {
+		t.Fatalf("lab name = %v", report.LabName)
+	}
+	if report.PatientName == nil || *report.PatientName != "MARIA SILVA" {
+		t.Fatalf("patient name = %v", report.PatientName)
+	}
+	if report.RequestingDoctor == nil || *report.RequestingDoctor != "JOAO SOUZA" {
+		t.Fatalf("requesting doctor = %v", report.RequestingDoctor)
+	}
+	if report.TechnicalManager == nil || *report.TechnicalManager != "ANA OLIVEIRA" {
+		t.Fatalf("technical manager = %v", report.TechnicalManager)
+	}
+	if want := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC); report.ReportDate == nil || !report.ReportDate.Equal(want) {
+		t.Fatalf("report date = %v", report.ReportDate)
+	}
+
+	if len(report.TestResults) != 3 {
+		t.Fatalf("got %d results", len(report.TestResults))
+	}
+
+	blood := report.TestResults[0]
+	if blood.TestName != "Hemograma" || len(blood.Items) != 2 {
+		t.Fatalf("hemograma = %+v (OBX com status X deve ser ignorado)", blood)
+	}
+	if blood.Material == nil || *blood.Material != "Sangue total" {
+		t.Fatalf("material = %v", blood.Material)
+	}
+	if want := time.Date(2025, 3, 10, 10, 30, 0, 0, time.UTC); blood.CollectedAt == nil || !blood.CollectedAt.Equal(want) {
+		t.Fatalf("collected at = %v", blood.CollectedAt)
+	}
+
+	hb := blood.Items[0]
+	if hb.NumericValue == nil || *hb.NumericValue != 11.2 || hb.ResultValue == nil || *hb.ResultValue != "11,2" {
+		t.Fatalf("hemoglobin value = %v / %v", hb.NumericValue, hb.ResultValue)
+	}
+	if hb.UCUMUnit == nil || *hb.UCUMUnit != "g/dL" {
+		t.Fatalf("hemoglobin unit = %v", hb.UCUMUnit)
+	}
+	if hb.ReferenceLow == nil || *hb.ReferenceLow != 12 || hb.ReferenceHigh == nil || *hb.ReferenceHigh != 16 {
+		t.Fatalf("hemoglobin reference = %v-%v", hb.ReferenceLow, hb.ReferenceHigh)
+	}
+	if hb.ReferenceText == nil || *hb.ReferenceText != "12 a 16" {
+		t.Fatalf("hemoglobin reference text = %v", hb.ReferenceText)
+	}
+	if hb.Interpretation != labs.InterpretationLow {
+		t.Fatalf("hemoglobin interpretation = %q", hb.Interpretation)
+	}
+	if hb.AnalyteCode == nil || *hb.AnalyteCode != "hemoglobin" || hb.AnalyteLink != labs.AnalyteLinkCode {
+		t.Fatalf("hemoglobin analyte = %v (%q)", hb.AnalyteCode, hb.AnalyteLink)
+	}
+
+	if platelets := blood.Items[1]; platelets.NumericValue == nil || *platelets.NumericValue != 250000 {
+		t.Fatalf("platelets = %v", platelets.NumericValue)
+	}
+
+	glucose := report.TestResults[1].Items[0]
+	if glucose.Comparator != labs.ComparatorLess || glucose.NumericValue == nil || *glucose.NumericValue != 40 {
+		t.Fatalf("glucose = %v %v", glucose.Comparator, glucose.NumericValue)
+	}
+	if report.TestResults[1].Material == nil || *report.TestResults[1].Material != "Soro" {
+		t.Fatalf("SPM material = %v", report.TestResults[1].Material)
+	}
+
+	hbsag := report.TestResults[2].Items[0]
+	if hbsag.QualitativeValue == nil || *hbsag.QualitativeValue != "Não reagente" || hbsag.Interpretation != labs.InterpretationNormal {
+		t.Fatalf("HBsAg = %v %q", hbsag.QualitativeValue, hbsag.Interpretation)
+	}
+}
+
+func TestToLabReport_ResultStatus(t *testing.T) {
+	cases := map[string]labs.ReportStatus{
+		"F": labs.StatusFinal,
+		"C": labs.StatusCorrected,
+		"P": labs.StatusPreliminary,
+	}
+	for code, want := range cases {
+		msg, err := Parse([]byte(strings.Replace(sampleORU, "|||F|||||||99", "|||"+code+"|||||||99", 1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, issues := ToLabReport(msg, samplePatient(), uuid.New(), nil)
+		if len(issues) > 0 {
+			t.Fatalf("OBR-25 %s: unexpected issues: %v", code, issues)
+		}
+		if report.Status != want {
+			t.Fatalf("OBR-25 %s: status = %q, want %q", code, report.Status, want)
+		}
+	}
+}
+
+func TestToLabReport_Issues(t *testing.T) {
+	p := samplePatient()
+
+	adt, _ := Parse([]byte("MSH|^~\\&|HIS|H|||20250311||ADT^A01|1|P|2.5\rPID|1\r"))
+	if _, issues := ToLabReport(adt, p, uuid.New(), nil); len(issues) != 1 || issues[0].Code != ErrorUnsupportedMessageType {
+		t.Fatalf("ADT issues = %v", issues)
+	}
+
+	bad := strings.Replace(sampleORU, "||11.2|", "||onze|", 1)
+	bad = strings.Replace(bad, "58410-2^Hemograma^LN", "", 1)
+	msg, _ := Parse([]byte(bad))
+	_, issues := ToLabReport(msg, p, uuid.New(), nil)
+	if len(issues) != 1 || issues[0].Segment != "OBR" || issues[0].Field != 4 {
+		t.Fatalf("issues = %v", issues)
+	}
+
+	msg, _ = Parse([]byte(strings.Replace(sampleORU, "||11.2|", "||onze|", 1)))
+	_, issues = ToLabReport(msg, p, uuid.New(), nil)
+	if len(issues) != 1 || issues[0].Code != ErrorDataType || issues[0].Error() != `OBX[1]-5: valor NM inválido: "onze"` {
+		t.Fatalf("issues = %v", issues)
+	}
+
+	if _, issues := ToLabReport(msg, nil, uuid.New(), nil); len(issues) != 1 || issues[0].Code != ErrorUnknownKey {
+		t.Fatalf("unknown patient issues = %v", issues)
+	}
+}
@@ -0,0 +1,219 @@
+// Package hl7v2 lê mensagens HL7 v2 (ER7, o formato com pipes) usadas pelos
+// sistemas de laboratório que ainda não falam FHIR.
+package hl7v2
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrEmptyMessage = errors.New("hl7v2: mensagem vazia")
+	ErrMissingMSH   = errors.New("hl7v2: a mensagem deve começar com o segmento MSH")
+	ErrInvalidMSH   = errors.New("hl7v2: delimitadores do MSH inválidos")
+)
+
+// Delimiters são os caracteres de MSH-1 e MSH-2.
+type Delimiters struct {
+	Field        byte
+	Component    byte
+	Repetition   byte
+	Escape       byte
+	Subcomponent byte
+}
+
+// DefaultDelimiters é o conjunto "|^~\&" usado em praticamente todos os sistemas.
+var DefaultDelimiters = Delimiters{Field: '|', Component: '^', Repetition: '~', Escape: '\\', Subcomponent: '&'}
+
+// Message é uma mensagem HL7 v2 já separada em segmentos.
+type Message struct {
+	Delimiters Delimiters
+	Segments   []Segment
+}
+
+// Segment guarda os campos crus; os acessores removem os escapes.
+type Segment struct {
+	Name   string
+	fields []string
+	delims *Delimiters
+}
+
+// Parse separa a mensagem em segmentos. Aceita \r (padrão), \n ou \r\n entre
+// segmentos, já que muitos sistemas e ferramentas de teste trocam o terminador.
+func Parse(raw []byte) (*Message, error) {
+	text := strings.TrimSpace(string(raw))
+	if text == "" {
+		return nil, ErrEmptyMessage
+	}
+	if !strings.HasPrefix(text, "MSH") {
+		return nil, ErrMissingMSH
+	}
+	if len(text) < 8 {
+		return nil, ErrInvalidMSH
+	}
+
+	d := Delimiters{Field: text[3], Component: text[4], Repetition: text[5], Escape: text[6], Subcomponent: text[7]}
+	// MSH-2 tem 4 caracteres (5 a partir da v2.7, com o de truncamento).
+	if len(text) > 8 && text[8] != d.Field && (len(text) < 10 || text[9] != d.Field) {
+		return nil, ErrInvalidMSH
+	}
+
+	msg := &Message{Delimiters: d}
+	lines := strings.FieldsFunc(text, func(r rune) bool { return r == '\r' || r == '\n' })
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) < 3 {
+			continue
+		}
+		fields := strings.Split(line, string(d.Field))
+		seg := Segment{Name: fields[0], delims: &msg.Delimiters}
+		if seg.Name == "MSH" {
+			// MSH-1 é o próprio separador: o índice do campo fica igual ao número HL7.
+			seg.fields = append([]string{"MSH", string(d.Field)}, fields[1:]...)
+		} else {
+			seg.fields = fields
+		}
+		msg.Segments = append(msg.Segments, seg)
+	}
+	return msg, nil
+}
+
+// MSH devolve o cabeçalho da mensagem.
+func (m *Message) MSH() *Segment {
+	if m == nil || len(m.Segments) == 0 {
+		return nil
+	}
+	return &m.Segments[0]
+}
+
+// ControlID é MSH-10, devolvido no MSA-2 do ACK.
+func (m *Message) ControlID() string {
+	return m.MSH().Get(10, 1)
+}
+
+// Type devolve MSH-9 (ex.: "ORU", "R01").
+func (m *Message) Type() (code, event string) {
+	msh := m.MSH()
+	return msh.Get(9, 1), msh.Get(9, 2)
+}
+
+// Version é MSH-12 (ex.: "2.5.1").
+func (m *Message) Version() string {
+	return m.MSH().Get(12, 1)
+}
+
+// NewSegment monta um segmento para mensagens de saída; os valores já devem
+// estar escapados (use Escape).
+func NewSegment(name string, fields ...string) Segment {
+	return Segment{Name: name, fields: append([]string{name}, fields...), delims: &DefaultDelimiters}
+}
+
+// Field devolve o campo n cru (com repetições e escapes).
+func (s *Segment) Field(n int) string {
+	if s == nil || n <= 0 || n >= len(s.fields) {
+		return ""
+	}
+	return s.fields[n]
+}
+
+// Get devolve o componente c (1-based) da primeira repetição do campo n, sem escapes.
+func (s *Segment) Get(n, c int) string {
+	reps := s.Repetitions(n)
+	if len(reps) == 0 || c <= 0 || c > len(reps[0]) {
+		return ""
+	}
+	return reps[0][c-1]
+}
+
+// Repetitions separa o campo n em repetições e componentes, já sem escapes.
+// Subcomponentes continuam unidos pelo separador original.
+func (s *Segment) Repetitions(n int) [][]string {
+	raw := s.Field(n)
+	if raw == "" {
+		return nil
+	}
+	if s.Name == "MSH" && n <= 2 {
+		return [][]string{{raw}}
+	}
+
+	d := s.delims
+	var reps [][]string
+	for _, rep := range strings.Split(raw, string(d.Repetition)) {
+		components := strings.Split(rep, string(d.Component))
+		for i := range components {
+			components[i] = d.Unescape(components[i])
+		}
+		reps = append(reps, components)
+	}
+	return reps
+}
+
+// String serializa o segmento com os delimitadores padrão.
+func (s Segment) String() string {
+	if s.Name == "MSH" && len(s.fields) > 2 {
+		return "MSH" + strings.Join(s.fields[1:], "|")[1:]
+	}
+	return strings.Join(s.fields, "|")
+}
+
+// Unescape resolve as sequências \F\ \S\ \T\ \R\ \E\ \.br\ e \Xhh\.
+// Sequências desconhecidas (formatação, charsets) são descartadas.
+func (d *Delimiters) Unescape(s string) string {
+	esc := string(d.Escape)
+	if !strings.Contains(s, esc) {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, esc)
+		if start < 0 {
+			b.WriteString(s)
+			break
+		}
+		end := strings.Index(s[start+1:], esc)
+		if end < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:start])
+
+		seq := s[start+1 : start+1+end]
+		switch {
+		case seq == "F":
+			b.WriteByte(d.Field)
+		case seq == "S":
+			b.WriteByte(d.Component)
+		case seq == "T":
+			b.WriteByte(d.Subcomponent)
+		case seq == "R":
+			b.WriteByte(d.Repetition)
+		case seq == "E":
+			b.WriteByte(d.Escape)
+		case seq == ".br":
+			b.WriteByte('\n')
+		case strings.HasPrefix(seq, "X"):
+			if decoded, err := hex.DecodeString(seq[1:]); err == nil {
+				b.Write(decoded)
+			}
+		}
+		s = s[start+2+end:]
+	}
+	return b.String()
+}
+
+// Escape protege os delimitadores padrão em texto livre (ex.: MSA-3).
+func Escape(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\E\`,
+		"|", `\F\`,
+		"^", `\S\`,
+		"&", `\T\`,
+		"~", `\R\`,
+		"\r\n", `\.br\`,
+		"\n", `\.br\`,
+		"\r", `\.br\`,
+	)
+	return r.Replace(s)
+}
@@ -0,0 +1,101 @@
+package hl7v2
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	raw := "MSH|^~\\&|LIS|LAB CENTRAL|SONNDA|SONNDA|20250310083000||ORU^R01^ORU_R01|MSG0001|P|2.5.1\n" +
+		"PID|1||12345678909^^^RFB^CPF~898001160660005^^^MS^CNS||SILVA^MARIA^APARECIDA||19800102|F\r\n" +
+		"OBX|1|TX|11502-2^Laudo^LN||Linha 1\\.br\\Cálcio \\T\\ fósforo\\F\\ok||||||F\r"
+
+	msg, err := Parse([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Segments) != 3 {
+		t.Fatalf("got %d segments", len(msg.Segments))
+	}
+
+	if code, event := msg.Type(); code != "ORU" || event != "R01" {
+		t.Fatalf("type = %s^%s", code, event)
+	}
+	if msg.ControlID() != "MSG0001" || msg.Version() != "2.5.1" {
+		t.Fatalf("control id %q version %q", msg.ControlID(), msg.Version())
+	}
+	if got := msg.MSH().Field(2); got != `^~\&` {
+		t.Fatalf("MSH-2 = %q", got)
+	}
+
+	pid := msg.Segments[1]
+	if reps := pid.Repetitions(3); len(reps) != 2 || reps[1][4] != "CNS" {
+		t.Fatalf("PID-3 repetitions = %v", reps)
+	}
+	if got := pid.Get(5, 2); got != "MARIA" {
+		t.Fatalf("PID-5.2 = %q", got)
+	}
+
+	obx := msg.Segments[2]
+	if got := obx.Get(5, 1); got != "Linha 1\nCálcio & fósforo|ok" {
+		t.Fatalf("OBX-5 unescaped = %q", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]error{
+		"":                     ErrEmptyMessage,
+		"PID|1||123":           ErrMissingMSH,
+		"MSH|^~":               ErrInvalidMSH,
+		"MSH|^~\\&xyz|LIS|LAB": ErrInvalidMSH,
+	}
+	for raw, want := range cases {
+		if _, err := Parse([]byte(raw)); err != want {
+			t.Errorf("Parse(%q) error = %v, want %v", raw, err, want)
+		}
+	}
+}
+
+func TestNewACK(t *testing.T) {
+	msg, err := Parse([]byte("MSH|^~\\&|LIS|LAB|SONNDA|SONNDA|20250310083000||ORU^R01|MSG0001|P|2.5.1\r"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	ack := NewACK(msg, AckError, "paciente não encontrado", []Issue{{
+		Segment: "PID", Sequence: 1, Field: 3, Code: ErrorUnknownKey, Message: "CPF|CNS desconhecido",
+	}}, now)
+
+	lines := strings.Split(strings.TrimSuffix(ack, "\r"), "\r")
+	if len(lines) != 3 {
+		t.Fatalf("ACK = %q", ack)
+	}
+	if !strings.HasPrefix(lines[0], "MSH|^~\\&|SONNDA|SONNDA|LIS|LAB|20250310120000+0000||ACK^R01^ACK|") ||
+		!strings.HasSuffix(lines[0], "|P|2.5.1") {
+		t.Fatalf("MSH = %q", lines[0])
+	}
+	if lines[1] != "MSA|AE|MSG0001|paciente não encontrado" {
+		t.Fatalf("MSA = %q", lines[1])
+	}
+	if lines[2] != "ERR||PID^1^3|204^Unknown key identifier^HL70357|E||||CPF\\F\\CNS desconhecido" {
+		t.Fatalf("ERR = %q", lines[2])
+	}
+
+	// O próprio ACK precisa ser legível pelo parser.
+	parsed, err := Parse([]byte(ack))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Segments[1].Get(1, 1) != string(AckError) {
+		t.Fatalf("parsed MSA-1 = %q", parsed.Segments[1].Get(1, 1))
+	}
+}
+
+func TestNewACK_WithoutMessage(t *testing.T) {
+	ack := NewACK(nil, AckReject, "mensagem ilegível", nil, time.Now())
+	if !strings.Contains(ack, "\rMSA|AR||mensagem ilegível\r") {
+		t.Fatalf("ACK = %q", ack)
+	}
+}
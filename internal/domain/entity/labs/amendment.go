@@ -0,0 +1,330 @@
+package labs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportStatus follows FHIR DiagnosticReport.status, restricted to the
+// statuses a stored report can have.
+type ReportStatus string
+
+const (
+	StatusPreliminary ReportStatus = "preliminary"
+	StatusFinal       ReportStatus = "final"
+	// StatusAmended marks a report changed after release (e.g. added data).
+	StatusAmended ReportStatus = "amended"
+	// StatusCorrected marks a report changed after release to fix an error.
+	StatusCorrected ReportStatus = "corrected"
+)
+
+func (s ReportStatus) IsValid() bool {
+	switch s {
+	case StatusPreliminary, StatusFinal, StatusAmended, StatusCorrected:
+		return true
+	default:
+		return false
+	}
+}
+
+// EditTarget is the part of the report a field edit applies to.
+type EditTarget string
+
+const (
+	EditTargetReport EditTarget = "report"
+	EditTargetResult EditTarget = "result"
+	EditTargetItem   EditTarget = "item"
+)
+
+// FieldEdit sets one field; a nil Value clears it. TargetID is ignored for
+// EditTargetReport.
+type FieldEdit struct {
+	Target   EditTarget
+	TargetID uuid.UUID
+	Field    string
+	Value    *string
+}
+
+// FieldEditError tells which edit (by position) was rejected.
+type FieldEditError struct {
+	Index int
+	Err   error
+}
+
+func (e *FieldEditError) Error() string {
+	return fmt.Sprintf("edit %d: %v", e.Index, e.Err)
+}
+
+func (e *FieldEditError) Unwrap() error { return e.Err }
+
+// FieldChange is a field whose value actually changed. Values use the same
+// text form as the edits (dates as 2006-01-02, timestamps as RFC 3339).
+type FieldChange struct {
+	Target   EditTarget `json:"target"`
+	TargetID uuid.UUID  `json:"target_id"`
+	Field    string     `json:"field"`
+	OldValue *string    `json:"old_value"`
+	NewValue *string    `json:"new_value"`
+}
+
+// Revision is one amendment of a report: the version it produced, who made
+// it and the previous value of every changed field.
+type Revision struct {
+	ID        uuid.UUID
+	ReportID  uuid.UUID
+	Version   int
+	Status    ReportStatus
+	ChangedBy uuid.UUID
+	ChangedAt time.Time
+	Reason    *string
+	Changes   []FieldChange
+}
+
+// editableField reads and writes one field in its text form.
+type editableField struct {
+	get func() *string
+	set func(*string) error
+}
+
+// Amend applies the edits, sets the new status and bumps the version.
+// status may be empty: a preliminary report stays preliminary and a released
+// one becomes corrected. Edits that do not change the value are ignored;
+// ErrNoChanges is returned when nothing changed at all. A report in review
+// can only be released through Review (ErrInReview).
+//
+// Structured values of edited items are not recomputed here; callers run
+// ParseStructuredResult and LinkAnalyte on the items listed in the revision.
+func (r *LabReport) Amend(
+	edits []FieldEdit,
+	status ReportStatus,
+	changedBy uuid.UUID,
+	reason *string,
+	at time.Time,
+) (*Revision, error) {
+	if status == "" {
+		status = StatusCorrected
+		if r.Status == StatusPreliminary {
+			status = StatusPreliminary
+		}
+	}
+	if !status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+	if r.InReview() && status != StatusPreliminary {
+		return nil, ErrInReview
+	}
+
+	changes, err := r.applyEdits(edits, status)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, ErrNoChanges
+	}
+	return r.newRevision(changes, changedBy, reason, at), nil
+}
+
+// applyEdits sets each field and the status, returning what actually changed.
+func (r *LabReport) applyEdits(edits []FieldEdit, status ReportStatus) ([]FieldChange, error) {
+	var changes []FieldChange
+	for i, edit := range edits {
+		targetID, field, err := r.editableField(edit.Target, edit.TargetID, edit.Field)
+		if err != nil {
+			return nil, &FieldEditError{Index: i, Err: err}
+		}
+
+		old := field.get()
+		if err := field.set(edit.Value); err != nil {
+			return nil, &FieldEditError{Index: i, Err: err}
+		}
+		current := field.get()
+		if equalText(old, current) {
+			continue
+		}
+
+		changes = append(changes, FieldChange{
+			Target:   edit.Target,
+			TargetID: targetID,
+			Field:    edit.Field,
+			OldValue: old,
+			NewValue: current,
+		})
+	}
+
+	if r.Status != status {
+		old, current := string(r.Status), string(status)
+		changes = append(changes, FieldChange{
+			Target:   EditTargetReport,
+			TargetID: r.ID,
+			Field:    "status",
+			OldValue: &old,
+			NewValue: &current,
+		})
+		r.Status = status
+	}
+
+	return changes, nil
+}
+
+// newRevision bumps the version and records the changes.
+func (r *LabReport) newRevision(changes []FieldChange, changedBy uuid.UUID, reason *string, at time.Time) *Revision {
+	r.Version++
+	r.UpdatedAt = at.UTC()
+
+	return &Revision{
+		ID:        uuid.Must(uuid.NewV7()),
+		ReportID:  r.ID,
+		Version:   r.Version,
+		Status:    r.Status,
+		ChangedBy: changedBy,
+		ChangedAt: r.UpdatedAt,
+		Reason:    trimToNil(reason),
+		Changes:   changes,
+	}
+}
+
+// Item returns the item with the given ID, or nil.
+func (r *LabReport) Item(id uuid.UUID) *LabResultItem {
+	for i := range r.TestResults {
+		for j := range r.TestResults[i].Items {
+			if r.TestResults[i].Items[j].ID == id {
+				return &r.TestResults[i].Items[j]
+			}
+		}
+	}
+	return nil
+}
+
+func (r *LabReport) result(id uuid.UUID) *LabResult {
+	for i := range r.TestResults {
+		if r.TestResults[i].ID == id {
+			return &r.TestResults[i]
+		}
+	}
+	return nil
+}
+
+func (r *LabReport) editableField(target EditTarget, targetID uuid.UUID, name string) (uuid.UUID, editableField, error) {
+	var fields map[string]editableField
+
+	switch target {
+	case EditTargetReport:
+		targetID = r.ID
+		fields = map[string]editableField{
+			"patient_name":       textField(&r.PatientName),
+			"lab_name":           textField(&r.LabName),
+			"lab_phone":          textField(&r.LabPhone),
+			"insurance_provider": textField(&r.InsuranceProvider),
+			"requesting_doctor":  textField(&r.RequestingDoctor),
+			"technical_manager":  textField(&r.TechnicalManager),
+			"report_date":        dateField(&r.ReportDate),
+		}
+	case EditTargetResult:
+		res := r.result(targetID)
+		if res == nil {
+			return targetID, editableField{}, ErrEditTargetNotFound
+		}
+		fields = map[string]editableField{
+			"test_name":    requiredTextField(&res.TestName, ErrInvalidTestName),
+			"material":     textField(&res.Material),
+			"method":       textField(&res.Method),
+			"collected_at": timestampField(&res.CollectedAt),
+			"release_at":   timestampField(&res.ReleaseAt),
+		}
+	case EditTargetItem:
+		item := r.Item(targetID)
+		if item == nil {
+			return targetID, editableField{}, ErrEditTargetNotFound
+		}
+		// Derived items follow their inputs; they are recomputed, not edited.
+		if item.Derived {
+			return targetID, editableField{}, ErrFieldNotEditable
+		}
+		fields = map[string]editableField{
+			"parameter_name": requiredTextField(&item.ParameterName, ErrInvalidParameterName),
+			"result_value":   textField(&item.ResultValue),
+			"result_unit":    textField(&item.ResultUnit),
+			"reference_text": textField(&item.ReferenceText),
+		}
+	default:
+		return targetID, editableField{}, ErrUnknownEditTarget
+	}
+
+	field, ok := fields[name]
+	if !ok {
+		return targetID, editableField{}, ErrFieldNotEditable
+	}
+	return targetID, field, nil
+}
+
+func textField(p **string) editableField {
+	return editableField{
+		get: func() *string { return *p },
+		set: func(v *string) error {
+			*p = trimToNil(v)
+			return nil
+		},
+	}
+}
+
+func requiredTextField(p *string, errRequired error) editableField {
+	return editableField{
+		get: func() *string {
+			v := *p
+			return &v
+		},
+		set: func(v *string) error {
+			if v == nil || strings.TrimSpace(*v) == "" {
+				return errRequired
+			}
+			*p = strings.TrimSpace(*v)
+			return nil
+		},
+	}
+}
+
+func dateField(p **time.Time) editableField {
+	return timeField(p, "2006-01-02", func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	})
+}
+
+func timestampField(p **time.Time) editableField {
+	return timeField(p, time.RFC3339, func(t time.Time) time.Time { return t.UTC() })
+}
+
+func timeField(p **time.Time, layout string, normalize func(time.Time) time.Time) editableField {
+	return editableField{
+		get: func() *string {
+			if *p == nil {
+				return nil
+			}
+			v := (*p).UTC().Format(layout)
+			return &v
+		},
+		set: func(v *string) error {
+			v = trimToNil(v)
+			if v == nil {
+				*p = nil
+				return nil
+			}
+			t, err := time.Parse(layout, *v)
+			if err != nil {
+				return ErrInvalidDateFormat
+			}
+			t = normalize(t)
+			*p = &t
+			return nil
+		},
+	}
+}
+
+func equalText(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
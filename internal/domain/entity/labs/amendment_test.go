@@ -0,0 +1,91 @@
+package labs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func amendmentFixture() *LabReport {
+	value, unit := "1,35", "g/dL"
+	lab := "Lab Central"
+	report := &LabReport{ID: uuid.New(), LabName: &lab, Status: StatusFinal, Version: 1}
+	report.TestResults = []LabResult{{
+		ID:       uuid.New(),
+		TestName: "Hemograma",
+		Items:    []LabResultItem{{ID: uuid.New(), ParameterName: "Hemoglobina", ResultValue: &value, ResultUnit: &unit}},
+	}}
+	return report
+}
+
+func TestAmend_RecordsPreviousValues(t *testing.T) {
+	report := amendmentFixture()
+	item := report.TestResults[0].Items[0]
+	fixed, date := "13,5", "2025-03-11"
+	by := uuid.New()
+
+	rev, err := report.Amend([]FieldEdit{
+		{Target: EditTargetItem, TargetID: item.ID, Field: "result_value", Value: &fixed},
+		{Target: EditTargetItem, TargetID: item.ID, Field: "result_unit", Value: item.ResultUnit}, // unchanged
+		{Target: EditTargetReport, Field: "report_date", Value: &date},
+		{Target: EditTargetReport, Field: "lab_name", Value: nil},
+	}, "", by, nil, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Version != 2 || rev.Version != 2 || report.Status != StatusCorrected {
+		t.Fatalf("expected version 2 corrected, got v%d %s", report.Version, report.Status)
+	}
+	if rev.ChangedBy != by {
+		t.Fatalf("unexpected author %s", rev.ChangedBy)
+	}
+	// result_value, report_date, lab_name and status.
+	if len(rev.Changes) != 4 {
+		t.Fatalf("expected 4 changes, got %+v", rev.Changes)
+	}
+	c := rev.Changes[0]
+	if c.TargetID != item.ID || *c.OldValue != "1,35" || *c.NewValue != "13,5" {
+		t.Fatalf("unexpected item change %+v", c)
+	}
+	if rev.Changes[1].OldValue != nil || *rev.Changes[1].NewValue != date {
+		t.Fatalf("unexpected date change %+v", rev.Changes[1])
+	}
+	if *rev.Changes[2].OldValue != "Lab Central" || rev.Changes[2].NewValue != nil || report.LabName != nil {
+		t.Fatalf("expected lab_name to be cleared, got %+v", rev.Changes[2])
+	}
+	if *report.Item(item.ID).ResultValue != "13,5" {
+		t.Fatal("item value was not updated")
+	}
+}
+
+func TestAmend_Errors(t *testing.T) {
+	report := amendmentFixture()
+	empty := ""
+
+	tests := []struct {
+		name string
+		edit FieldEdit
+		want error
+	}{
+		{"unknown field", FieldEdit{Target: EditTargetReport, Field: "fingerprint"}, ErrFieldNotEditable},
+		{"unknown item", FieldEdit{Target: EditTargetItem, TargetID: uuid.New(), Field: "result_value"}, ErrEditTargetNotFound},
+		{"required", FieldEdit{Target: EditTargetResult, TargetID: report.TestResults[0].ID, Field: "test_name", Value: &empty}, ErrInvalidTestName},
+		{"bad target", FieldEdit{Target: "patient", Field: "name"}, ErrUnknownEditTarget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := report.Amend([]FieldEdit{tt.edit}, "", uuid.New(), nil, time.Now())
+			var editErr *FieldEditError
+			if !errors.As(err, &editErr) || editErr.Index != 0 || !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v at index 0, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := report.Amend(nil, StatusFinal, uuid.New(), nil, time.Now()); !errors.Is(err, ErrNoChanges) {
+		t.Fatalf("expected ErrNoChanges, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+// internal/domain/entity/labs/analyte.go
+package labs
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Analyte is a canonical lab measurement (e.g. hemoglobin) identified by a
+// stable code and, when available, its LOINC code.
+type Analyte struct {
+	Code        string   `json:"code"`
+	LOINC       *string  `json:"loinc,omitempty"`
+	DisplayName string   `json:"display_name"`
+	DefaultUnit *string  `json:"default_unit,omitempty"`
+	Synonyms    []string `json:"synonyms"`
+}
+
+// NormalizeAnalyteName builds the lookup key for a parameter name: lower case,
+// without accents and punctuation, single spaces ("HDL-Colesterol" -> "hdl colesterol").
+func NormalizeAnalyteName(name string) string {
+	folded := foldAccents(strings.ToLower(name))
+	fields := strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// AnalyteCatalog resolves parameter names printed by labs to canonical analytes.
+type AnalyteCatalog struct {
+	byCode  map[string]*Analyte
+	byKey   map[string]*Analyte
+	byLOINC map[string]*Analyte
+}
+
+// NewAnalyteCatalog indexes analytes by code, display name and synonyms.
+func NewAnalyteCatalog(analytes []Analyte) *AnalyteCatalog {
+	c := &AnalyteCatalog{
+		byCode:  make(map[string]*Analyte, len(analytes)),
+		byKey:   make(map[string]*Analyte),
+		byLOINC: make(map[string]*Analyte),
+	}
+	for idx := range analytes {
+		a := &analytes[idx]
+		c.byCode[a.Code] = a
+		if a.LOINC != nil {
+			c.byLOINC[*a.LOINC] = a
+		}
+		c.index(a.DisplayName, a)
+		for _, s := range a.Synonyms {
+			c.index(s, a)
+		}
+	}
+	return c
+}
+
+func (c *AnalyteCatalog) index(name string, a *Analyte) {
+	if key := NormalizeAnalyteName(name); key != "" {
+		c.byKey[key] = a
+	}
+}
+
+// Get returns the analyte with the given code, or nil.
+func (c *AnalyteCatalog) Get(code string) *Analyte {
+	if c == nil {
+		return nil
+	}
+	return c.byCode[code]
+}
+
+// GetByLOINC returns the analyte with the given LOINC code, or nil.
+func (c *AnalyteCatalog) GetByLOINC(loinc string) *Analyte {
+	if c == nil {
+		return nil
+	}
+	return c.byLOINC[loinc]
+}
+
+// Match returns the analyte for a parameter name, or nil when unknown.
+// Parenthesised qualifiers are ignored as a fallback ("Glicose (jejum)").
+func (c *AnalyteCatalog) Match(parameterName string) *Analyte {
+	if c == nil {
+		return nil
+	}
+	for _, key := range MatchKeys(parameterName) {
+		if a := c.byKey[key]; a != nil {
+			return a
+		}
+	}
+	return nil
+}
+
+// MatchKeys returns the keys Match looks up for a parameter name, in order:
+// the whole name and, for "Glicose (jejum)", the part before the parenthesis.
+func MatchKeys(parameterName string) []string {
+	keys := []string{NormalizeAnalyteName(parameterName)}
+	if idx := strings.Index(parameterName, "("); idx > 0 {
+		keys = append(keys, NormalizeAnalyteName(parameterName[:idx]))
+	}
+	return keys
+}
+
+// AnalyteRelink is the analyte that items printed as ParameterName get after
+// a catalog change; a nil AnalyteCode unlinks them.
+type AnalyteRelink struct {
+	ParameterName string
+	AnalyteCode   *string
+}
+
+// AnalyteLink records how an item got its AnalyteCode.
+type AnalyteLink string
+
+const (
+	AnalyteLinkNone AnalyteLink = ""
+	// AnalyteLinkName comes from the parameter name and follows catalog changes.
+	AnalyteLinkName AnalyteLink = "name"
+	// AnalyteLinkCode comes from a code sent with the result (LOINC) or from a
+	// formula; catalog remaps keep it.
+	AnalyteLinkCode AnalyteLink = "code"
+)
+
+// LinkAnalyte sets AnalyteCode from the catalog; unknown names are left
+// unlinked. Items linked by code keep their analyte.
+func (i *LabResultItem) LinkAnalyte(catalog *AnalyteCatalog) {
+	if i == nil || i.AnalyteLink == AnalyteLinkCode {
+		return
+	}
+	i.AnalyteCode, i.AnalyteLink = nil, AnalyteLinkNone
+	if a := catalog.Match(i.ParameterName); a != nil {
+		code := a.Code
+		i.AnalyteCode, i.AnalyteLink = &code, AnalyteLinkName
+	}
+}
+
+// LinkAnalyteCode links the item to an analyte identified by code.
+func (i *LabResultItem) LinkAnalyteCode(code string) {
+	i.AnalyteCode, i.AnalyteLink = &code, AnalyteLinkCode
+}
@@ -0,0 +1,52 @@
+// internal/domain/entity/labs/analyte_test.go
+package labs
+
+import "testing"
+
+func TestNormalizeAnalyteName(t *testing.T) {
+	cases := map[string]string{
+		"HEMOGLOBINA":      "hemoglobina",
+		"  Ácido   Úrico ": "acido urico",
+		"HDL-Colesterol":   "hdl colesterol",
+		"25(OH)D":          "25 oh d",
+	}
+	for in, want := range cases {
+		if got := NormalizeAnalyteName(in); got != want {
+			t.Errorf("NormalizeAnalyteName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLabResultItem_LinkAnalyte(t *testing.T) {
+	loinc := "718-7"
+	catalog := NewAnalyteCatalog([]Analyte{
+		{Code: "hemoglobin", LOINC: &loinc, DisplayName: "Hemoglobina", Synonyms: []string{"Hb", "Hgb"}},
+	})
+
+	for _, name := range []string{"Hemoglobina", "HEMOGLOBINA", "Hb", "hgb", "Hemoglobina (sangue total)"} {
+		item := &LabResultItem{ParameterName: name}
+		item.LinkAnalyte(catalog)
+		if item.AnalyteCode == nil || *item.AnalyteCode != "hemoglobin" || item.AnalyteLink != AnalyteLinkName {
+			t.Errorf("%q: expected hemoglobin by name, got %v (%q)", name, item.AnalyteCode, item.AnalyteLink)
+		}
+	}
+
+	item := &LabResultItem{ParameterName: "Ferritina"}
+	item.LinkAnalyte(catalog)
+	if item.AnalyteCode != nil || item.AnalyteLink != AnalyteLinkNone {
+		t.Fatalf("expected unknown analyte to stay unlinked, got %q", *item.AnalyteCode)
+	}
+}
+
+func TestLabResultItem_LinkAnalyte_KeepsCodeLink(t *testing.T) {
+	catalog := NewAnalyteCatalog([]Analyte{{Code: "glucose", DisplayName: "Glicose"}})
+
+	// Glucose LOINC under a local name the catalog does not know.
+	item := &LabResultItem{ParameterName: "GLI"}
+	item.LinkAnalyteCode("glucose")
+	item.LinkAnalyte(catalog)
+
+	if item.AnalyteCode == nil || *item.AnalyteCode != "glucose" || item.AnalyteLink != AnalyteLinkCode {
+		t.Fatalf("expected code link to be kept, got %v (%q)", item.AnalyteCode, item.AnalyteLink)
+	}
+}
@@ -0,0 +1,212 @@
+// internal/domain/entity/labs/compare.go
+package labs
+
+import (
+	"math"
+	"strings"
+)
+
+// Presence tells in which of the two compared reports an item was found.
+type Presence string
+
+const (
+	PresenceBoth Presence = "both"
+	// PresenceAppeared is an item found only in the second report (B).
+	PresenceAppeared Presence = "appeared"
+	// PresenceDisappeared is an item found only in the first report (A).
+	PresenceDisappeared Presence = "disappeared"
+)
+
+// ItemComparison aligns the same analyte in two reports. Values and deltas
+// are in Unit (B's unit, A converted to it) and only set when both results
+// are exact numbers in convertible units.
+type ItemComparison struct {
+	// Key is the analyte code or, for unlinked items, the normalized name.
+	Key           string   `json:"key"`
+	AnalyteCode   *string  `json:"analyte_code,omitempty"`
+	ParameterName string   `json:"parameter_name"`
+	Presence      Presence `json:"presence"`
+
+	A *LabResultItem `json:"a,omitempty"`
+	B *LabResultItem `json:"b,omitempty"`
+
+	Unit          *string  `json:"unit,omitempty"`
+	ValueA        *float64 `json:"value_a,omitempty"`
+	ValueB        *float64 `json:"value_b,omitempty"`
+	AbsoluteDelta *float64 `json:"absolute_delta,omitempty"`
+	// PercentDelta is relative to ValueA; nil when ValueA is zero.
+	PercentDelta *float64 `json:"percent_delta,omitempty"`
+
+	// CrossedReference is set when the item moved between normal, low, high
+	// or abnormal (e.g. N -> H, L -> N).
+	CrossedReference bool `json:"crossed_reference"`
+}
+
+// Highlighted reports items worth the reader's attention: crossed a
+// reference boundary, appeared or disappeared.
+func (c ItemComparison) Highlighted() bool {
+	return c.CrossedReference || c.Presence != PresenceBoth
+}
+
+// CompareReports aligns the items of a and b by analyte. Items keep a's
+// order; those only in b follow in b's order. A name repeated inside a
+// report (e.g. "Neutrófilos" in % and /mm³) is paired with the first item of
+// the other report in a compatible unit.
+func CompareReports(a, b *LabReport) []ItemComparison {
+	bItems := flattenItems(b)
+	used := make([]bool, len(bItems))
+
+	var out []ItemComparison
+	for _, ia := range flattenItems(a) {
+		key := comparisonKey(ia)
+		match := -1
+		for j, ib := range bItems {
+			if !used[j] && comparisonKey(ib) == key && unitsCompatible(ia, ib) {
+				match = j
+				break
+			}
+		}
+		if match < 0 {
+			out = append(out, ItemComparison{
+				Key:           key,
+				AnalyteCode:   ia.AnalyteCode,
+				ParameterName: ia.ParameterName,
+				Presence:      PresenceDisappeared,
+				A:             ia,
+			})
+			continue
+		}
+		used[match] = true
+		out = append(out, compareItems(key, ia, bItems[match]))
+	}
+
+	for j, ib := range bItems {
+		if used[j] {
+			continue
+		}
+		out = append(out, ItemComparison{
+			Key:           comparisonKey(ib),
+			AnalyteCode:   ib.AnalyteCode,
+			ParameterName: ib.ParameterName,
+			Presence:      PresenceAppeared,
+			B:             ib,
+		})
+	}
+	return out
+}
+
+func compareItems(key string, a, b *LabResultItem) ItemComparison {
+	c := ItemComparison{
+		Key:           key,
+		AnalyteCode:   b.AnalyteCode,
+		ParameterName: b.ParameterName,
+		Presence:      PresenceBoth,
+		A:             a,
+		B:             b,
+	}
+	if c.AnalyteCode == nil {
+		c.AnalyteCode = a.AnalyteCode
+	}
+
+	bandA, okA := referenceBand(a.Interpretation)
+	bandB, okB := referenceBand(b.Interpretation)
+	c.CrossedReference = okA && okB && bandA != bandB
+
+	valueA, ok := valueIn(a, b, analyteOf(c.AnalyteCode))
+	if !ok || b.NumericValue == nil || b.Comparator != ComparatorNone {
+		return c
+	}
+	valueB := *b.NumericValue
+	delta := valueB - valueA
+	c.Unit = b.UCUMUnit
+	if c.Unit == nil {
+		c.Unit = b.ResultUnit
+	}
+	c.ValueA = &valueA
+	c.ValueB = &valueB
+	c.AbsoluteDelta = &delta
+	if valueA != 0 {
+		pct := delta / math.Abs(valueA) * 100
+		c.PercentDelta = &pct
+	}
+	return c
+}
+
+// valueIn returns a's exact numeric value in b's unit.
+func valueIn(a, b *LabResultItem, analyteCode string) (float64, bool) {
+	if a.NumericValue == nil || a.Comparator != ComparatorNone {
+		return 0, false
+	}
+	if a.UCUMUnit != nil && b.UCUMUnit != nil {
+		return ConvertUnit(analyteCode, *a.NumericValue, *a.UCUMUnit, *b.UCUMUnit)
+	}
+	if a.UCUMUnit == nil && b.UCUMUnit == nil && sameRawUnit(a.ResultUnit, b.ResultUnit) {
+		return *a.NumericValue, true
+	}
+	return 0, false
+}
+
+// unitsCompatible rejects pairs whose units cannot be converted, so a
+// percentage is never paired with an absolute count of the same name.
+func unitsCompatible(a, b *LabResultItem) bool {
+	if a.UCUMUnit != nil && b.UCUMUnit != nil {
+		_, ok := ConvertUnit(analyteOf(a.AnalyteCode), 1, *a.UCUMUnit, *b.UCUMUnit)
+		return ok
+	}
+	if a.UCUMUnit == nil && b.UCUMUnit == nil {
+		return sameRawUnit(a.ResultUnit, b.ResultUnit)
+	}
+	// Only one side parsed: accept, deltas are simply left out.
+	return true
+}
+
+func sameRawUnit(a, b *string) bool {
+	norm := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return strings.ToLower(strings.Join(strings.Fields(*s), ""))
+	}
+	return norm(a) == norm(b)
+}
+
+// referenceBand places an interpretation below, within or above the
+// reference range; qualitative abnormal is its own band.
+func referenceBand(i Interpretation) (int, bool) {
+	switch i {
+	case InterpretationNormal:
+		return 0, true
+	case InterpretationLow, InterpretationCriticalLow:
+		return -1, true
+	case InterpretationHigh, InterpretationCriticalHigh:
+		return 1, true
+	case InterpretationAbnormal:
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
+func comparisonKey(i *LabResultItem) string {
+	if i.AnalyteCode != nil && *i.AnalyteCode != "" {
+		return *i.AnalyteCode
+	}
+	return NormalizeAnalyteName(i.ParameterName)
+}
+
+func analyteOf(code *string) string {
+	if code == nil {
+		return ""
+	}
+	return *code
+}
+
+func flattenItems(r *LabReport) []*LabResultItem {
+	var items []*LabResultItem
+	for i := range r.TestResults {
+		for j := range r.TestResults[i].Items {
+			items = append(items, &r.TestResults[i].Items[j])
+		}
+	}
+	return items
+}
@@ -0,0 +1,82 @@
+package labs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func compareItem(name string, code *string, value float64, unit string, interp Interpretation) LabResultItem {
+	return LabResultItem{
+		ID: uuid.New(), ParameterName: name, AnalyteCode: code,
+		NumericValue: &value, UCUMUnit: &unit, ResultUnit: &unit, Interpretation: interp,
+	}
+}
+
+func reportWith(items ...LabResultItem) *LabReport {
+	return &LabReport{ID: uuid.New(), TestResults: []LabResult{{Items: items}}}
+}
+
+func TestCompareReports(t *testing.T) {
+	glucose, hb := "glucose", "hemoglobin"
+	a := reportWith(
+		compareItem("Glicose", &glucose, 5.5, "mmol/L", InterpretationNormal),
+		compareItem("Hemoglobina", &hb, 13, "g/dL", InterpretationNormal),
+		compareItem("Neutrófilos", nil, 60, "%", InterpretationNormal),
+		compareItem("Neutrófilos", nil, 4200, "/mm3", InterpretationNormal),
+		compareItem("VHS", nil, 10, "mm/h", InterpretationNormal),
+	)
+	b := reportWith(
+		compareItem("GLICEMIA", &glucose, 126, "mg/dL", InterpretationHigh),
+		compareItem("Hb", &hb, 13, "g/dL", InterpretationNormal),
+		compareItem("Neutrofilos", nil, 3900, "/mm3", InterpretationNormal),
+		compareItem("Neutrofilos", nil, 55, "%", InterpretationNormal),
+		compareItem("PCR", nil, 12, "mg/L", InterpretationHigh),
+	)
+
+	got := CompareReports(a, b)
+	if len(got) != 6 {
+		t.Fatalf("expected 6 rows, got %d: %+v", len(got), got)
+	}
+
+	g := got[0]
+	if g.Presence != PresenceBoth || !g.CrossedReference || *g.Unit != "mg/dL" {
+		t.Fatalf("unexpected glucose row: %+v", g)
+	}
+	// 5,5 mmol/L is ~99 mg/dL.
+	if math.Abs(*g.ValueA-99.1) > 0.5 || math.Abs(*g.AbsoluteDelta-26.9) > 0.5 || math.Abs(*g.PercentDelta-27.1) > 0.5 {
+		t.Fatalf("unexpected glucose deltas: %v %v %v", *g.ValueA, *g.AbsoluteDelta, *g.PercentDelta)
+	}
+
+	if h := got[1]; h.CrossedReference || h.Highlighted() || *h.AbsoluteDelta != 0 {
+		t.Fatalf("unexpected hemoglobin row: %+v", h)
+	}
+
+	// Repeated names are paired by compatible unit, not by position.
+	if pct := got[2]; pct.B == nil || *pct.B.UCUMUnit != "%" || *pct.AbsoluteDelta != -5 {
+		t.Fatalf("expected percent neutrophils paired together, got %+v", pct)
+	}
+	if abs := got[3]; abs.B == nil || *abs.AbsoluteDelta != -300 {
+		t.Fatalf("expected absolute neutrophils paired together, got %+v", abs)
+	}
+
+	if v := got[4]; v.Presence != PresenceDisappeared || v.B != nil || !v.Highlighted() {
+		t.Fatalf("unexpected disappeared row: %+v", v)
+	}
+	if p := got[5]; p.Presence != PresenceAppeared || p.A != nil || p.Key != "pcr" {
+		t.Fatalf("unexpected appeared row: %+v", p)
+	}
+}
+
+func TestCompareReports_BoundedValuesHaveNoDelta(t *testing.T) {
+	a := reportWith(compareItem("PCR", nil, 0.5, "mg/dL", InterpretationNormal))
+	bItem := compareItem("PCR", nil, 3, "mg/dL", InterpretationHigh)
+	a.TestResults[0].Items[0].Comparator = ComparatorLess
+	b := reportWith(bItem)
+
+	got := CompareReports(a, b)
+	if len(got) != 1 || got[0].AbsoluteDelta != nil || !got[0].CrossedReference {
+		t.Fatalf("expected crossing without delta, got %+v", got)
+	}
+}
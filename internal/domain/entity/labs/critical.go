@@ -0,0 +1,242 @@
+// internal/domain/entity/labs/critical.go
+package labs
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+
+	"github.com/google/uuid"
+)
+
+// CriticalRule sets the critical (panic) limits of an analyte for a sex and
+// age band. Values at or beyond Low/High must be reported to the care team
+// right away. Sex nil applies to everyone; ages are in whole years, MinAge
+// inclusive and MaxAge exclusive, nil meaning unbounded.
+type CriticalRule struct {
+	ID          uuid.UUID            `json:"id"`
+	AnalyteCode string               `json:"analyte_code"`
+	Sex         *demographics.Gender `json:"sex,omitempty"`
+	MinAge      *int                 `json:"min_age,omitempty"`
+	MaxAge      *int                 `json:"max_age,omitempty"`
+	Low         *float64             `json:"low,omitempty"`
+	High        *float64             `json:"high,omitempty"`
+	// Unit is the UCUM unit of Low/High; results are converted to it.
+	Unit      string    `json:"unit"`
+	CreatedAt time.Time `json:"created_at"`
+	// CreatedBy is the admin who added the rule; nil for the seeded defaults.
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+}
+
+// NewCriticalRule validates a rule. The unit must be a UCUM code known to
+// the converter (e.g. "mmol/L").
+func NewCriticalRule(
+	analyteCode string,
+	sex *demographics.Gender,
+	minAge, maxAge *int,
+	low, high *float64,
+	unit string,
+) (*CriticalRule, error) {
+	analyteCode = strings.TrimSpace(analyteCode)
+	code, ok := ParseUCUM(unit)
+	if analyteCode == "" || !ok || (low == nil && high == nil) {
+		return nil, ErrInvalidCriticalRule
+	}
+	if low != nil && high != nil && *low >= *high {
+		return nil, ErrInvalidCriticalRule
+	}
+	if (minAge != nil && *minAge < 0) || (maxAge != nil && *maxAge <= 0) ||
+		(minAge != nil && maxAge != nil && *minAge >= *maxAge) {
+		return nil, ErrInvalidAgeRange
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	return &CriticalRule{
+		ID:          id,
+		AnalyteCode: analyteCode,
+		Sex:         sex,
+		MinAge:      minAge,
+		MaxAge:      maxAge,
+		Low:         low,
+		High:        high,
+		Unit:        code,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// Applies reports whether the rule covers a patient of the given sex and age.
+func (r CriticalRule) Applies(sex demographics.Gender, age int) bool {
+	if r.Sex != nil && *r.Sex != sex {
+		return false
+	}
+	if r.MinAge != nil && age < *r.MinAge {
+		return false
+	}
+	if r.MaxAge != nil && age >= *r.MaxAge {
+		return false
+	}
+	return true
+}
+
+// specificity ranks overlapping rules: sex-specific first, then the
+// narrowest age band.
+func (r CriticalRule) specificity() int {
+	score := 0
+	if r.Sex != nil {
+		score += 1000
+	}
+	lo, hi := 0, 150
+	if r.MinAge != nil {
+		lo = *r.MinAge
+	}
+	if r.MaxAge != nil {
+		hi = *r.MaxAge
+	}
+	return score + 150 - (hi - lo)
+}
+
+// CriticalRuleSet picks, for each analyte, the most specific rule that
+// applies to the patient.
+type CriticalRuleSet struct {
+	byAnalyte map[string][]CriticalRule
+}
+
+func NewCriticalRuleSet(rules []CriticalRule) *CriticalRuleSet {
+	s := &CriticalRuleSet{byAnalyte: make(map[string][]CriticalRule)}
+	for _, r := range rules {
+		s.byAnalyte[r.AnalyteCode] = append(s.byAnalyte[r.AnalyteCode], r)
+	}
+	return s
+}
+
+// RuleFor returns the rule for the analyte and patient, or nil.
+func (s *CriticalRuleSet) RuleFor(analyteCode string, sex demographics.Gender, age int) *CriticalRule {
+	var best *CriticalRule
+	for i := range s.byAnalyte[analyteCode] {
+		r := &s.byAnalyte[analyteCode][i]
+		if !r.Applies(sex, age) {
+			continue
+		}
+		if best == nil || r.specificity() > best.specificity() {
+			best = r
+		}
+	}
+	return best
+}
+
+// CriticalAlert records a result beyond a critical limit and who was told.
+type CriticalAlert struct {
+	ID            uuid.UUID `json:"id"`
+	ReportID      uuid.UUID `json:"report_id"`
+	PatientID     uuid.UUID `json:"patient_id"`
+	ItemID        uuid.UUID `json:"item_id"`
+	RuleID        uuid.UUID `json:"rule_id"`
+	AnalyteCode   string    `json:"analyte_code"`
+	ParameterName string    `json:"parameter_name"`
+	// Value and Limit are in the rule's Unit.
+	Value float64 `json:"value"`
+	Limit float64 `json:"limit"`
+	Unit  string  `json:"unit"`
+	// Interpretation is LL or HH.
+	Interpretation Interpretation `json:"interpretation"`
+	CreatedAt      time.Time      `json:"created_at"`
+
+	Recipients []AlertRecipient `json:"recipients,omitempty"`
+}
+
+// AlertRecipient is a professional notified of an alert; AcknowledgedAt is
+// set when they confirm they saw it.
+type AlertRecipient struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	NotifiedAt     *time.Time `json:"notified_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// EvaluateCritical checks every linked numeric item of the report against
+// the rules. Age is taken at collection (or report) date. Bounded results
+// ("< 2,0") only alert when the bound itself is beyond the limit.
+func (s *CriticalRuleSet) EvaluateCritical(
+	report *LabReport,
+	sex demographics.Gender,
+	birthDate time.Time,
+	at time.Time,
+) []CriticalAlert {
+	var alerts []CriticalAlert
+	for _, tr := range report.TestResults {
+		effective := at
+		switch {
+		case tr.CollectedAt != nil:
+			effective = *tr.CollectedAt
+		case report.ReportDate != nil:
+			effective = *report.ReportDate
+		}
+		age := AgeInYears(birthDate, effective)
+
+		for _, item := range tr.Items {
+			if item.AnalyteCode == nil || item.NumericValue == nil || item.UCUMUnit == nil {
+				continue
+			}
+			rule := s.RuleFor(*item.AnalyteCode, sex, age)
+			if rule == nil {
+				continue
+			}
+			value, ok := ConvertUnit(rule.AnalyteCode, *item.NumericValue, *item.UCUMUnit, rule.Unit)
+			if !ok {
+				continue
+			}
+
+			var limit float64
+			var flag Interpretation
+			switch {
+			case rule.Low != nil && value <= *rule.Low && item.Comparator != ComparatorGreater && item.Comparator != ComparatorGreaterEqual:
+				limit, flag = *rule.Low, InterpretationCriticalLow
+			case rule.High != nil && value >= *rule.High && item.Comparator != ComparatorLess && item.Comparator != ComparatorLessEqual:
+				limit, flag = *rule.High, InterpretationCriticalHigh
+			default:
+				continue
+			}
+
+			alerts = append(alerts, CriticalAlert{
+				ID:             uuid.Must(uuid.NewV7()),
+				ReportID:       report.ID,
+				PatientID:      report.PatientID,
+				ItemID:         item.ID,
+				RuleID:         rule.ID,
+				AnalyteCode:    rule.AnalyteCode,
+				ParameterName:  item.ParameterName,
+				Value:          value,
+				Limit:          limit,
+				Unit:           rule.Unit,
+				Interpretation: flag,
+				CreatedAt:      at.UTC(),
+			})
+		}
+	}
+	return alerts
+}
+
+// FlagCritical sets LL/HH on the items of the alerts. Interpret only yields
+// H/L, so these flags always match a configured rule.
+func (r *LabReport) FlagCritical(alerts []CriticalAlert) {
+	for _, alert := range alerts {
+		if item := r.Item(alert.ItemID); item != nil {
+			item.Interpretation = alert.Interpretation
+		}
+	}
+}
+
+// AgeInYears returns the completed years between birth and at.
+func AgeInYears(birth, at time.Time) int {
+	if birth.IsZero() || at.Before(birth) {
+		return 0
+	}
+	age := at.Year() - birth.Year()
+	if at.Month() < birth.Month() || (at.Month() == birth.Month() && at.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,109 @@
+package labs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+
+	"github.com/google/uuid"
+)
+
+func mustCriticalRule(t *testing.T, analyte string, minAge, maxAge *int, low, high float64, unit string) CriticalRule {
+	t.Helper()
+	r, err := NewCriticalRule(analyte, nil, minAge, maxAge, &low, &high, unit)
+	if err != nil {
+		t.Fatalf("rule: %v", err)
+	}
+	return *r
+}
+
+func criticalItem(name, analyte string, value float64, unit string, cmp Comparator) LabResultItem {
+	return LabResultItem{
+		ID: uuid.New(), ParameterName: name, AnalyteCode: &analyte,
+		NumericValue: &value, UCUMUnit: &unit, Comparator: cmp,
+	}
+}
+
+func TestNewCriticalRule_Validates(t *testing.T) {
+	low, high := 2.5, 6.5
+	if _, err := NewCriticalRule("potassium", nil, nil, nil, nil, nil, "mmol/L"); !errors.Is(err, ErrInvalidCriticalRule) {
+		t.Fatalf("expected missing limits to fail, got %v", err)
+	}
+	if _, err := NewCriticalRule("potassium", nil, nil, nil, &high, &low, "mmol/L"); !errors.Is(err, ErrInvalidCriticalRule) {
+		t.Fatalf("expected low >= high to fail, got %v", err)
+	}
+	min, max := 18, 18
+	if _, err := NewCriticalRule("potassium", nil, &min, &max, &low, &high, "mmol/L"); !errors.Is(err, ErrInvalidAgeRange) {
+		t.Fatalf("expected empty age band to fail, got %v", err)
+	}
+	r, err := NewCriticalRule("potassium", nil, nil, nil, &low, &high, "mEq/L")
+	if err != nil || r.Unit != "meq/L" {
+		t.Fatalf("expected unit normalized to UCUM, got %v %v", r, err)
+	}
+}
+
+func TestEvaluateCritical(t *testing.T) {
+	one := 1
+	female := demographics.GenderFemale
+	rules := []CriticalRule{
+		mustCriticalRule(t, "potassium", nil, nil, 2.5, 6.5, "mmol/L"),
+		mustCriticalRule(t, "glucose", &one, nil, 40, 450, "mg/dL"),
+		mustCriticalRule(t, "glucose", nil, &one, 30, 300, "mg/dL"),
+	}
+	femaleGlucose := mustCriticalRule(t, "glucose", &one, nil, 60, 400, "mg/dL")
+	femaleGlucose.Sex = &female
+	rules = append(rules, femaleGlucose)
+	set := NewCriticalRuleSet(rules)
+
+	collected := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	report := &LabReport{
+		ID:        uuid.New(),
+		PatientID: uuid.New(),
+		TestResults: []LabResult{{
+			CollectedAt: &collected,
+			Items: []LabResultItem{
+				criticalItem("Potássio", "potassium", 7.1, "meq/L", ComparatorNone),
+				criticalItem("Glicose", "glucose", 35, "mg/dL", ComparatorNone),
+				// "> 2,0" cannot be critically low.
+				criticalItem("Potássio", "potassium", 2.0, "mmol/L", ComparatorGreater),
+				criticalItem("Glicose", "glucose", 3.0, "mmol/L", ComparatorNone),
+			},
+		}},
+	}
+
+	adult := time.Date(1980, 5, 1, 0, 0, 0, 0, time.UTC)
+	alerts := set.EvaluateCritical(report, demographics.GenderMale, adult, time.Now())
+	if len(alerts) != 2 {
+		t.Fatalf("expected potassium and glucose alerts, got %+v", alerts)
+	}
+	if alerts[0].Interpretation != InterpretationCriticalHigh || alerts[0].Limit != 6.5 || alerts[0].Unit != "mmol/L" {
+		t.Fatalf("unexpected potassium alert: %+v", alerts[0])
+	}
+	if alerts[1].Interpretation != InterpretationCriticalLow || alerts[1].Limit != 40 {
+		t.Fatalf("unexpected glucose alert: %+v", alerts[1])
+	}
+
+	// The sex-specific rule wins; 3 mmol/L is ~54 mg/dL, below its limit of 60.
+	got := set.EvaluateCritical(report, demographics.GenderFemale, adult, time.Now())
+	if len(got) != 3 || got[2].Value < 53 || got[2].Value > 55 || got[2].Limit != 60 {
+		t.Fatalf("expected female rule to flag the converted glucose, got %+v", got)
+	}
+
+	// Three-day-old newborn: 35 mg/dL is above the neonatal limit.
+	newborn := collected.AddDate(0, 0, -3)
+	if got := set.EvaluateCritical(report, demographics.GenderMale, newborn, time.Now()); len(got) != 1 {
+		t.Fatalf("expected only potassium for a newborn, got %+v", got)
+	}
+}
+
+func TestAgeInYears(t *testing.T) {
+	birth := time.Date(2000, 6, 15, 0, 0, 0, 0, time.UTC)
+	if got := AgeInYears(birth, time.Date(2026, 6, 14, 0, 0, 0, 0, time.UTC)); got != 25 {
+		t.Fatalf("expected 25, got %d", got)
+	}
+	if got := AgeInYears(birth, time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)); got != 26 {
+		t.Fatalf("expected 26, got %d", got)
+	}
+}
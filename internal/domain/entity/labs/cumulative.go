@@ -0,0 +1,226 @@
+// internal/domain/entity/labs/cumulative.go
+package labs
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CumulativeTable is the cumulative report grid: one row per analyte and
+// one column per collection day, oldest first.
+type CumulativeTable struct {
+	Dates []time.Time
+	Rows  []CumulativeRow
+}
+
+// CumulativeRow holds an analyte's results aligned with the table dates.
+// Values are in Unit; results in other convertible units are converted.
+type CumulativeRow struct {
+	// Key is the analyte code or, for unlinked items, the normalized name.
+	Key         string
+	AnalyteCode *string
+	// Group is the test (panel) name of the most recent result.
+	Group     string
+	Name      string
+	Unit      string
+	Reference string
+	// Cells has one entry per date; nil when the analyte was not measured.
+	Cells []*CumulativeCell
+
+	ucumUnit *string
+	rawUnit  *string
+}
+
+// CumulativeCell is a single result in the grid.
+type CumulativeCell struct {
+	ReportID       uuid.UUID
+	ItemID         uuid.UUID
+	Value          string
+	Interpretation Interpretation
+	Abnormal       bool
+}
+
+// AbnormalCount counts the highlighted cells of the table.
+func (t CumulativeTable) AbnormalCount() int {
+	n := 0
+	for _, row := range t.Rows {
+		for _, cell := range row.Cells {
+			if cell != nil && cell.Abnormal {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// BuildCumulativeTable arranges timeline items, expected most recent first,
+// into the cumulative grid. Items without a collection or report date are
+// left out. When an analyte was measured more than once on the same day the
+// most recent result wins. Rows are ordered by group and name; the same
+// name in incompatible units (e.g. % and /mm³) gets its own row.
+func BuildCumulativeTable(items []LabResultItemTimeline) CumulativeTable {
+	var (
+		dates    []time.Time
+		dateIdx  = make(map[time.Time]int)
+		rows     []*CumulativeRow
+		rowCells []map[time.Time]*CumulativeCell
+	)
+
+	for _, it := range items {
+		at := it.EffectiveAt()
+		if at == nil {
+			continue
+		}
+		day := collectionDay(*at)
+		if _, ok := dateIdx[day]; !ok {
+			dateIdx[day] = len(dates)
+			dates = append(dates, day)
+		}
+
+		key := cumulativeKey(it)
+		idx := -1
+		for i, row := range rows {
+			if row.Key == key && row.accepts(it) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			rows = append(rows, newCumulativeRow(key, it))
+			rowCells = append(rowCells, make(map[time.Time]*CumulativeCell))
+			idx = len(rows) - 1
+		}
+		if _, taken := rowCells[idx][day]; taken {
+			continue
+		}
+		rowCells[idx][day] = &CumulativeCell{
+			ReportID:       it.ReportID,
+			ItemID:         it.ItemID,
+			Value:          rows[idx].valueOf(it),
+			Interpretation: it.Interpretation,
+			Abnormal:       it.Interpretation.IsAbnormal(),
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	table := CumulativeTable{Dates: dates, Rows: make([]CumulativeRow, 0, len(rows))}
+	for i, row := range rows {
+		row.Cells = make([]*CumulativeCell, len(dates))
+		for j, day := range dates {
+			row.Cells[j] = rowCells[i][day]
+		}
+		table.Rows = append(table.Rows, *row)
+	}
+	sort.SliceStable(table.Rows, func(i, j int) bool {
+		gi, gj := NormalizeAnalyteName(table.Rows[i].Group), NormalizeAnalyteName(table.Rows[j].Group)
+		if gi != gj {
+			return gi < gj
+		}
+		return NormalizeAnalyteName(table.Rows[i].Name) < NormalizeAnalyteName(table.Rows[j].Name)
+	})
+	return table
+}
+
+// newCumulativeRow takes unit, names and reference from the most recent
+// item. The catalog's default unit is preferred when the value converts.
+func newCumulativeRow(key string, it LabResultItemTimeline) *CumulativeRow {
+	row := &CumulativeRow{
+		Key:         key,
+		AnalyteCode: it.AnalyteCode,
+		Group:       strings.TrimSpace(it.TestName),
+		Name:        strings.TrimSpace(it.ParameterName),
+		ucumUnit:    it.UCUMUnit,
+		rawUnit:     it.ResultUnit,
+	}
+	if it.CanonicalUnit != nil && it.UCUMUnit != nil && *it.CanonicalUnit != *it.UCUMUnit {
+		if _, ok := it.ValueIn(*it.CanonicalUnit); ok {
+			row.ucumUnit = it.CanonicalUnit
+		}
+	}
+
+	sameUnit := row.ucumUnit == nil || (it.UCUMUnit != nil && *it.UCUMUnit == *row.ucumUnit)
+	switch {
+	case sameUnit && it.ResultUnit != nil:
+		row.Unit = strings.TrimSpace(*it.ResultUnit)
+	case row.ucumUnit != nil:
+		row.Unit = *row.ucumUnit
+	}
+
+	if sameUnit && it.ReferenceText != nil && strings.TrimSpace(*it.ReferenceText) != "" {
+		row.Reference = strings.TrimSpace(*it.ReferenceText)
+	} else if row.ucumUnit != nil {
+		row.Reference = formatReference(it.ReferenceIn(*row.ucumUnit))
+	}
+	return row
+}
+
+// accepts tells whether the item's unit fits the row, as in CompareReports.
+func (r *CumulativeRow) accepts(it LabResultItemTimeline) bool {
+	if r.ucumUnit != nil && it.UCUMUnit != nil {
+		_, ok := ConvertUnit(analyteOf(r.AnalyteCode), 1, *it.UCUMUnit, *r.ucumUnit)
+		return ok
+	}
+	if r.ucumUnit == nil && it.UCUMUnit == nil {
+		return sameRawUnit(r.rawUnit, it.ResultUnit)
+	}
+	return true
+}
+
+// valueOf keeps the reported text unless the value must be converted to
+// the row's unit. Censored values ("<0,5") are never converted.
+func (r *CumulativeRow) valueOf(it LabResultItemTimeline) string {
+	text := ""
+	if it.ResultValue != nil {
+		text = strings.TrimSpace(*it.ResultValue)
+	}
+	if text == "" && it.NumericValue != nil {
+		text = formatCumulativeNumber(*it.NumericValue)
+	}
+	if r.ucumUnit == nil || it.UCUMUnit == nil || *it.UCUMUnit == *r.ucumUnit || strings.ContainsAny(text, "<>") {
+		return text
+	}
+	if v, ok := it.ValueIn(*r.ucumUnit); ok {
+		return formatCumulativeNumber(v)
+	}
+	return text
+}
+
+func cumulativeKey(it LabResultItemTimeline) string {
+	if it.AnalyteCode != nil && *it.AnalyteCode != "" {
+		return *it.AnalyteCode
+	}
+	return NormalizeAnalyteName(it.ParameterName)
+}
+
+// collectionDay truncates to the calendar day in UTC.
+func collectionDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func formatReference(low, high *float64) string {
+	switch {
+	case low != nil && high != nil:
+		return formatCumulativeNumber(*low) + " - " + formatCumulativeNumber(*high)
+	case low != nil:
+		return ">= " + formatCumulativeNumber(*low)
+	case high != nil:
+		return "<= " + formatCumulativeNumber(*high)
+	default:
+		return ""
+	}
+}
+
+// formatCumulativeNumber keeps four significant digits, without exponent.
+func formatCumulativeNumber(v float64) string {
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 4, 64), 64)
+	if err != nil {
+		rounded = v
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
@@ -0,0 +1,81 @@
+package labs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func cumulativeItem(test, name string, code *string, value float64, unit string, at time.Time, interp Interpretation) LabResultItemTimeline {
+	text := formatCumulativeNumber(value)
+	return LabResultItemTimeline{
+		ReportID: uuid.New(), ItemID: uuid.New(), CollectedAt: &at,
+		TestName: test, ParameterName: name, AnalyteCode: code,
+		ResultValue: &text, ResultUnit: &unit, NumericValue: &value, UCUMUnit: &unit,
+		Interpretation: interp,
+	}
+}
+
+func TestBuildCumulativeTable(t *testing.T) {
+	glucose, mgdl := "glucose", "mg/dL"
+	jan := time.Date(2026, 1, 10, 11, 0, 0, 0, time.UTC)
+	mar := time.Date(2026, 3, 5, 9, 30, 0, 0, time.UTC)
+
+	newer := cumulativeItem("Glicemia", "Glicose", &glucose, 126, "mg/dL", mar, InterpretationHigh)
+	newer.CanonicalUnit = &mgdl
+	older := cumulativeItem("Bioquímica", "GLICEMIA", &glucose, 5.5, "mmol/L", jan, InterpretationNormal)
+	refText := "70 a 99"
+	newer.ReferenceText = &refText
+
+	// Most recent first, as returned by the repository.
+	items := []LabResultItemTimeline{
+		newer,
+		cumulativeItem("Glicemia", "Glicose", &glucose, 99, "mg/dL", mar.Add(-time.Hour), InterpretationNormal),
+		cumulativeItem("Hemograma", "Neutrófilos", nil, 60, "%", mar, InterpretationNormal),
+		cumulativeItem("Hemograma", "Neutrofilos", nil, 4200, "/mm3", mar, InterpretationNormal),
+		older,
+		{ParameterName: "Sem data"},
+	}
+
+	table := BuildCumulativeTable(items)
+	if len(table.Dates) != 2 || !table.Dates[0].Equal(time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected two days oldest first, got %v", table.Dates)
+	}
+	if len(table.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d: %+v", len(table.Rows), table.Rows)
+	}
+
+	g := table.Rows[0]
+	if g.Key != "glucose" || g.Group != "Glicemia" || g.Unit != "mg/dL" || g.Reference != "70 a 99" {
+		t.Fatalf("unexpected glucose row: %+v", g)
+	}
+	// 5,5 mmol/L converted to the row unit; the later same-day result wins.
+	if g.Cells[0] == nil || g.Cells[0].Value != "99.09" || g.Cells[0].Abnormal {
+		t.Fatalf("unexpected january cell: %+v", g.Cells[0])
+	}
+	if g.Cells[1] == nil || g.Cells[1].Value != "126" || !g.Cells[1].Abnormal {
+		t.Fatalf("unexpected march cell: %+v", g.Cells[1])
+	}
+
+	// Same name in incompatible units stays in separate rows.
+	if table.Rows[1].Group != "Hemograma" || table.Rows[2].Group != "Hemograma" || table.Rows[1].Unit == table.Rows[2].Unit {
+		t.Fatalf("expected neutrophils split by unit, got %+v / %+v", table.Rows[1], table.Rows[2])
+	}
+	if table.Rows[1].Cells[0] != nil {
+		t.Fatalf("expected empty cell where the analyte was not measured")
+	}
+
+	if n := table.AbnormalCount(); n != 1 {
+		t.Fatalf("expected 1 abnormal cell, got %d", n)
+	}
+}
+
+func TestFormatCumulativeNumber(t *testing.T) {
+	cases := map[float64]string{250000: "250000", 0.0123456: "0.01235", 99.0918: "99.09", 5: "5"}
+	for in, want := range cases {
+		if got := formatCumulativeNumber(in); got != want {
+			t.Errorf("formatCumulativeNumber(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// internal/domain/entity/labs/derived.go
+package labs
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+
+	"github.com/google/uuid"
+)
+
+// Formula names the equation behind a derived item.
+type Formula string
+
+const (
+	// FormulaCKDEPI2021 is the race-free CKD-EPI creatinine equation (2021).
+	FormulaCKDEPI2021 Formula = "ckd-epi-2021"
+	// FormulaFriedewald is LDL = total - HDL - TG/5, for TG below 400 mg/dL.
+	FormulaFriedewald Formula = "friedewald"
+	// FormulaMartinHopkins divides TG by a factor picked from TG and non-HDL
+	// (Martin et al., JAMA 2013), for TG below 400 mg/dL.
+	FormulaMartinHopkins Formula = "martin-hopkins"
+	// FormulaNonHDL is total cholesterol minus HDL.
+	FormulaNonHDL Formula = "non-hdl"
+	// FormulaHOMAIR is fasting glucose (mg/dL) x insulin (µIU/mL) / 405.
+	FormulaHOMAIR Formula = "homa-ir"
+)
+
+func (f Formula) IsValid() bool {
+	switch f {
+	case FormulaCKDEPI2021, FormulaFriedewald, FormulaMartinHopkins, FormulaNonHDL, FormulaHOMAIR:
+		return true
+	default:
+		return false
+	}
+}
+
+// DerivedTestName is the result that holds the derived items of a report.
+const DerivedTestName = "Cálculos"
+
+// Lipid equations are not valid from this triglyceride level on (mg/dL).
+const maxTriglyceridesForLDL = 400
+
+// derivedInput is a measured value already converted to the unit the
+// equations expect.
+type derivedInput struct {
+	value       float64
+	collectedAt *time.Time
+}
+
+// DeriveValues computes eGFR, LDL, non-HDL and HOMA-IR from the measured
+// items of the report, returning them in a new result (nil when nothing can
+// be computed). Only exact numeric values are used ("< 0,5" is not), and
+// values the lab already reported are not computed again, except Friedewald,
+// which is kept unlinked next to the lab's LDL. eGFR needs an adult of known
+// sex; age is taken at collection (or report) date, like EvaluateCritical.
+func DeriveValues(report *LabReport, sex demographics.Gender, birthDate time.Time, at time.Time) *LabResult {
+	if report == nil {
+		return nil
+	}
+
+	result := &LabResult{
+		ID:          uuid.Must(uuid.NewV7()),
+		LabReportID: report.ID,
+		TestName:    DerivedTestName,
+		Items:       make([]LabResultItem, 0),
+	}
+	reported := report.measuredAnalytes()
+
+	add := func(code *string, name string, formula Formula, value float64, decimals int, unit, ucum *string, inputs ...derivedInput) {
+		if code != nil && reported[*code] {
+			return
+		}
+		value = roundTo(value, decimals)
+		text := strings.Replace(strconv.FormatFloat(value, 'f', decimals, 64), ".", ",", 1)
+		item := LabResultItem{
+			ID:            uuid.Must(uuid.NewV7()),
+			LabResultID:   result.ID,
+			ParameterName: name,
+			ResultValue:   &text,
+			ResultUnit:    unit,
+			NumericValue:  &value,
+			UCUMUnit:      ucum,
+			Derived:       true,
+			Formula:       formula,
+		}
+		if code != nil {
+			item.LinkAnalyteCode(*code)
+		}
+		result.Items = append(result.Items, item)
+		for _, in := range inputs {
+			if in.collectedAt != nil && (result.CollectedAt == nil || in.collectedAt.After(*result.CollectedAt)) {
+				t := *in.collectedAt
+				result.CollectedAt = &t
+			}
+		}
+	}
+
+	if creatinine, ok := report.measuredValue("creatinine", "mg/dL"); ok {
+		effective := at
+		switch {
+		case creatinine.collectedAt != nil:
+			effective = *creatinine.collectedAt
+		case report.ReportDate != nil:
+			effective = *report.ReportDate
+		}
+		if !birthDate.IsZero() {
+			if egfr, ok := CKDEPI2021(creatinine.value, AgeInYears(birthDate, effective), sex); ok {
+				add(strPtr("egfr"), "TFG estimada (CKD-EPI 2021)", FormulaCKDEPI2021, egfr, 0,
+					strPtr("mL/min/1,73m²"), strPtr("mL/min/{1.73_m2}"), creatinine)
+			}
+		}
+	}
+
+	total, okTotal := report.measuredValue("cholesterol-total", "mg/dL")
+	hdl, okHDL := report.measuredValue("cholesterol-hdl", "mg/dL")
+	tg, okTG := report.measuredValue("triglycerides", "mg/dL")
+	if okTotal && okHDL {
+		nonHDL := total.value - hdl.value
+		if nonHDL > 0 {
+			add(strPtr("cholesterol-non-hdl"), "Colesterol não-HDL", FormulaNonHDL, nonHDL, 0,
+				strPtr("mg/dL"), strPtr("mg/dL"), total, hdl)
+		}
+		if okTG {
+			if ldl, ok := Friedewald(total.value, hdl.value, tg.value); ok {
+				add(nil, "LDL colesterol (Friedewald)", FormulaFriedewald, ldl, 0,
+					strPtr("mg/dL"), strPtr("mg/dL"), total, hdl, tg)
+			}
+			if ldl, ok := MartinHopkins(total.value, hdl.value, tg.value); ok {
+				add(strPtr("cholesterol-ldl"), "LDL colesterol (Martin-Hopkins)", FormulaMartinHopkins, ldl, 0,
+					strPtr("mg/dL"), strPtr("mg/dL"), total, hdl, tg)
+			}
+		}
+	}
+
+	glucose, okGlucose := report.measuredValue("glucose", "mg/dL")
+	insulin, okInsulin := report.measuredValue("insulin", "u[IU]/mL")
+	if okGlucose && okInsulin && glucose.value > 0 && insulin.value > 0 {
+		add(strPtr("homa-ir"), "HOMA-IR", FormulaHOMAIR, glucose.value*insulin.value/405, 2,
+			nil, strPtr("1"), glucose, insulin)
+	}
+
+	if len(result.Items) == 0 {
+		return nil
+	}
+	return result
+}
+
+// CKDEPI2021 returns the eGFR in mL/min/1.73 m² for serum creatinine in
+// mg/dL. It is defined for adults (18+) of male or female sex only.
+func CKDEPI2021(creatinine float64, age int, sex demographics.Gender) (float64, bool) {
+	if creatinine <= 0 || age < 18 {
+		return 0, false
+	}
+	var kappa, alpha, factor float64
+	switch sex {
+	case demographics.GenderFemale:
+		kappa, alpha, factor = 0.7, -0.241, 1.012
+	case demographics.GenderMale:
+		kappa, alpha, factor = 0.9, -0.302, 1
+	default:
+		return 0, false
+	}
+	ratio := creatinine / kappa
+	return 142 *
+		math.Pow(math.Min(ratio, 1), alpha) *
+		math.Pow(math.Max(ratio, 1), -1.200) *
+		math.Pow(0.9938, float64(age)) *
+		factor, true
+}
+
+// Friedewald returns LDL = total - HDL - TG/5, all in mg/dL.
+func Friedewald(total, hdl, triglycerides float64) (float64, bool) {
+	if triglycerides <= 0 || triglycerides >= maxTriglyceridesForLDL {
+		return 0, false
+	}
+	ldl := total - hdl - triglycerides/5
+	return ldl, ldl > 0
+}
+
+// MartinHopkins returns LDL = non-HDL - TG/factor, all in mg/dL, with the
+// factor from the 180-cell table of the original study.
+func MartinHopkins(total, hdl, triglycerides float64) (float64, bool) {
+	if triglycerides <= 0 || triglycerides >= maxTriglyceridesForLDL {
+		return 0, false
+	}
+	nonHDL := total - hdl
+	row := len(martinHopkinsTGLimits)
+	for i, limit := range martinHopkinsTGLimits {
+		if triglycerides < limit {
+			row = i
+			break
+		}
+	}
+	col := len(martinHopkinsNonHDLLimits)
+	for i, limit := range martinHopkinsNonHDLLimits {
+		if nonHDL < limit {
+			col = i
+			break
+		}
+	}
+	ldl := nonHDL - triglycerides/martinHopkinsFactors[row][col]
+	return ldl, ldl > 0
+}
+
+// Upper bounds (exclusive, mg/dL) of the Martin-Hopkins strata; the last
+// row and column of martinHopkinsFactors take everything above.
+var (
+	martinHopkinsTGLimits = []float64{
+		50, 57, 62, 67, 72, 76, 80, 84, 88, 93, 97, 101, 106, 111, 116,
+		121, 127, 133, 139, 147, 155, 164, 174, 186, 202, 221, 248, 293,
+	}
+	martinHopkinsNonHDLLimits = []float64{100, 130, 160, 190, 220}
+)
+
+var martinHopkinsFactors = [][6]float64{
+	{3.5, 3.4, 3.3, 3.3, 3.2, 3.1}, // 7-49
+	{4.0, 3.9, 3.7, 3.6, 3.6, 3.4}, // 50-56
+	{4.3, 4.1, 4.0, 3.9, 3.8, 3.6}, // 57-61
+	{4.5, 4.3, 4.1, 4.0, 3.9, 3.9}, // 62-66
+	{4.7, 4.4, 4.3, 4.2, 4.1, 3.9}, // 67-71
+	{4.8, 4.6, 4.4, 4.2, 4.2, 4.1}, // 72-75
+	{4.9, 4.6, 4.5, 4.3, 4.3, 4.2}, // 76-79
+	{5.0, 4.8, 4.6, 4.4, 4.3, 4.2}, // 80-83
+	{5.1, 4.8, 4.6, 4.5, 4.4, 4.3}, // 84-87
+	{5.2, 4.9, 4.7, 4.6, 4.4, 4.3}, // 88-92
+	{5.3, 5.0, 4.8, 4.7, 4.5, 4.4}, // 93-96
+	{5.4, 5.1, 4.8, 4.7, 4.5, 4.3}, // 97-100
+	{5.5, 5.2, 5.0, 4.7, 4.6, 4.5}, // 101-105
+	{5.6, 5.3, 5.0, 4.8, 4.6, 4.5}, // 106-110
+	{5.7, 5.4, 5.1, 4.9, 4.7, 4.5}, // 111-115
+	{5.8, 5.5, 5.2, 5.0, 4.8, 4.6}, // 116-120
+	{6.0, 5.5, 5.3, 5.0, 4.8, 4.6}, // 121-126
+	{6.1, 5.7, 5.3, 5.1, 4.9, 4.7}, // 127-132
+	{6.2, 5.8, 5.4, 5.2, 5.0, 4.7}, // 133-138
+	{6.3, 5.9, 5.6, 5.3, 5.0, 4.8}, // 139-146
+	{6.5, 6.0, 5.7, 5.4, 5.1, 4.8}, // 147-154
+	{6.7, 6.2, 5.8, 5.4, 5.2, 4.9}, // 155-163
+	{6.8, 6.3, 5.9, 5.5, 5.3, 5.0}, // 164-173
+	{7.0, 6.5, 6.0, 5.7, 5.4, 5.1}, // 174-185
+	{7.3, 6.7, 6.2, 5.8, 5.5, 5.2}, // 186-201
+	{7.6, 6.9, 6.4, 6.0, 5.6, 5.3}, // 202-220
+	{8.0, 7.2, 6.6, 6.2, 5.9, 5.4}, // 221-247
+	{8.5, 7.6, 7.0, 6.5, 6.1, 5.6}, // 248-292
+	{9.5, 8.3, 7.5, 7.0, 6.5, 5.9}, // 293-399
+}
+
+// measuredValue returns the first exact numeric item of the analyte that
+// can be expressed in unit, ignoring derived items.
+func (r *LabReport) measuredValue(code, unit string) (derivedInput, bool) {
+	for _, tr := range r.TestResults {
+		for _, item := range tr.Items {
+			if item.Derived || item.AnalyteCode == nil || *item.AnalyteCode != code {
+				continue
+			}
+			if item.NumericValue == nil || item.UCUMUnit == nil || item.Comparator != ComparatorNone {
+				continue
+			}
+			if v, ok := ConvertUnit(code, *item.NumericValue, *item.UCUMUnit, unit); ok {
+				return derivedInput{value: v, collectedAt: tr.CollectedAt}, true
+			}
+		}
+	}
+	return derivedInput{}, false
+}
+
+// measuredAnalytes lists the analytes the lab itself reported.
+func (r *LabReport) measuredAnalytes() map[string]bool {
+	codes := make(map[string]bool)
+	for _, tr := range r.TestResults {
+		for _, item := range tr.Items {
+			if !item.Derived && item.AnalyteCode != nil {
+				codes[*item.AnalyteCode] = true
+			}
+		}
+	}
+	return codes
+}
+
+// DerivedChanged reports whether result differs from the derived items
+// already in the report, so unchanged values are not rewritten.
+func (r *LabReport) DerivedChanged(result *LabResult) bool {
+	current := make(map[Formula]LabResultItem)
+	for _, tr := range r.TestResults {
+		for _, item := range tr.Items {
+			if item.Derived {
+				current[item.Formula] = item
+			}
+		}
+	}
+	var next []LabResultItem
+	if result != nil {
+		next = result.Items
+	}
+	if len(current) != len(next) {
+		return true
+	}
+	for _, item := range next {
+		old, ok := current[item.Formula]
+		if !ok || old.NumericValue == nil || item.NumericValue == nil || *old.NumericValue != *item.NumericValue {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDerived replaces the derived items of the report with the ones in
+// result (nil removes them). Results left empty are dropped.
+func (r *LabReport) SetDerived(result *LabResult) {
+	kept := r.TestResults[:0]
+	for _, tr := range r.TestResults {
+		items := tr.Items[:0]
+		removed := false
+		for _, item := range tr.Items {
+			if item.Derived {
+				removed = true
+				continue
+			}
+			items = append(items, item)
+		}
+		tr.Items = items
+		if removed && len(items) == 0 {
+			continue
+		}
+		kept = append(kept, tr)
+	}
+	r.TestResults = kept
+	if result != nil && len(result.Items) > 0 {
+		r.TestResults = append(r.TestResults, *result)
+	}
+}
+
+func roundTo(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,169 @@
+package labs
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+
+	"github.com/google/uuid"
+)
+
+func TestCKDEPI2021(t *testing.T) {
+	cases := []struct {
+		creatinine float64
+		age        int
+		sex        demographics.Gender
+		want       float64
+	}{
+		{1.0, 50, demographics.GenderMale, 92},
+		{0.8, 60, demographics.GenderFemale, 84},
+		{0.6, 30, demographics.GenderFemale, 124},
+	}
+	for _, c := range cases {
+		got, ok := CKDEPI2021(c.creatinine, c.age, c.sex)
+		if !ok || math.Round(got) != c.want {
+			t.Errorf("CKDEPI2021(%v, %d, %s) = %v, want %v", c.creatinine, c.age, c.sex, got, c.want)
+		}
+	}
+	if _, ok := CKDEPI2021(1.0, 17, demographics.GenderMale); ok {
+		t.Errorf("expected no eGFR for a minor")
+	}
+	if _, ok := CKDEPI2021(1.0, 40, demographics.GenderUnknown); ok {
+		t.Errorf("expected no eGFR without sex")
+	}
+}
+
+func TestLDLEquations(t *testing.T) {
+	if got, ok := Friedewald(200, 50, 150); !ok || got != 120 {
+		t.Fatalf("Friedewald = %v, %v", got, ok)
+	}
+	// non-HDL 150 and TG 150 fall in the 5.7 cell.
+	if got, ok := MartinHopkins(200, 50, 150); !ok || math.Round(got) != 124 {
+		t.Fatalf("MartinHopkins = %v, %v", got, ok)
+	}
+	// Low TG and high non-HDL use the first row, last column.
+	if got, ok := MartinHopkins(300, 40, 40); !ok || math.Abs(got-(260-40/3.1)) > 1e-9 {
+		t.Fatalf("MartinHopkins = %v, %v", got, ok)
+	}
+	if _, ok := Friedewald(200, 50, 400); ok {
+		t.Fatalf("expected Friedewald to refuse TG >= 400")
+	}
+	if _, ok := MartinHopkins(200, 50, 450); ok {
+		t.Fatalf("expected Martin-Hopkins to refuse TG >= 400")
+	}
+	if len(martinHopkinsFactors) != len(martinHopkinsTGLimits)+1 {
+		t.Fatalf("factor table has %d rows for %d limits", len(martinHopkinsFactors), len(martinHopkinsTGLimits))
+	}
+}
+
+func derivedItem(name, analyte string, value float64, unit string) LabResultItem {
+	return LabResultItem{
+		ID: uuid.New(), ParameterName: name, AnalyteCode: &analyte,
+		NumericValue: &value, UCUMUnit: &unit,
+	}
+}
+
+func TestDeriveValues(t *testing.T) {
+	collected := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	report := &LabReport{
+		ID: uuid.New(),
+		TestResults: []LabResult{
+			{
+				TestName: "Bioquímica", CollectedAt: &collected,
+				Items: []LabResultItem{
+					// 88,4 µmol/L = 1,0 mg/dL
+					derivedItem("Creatinina", "creatinine", 88.4, "umol/L"),
+					derivedItem("Glicose", "glucose", 100, "mg/dL"),
+					derivedItem("Insulina", "insulin", 10, "u[IU]/mL"),
+				},
+			},
+			{
+				TestName: "Perfil lipídico", CollectedAt: &collected,
+				Items: []LabResultItem{
+					derivedItem("Colesterol total", "cholesterol-total", 200, "mg/dL"),
+					derivedItem("HDL", "cholesterol-hdl", 50, "mg/dL"),
+					derivedItem("Triglicérides", "triglycerides", 150, "mg/dL"),
+					// Printed by the lab: Martin-Hopkins is not computed again.
+					derivedItem("LDL", "cholesterol-ldl", 118, "mg/dL"),
+				},
+			},
+		},
+	}
+
+	birth := time.Date(1976, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := DeriveValues(report, demographics.GenderMale, birth, time.Now())
+	if result == nil || result.TestName != DerivedTestName || result.CollectedAt == nil || !result.CollectedAt.Equal(collected) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	got := make(map[Formula]LabResultItem)
+	for _, item := range result.Items {
+		if !item.Derived || item.LabResultID != result.ID {
+			t.Fatalf("expected derived item of the new result, got %+v", item)
+		}
+		got[item.Formula] = item
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected eGFR, non-HDL, Friedewald and HOMA-IR, got %+v", got)
+	}
+	if egfr := got[FormulaCKDEPI2021]; *egfr.NumericValue != 92 || *egfr.AnalyteCode != "egfr" || *egfr.ResultValue != "92" {
+		t.Fatalf("unexpected eGFR: %+v", egfr)
+	}
+	if nonHDL := got[FormulaNonHDL]; *nonHDL.NumericValue != 150 {
+		t.Fatalf("unexpected non-HDL: %+v", nonHDL)
+	}
+	if ldl := got[FormulaFriedewald]; *ldl.NumericValue != 120 || ldl.AnalyteCode != nil {
+		t.Fatalf("unexpected Friedewald LDL: %+v", ldl)
+	}
+	if homa := got[FormulaHOMAIR]; *homa.NumericValue != 2.47 || *homa.ResultValue != "2,47" {
+		t.Fatalf("unexpected HOMA-IR: %+v", homa)
+	}
+
+	// Recomputing over a report that already holds the derived items gives
+	// the same values and ignores them as inputs.
+	report.SetDerived(result)
+	again := DeriveValues(report, demographics.GenderMale, birth, time.Now())
+	if report.DerivedChanged(again) {
+		t.Fatalf("expected unchanged derived values")
+	}
+
+	*report.TestResults[0].Items[0].NumericValue = 176.8
+	changed := DeriveValues(report, demographics.GenderMale, birth, time.Now())
+	if !report.DerivedChanged(changed) {
+		t.Fatalf("expected a new creatinine to change eGFR")
+	}
+	report.SetDerived(changed)
+	if len(report.TestResults) != 3 {
+		t.Fatalf("expected the old derived result replaced, got %d results", len(report.TestResults))
+	}
+
+	report.SetDerived(nil)
+	if len(report.TestResults) != 2 || !report.DerivedChanged(changed) {
+		t.Fatalf("expected derived result removed, got %+v", report.TestResults)
+	}
+}
+
+func TestDeriveValues_NothingToCompute(t *testing.T) {
+	report := &LabReport{TestResults: []LabResult{{Items: []LabResultItem{
+		derivedItem("Hemoglobina", "hemoglobin", 14, "g/dL"),
+		// "< 0,5" is a bound, not a value.
+		{ParameterName: "Creatinina", AnalyteCode: strPtr("creatinine"), NumericValue: new(float64), UCUMUnit: strPtr("mg/dL"), Comparator: ComparatorLess},
+	}}}}
+	if result := DeriveValues(report, demographics.GenderFemale, time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), time.Now()); result != nil {
+		t.Fatalf("expected nothing derived, got %+v", result)
+	}
+}
+
+func TestAmend_RejectsDerivedItem(t *testing.T) {
+	item := derivedItem("HOMA-IR", "homa-ir", 2.1, "1")
+	item.Derived, item.Formula = true, FormulaHOMAIR
+	report := &LabReport{ID: uuid.New(), Status: StatusFinal, Version: 1, TestResults: []LabResult{{Items: []LabResultItem{item}}}}
+
+	value := "3"
+	_, err := report.Amend([]FieldEdit{{Target: EditTargetItem, TargetID: item.ID, Field: "result_value", Value: &value}}, "", uuid.New(), nil, time.Now())
+	if err == nil {
+		t.Fatalf("expected derived item edit to be rejected")
+	}
+}
@@ -11,8 +11,26 @@ var (
 	ErrInvalidInput           = errors.New("invalid input")
 	ErrMissingId              = errors.New("missing id")
 	ErrLabReportAlreadyExists = errors.New("lab report already exists")
+	ErrDuplicateDocument      = errors.New("patient already has a report for this file")
 	ErrInvalidPatientID       = errors.New("patient id is required")
 	ErrInvalidUploadedByUser  = errors.New("uploaded by user id is required")
 	ErrInvalidTestName        = errors.New("test name is required")
 	ErrInvalidParameterName   = errors.New("parameter name is required")
+
+	// Amendments
+	ErrInvalidStatus      = errors.New("invalid report status")
+	ErrUnknownEditTarget  = errors.New("unknown edit target")
+	ErrEditTargetNotFound = errors.New("result or item not found in report")
+	ErrFieldNotEditable   = errors.New("field cannot be edited")
+	ErrNoChanges          = errors.New("amendment has no changes")
+	ErrVersionConflict    = errors.New("report was changed by someone else")
+
+	// Review
+	ErrNotInReview = errors.New("report is not waiting for review")
+	ErrInReview    = errors.New("report must be approved in review before release")
+
+	// Critical values
+	ErrInvalidCriticalRule = errors.New("critical rule needs an analyte, a UCUM unit and at least one limit")
+	ErrInvalidAgeRange     = errors.New("critical rule age range is invalid")
+	ErrCriticalRuleExists  = errors.New("a rule already covers this analyte, sex and age range")
 )
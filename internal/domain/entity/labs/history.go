@@ -0,0 +1,45 @@
+// internal/domain/entity/labs/history.go
+package labs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HistoryRow is one item of the patient's lab history with the metadata of
+// its report and result, as exported to spreadsheets.
+type HistoryRow struct {
+	ReportID   uuid.UUID
+	ReportDate *time.Time
+	LabName    *string
+	Source     ReportSource
+	Status     ReportStatus
+
+	ResultID    uuid.UUID
+	TestName    string
+	Material    *string
+	Method      *string
+	CollectedAt *time.Time
+
+	Item LabResultItem
+
+	// CanonicalUnit is the analyte's default UCUM unit, when linked to the catalog.
+	CanonicalUnit *string
+}
+
+// Normalized returns the numeric value in the analyte's default unit or,
+// when it cannot be converted, in the item's own UCUM unit. It reports
+// false for qualitative results and units that were not parsed.
+func (r HistoryRow) Normalized() (float64, string, bool) {
+	it := r.Item
+	if it.NumericValue == nil || it.UCUMUnit == nil {
+		return 0, "", false
+	}
+	if r.CanonicalUnit != nil {
+		if v, ok := ConvertUnit(analyteOf(it.AnalyteCode), *it.NumericValue, *it.UCUMUnit, *r.CanonicalUnit); ok {
+			return v, *r.CanonicalUnit, true
+		}
+	}
+	return *it.NumericValue, *it.UCUMUnit, true
+}
@@ -0,0 +1,28 @@
+package labs
+
+import "testing"
+
+func TestHistoryRowNormalized(t *testing.T) {
+	glucose, mgdl, mmol := "glucose", "mg/dL", "mmol/L"
+	value := 5.5
+	row := HistoryRow{
+		Item:          LabResultItem{AnalyteCode: &glucose, NumericValue: &value, UCUMUnit: &mmol},
+		CanonicalUnit: &mgdl,
+	}
+	if v, unit, ok := row.Normalized(); !ok || unit != "mg/dL" || formatCumulativeNumber(v) != "99.09" {
+		t.Fatalf("expected glucose in mg/dL, got %v %q %v", v, unit, ok)
+	}
+
+	// Without a known conversion the item keeps its own unit.
+	row.CanonicalUnit = &mmol
+	row.Item.UCUMUnit = &mgdl
+	row.Item.AnalyteCode = nil
+	if v, unit, ok := row.Normalized(); !ok || unit != "mg/dL" || v != 5.5 {
+		t.Fatalf("expected the item unit, got %v %q %v", v, unit, ok)
+	}
+
+	row.Item.NumericValue = nil
+	if _, _, ok := row.Normalized(); ok {
+		t.Fatalf("expected no normalized value for qualitative results")
+	}
+}
@@ -0,0 +1,267 @@
+// internal/domain/entity/labs/identity.go
+package labs
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// IdentityStatus is the outcome of cross-checking the identity printed on an
+// extracted report against the patient record.
+type IdentityStatus string
+
+const (
+	// IdentityVerified means the printed identity matches the patient.
+	IdentityVerified IdentityStatus = "verified"
+	// IdentityFlagged means a partial match: the report is kept but should
+	// be reviewed (e.g. OCR misread the birth date).
+	IdentityFlagged IdentityStatus = "flagged"
+	// IdentityMismatch means the report most likely belongs to someone else;
+	// it is rejected unless an authorized user overrides the check.
+	IdentityMismatch IdentityStatus = "mismatch"
+	// IdentityUnverified means the report carries no name, birth date or CPF.
+	IdentityUnverified IdentityStatus = "unverified"
+	// IdentityOverridden is a mismatch explicitly accepted at upload time.
+	IdentityOverridden IdentityStatus = "overridden"
+)
+
+const (
+	identityVerifiedThreshold = 0.85
+	identityFlaggedThreshold  = 0.6
+
+	nameWeight = 0.5
+	dobWeight  = 0.25
+	cpfWeight  = 0.25
+)
+
+// PatientIdentity is what the patient record says about who the exam belongs to.
+type PatientIdentity struct {
+	FullName  string
+	BirthDate time.Time
+	CPF       string
+}
+
+// IdentityMatch holds each signal found on the report and the weighted score.
+// Signals missing from the report are nil and do not count towards Score.
+type IdentityMatch struct {
+	Status    IdentityStatus
+	Score     *float64
+	NameScore *float64
+	DOBMatch  *bool
+	CPFMatch  *bool
+}
+
+// MatchIdentity compares the patient name, birth date and any CPF found in
+// the raw text of an extracted report with the patient record. Reports often
+// print other CPFs (requesting doctor, guarantor), so the CPF signal matches
+// when any labelled CPF on the page is the patient's; only when none is does
+// it override the other signals as a mismatch.
+func MatchIdentity(report *LabReport, patient PatientIdentity) IdentityMatch {
+	var m IdentityMatch
+	if report == nil {
+		m.Status = IdentityUnverified
+		return m
+	}
+
+	var total, weights float64
+	if report.PatientName != nil {
+		if score, ok := NameSimilarity(*report.PatientName, patient.FullName); ok {
+			m.NameScore = &score
+			total += nameWeight * score
+			weights += nameWeight
+		}
+	}
+	if report.PatientDOB != nil && !patient.BirthDate.IsZero() {
+		same := sameDate(*report.PatientDOB, patient.BirthDate)
+		m.DOBMatch = &same
+		total += dobWeight * boolScore(same)
+		weights += dobWeight
+	}
+	if report.RawText != nil {
+		if cpf := digitsOnly(patient.CPF); len(cpf) == 11 {
+			if found := findCPFs(*report.RawText); len(found) > 0 {
+				same := false
+				for _, f := range found {
+					same = same || f == cpf
+				}
+				m.CPFMatch = &same
+				total += cpfWeight * boolScore(same)
+				weights += cpfWeight
+			}
+		}
+	}
+
+	if weights == 0 {
+		m.Status = IdentityUnverified
+		return m
+	}
+
+	score := total / weights
+	m.Score = &score
+	switch {
+	case m.CPFMatch != nil && !*m.CPFMatch:
+		m.Status = IdentityMismatch
+	case score >= identityVerifiedThreshold:
+		m.Status = IdentityVerified
+	case score >= identityFlaggedThreshold:
+		m.Status = IdentityFlagged
+	default:
+		m.Status = IdentityMismatch
+	}
+	return m
+}
+
+// MismatchedFields lists the signals that point to another person, named
+// after the report fields they come from.
+func (m IdentityMatch) MismatchedFields() []string {
+	var fields []string
+	if m.NameScore != nil && *m.NameScore < identityVerifiedThreshold {
+		fields = append(fields, "patient_name")
+	}
+	if m.DOBMatch != nil && !*m.DOBMatch {
+		fields = append(fields, "patient_dob")
+	}
+	if m.CPFMatch != nil && !*m.CPFMatch {
+		fields = append(fields, "cpf")
+	}
+	return fields
+}
+
+var nameParticles = map[string]bool{
+	"de": true, "da": true, "do": true, "das": true, "dos": true, "e": true,
+}
+
+// NameSimilarity scores two person names in [0, 1], ignoring case, accents,
+// punctuation and particles ("de", "da"...). Each token of the shorter name
+// is matched to its closest token in the other; initials ("J.") match any
+// token with the same first letter. ok is false when either name is empty.
+func NameSimilarity(a, b string) (score float64, ok bool) {
+	ta, tb := nameTokens(a), nameTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0, false
+	}
+	if len(ta) > len(tb) {
+		ta, tb = tb, ta
+	}
+
+	var sum float64
+	for _, t := range ta {
+		best := 0.0
+		for _, u := range tb {
+			if s := tokenSimilarity(t, u); s > best {
+				best = s
+			}
+		}
+		sum += best
+	}
+	score = sum / float64(len(ta))
+
+	// First names weigh more: "Ana Silva" is not "Maria Silva".
+	score = (score + tokenSimilarity(ta[0], tb[0])) / 2
+
+	// A single given name says little about a full name.
+	if len(ta) == 1 && len(tb) > 1 && score > identityFlaggedThreshold {
+		score = identityFlaggedThreshold
+	}
+	return score, true
+}
+
+func nameTokens(name string) []string {
+	folded := foldAccents(strings.ToLower(name))
+	fields := strings.FieldsFunc(folded, func(r rune) bool { return !unicode.IsLetter(r) })
+	tokens := fields[:0]
+	for _, f := range fields {
+		if !nameParticles[f] {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+func tokenSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 1 || len(rb) == 1 {
+		return boolScore(ra[0] == rb[0])
+	}
+	longest := max(len(ra), len(rb))
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// cpfPattern only takes numbers right after a "CPF" label ("CPF:", "C.P.F.
+// nº"...), so protocol and order numbers with 11 digits are not read as CPFs.
+var cpfPattern = regexp.MustCompile(`(?i)\bC\.?P\.?F\b\.?[^\d\n]{0,10}(\d{3}\.?\d{3}\.?\d{3}-?\d{2})\b`)
+
+// findCPFs returns the labelled CPFs in text with valid check digits.
+func findCPFs(text string) []string {
+	matches := cpfPattern.FindAllStringSubmatch(text, -1)
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if cpf := digitsOnly(m[1]); validCPF(cpf) {
+			out = append(out, cpf)
+		}
+	}
+	return out
+}
+
+// validCPF checks the two mod-11 check digits of an 11-digit CPF. Repeated
+// digits ("111.111.111-11") pass the arithmetic but are not issued.
+func validCPF(cpf string) bool {
+	if len(cpf) != 11 || strings.Count(cpf, cpf[:1]) == 11 {
+		return false
+	}
+	for _, n := range []int{9, 10} {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += int(cpf[i]-'0') * (n + 1 - i)
+		}
+		digit := sum * 10 % 11 % 10
+		if int(cpf[n]-'0') != digit {
+			return false
+		}
+	}
+	return true
+}
+
+func digitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func boolScore(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
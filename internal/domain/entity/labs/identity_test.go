@@ -0,0 +1,92 @@
+// internal/domain/entity/labs/identity_test.go
+package labs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	cases := []struct {
+		a, b    string
+		atLeast float64
+		below   float64
+	}{
+		{a: "JOSÉ DA SILVA", b: "Jose Silva", atLeast: 1, below: 1.01},
+		{a: "Maria C. Souza", b: "Maria Conceição de Souza", atLeast: 1, below: 1.01},
+		{a: "Joao Pereira Santos", b: "João Pereira dos Santos", atLeast: 1, below: 1.01},
+		{a: "Gabriel Mendes", b: "Gabriel Mendez", atLeast: 0.85, below: 1},
+		{a: "Ana Silva", b: "Maria Silva", below: 0.6},
+		{a: "Pedro Alves", b: "Lucia Ramos", below: 0.3},
+		{a: "Maria", b: "Maria Silva", atLeast: 0.6, below: 0.61},
+	}
+	for _, tc := range cases {
+		got, ok := NameSimilarity(tc.a, tc.b)
+		if !ok {
+			t.Fatalf("NameSimilarity(%q, %q): expected ok", tc.a, tc.b)
+		}
+		if got < tc.atLeast || got >= tc.below {
+			t.Errorf("NameSimilarity(%q, %q) = %.2f, want [%.2f, %.2f)", tc.a, tc.b, got, tc.atLeast, tc.below)
+		}
+	}
+
+	if _, ok := NameSimilarity("  ", "Maria"); ok {
+		t.Fatalf("expected blank name to be ignored")
+	}
+}
+
+func TestMatchIdentity(t *testing.T) {
+	patient := PatientIdentity{
+		FullName:  "Maria Conceição de Souza",
+		BirthDate: time.Date(1980, 5, 17, 0, 0, 0, 0, time.UTC),
+		CPF:       "123.456.789-09",
+	}
+	name := "MARIA CONCEICAO SOUZA"
+	dob := time.Date(1980, 5, 17, 0, 0, 0, 0, time.UTC)
+	otherDOB := time.Date(1980, 5, 18, 0, 0, 0, 0, time.UTC)
+	otherName := "Ana Paula Ribeiro"
+	sameCPF := "Paciente: MARIA CONCEICAO SOUZA CPF: 123.456.789-09"
+	otherCPF := "Paciente: MARIA CONCEICAO SOUZA CPF 98765432100"
+	protocolOnly := "Paciente: MARIA CONCEICAO SOUZA Protocolo: 98765432100"
+	badCheckDigits := "Paciente: MARIA CONCEICAO SOUZA CPF: 987.654.321-01"
+	doctorCPF := "Paciente: MARIA CONCEICAO SOUZA C.P.F. nº 123.456.789-09\nSolicitante: Dr. Jose CPF: 987.654.321-00"
+
+	cases := []struct {
+		label  string
+		report *LabReport
+		want   IdentityStatus
+	}{
+		{"all signals match", &LabReport{PatientName: &name, PatientDOB: &dob, RawText: &sameCPF}, IdentityVerified},
+		{"birth date misread", &LabReport{PatientName: &name, PatientDOB: &otherDOB}, IdentityFlagged},
+		{"another person", &LabReport{PatientName: &otherName, PatientDOB: &otherDOB}, IdentityMismatch},
+		{"cpf differs", &LabReport{PatientName: &name, PatientDOB: &dob, RawText: &otherCPF}, IdentityMismatch},
+		{"unlabelled number is not a cpf", &LabReport{PatientName: &name, PatientDOB: &dob, RawText: &protocolOnly}, IdentityVerified},
+		{"invalid check digits are ignored", &LabReport{PatientName: &name, PatientDOB: &dob, RawText: &badCheckDigits}, IdentityVerified},
+		{"another cpf next to the patient's", &LabReport{PatientName: &name, PatientDOB: &dob, RawText: &doctorCPF}, IdentityVerified},
+		{"nothing printed", &LabReport{}, IdentityUnverified},
+	}
+	for _, tc := range cases {
+		got := MatchIdentity(tc.report, patient)
+		if got.Status != tc.want {
+			t.Errorf("%s: status = %s, want %s (%+v)", tc.label, got.Status, tc.want, got)
+		}
+		if tc.want == IdentityUnverified && got.Score != nil {
+			t.Errorf("%s: expected no score, got %v", tc.label, *got.Score)
+		}
+	}
+
+	m := MatchIdentity(&LabReport{PatientName: &name, PatientDOB: &dob, RawText: &sameCPF}, patient)
+	if m.CPFMatch == nil || !*m.CPFMatch || m.DOBMatch == nil || !*m.DOBMatch {
+		t.Fatalf("expected cpf and birth date signals, got %+v", m)
+	}
+
+	m = MatchIdentity(&LabReport{PatientName: &name, PatientDOB: &dob, RawText: &protocolOnly}, patient)
+	if m.CPFMatch != nil {
+		t.Fatalf("expected no cpf signal from an unlabelled number, got %v", *m.CPFMatch)
+	}
+
+	m = MatchIdentity(&LabReport{PatientName: &otherName, PatientDOB: &otherDOB}, patient)
+	if got := m.MismatchedFields(); len(got) != 2 || got[0] != "patient_name" || got[1] != "patient_dob" {
+		t.Fatalf("expected name and birth date to be reported, got %v", got)
+	}
+}
@@ -8,6 +8,27 @@ import (
 	"github.com/google/uuid"
 )
 
+// ReportSource records how a report entered the system.
+type ReportSource string
+
+const (
+	// SourceDocument is a report extracted by AI from an uploaded file.
+	SourceDocument ReportSource = "document"
+	// SourceManual is a report typed in by a professional, without a file.
+	SourceManual ReportSource = "manual"
+	SourceFHIR   ReportSource = "fhir"
+	SourceHL7v2  ReportSource = "hl7v2"
+)
+
+func (s ReportSource) IsValid() bool {
+	switch s {
+	case SourceDocument, SourceManual, SourceFHIR, SourceHL7v2:
+		return true
+	default:
+		return false
+	}
+}
+
 type LabReport struct {
 	ID        uuid.UUID `json:"id"`
 	PatientID uuid.UUID `json:"patient_id"`
@@ -22,8 +43,35 @@ type LabReport struct {
 	ReportDate        *time.Time `json:"report_date,omitempty"`
 	Fingerprint       *string    `json:"fingerprint,omitempty"`
 
+	Source ReportSource `json:"source"`
+	// Status follows FHIR DiagnosticReport.status; Version starts at 1 and
+	// grows with each amendment (see Amend).
+	Status  ReportStatus `json:"status"`
+	Version int          `json:"version"`
+
 	RawText *string `json:"raw_text,omitempty"`
 
+	// DocumentURI is the storage URI of the uploaded file; nil when the
+	// report did not come from a document (FHIR, HL7 v2).
+	DocumentURI *string `json:"document_uri,omitempty"`
+	MimeType    *string `json:"mime_type,omitempty"`
+	// ContentHash is the hex SHA-256 of the uploaded file, used to reject
+	// re-uploads before extraction.
+	ContentHash *string `json:"content_hash,omitempty"`
+	// IdentityStatus and IdentityScore record the cross-check of the printed
+	// identity against the patient (see MatchIdentity); nil for sources
+	// matched by identifier.
+	IdentityStatus *IdentityStatus `json:"identity_status,omitempty"`
+	IdentityScore  *float64        `json:"identity_score,omitempty"`
+
+	// FieldConfidence holds the extractor's confidence in header fields.
+	// ReviewStatus is set when low-confidence values sent the report to
+	// review (see FlagForReview); ReviewedBy/At record the approval.
+	FieldConfidence FieldConfidence `json:"field_confidence,omitempty"`
+	ReviewStatus    *ReviewStatus   `json:"review_status,omitempty"`
+	ReviewedBy      *uuid.UUID      `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time      `json:"reviewed_at,omitempty"`
+
 	TestResults []LabResult `json:"test_results"`
 
 	CreatedAt  time.Time `json:"created_at"`
@@ -58,6 +106,8 @@ func NewLabReport(patientID, uploadedBy string) (*LabReport, error) {
 	return &LabReport{
 		ID:         uuid.Must(uuid.NewV7()),
 		PatientID:  parsedPatientID,
+		Status:     StatusFinal,
+		Version:    1,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 		UploadedBy: parsedUploadedBy,
@@ -80,6 +130,8 @@ func (r *LabReport) Normalize() {
 	r.TechnicalManager = trimToNil(r.TechnicalManager)
 	r.Fingerprint = trimToNil(r.Fingerprint)
 	r.RawText = trimToNil(r.RawText)
+	r.DocumentURI = trimToNil(r.DocumentURI)
+	r.MimeType = trimToNil(r.MimeType)
 
 	// Times: force UTC if set
 	r.PatientDOB = utcOrNil(r.PatientDOB)
@@ -148,6 +200,32 @@ type LabResultItem struct {
 	ResultValue   *string `json:"result_value,omitempty"`
 	ResultUnit    *string `json:"result_unit,omitempty"`
 	ReferenceText *string `json:"reference_text,omitempty"`
+
+	// AnalyteCode links the item to the analyte catalog; nil when unmatched.
+	AnalyteCode *string `json:"analyte_code,omitempty"`
+	// AnalyteLink tells whether AnalyteCode came from the name or a code.
+	AnalyteLink AnalyteLink `json:"analyte_link,omitempty"`
+
+	// Structured form of ResultValue/ResultUnit/ReferenceText, filled by ParseStructuredResult.
+	NumericValue     *float64       `json:"numeric_value,omitempty"`
+	UCUMUnit         *string        `json:"ucum_unit,omitempty"`
+	Comparator       Comparator     `json:"comparator,omitempty"`
+	QualitativeValue *string        `json:"qualitative_value,omitempty"`
+	ReferenceLow     *float64       `json:"reference_low,omitempty"`
+	ReferenceHigh    *float64       `json:"reference_high,omitempty"`
+	Interpretation   Interpretation `json:"interpretation,omitempty"`
+
+	// Confidence is the extractor's confidence in the whole item and
+	// FieldConfidence in each of its fields; nil/empty when not scored.
+	// Confirmed is set once a professional checked the values in review.
+	Confidence      *float64        `json:"confidence,omitempty"`
+	FieldConfidence FieldConfidence `json:"field_confidence,omitempty"`
+	Confirmed       bool            `json:"confirmed,omitempty"`
+
+	// Derived marks items computed from other items of the report (see
+	// DeriveValues); Formula names the equation. They cannot be edited.
+	Derived bool    `json:"derived,omitempty"`
+	Formula Formula `json:"formula,omitempty"`
 }
 
 // NewLabResultItem creates an item with generated ID and required parameter name.
@@ -190,10 +268,67 @@ type LabResultItemTimeline struct {
 	LabResultID   uuid.UUID  `json:"lab_result_id"`
 	ItemID        uuid.UUID  `json:"item_id"`
 	ReportDate    *time.Time `json:"report_date,omitempty"`
+	CollectedAt   *time.Time `json:"collected_at,omitempty"`
 	TestName      string     `json:"test_name"`
 	ParameterName string     `json:"parameter_name"`
+	AnalyteCode   *string    `json:"analyte_code,omitempty"`
 	ResultValue   *string    `json:"result_value,omitempty"`
 	ResultUnit    *string    `json:"result_unit,omitempty"`
+	ReferenceText *string    `json:"reference_text,omitempty"`
+
+	NumericValue   *float64       `json:"numeric_value,omitempty"`
+	UCUMUnit       *string        `json:"ucum_unit,omitempty"`
+	ReferenceLow   *float64       `json:"reference_low,omitempty"`
+	ReferenceHigh  *float64       `json:"reference_high,omitempty"`
+	Interpretation Interpretation `json:"interpretation,omitempty"`
+
+	Derived bool    `json:"derived,omitempty"`
+	Formula Formula `json:"formula,omitempty"`
+
+	// CanonicalUnit is the analyte's default UCUM unit, when linked to the catalog.
+	CanonicalUnit *string `json:"canonical_unit,omitempty"`
+}
+
+// ValueIn returns the numeric value converted to the given UCUM unit.
+func (t LabResultItemTimeline) ValueIn(unit string) (float64, bool) {
+	if t.NumericValue == nil || t.UCUMUnit == nil {
+		return 0, false
+	}
+	code := ""
+	if t.AnalyteCode != nil {
+		code = *t.AnalyteCode
+	}
+	return ConvertUnit(code, *t.NumericValue, *t.UCUMUnit, unit)
+}
+
+// ReferenceIn returns the reference bounds converted to the given UCUM unit.
+// Bounds are stored in the result's own unit, so they convert like the value.
+func (t LabResultItemTimeline) ReferenceIn(unit string) (low, high *float64) {
+	if t.UCUMUnit == nil {
+		return nil, nil
+	}
+	code := ""
+	if t.AnalyteCode != nil {
+		code = *t.AnalyteCode
+	}
+	convert := func(v *float64) *float64 {
+		if v == nil {
+			return nil
+		}
+		if c, ok := ConvertUnit(code, *v, *t.UCUMUnit, unit); ok {
+			return &c
+		}
+		return nil
+	}
+	return convert(t.ReferenceLow), convert(t.ReferenceHigh)
+}
+
+// EffectiveAt is when the sample was taken, or the report date without it.
+func (t LabResultItemTimeline) EffectiveAt() *time.Time {
+	if t.CollectedAt != nil {
+		return t.CollectedAt
+	}
+	return t.ReportDate
 }
 
 func trimToNil(s *string) *string {
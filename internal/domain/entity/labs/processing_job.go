@@ -0,0 +1,88 @@
+package labs
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusQueued, JobStatusRunning, JobStatusSucceeded, JobStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinished reports whether the job reached a terminal status.
+func (s JobStatus) IsFinished() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed
+}
+
+// ProcessingJob tracks the asynchronous extraction of an uploaded lab document.
+type ProcessingJob struct {
+	ID          uuid.UUID `json:"id"`
+	PatientID   uuid.UUID `json:"patient_id"`
+	UploadedBy  uuid.UUID `json:"uploaded_by"`
+	DocumentURI string    `json:"document_uri"`
+	MimeType    string    `json:"mime_type"`
+	// ContentHash is the hex SHA-256 of the file, passed on to the report.
+	ContentHash *string `json:"content_hash,omitempty"`
+	// IdentityOverride accepts the document even if the identity check fails.
+	IdentityOverride bool `json:"identity_override"`
+
+	Status   JobStatus `json:"status"`
+	Attempts int       `json:"attempts"`
+
+	// Filled when the job succeeds.
+	LabReportID *uuid.UUID `json:"lab_report_id,omitempty"`
+
+	// Filled when the job fails; ErrorCode holds an apperr.ErrorKind.
+	ErrorCode    *string `json:"error_code,omitempty"`
+	ErrorMessage *string `json:"error_message,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewProcessingJob creates a queued job for a document already in storage.
+func NewProcessingJob(patientID, uploadedBy uuid.UUID, documentURI, mimeType string) (*ProcessingJob, error) {
+	documentURI = strings.TrimSpace(documentURI)
+	mimeType = strings.TrimSpace(mimeType)
+
+	if patientID == uuid.Nil {
+		return nil, ErrInvalidPatientID
+	}
+	if uploadedBy == uuid.Nil {
+		return nil, ErrInvalidUploadedByUser
+	}
+	if documentURI == "" || mimeType == "" {
+		return nil, ErrInvalidDocument
+	}
+
+	now := time.Now().UTC()
+
+	return &ProcessingJob{
+		ID:          uuid.Must(uuid.NewV7()),
+		PatientID:   patientID,
+		UploadedBy:  uploadedBy,
+		DocumentURI: documentURI,
+		MimeType:    mimeType,
+		Status:      JobStatusQueued,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
@@ -0,0 +1,340 @@
+// internal/domain/entity/labs/result_value.go
+package labs
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+)
+
+// Comparator qualifies a numeric result reported as a bound ("< 0,5").
+type Comparator string
+
+const (
+	ComparatorNone         Comparator = ""
+	ComparatorLess         Comparator = "<"
+	ComparatorLessEqual    Comparator = "<="
+	ComparatorGreater      Comparator = ">"
+	ComparatorGreaterEqual Comparator = ">="
+)
+
+func (c Comparator) IsValid() bool {
+	switch c {
+	case ComparatorNone, ComparatorLess, ComparatorLessEqual, ComparatorGreater, ComparatorGreaterEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+// Interpretation follows the HL7 observation interpretation codes.
+type Interpretation string
+
+const (
+	InterpretationUnknown      Interpretation = ""
+	InterpretationNormal       Interpretation = "N"
+	InterpretationLow          Interpretation = "L"
+	InterpretationHigh         Interpretation = "H"
+	InterpretationCriticalLow  Interpretation = "LL"
+	InterpretationCriticalHigh Interpretation = "HH"
+	// Abnormal is used for qualitative results that differ from the reference.
+	InterpretationAbnormal Interpretation = "A"
+)
+
+func (i Interpretation) IsValid() bool {
+	switch i {
+	case InterpretationUnknown, InterpretationNormal, InterpretationLow, InterpretationHigh,
+		InterpretationCriticalLow, InterpretationCriticalHigh, InterpretationAbnormal:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAbnormal reports any flag other than normal (unknown is not abnormal).
+func (i Interpretation) IsAbnormal() bool {
+	return i != InterpretationUnknown && i != InterpretationNormal
+}
+
+// IsCritical reports whether the interpretation is a critical (panic) value.
+func (i Interpretation) IsCritical() bool {
+	return i == InterpretationCriticalLow || i == InterpretationCriticalHigh
+}
+
+// ReferenceRange is the parsed form of a free-text reference.
+type ReferenceRange struct {
+	Low           *float64
+	High          *float64
+	LowInclusive  bool
+	HighInclusive bool
+	// Qualitative holds the expected value for non-numeric references ("Negativo").
+	Qualitative *string
+}
+
+func (r ReferenceRange) IsEmpty() bool {
+	return r.Low == nil && r.High == nil && r.Qualitative == nil
+}
+
+const numberPattern = `(\d{1,3}(?:\.\d{3})+(?:,\d+)?|\d+(?:[.,]\d+)?)`
+
+var (
+	reNumber    = regexp.MustCompile(`^` + numberPattern + `$`)
+	reThousands = regexp.MustCompile(`^\d{1,3}(?:\.\d{3})+$`)
+
+	reResultComparator = regexp.MustCompile(`^(<=|>=|≤|≥|<|>|inferior a|menor que|superior a|maior que)?\s*` + numberPattern + `\s*$`)
+
+	reRefBetween = regexp.MustCompile(`(?:de\s+|entre\s+)?` + numberPattern + `\s*(?:a|à|-|–|até|ate|e)\s*` + numberPattern)
+	reRefUpper   = regexp.MustCompile(`(até|ate|inferior a|menor que|menor ou igual a|abaixo de|<=|≤|<|m[áa]ximo:?)\s*` + numberPattern)
+	reRefLower   = regexp.MustCompile(`(superior a|maior que|maior ou igual a|acima de|a partir de|>=|≥|>|m[íi]nimo:?)\s*` + numberPattern)
+
+	reRefMaleLabel    = regexp.MustCompile(`homens|homem|masculino`)
+	reRefFemaleLabel  = regexp.MustCompile(`mulheres|mulher|feminino`)
+	reRefDefaultLabel = regexp.MustCompile(`desej[áa]vel|[óo]timo|normal|adultos?`)
+)
+
+var qualitativeReferences = []string{
+	"nao reagente", "reagente", "negativo", "positivo", "ausente", "ausentes",
+	"presente", "presentes", "indetectavel", "detectavel", "normal",
+}
+
+// ParseNumberPTBR parses numbers written in Brazilian format ("6.500", "14,2").
+// A dot followed by exactly three digits is a thousands separator.
+func ParseNumberPTBR(raw string) (float64, bool) {
+	raw = strings.TrimSpace(raw)
+	if !reNumber.MatchString(raw) {
+		return 0, false
+	}
+
+	if strings.Contains(raw, ",") || reThousands.MatchString(raw) {
+		raw = strings.ReplaceAll(raw, ".", "")
+		raw = strings.ReplaceAll(raw, ",", ".")
+	}
+
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// ParseResultValue splits a raw result into numeric value and comparator, or
+// a qualitative value when it is not a number.
+func ParseResultValue(raw string) (*float64, Comparator, *string) {
+	raw = strings.Join(strings.Fields(raw), " ")
+	if raw == "" {
+		return nil, ComparatorNone, nil
+	}
+
+	if m := reResultComparator.FindStringSubmatch(strings.ToLower(raw)); m != nil {
+		if v, ok := ParseNumberPTBR(m[2]); ok {
+			return &v, parseComparator(m[1]), nil
+		}
+	}
+
+	return nil, ComparatorNone, &raw
+}
+
+func parseComparator(raw string) Comparator {
+	switch raw {
+	case "<", "inferior a", "menor que":
+		return ComparatorLess
+	case "<=", "≤":
+		return ComparatorLessEqual
+	case ">", "superior a", "maior que":
+		return ComparatorGreater
+	case ">=", "≥":
+		return ComparatorGreaterEqual
+	default:
+		return ComparatorNone
+	}
+}
+
+type rangeMatch struct {
+	start, end int
+	rng        ReferenceRange
+}
+
+// ParseReferenceRange parses Portuguese reference text such as "70 a 99",
+// "Até 99 mg/dL" or "Superior a 40". When the text lists several ranges, the
+// one labelled for the patient's sex (or "desejável"/"normal") is used;
+// otherwise the range is considered ambiguous and left empty.
+func ParseReferenceRange(text string, sex demographics.Gender) ReferenceRange {
+	lower := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if lower == "" {
+		return ReferenceRange{}
+	}
+
+	matches := findRangeMatches(lower)
+	switch len(matches) {
+	case 0:
+		return parseQualitativeReference(lower)
+	case 1:
+		return matches[0].rng
+	}
+
+	var label *regexp.Regexp
+	switch sex {
+	case demographics.GenderMale:
+		label = reRefMaleLabel
+	case demographics.GenderFemale:
+		label = reRefFemaleLabel
+	default:
+		label = reRefDefaultLabel
+	}
+
+	loc := label.FindStringIndex(lower)
+	if loc == nil && label != reRefDefaultLabel {
+		loc = reRefDefaultLabel.FindStringIndex(lower)
+	}
+	if loc == nil {
+		return ReferenceRange{}
+	}
+	for _, m := range matches {
+		if m.start >= loc[1] {
+			return m.rng
+		}
+	}
+	return ReferenceRange{}
+}
+
+func findRangeMatches(text string) []rangeMatch {
+	var matches []rangeMatch
+	overlaps := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.end && end > m.start {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, loc := range reRefBetween.FindAllStringSubmatchIndex(text, -1) {
+		low, okLow := ParseNumberPTBR(text[loc[2]:loc[3]])
+		high, okHigh := ParseNumberPTBR(text[loc[4]:loc[5]])
+		if !okLow || !okHigh || low > high {
+			continue
+		}
+		matches = append(matches, rangeMatch{loc[0], loc[1], ReferenceRange{
+			Low: &low, High: &high, LowInclusive: true, HighInclusive: true,
+		}})
+	}
+
+	for _, loc := range reRefUpper.FindAllStringSubmatchIndex(text, -1) {
+		if overlaps(loc[0], loc[1]) {
+			continue
+		}
+		high, ok := ParseNumberPTBR(text[loc[4]:loc[5]])
+		if !ok {
+			continue
+		}
+		op := text[loc[2]:loc[3]]
+		inclusive := op != "<" && op != "inferior a" && op != "menor que" && op != "abaixo de"
+		matches = append(matches, rangeMatch{loc[0], loc[1], ReferenceRange{High: &high, HighInclusive: inclusive}})
+	}
+
+	for _, loc := range reRefLower.FindAllStringSubmatchIndex(text, -1) {
+		if overlaps(loc[0], loc[1]) {
+			continue
+		}
+		low, ok := ParseNumberPTBR(text[loc[4]:loc[5]])
+		if !ok {
+			continue
+		}
+		op := text[loc[2]:loc[3]]
+		inclusive := op != ">" && op != "superior a" && op != "maior que" && op != "acima de"
+		matches = append(matches, rangeMatch{loc[0], loc[1], ReferenceRange{Low: &low, LowInclusive: inclusive}})
+	}
+
+	sort.Slice(matches, func(a, b int) bool { return matches[a].start < matches[b].start })
+	return matches
+}
+
+func parseQualitativeReference(text string) ReferenceRange {
+	normalized := foldAccents(text)
+	for _, q := range qualitativeReferences {
+		if strings.HasPrefix(normalized, q) {
+			// Keeps the original spelling (with accents) of the matched words.
+			words := strings.Fields(text)[:len(strings.Fields(q))]
+			v := strings.Join(words, " ")
+			return ReferenceRange{Qualitative: &v}
+		}
+	}
+	return ReferenceRange{}
+}
+
+// ParseStructuredResult fills the numeric, unit, qualitative, reference and
+// interpretation fields from the free-text ResultValue, ResultUnit and ReferenceText.
+// sex selects sex-specific reference ranges; use GenderUnknown when unknown.
+func (i *LabResultItem) ParseStructuredResult(sex demographics.Gender) {
+	if i == nil {
+		return
+	}
+
+	i.NumericValue, i.Comparator, i.QualitativeValue = nil, ComparatorNone, nil
+	i.UCUMUnit = nil
+	i.ReferenceLow, i.ReferenceHigh = nil, nil
+	i.Interpretation = InterpretationUnknown
+
+	if i.ResultValue != nil {
+		i.NumericValue, i.Comparator, i.QualitativeValue = ParseResultValue(*i.ResultValue)
+	}
+	if i.ResultUnit != nil {
+		if code, ok := ParseUCUM(*i.ResultUnit); ok {
+			i.UCUMUnit = &code
+		}
+	}
+
+	var ref ReferenceRange
+	if i.ReferenceText != nil {
+		ref = ParseReferenceRange(*i.ReferenceText, sex)
+		i.ReferenceLow, i.ReferenceHigh = ref.Low, ref.High
+	}
+
+	i.Interpretation = Interpret(i.NumericValue, i.QualitativeValue, ref)
+}
+
+// Interpret classifies a result against its reference range. It never
+// returns LL/HH: critical flags come only from a CriticalRule (FlagCritical).
+func Interpret(value *float64, qualitative *string, ref ReferenceRange) Interpretation {
+	if value != nil {
+		return interpretNumeric(*value, ref)
+	}
+	if qualitative != nil && ref.Qualitative != nil {
+		if foldAccents(strings.ToLower(*qualitative)) == foldAccents(strings.ToLower(*ref.Qualitative)) {
+			return InterpretationNormal
+		}
+		return InterpretationAbnormal
+	}
+	return InterpretationUnknown
+}
+
+func interpretNumeric(v float64, ref ReferenceRange) Interpretation {
+	if ref.Low == nil && ref.High == nil {
+		return InterpretationUnknown
+	}
+
+	if ref.High != nil && (v > *ref.High || (!ref.HighInclusive && v == *ref.High)) {
+		return InterpretationHigh
+	}
+	if ref.Low != nil && (v < *ref.Low || (!ref.LowInclusive && v == *ref.Low)) {
+		return InterpretationLow
+	}
+	return InterpretationNormal
+}
+
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+func foldAccents(s string) string {
+	return accentFolder.Replace(s)
+}
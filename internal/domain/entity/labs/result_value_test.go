@@ -0,0 +1,108 @@
+// internal/domain/entity/labs/result_value_test.go
+package labs
+
+import (
+	"testing"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+)
+
+func TestParseNumberPTBR(t *testing.T) {
+	cases := map[string]float64{
+		"14,2":    14.2,
+		"6.500":   6500,
+		"1.234,5": 1234.5,
+		"0.8":     0.8,
+		"92":      92,
+	}
+	for raw, want := range cases {
+		got, ok := ParseNumberPTBR(raw)
+		if !ok || got != want {
+			t.Errorf("ParseNumberPTBR(%q) = %v, %v; want %v", raw, got, ok, want)
+		}
+	}
+	if _, ok := ParseNumberPTBR("Negativo"); ok {
+		t.Errorf("expected qualitative text to fail")
+	}
+}
+
+func TestParseResultValue(t *testing.T) {
+	v, cmp, q := ParseResultValue("< 0,5")
+	if v == nil || *v != 0.5 || cmp != ComparatorLess || q != nil {
+		t.Fatalf("unexpected parse of comparator value: %v %q %v", v, cmp, q)
+	}
+
+	v, cmp, q = ParseResultValue("Não reagente")
+	if v != nil || cmp != ComparatorNone || q == nil || *q != "Não reagente" {
+		t.Fatalf("unexpected parse of qualitative value: %v %q %v", v, cmp, q)
+	}
+}
+
+func TestParseReferenceRange(t *testing.T) {
+	cases := []struct {
+		text      string
+		sex       demographics.Gender
+		low, high *float64
+	}{
+		{"70 a 99", demographics.GenderUnknown, f(70), f(99)},
+		{"Até 99 mg/dL", demographics.GenderUnknown, nil, f(99)},
+		{"Inferior a 200 mg/dL", demographics.GenderUnknown, nil, f(200)},
+		{"Superior a 40", demographics.GenderUnknown, f(40), nil},
+		{"4.000 a 11.000 /mm³", demographics.GenderUnknown, f(4000), f(11000)},
+		{"Homens: 13,0 a 17,0 Mulheres: 12,0 a 16,0", demographics.GenderFemale, f(12), f(16)},
+		{"Homens: 13,0 a 17,0 Mulheres: 12,0 a 16,0", demographics.GenderMale, f(13), f(17)},
+		{"Desejável: inferior a 100 Limítrofe: 100 a 129 Alto: 160 a 189", demographics.GenderUnknown, nil, f(100)},
+		{"Homens: 13,0 a 17,0 Mulheres: 12,0 a 16,0", demographics.GenderUnknown, nil, nil},
+	}
+
+	for _, tc := range cases {
+		got := ParseReferenceRange(tc.text, tc.sex)
+		if !sameFloat(got.Low, tc.low) || !sameFloat(got.High, tc.high) {
+			t.Errorf("ParseReferenceRange(%q, %s) = [%v, %v]; want [%v, %v]",
+				tc.text, tc.sex, deref(got.Low), deref(got.High), deref(tc.low), deref(tc.high))
+		}
+	}
+}
+
+func TestLabResultItem_ParseStructuredResult_Interpretation(t *testing.T) {
+	cases := []struct {
+		value, ref string
+		want       Interpretation
+	}{
+		{"126", "70 a 99", InterpretationHigh},
+		{"85", "70 a 99", InterpretationNormal},
+		{"60", "70 a 99", InterpretationLow},
+		{"250", "70 a 99", InterpretationHigh},
+		{"30", "70 a 99", InterpretationLow},
+		{"200", "Inferior a 200", InterpretationHigh},
+		{"99", "Até 99", InterpretationNormal},
+		{"Negativo", "Negativo", InterpretationNormal},
+		{"Reagente", "Não reagente", InterpretationAbnormal},
+		{"14", "sem referência", InterpretationUnknown},
+	}
+
+	for _, tc := range cases {
+		value, ref := tc.value, tc.ref
+		item := &LabResultItem{ResultValue: &value, ReferenceText: &ref}
+		item.ParseStructuredResult(demographics.GenderUnknown)
+		if item.Interpretation != tc.want {
+			t.Errorf("value %q ref %q: got %q, want %q", tc.value, tc.ref, item.Interpretation, tc.want)
+		}
+	}
+}
+
+func f(v float64) *float64 { return &v }
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func deref(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,174 @@
+package labs
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewStatus tracks the human review of a report extracted with low
+// confidence. Reports that never needed review have no review status.
+type ReviewStatus string
+
+const (
+	// ReviewPending keeps the report preliminary until a professional
+	// confirms or corrects the low-confidence values.
+	ReviewPending ReviewStatus = "needs_review"
+	// ReviewApproved marks a reviewed report, released as final.
+	ReviewApproved ReviewStatus = "approved"
+)
+
+// LowConfidenceThreshold is the extraction confidence below which a value
+// must be checked by a person before the report is released.
+const LowConfidenceThreshold = 0.8
+
+// FieldConfidence maps a field name (as used in FieldEdit, e.g.
+// "result_value") to the extractor's confidence in [0, 1]. Fields the
+// extractor did not score are absent.
+type FieldConfidence map[string]float64
+
+// LowFields lists, sorted, the fields scored below LowConfidenceThreshold.
+func (c FieldConfidence) LowFields() []string {
+	var fields []string
+	for field, confidence := range c {
+		if confidence < LowConfidenceThreshold {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// LowConfidence reports whether the item or any of its fields was extracted
+// below LowConfidenceThreshold.
+func (i LabResultItem) LowConfidence() bool {
+	if i.Confidence != nil && *i.Confidence < LowConfidenceThreshold {
+		return true
+	}
+	return len(i.FieldConfidence.LowFields()) > 0
+}
+
+// NeedsReview reports whether the item still has to be confirmed.
+func (i LabResultItem) NeedsReview() bool {
+	return !i.Confirmed && i.LowConfidence()
+}
+
+// PendingReviewItems returns the IDs of the items not yet confirmed.
+func (r *LabReport) PendingReviewItems() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, tr := range r.TestResults {
+		for _, item := range tr.Items {
+			if item.NeedsReview() {
+				ids = append(ids, item.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// FlagForReview sends a freshly extracted report to review when any header
+// field or item has low confidence: it stays preliminary until approved.
+// It reports whether the report was flagged.
+func (r *LabReport) FlagForReview() bool {
+	if len(r.FieldConfidence.LowFields()) == 0 && len(r.PendingReviewItems()) == 0 {
+		return false
+	}
+	status := ReviewPending
+	r.ReviewStatus = &status
+	r.Status = StatusPreliminary
+	return true
+}
+
+// InReview reports whether the report is waiting for a professional.
+func (r *LabReport) InReview() bool {
+	return r.ReviewStatus != nil && *r.ReviewStatus == ReviewPending
+}
+
+// Review applies corrections and confirmations made by a professional while
+// the report is in review. Corrected items count as confirmed. With approve,
+// every remaining item is confirmed and the report is released as final.
+// Each confirmation is recorded in the revision as a "confirmed" change.
+func (r *LabReport) Review(
+	edits []FieldEdit,
+	confirm []uuid.UUID,
+	approve bool,
+	reviewedBy uuid.UUID,
+	reason *string,
+	at time.Time,
+) (*Revision, error) {
+	if !r.InReview() {
+		return nil, ErrNotInReview
+	}
+
+	status := StatusPreliminary
+	if approve {
+		status = StatusFinal
+	}
+	changes, err := r.applyEdits(edits, status)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edit := range edits {
+		if edit.Target == EditTargetItem {
+			confirm = append(confirm, edit.TargetID)
+		}
+	}
+	if approve {
+		confirm = append(confirm, r.PendingReviewItems()...)
+	}
+	for _, id := range confirm {
+		item := r.Item(id)
+		if item == nil {
+			return nil, ErrEditTargetNotFound
+		}
+		if item.Confirmed {
+			continue
+		}
+		item.Confirmed = true
+		changes = append(changes, FieldChange{
+			Target:   EditTargetItem,
+			TargetID: id,
+			Field:    "confirmed",
+			OldValue: textPtr("false"),
+			NewValue: textPtr("true"),
+		})
+	}
+
+	if approve {
+		approved := ReviewApproved
+		changes = append(changes, FieldChange{
+			Target:   EditTargetReport,
+			TargetID: r.ID,
+			Field:    "review_status",
+			OldValue: textPtr(string(*r.ReviewStatus)),
+			NewValue: textPtr(string(approved)),
+		})
+		reviewedAt := at.UTC()
+		r.ReviewStatus = &approved
+		r.ReviewedBy = &reviewedBy
+		r.ReviewedAt = &reviewedAt
+	}
+
+	if len(changes) == 0 {
+		return nil, ErrNoChanges
+	}
+	return r.newRevision(changes, reviewedBy, reason, at), nil
+}
+
+func textPtr(s string) *string {
+	return &s
+}
+
+// ReviewQueueEntry summarizes a report waiting for review in a
+// professional's queue.
+type ReviewQueueEntry struct {
+	ReportID     uuid.UUID  `json:"report_id"`
+	PatientID    uuid.UUID  `json:"patient_id"`
+	PatientName  string     `json:"patient_name"`
+	LabName      *string    `json:"lab_name,omitempty"`
+	ReportDate   *time.Time `json:"report_date,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	PendingItems int        `json:"pending_items"`
+}
@@ -0,0 +1,114 @@
+package labs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func reviewFixture() *LabReport {
+	report := amendmentFixture()
+	low, high := 0.42, 0.97
+	report.TestResults[0].Items[0].Confidence = &high
+	report.TestResults[0].Items[0].FieldConfidence = FieldConfidence{"result_value": low, "parameter_name": high}
+	report.TestResults[0].Items = append(report.TestResults[0].Items, LabResultItem{
+		ID: uuid.New(), ParameterName: "Hematócrito", Confidence: &high,
+	})
+	return report
+}
+
+func TestFlagForReview(t *testing.T) {
+	report := reviewFixture()
+	if !report.FlagForReview() {
+		t.Fatal("expected low-confidence report to be flagged")
+	}
+	if !report.InReview() || report.Status != StatusPreliminary {
+		t.Fatalf("expected preliminary report in review, got %s %v", report.Status, report.ReviewStatus)
+	}
+	pending := report.PendingReviewItems()
+	if len(pending) != 1 || pending[0] != report.TestResults[0].Items[0].ID {
+		t.Fatalf("expected only the low-confidence item pending, got %v", pending)
+	}
+
+	confident := amendmentFixture()
+	if confident.FlagForReview() || confident.ReviewStatus != nil || confident.Status != StatusFinal {
+		t.Fatalf("expected unscored report to stay final, got %s %v", confident.Status, confident.ReviewStatus)
+	}
+
+	header := amendmentFixture()
+	header.FieldConfidence = FieldConfidence{"report_date": 0.5}
+	if !header.FlagForReview() {
+		t.Fatal("expected low-confidence header field to flag the report")
+	}
+}
+
+func TestReview_CorrectThenApprove(t *testing.T) {
+	report := reviewFixture()
+	report.FlagForReview()
+	item := report.TestResults[0].Items[0]
+	by := uuid.New()
+
+	if _, err := report.Amend(nil, StatusFinal, by, nil, time.Now()); !errors.Is(err, ErrInReview) {
+		t.Fatalf("expected amend to be blocked while in review, got %v", err)
+	}
+
+	fixed := "13,5"
+	rev, err := report.Review([]FieldEdit{
+		{Target: EditTargetItem, TargetID: item.ID, Field: "result_value", Value: &fixed},
+	}, nil, false, by, nil, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// result_value and confirmed.
+	if len(rev.Changes) != 2 || rev.Changes[1].Field != "confirmed" {
+		t.Fatalf("expected correction and confirmation, got %+v", rev.Changes)
+	}
+	if !report.Item(item.ID).Confirmed || !report.InReview() || report.Status != StatusPreliminary {
+		t.Fatalf("expected item confirmed and report still in review")
+	}
+
+	rev, err = report.Review(nil, nil, true, by, nil, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Status != StatusFinal || *report.ReviewStatus != ReviewApproved || report.ReviewedBy == nil || *report.ReviewedBy != by {
+		t.Fatalf("expected approved final report, got %s %v", report.Status, report.ReviewStatus)
+	}
+	if report.Version != 3 || rev.Status != StatusFinal {
+		t.Fatalf("expected version 3 final, got v%d %s", report.Version, rev.Status)
+	}
+
+	if _, err := report.Review(nil, nil, true, by, nil, time.Now()); !errors.Is(err, ErrNotInReview) {
+		t.Fatalf("expected approved report to leave review, got %v", err)
+	}
+}
+
+func TestReview_ApproveConfirmsPendingItems(t *testing.T) {
+	report := reviewFixture()
+	report.FlagForReview()
+	item := report.TestResults[0].Items[0]
+
+	rev, err := report.Review(nil, nil, true, uuid.New(), nil, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Item(item.ID).Confirmed || len(report.PendingReviewItems()) != 0 {
+		t.Fatal("expected approval to confirm pending items")
+	}
+	// status, confirmed and review_status.
+	if len(rev.Changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", rev.Changes)
+	}
+
+	if _, err := reviewFixture().Review(nil, []uuid.UUID{uuid.New()}, false, uuid.New(), nil, time.Now()); !errors.Is(err, ErrNotInReview) {
+		t.Fatalf("expected unflagged report to be rejected, got %v", err)
+	}
+
+	flagged := reviewFixture()
+	flagged.FlagForReview()
+	if _, err := flagged.Review(nil, []uuid.UUID{uuid.New()}, false, uuid.New(), nil, time.Now()); !errors.Is(err, ErrEditTargetNotFound) {
+		t.Fatalf("expected unknown item to be rejected, got %v", err)
+	}
+}
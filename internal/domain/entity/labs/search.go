@@ -0,0 +1,22 @@
+// internal/domain/entity/labs/search.go
+package labs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchHit is a report whose raw text matched a full-text query. Rank is
+// relative to the other hits of the same query; Snippet holds the matching
+// fragments, HTML-escaped, with the terms wrapped in <mark></mark>.
+type SearchHit struct {
+	ReportID   uuid.UUID    `json:"report_id"`
+	LabName    *string      `json:"lab_name,omitempty"`
+	ReportDate *time.Time   `json:"report_date,omitempty"`
+	Source     ReportSource `json:"source"`
+	Status     ReportStatus `json:"status"`
+	CreatedAt  time.Time    `json:"created_at"`
+	Rank       float64      `json:"rank"`
+	Snippet    string       `json:"snippet"`
+}
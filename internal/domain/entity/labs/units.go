@@ -0,0 +1,252 @@
+// internal/domain/entity/labs/units.go
+package labs
+
+import (
+	"strings"
+)
+
+// UCUMSystem is the FHIR system URI for UCUM unit codes.
+const UCUMSystem = "http://unitsofmeasure.org"
+
+type unitKind string
+
+const (
+	kindMass      unitKind = "mass"      // base g/L
+	kindMolar     unitKind = "molar"     // base mol/L
+	kindEquiv     unitKind = "equiv"     // base eq/L
+	kindCount     unitKind = "count"     // base /L
+	kindCatalytic unitKind = "catalytic" // base U/L
+	kindIU        unitKind = "iu"        // base [IU]/L
+	kindFraction  unitKind = "fraction"  // base 1
+	kindOther     unitKind = "other"     // only identity conversions
+)
+
+type unitDef struct {
+	kind   unitKind
+	factor float64
+}
+
+// ucumUnits lists the UCUM codes understood by the converter.
+var ucumUnits = map[string]unitDef{
+	"g/L":   {kindMass, 1},
+	"g/dL":  {kindMass, 10},
+	"mg/dL": {kindMass, 1e-2},
+	"mg/L":  {kindMass, 1e-3},
+	"ug/mL": {kindMass, 1e-3},
+	"ug/dL": {kindMass, 1e-5},
+	"ug/L":  {kindMass, 1e-6},
+	"ng/mL": {kindMass, 1e-6},
+	"ng/dL": {kindMass, 1e-8},
+	"pg/mL": {kindMass, 1e-9},
+
+	"mmol/L": {kindMolar, 1e-3},
+	"umol/L": {kindMolar, 1e-6},
+	"nmol/L": {kindMolar, 1e-9},
+	"pmol/L": {kindMolar, 1e-12},
+
+	"meq/L": {kindEquiv, 1e-3},
+
+	"10*12/L": {kindCount, 1e12},
+	"10*9/L":  {kindCount, 1e9},
+	"10*6/uL": {kindCount, 1e12},
+	"10*3/uL": {kindCount, 1e9},
+	"/uL":     {kindCount, 1e6},
+
+	"U/L": {kindCatalytic, 1},
+
+	"m[IU]/L":  {kindIU, 1e-3},
+	"u[IU]/mL": {kindIU, 1e-3},
+
+	"%":        {kindFraction, 1e-2},
+	"mmol/mol": {kindOther, 1},
+
+	"fL":               {kindOther, 1},
+	"pg":               {kindOther, 1},
+	"mL/min/{1.73_m2}": {kindOther, 1},
+}
+
+// unitAliases maps spellings found in Brazilian reports (after lower-casing,
+// removing spaces and replacing µ/³) to UCUM codes.
+var unitAliases = map[string]string{
+	"g/l": "g/L", "g/dl": "g/dL", "mg/dl": "mg/dL", "mg/l": "mg/L",
+	"ug/ml": "ug/mL", "mcg/ml": "ug/mL",
+	"ug/dl": "ug/dL", "mcg/dl": "ug/dL",
+	"ug/l": "ug/L", "mcg/l": "ug/L",
+	"ng/ml": "ng/mL", "ng/dl": "ng/dL", "pg/ml": "pg/mL",
+
+	"mmol/l": "mmol/L", "umol/l": "umol/L", "nmol/l": "nmol/L", "pmol/l": "pmol/L",
+	"meq/l":    "meq/L",
+	"mmol/mol": "mmol/mol",
+
+	"/mm3": "/uL", "/ul": "/uL", "cels/mm3": "/uL", "celulas/mm3": "/uL",
+	"mil/mm3": "10*3/uL", "mil/ul": "10*3/uL", "x10^3/ul": "10*3/uL", "10^3/ul": "10*3/uL",
+	"10*3/ul": "10*3/uL", "x103/ul": "10*3/uL", "10^3/mm3": "10*3/uL", "x10^3/mm3": "10*3/uL",
+	"milhoes/mm3": "10*6/uL", "milhoes/ul": "10*6/uL", "x10^6/ul": "10*6/uL", "10^6/ul": "10*6/uL",
+	"10*6/ul": "10*6/uL", "x106/ul": "10*6/uL", "10^6/mm3": "10*6/uL", "x10^6/mm3": "10*6/uL",
+	"10^9/l": "10*9/L", "x10^9/l": "10*9/L", "10*9/l": "10*9/L",
+	"10^12/l": "10*12/L", "x10^12/l": "10*12/L", "10*12/l": "10*12/L",
+
+	"u/l": "U/L", "ui/l": "U/L", "iu/l": "U/L",
+	"mui/l": "m[IU]/L", "miu/l": "m[IU]/L", "m[iu]/l": "m[IU]/L",
+	"uui/ml": "u[IU]/mL", "uiu/ml": "u[IU]/mL", "u[iu]/ml": "u[IU]/mL",
+
+	"%":  "%",
+	"fl": "fL", "u3": "fL",
+	"pg": "pg",
+
+	"ml/min/1.73m2": "mL/min/{1.73_m2}", "ml/min/1,73m2": "mL/min/{1.73_m2}",
+	"ml/min/1.73": "mL/min/{1.73_m2}", "ml/min/1,73": "mL/min/{1.73_m2}",
+}
+
+var unitCleaner = strings.NewReplacer(
+	" ", "", "µ", "u", "μ", "u", "³", "3", "²", "2", "⁶", "6", "⁹", "9", "¹²", "12", "ˆ", "^",
+)
+
+// ParseUCUM maps a unit as printed by the lab to its UCUM code.
+func ParseUCUM(raw string) (string, bool) {
+	key := foldAccents(strings.ToLower(unitCleaner.Replace(strings.TrimSpace(raw))))
+	if key == "" {
+		return "", false
+	}
+	if code, ok := unitAliases[key]; ok {
+		return code, true
+	}
+	// Already a UCUM code ("mg/dL", "10*3/uL").
+	for code := range ucumUnits {
+		if strings.EqualFold(code, key) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// analyteChemistry holds what is needed to convert between mass, molar and
+// equivalent concentrations of an analyte.
+type analyteChemistry struct {
+	molarMass float64 // g/mol
+	valence   float64 // for meq/L; 0 when not applicable
+}
+
+// Keys are analyte catalog codes.
+var analyteChemistries = map[string]analyteChemistry{
+	"glucose":             {molarMass: 180.156},
+	"creatinine":          {molarMass: 113.12},
+	"urea":                {molarMass: 60.06},
+	"uric-acid":           {molarMass: 168.11},
+	"cholesterol-total":   {molarMass: 386.65},
+	"cholesterol-hdl":     {molarMass: 386.65},
+	"cholesterol-ldl":     {molarMass: 386.65},
+	"cholesterol-vldl":    {molarMass: 386.65},
+	"cholesterol-non-hdl": {molarMass: 386.65},
+	"triglycerides":       {molarMass: 885.7},
+	"bilirubin-total":     {molarMass: 584.66},
+	"calcium":             {molarMass: 40.078, valence: 2},
+	"sodium":              {molarMass: 22.99, valence: 1},
+	"potassium":           {molarMass: 39.098, valence: 1},
+	"hemoglobin":          {molarMass: 16114.5}, // monomer, as used for mmol/L reporting
+	"free-t4":             {molarMass: 776.87},
+	"vitamin-d-25oh":      {molarMass: 400.64},
+	"vitamin-b12":         {molarMass: 1355.37},
+}
+
+// Insulin: 1 µIU/mL = 6 pmol/L.
+const insulinPmolPerMicroIU = 6.0
+
+// ConvertUnit converts value between two UCUM units for the given analyte.
+// Mass <-> molar conversions use the analyte's molar mass; it returns false
+// when the conversion is not known.
+func ConvertUnit(analyteCode string, value float64, from, to string) (float64, bool) {
+	if from == to {
+		return value, true
+	}
+
+	if analyteCode == "hba1c" {
+		return convertHbA1c(value, from, to)
+	}
+
+	src, okFrom := ucumUnits[from]
+	dst, okTo := ucumUnits[to]
+	if !okFrom || !okTo {
+		return 0, false
+	}
+
+	if analyteCode == "insulin" {
+		return convertInsulin(value, src, dst)
+	}
+
+	if src.kind == dst.kind && src.kind != kindOther {
+		return value * src.factor / dst.factor, true
+	}
+
+	chem, ok := analyteChemistries[analyteCode]
+	if !ok {
+		return 0, false
+	}
+
+	molPerL, ok := toMolar(value, src, chem)
+	if !ok {
+		return 0, false
+	}
+	return fromMolar(molPerL, dst, chem)
+}
+
+func toMolar(value float64, u unitDef, chem analyteChemistry) (float64, bool) {
+	base := value * u.factor
+	switch u.kind {
+	case kindMolar:
+		return base, true
+	case kindMass:
+		return base / chem.molarMass, true
+	case kindEquiv:
+		if chem.valence == 0 {
+			return 0, false
+		}
+		return base / chem.valence, true
+	default:
+		return 0, false
+	}
+}
+
+func fromMolar(molPerL float64, u unitDef, chem analyteChemistry) (float64, bool) {
+	switch u.kind {
+	case kindMolar:
+		return molPerL / u.factor, true
+	case kindMass:
+		return molPerL * chem.molarMass / u.factor, true
+	case kindEquiv:
+		if chem.valence == 0 {
+			return 0, false
+		}
+		return molPerL * chem.valence / u.factor, true
+	default:
+		return 0, false
+	}
+}
+
+// convertHbA1c uses the NGSP/IFCC master equation: IFCC = (NGSP - 2.15) * 10.929.
+func convertHbA1c(value float64, from, to string) (float64, bool) {
+	switch {
+	case from == "%" && to == "mmol/mol":
+		return (value - 2.15) * 10.929, true
+	case from == "mmol/mol" && to == "%":
+		return value/10.929 + 2.15, true
+	default:
+		return 0, false
+	}
+}
+
+func convertInsulin(value float64, src, dst unitDef) (float64, bool) {
+	switch {
+	case src.kind == dst.kind:
+		return value * src.factor / dst.factor, true
+	case src.kind == kindIU && dst.kind == kindMolar:
+		// [IU]/L -> µIU/mL -> pmol/L -> mol/L
+		pmol := value * src.factor / 1e-3 * insulinPmolPerMicroIU
+		return pmol * 1e-12 / dst.factor, true
+	case src.kind == kindMolar && dst.kind == kindIU:
+		microIU := value * src.factor / 1e-12 / insulinPmolPerMicroIU
+		return microIU * 1e-3 / dst.factor, true
+	default:
+		return 0, false
+	}
+}
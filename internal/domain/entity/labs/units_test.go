@@ -0,0 +1,67 @@
+// internal/domain/entity/labs/units_test.go
+package labs
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseUCUM(t *testing.T) {
+	cases := map[string]string{
+		"mg/dL":         "mg/dL",
+		"MG/DL":         "mg/dL",
+		"µmol/L":        "umol/L",
+		"/mm³":          "/uL",
+		"mil/mm³":       "10*3/uL",
+		"milhões/mm³":   "10*6/uL",
+		"x10^3/µL":      "10*3/uL",
+		"µUI/mL":        "u[IU]/mL",
+		"mUI/L":         "m[IU]/L",
+		"U/L":           "U/L",
+		"fL":            "fL",
+		"mL/min/1,73m²": "mL/min/{1.73_m2}",
+	}
+	for raw, want := range cases {
+		got, ok := ParseUCUM(raw)
+		if !ok || got != want {
+			t.Errorf("ParseUCUM(%q) = %q, %v; want %q", raw, got, ok, want)
+		}
+	}
+	if _, ok := ParseUCUM("unidades arbitrárias"); ok {
+		t.Errorf("expected unknown unit to fail")
+	}
+}
+
+func TestConvertUnit(t *testing.T) {
+	cases := []struct {
+		analyte  string
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{"glucose", 5.5, "mmol/L", "mg/dL", 99.09},
+		{"creatinine", 88.4, "umol/L", "mg/dL", 1.0},
+		{"cholesterol-total", 5.17, "mmol/L", "mg/dL", 199.9},
+		{"triglycerides", 150, "mg/dL", "mmol/L", 1.69},
+		{"hemoglobin", 140, "g/L", "g/dL", 14},
+		{"leukocytes", 6500, "/uL", "10*3/uL", 6.5},
+		{"sodium", 140, "meq/L", "mmol/L", 140},
+		{"calcium", 2.5, "mmol/L", "mg/dL", 10.02},
+		{"hba1c", 6.5, "%", "mmol/mol", 47.5},
+		{"insulin", 10, "u[IU]/mL", "pmol/L", 60},
+	}
+	for _, tc := range cases {
+		got, ok := ConvertUnit(tc.analyte, tc.value, tc.from, tc.to)
+		if !ok || math.Abs(got-tc.want) > 0.05 {
+			t.Errorf("%s %v %s -> %s = %v, %v; want %v", tc.analyte, tc.value, tc.from, tc.to, got, ok, tc.want)
+		}
+	}
+
+	// Sem massa molar não há conversão entre massa e mol.
+	if _, ok := ConvertUnit("ferritin", 100, "ng/mL", "pmol/L"); ok {
+		t.Errorf("expected conversion without molar mass to fail")
+	}
+	if _, ok := ConvertUnit("glucose", 100, "mg/dL", "U/L"); ok {
+		t.Errorf("expected conversion between unrelated kinds to fail")
+	}
+}
@@ -17,6 +17,20 @@ const (
 	// Exames laboratiriais do paciente
 	ActionReadLabs   Action = "labs:read"
 	ActionUploadLabs Action = "labs:upload"
+	ActionDeleteLabs Action = "labs:delete"
+	ActionAmendLabs  Action = "labs:amend"
+	// Aceitar laudo cuja identificação não confere com o paciente
+	ActionOverrideLabIdentity Action = "labs:identity_override"
+	// Revisar laudos extraídos com baixa confiança
+	ActionReviewLabs Action = "labs:review"
+	// Fila de revisão (não é escopada por paciente; a lista já filtra o acesso)
+	ActionListLabReviews Action = "labs:review_queue"
+	// Caixa de alertas de valores críticos do próprio usuário
+	ActionReadLabAlerts Action = "labs:alerts"
+	// Catálogo de analitos: global, só administradores
+	ActionManageLabCatalog Action = "labs:catalog_manage"
+	// Regras de valores críticos: globais, só administradores
+	ActionManageLabCriticalRules Action = "labs:critical_rules_manage"
 	//Prescrições médicas do paciente
 	ActionReadPrescriptions  Action = "prescriptions:read"
 	ActionWritePrescriptions Action = "prescriptions:write"
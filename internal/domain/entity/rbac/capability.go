@@ -8,12 +8,12 @@ type CapabilityLevel string
 const (
 	CapabilityClinical  CapabilityLevel = "clinical"   // Médicos, enfermeiros e outros profissionais de saúde
 	CapabilityBasicCare CapabilityLevel = "basic_care" // Pacientes e cuidadores
-	//CapabilityAdmin     CapabilityLevel = "admin"      // Administradores do sistema
+	CapabilityAdmin     CapabilityLevel = "admin"      // Administradores do sistema
 )
 
 func (cl CapabilityLevel) IsValid() bool {
 	switch cl {
-	case CapabilityClinical, CapabilityBasicCare:
+	case CapabilityClinical, CapabilityBasicCare, CapabilityAdmin:
 		return true
 	default:
 		return false
@@ -26,6 +26,8 @@ func CapabilityForAccountType(at user.AccountType) CapabilityLevel {
 		return CapabilityClinical
 	case user.AccountTypeBasicCare:
 		return CapabilityBasicCare
+	case user.AccountTypeAdmin:
+		return CapabilityAdmin
 	default:
 		return ""
 	}
@@ -21,6 +21,7 @@ func (ps *RbacPolicy) CanPerform(subject Subject, action Action) bool {
 
 	isProfessional := level == CapabilityClinical
 	isBasicCare := level == CapabilityBasicCare
+	isAdmin := level == CapabilityAdmin
 
 	switch action {
 	// Patient
@@ -44,6 +45,18 @@ func (ps *RbacPolicy) CanPerform(subject Subject, action Action) bool {
 		return isProfessional || isBasicCare
 	case ActionUploadLabs:
 		return isProfessional || isBasicCare
+	case ActionDeleteLabs:
+		return isProfessional || isBasicCare
+	case ActionAmendLabs:
+		return isProfessional || isBasicCare
+	case ActionOverrideLabIdentity:
+		return isProfessional
+	case ActionReviewLabs, ActionListLabReviews:
+		return isProfessional
+	case ActionReadLabAlerts:
+		return isProfessional
+	case ActionManageLabCatalog, ActionManageLabCriticalRules:
+		return isAdmin
 
 	// Prescriptions
 	case ActionReadPrescriptions:
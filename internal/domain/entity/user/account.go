@@ -9,7 +9,9 @@ type AccountType string
 const (
 	AccountTypeProfessional AccountType = "professional"
 	AccountTypeBasicCare    AccountType = "basic_care"
-	//AccountTypeAdmin        AccountType = "admin" // fora do MVP
+	// Administradores da plataforma: não se cadastram pela API, a conta é
+	// promovida direto no banco.
+	AccountTypeAdmin AccountType = "admin"
 )
 
 func (at AccountType) Normalize() AccountType {
@@ -18,7 +20,7 @@ func (at AccountType) Normalize() AccountType {
 
 func (at AccountType) IsValid() bool {
 	switch at {
-	case AccountTypeProfessional, AccountTypeBasicCare:
+	case AccountTypeProfessional, AccountTypeBasicCare, AccountTypeAdmin:
 		return true
 	default:
 		return false
@@ -0,0 +1,20 @@
+// internal/domain/notification/notifier.go
+package notification
+
+import (
+	"context"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+
+	"github.com/google/uuid"
+)
+
+// CriticalValueNotifier avisa um profissional sobre um valor crítico.
+// O alerta também fica disponível na caixa de entrada (GET /v1/labs/alerts),
+// então uma falha de envio não perde o aviso.
+type CriticalValueNotifier interface {
+	// NotifyCriticalValue devolve delivered=true só quando o aviso saiu por um
+	// canal que chega ao profissional (push, e-mail...); só então o alerta é
+	// marcado como notificado.
+	NotifyCriticalValue(ctx context.Context, userID uuid.UUID, alert labs.CriticalAlert) (delivered bool, err error)
+}
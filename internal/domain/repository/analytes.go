@@ -0,0 +1,22 @@
+// internal/domain/repository/analytes.go
+package repository
+
+import (
+	"context"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+)
+
+// Analytes persiste o catálogo de analitos (LOINC) e seus sinônimos.
+type Analytes interface {
+	// List retorna todos os analitos com seus sinônimos.
+	List(ctx context.Context) ([]labs.Analyte, error)
+	FindByCode(ctx context.Context, code string) (*labs.Analyte, error)
+	// FindSynonymOwner retorna o código do analito que já usa o nome normalizado, ou "".
+	FindSynonymOwner(ctx context.Context, nameKey string) (string, error)
+
+	// AddSynonym e RemoveSynonym aplicam relinks ao histórico na mesma
+	// transação da mudança do sinônimo.
+	AddSynonym(ctx context.Context, analyteCode, name string, relinks []labs.AnalyteRelink) error
+	RemoveSynonym(ctx context.Context, analyteCode, name string, relinks []labs.AnalyteRelink) (bool, error)
+}
@@ -0,0 +1,38 @@
+// internal/domain/repository/critical_alerts.go
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+
+	"github.com/google/uuid"
+)
+
+// CriticalAlerts persiste as regras de valores críticos e os alertas gerados.
+type CriticalAlerts interface {
+	// Regras
+	// ListRules traz só as regras em vigor (não excluídas).
+	ListRules(ctx context.Context) ([]labs.CriticalRule, error)
+	// CreateRule retorna labs.ErrCriticalRuleExists se já houver regra para o
+	// mesmo analito, sexo e faixa etária.
+	CreateRule(ctx context.Context, rule *labs.CriticalRule) error
+	// DeleteRule exclui a regra logicamente, registrando quem e quando;
+	// retorna false se ela não existe ou já foi excluída.
+	DeleteRule(ctx context.Context, id, deletedBy uuid.UUID, at time.Time) (bool, error)
+
+	// Alertas
+	// FlagItems grava a interpretação (LL/HH) de cada alerta no item do laudo.
+	FlagItems(ctx context.Context, alerts []labs.CriticalAlert) error
+	// ListAlertedItemIDs devolve os itens do laudo que já geraram alerta.
+	ListAlertedItemIDs(ctx context.Context, reportID uuid.UUID) ([]uuid.UUID, error)
+	// CreateAlerts grava os alertas e seus destinatários na mesma transação.
+	CreateAlerts(ctx context.Context, alerts []labs.CriticalAlert) error
+	MarkNotified(ctx context.Context, alertID, userID uuid.UUID, at time.Time) error
+	// ListByRecipient traz só o destinatário pedido em Recipients; onlyOpen
+	// esconde os alertas já confirmados por ele.
+	ListByRecipient(ctx context.Context, userID uuid.UUID, onlyOpen bool, limit, offset int) ([]labs.CriticalAlert, error)
+	// Acknowledge retorna false se o usuário não é destinatário do alerta.
+	Acknowledge(ctx context.Context, alertID, userID uuid.UUID, at time.Time) (bool, error)
+}
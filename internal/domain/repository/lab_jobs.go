@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+
+	"github.com/google/uuid"
+)
+
+// LabJobs persiste a fila de processamento assíncrono de laudos.
+type LabJobs interface {
+	Create(ctx context.Context, job *labs.ProcessingJob) error
+	FindByID(ctx context.Context, jobID uuid.UUID) (*labs.ProcessingJob, error)
+	// FindActiveIDByContentHash devolve o job queued/running do paciente com o
+	// mesmo arquivo (SHA-256), ou nil.
+	FindActiveIDByContentHash(ctx context.Context, patientID uuid.UUID, contentHash string) (*uuid.UUID, error)
+
+	// Fila
+	// ClaimNext marca o job mais antigo como running; retorna nil quando a fila está vazia.
+	ClaimNext(ctx context.Context) (*labs.ProcessingJob, error)
+	// MarkSucceeded e MarkFailed só gravam se o job ainda está running com o
+	// mesmo attempts do claim; false indica que o job foi devolvido à fila e
+	// pertence a outra execução.
+	MarkSucceeded(ctx context.Context, jobID uuid.UUID, attempt int, reportID uuid.UUID) (bool, error)
+	MarkFailed(ctx context.Context, jobID uuid.UUID, attempt int, errorCode, errorMessage string) (bool, error)
+	// RequeueStale devolve à fila os jobs em running desde antes de
+	// startedBefore com menos de maxAttempts tentativas.
+	RequeueStale(ctx context.Context, startedBefore time.Time, maxAttempts int) (int64, error)
+	// FailStale encerra como failed os jobs presos que já usaram maxAttempts.
+	FailStale(ctx context.Context, startedBefore time.Time, maxAttempts int, errorCode, errorMessage string) (int64, error)
+}
@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
 
@@ -11,17 +12,56 @@ import (
 
 type Labs interface {
 	// CRUD basico
+	// Create retorna labs.ErrDuplicateDocument se o paciente já tiver um
+	// laudo com o mesmo arquivo.
 	Create(ctx context.Context, report *labs.LabReport) error
 	FindByID(ctx context.Context, reportID uuid.UUID) (*labs.LabReport, error)
 	ExistsBySignature(ctx context.Context, patientID uuid.UUID, fingerprint string) (bool, error)
+	// FindIDByContentHash devolve o laudo do paciente com o mesmo arquivo (SHA-256), ou nil.
+	FindIDByContentHash(ctx context.Context, patientID uuid.UUID, contentHash string) (*uuid.UUID, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	// ReplaceDerived troca os itens derivados do laudo pelos de result (nil
+	// só apaga os atuais), numa transação.
+	ReplaceDerived(ctx context.Context, reportID uuid.UUID, result *labs.LabResult) error
+
+	// Correções
+	// Amend grava o laudo corrigido e a revisão; retorna labs.ErrVersionConflict
+	// se outra correção tiver sido gravada antes.
+	Amend(ctx context.Context, report *labs.LabReport, revision *labs.Revision) error
+	ListRevisions(ctx context.Context, reportID uuid.UUID) ([]labs.Revision, error)
+
+	// Revisão
+	// ListPendingReviews lista os laudos em revisão dos pacientes que o
+	// usuário possui ou acessa, dos mais antigos para os mais novos.
+	ListPendingReviews(ctx context.Context, userID uuid.UUID, limit, offset int) ([]labs.ReviewQueueEntry, error)
 
 	// Listas
 	ListLabs(ctx context.Context, patientID uuid.UUID, limit, offset int) ([]labs.LabReport, error)
+	// SearchRawText faz a busca textual (português, sem acento) no texto bruto
+	// dos laudos do paciente, do mais relevante para o menos relevante.
+	SearchRawText(ctx context.Context, patientID uuid.UUID, query string, limit, offset int) ([]labs.SearchHit, error)
+	// ListItemsByPatientAndParameter filtra pela coleta (ou data do laudo);
+	// from e to nil não limitam o período.
 	ListItemsByPatientAndParameter(
 		ctx context.Context,
 		patientID uuid.UUID,
 		parameterName string,
+		from, to *time.Time,
 		limit, offset int,
 	) ([]labs.LabResultItemTimeline, error)
+	// ListItemsByPatient lista todos os itens do paciente no período, do mais
+	// recente para o mais antigo.
+	ListItemsByPatient(ctx context.Context, patientID uuid.UUID, from, to *time.Time, limit, offset int) ([]labs.LabResultItemTimeline, error)
+	// ListHistory lista os itens do paciente com os dados do laudo e do
+	// resultado, da coleta mais antiga para a mais recente.
+	ListHistory(ctx context.Context, patientID uuid.UUID, limit, offset int) ([]labs.HistoryRow, error)
+
+	// Catálogo de analitos
+	ListDistinctParameterNames(ctx context.Context) ([]string, error)
+	// ListParameterNamesByKey lista os nomes de parâmetro em que
+	// labs.MatchKeys inclui nameKey (os afetados por um sinônimo).
+	ListParameterNamesByKey(ctx context.Context, nameKey string) ([]string, error)
+	// Relink revincula o histórico numa única transação; AnalyteCode nil
+	// desfaz o vínculo. Retorna quantos itens mudaram.
+	Relink(ctx context.Context, relinks []labs.AnalyteRelink) (int64, error)
 }
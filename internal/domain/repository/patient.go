@@ -20,6 +20,7 @@ type Patient interface {
 
 	// Finders
 	FindByCPF(ctx context.Context, cpf string) (*patient.Patient, error)
+	FindByCNS(ctx context.Context, cns string) (*patient.Patient, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*patient.Patient, error)
 	FindByName(ctx context.Context, name string) ([]patient.Patient, error)
 	// Listagem
@@ -28,4 +28,7 @@ type PatientAccessRepo interface {
 
 	// Verifica se o usuário tem acesso ativo ao paciente
 	HasActiveAccess(ctx context.Context, patientID, granteeID uuid.UUID) (bool, error)
+
+	// Lista os usuários com acesso ativo ao paciente numa relação (ex.: professional)
+	ListActiveGrantees(ctx context.Context, patientID uuid.UUID, relation patientaccess.RelationshipType) ([]uuid.UUID, error)
 }
@@ -12,3 +12,9 @@ type FileStorageService interface {
 	Delete(ctx context.Context, uri string) error
 	GetSignedURL(ctx context.Context, uri string, expirationMinutes int) (string, error)
 }
+
+// FileReader lê o conteúdo de um arquivo a partir da URI devolvida por Upload.
+// Usado por integrações que não acessam o storage diretamente (ex.: Document AI com s3://).
+type FileReader interface {
+	Open(ctx context.Context, uri string) (io.ReadCloser, error)
+}
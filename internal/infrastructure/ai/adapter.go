@@ -4,8 +4,13 @@ package ai
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 
 	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
+	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
+
+	"cloud.google.com/go/documentai/apiv1/documentaipb"
 )
 
 // LabReportExtractor já está definido em labs/ai.go
@@ -18,16 +23,19 @@ import (
 type DocumentAIAdapter struct {
 	client      Client
 	processorID string
+	// files lê documentos que não estão no GCS (file://, s3://) para envio inline.
+	files domainstorage.FileReader
 }
 
 // Garante que implementa a interface
 var _ domainai.DocumentExtractorService = (*DocumentAIAdapter)(nil)
 
 // NewDocumentAIAdapter é o construtor que você vai usar no module.go.
-func NewDocumentAIAdapter(client Client, processorID string) *DocumentAIAdapter {
+func NewDocumentAIAdapter(client Client, processorID string, files domainstorage.FileReader) *DocumentAIAdapter {
 	return &DocumentAIAdapter{
 		client:      client,
 		processorID: processorID,
+		files:       files,
 	}
 }
 
@@ -36,7 +44,7 @@ func (a *DocumentAIAdapter) ExtractLabReport(
 	documentURI, mimeType string,
 ) (*domainai.ExtractedLabReport, error) {
 	// 1. Processa documento via Google Document AI
-	doc, err := a.client.ProcessDocument(ctx, a.processorID, documentURI, mimeType)
+	doc, err := a.processDocument(ctx, documentURI, mimeType)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao processar documento: %w", err)
 	}
@@ -57,6 +65,27 @@ func (a *DocumentAIAdapter) ExtractLabReport(
 	return extracted, nil
 }
 
+// processDocument usa a leitura direta do Document AI para gs:// e envia o
+// conteúdo inline para os demais storages.
+func (a *DocumentAIAdapter) processDocument(ctx context.Context, documentURI, mimeType string) (*documentaipb.Document, error) {
+	if strings.HasPrefix(documentURI, "gs://") || a.files == nil {
+		return a.client.ProcessDocument(ctx, a.processorID, documentURI, mimeType)
+	}
+
+	rc, err := a.files.Open(ctx, documentURI)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler documento: %w", err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler documento: %w", err)
+	}
+
+	return a.client.ProcessRawDocument(ctx, a.processorID, content, mimeType)
+}
+
 func (a *DocumentAIAdapter) validateExtracted(extracted *domainai.ExtractedLabReport) error {
 	// Você pode adicionar validações aqui se necessário
 	// Por exemplo: garantir que pelo menos um teste foi extraído
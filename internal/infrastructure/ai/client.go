@@ -13,7 +13,8 @@ import (
 
 // Client é o contrato genérico de acesso ao Document AI.
 // Ele não conhece nenhum domínio (labs, examImage, etc.):
-// recebe um processorID e uma URI do GCS e devolve o Document cru.
+// recebe um processorID e uma URI do GCS (ou o conteúdo inline)
+// e devolve o Document cru.
 type Client struct {
 	client    *documentai.DocumentProcessorClient
 	projectID string
@@ -52,10 +53,8 @@ func (c *Client) ProcessDocument(
 	ctx context.Context,
 	processorID, gcsURI, mimeType string,
 ) (*documentaipb.Document, error) {
-	name := fmt.Sprintf("projects/%s/locations/%s/processors/%s", c.projectID, c.location, processorID)
-
 	req := &documentaipb.ProcessRequest{
-		Name: name,
+		Name: c.processorName(processorID),
 		Source: &documentaipb.ProcessRequest_GcsDocument{
 			GcsDocument: &documentaipb.GcsDocument{
 				GcsUri:   gcsURI,
@@ -64,6 +63,35 @@ func (c *Client) ProcessDocument(
 		},
 	}
 
+	return c.process(ctx, req)
+}
+
+// ProcessRawDocument envia o conteúdo inline; usado quando o arquivo não está
+// no GCS (storage local ou S3), já que o Document AI só lê gs:// diretamente.
+func (c *Client) ProcessRawDocument(
+	ctx context.Context,
+	processorID string,
+	content []byte,
+	mimeType string,
+) (*documentaipb.Document, error) {
+	req := &documentaipb.ProcessRequest{
+		Name: c.processorName(processorID),
+		Source: &documentaipb.ProcessRequest_RawDocument{
+			RawDocument: &documentaipb.RawDocument{
+				Content:  content,
+				MimeType: mimeType,
+			},
+		},
+	}
+
+	return c.process(ctx, req)
+}
+
+func (c *Client) processorName(processorID string) string {
+	return fmt.Sprintf("projects/%s/locations/%s/processors/%s", c.projectID, c.location, processorID)
+}
+
+func (c *Client) process(ctx context.Context, req *documentaipb.ProcessRequest) (*documentaipb.Document, error) {
 	resp, err := c.client.ProcessDocument(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("falha no processamento do DocAI: %w", err)
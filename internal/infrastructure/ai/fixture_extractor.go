@@ -0,0 +1,156 @@
+// internal/infrastructure/ai/fixture_extractor.go
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
+	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
+	applog "github.com/gabrielgcmr/sonnda/internal/kernel/observability"
+
+	"cloud.google.com/go/documentai/apiv1/documentaipb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type FixtureMode string
+
+const (
+	// FixtureModeReplay só lê fixtures; não acessa o Google Cloud.
+	FixtureModeReplay FixtureMode = "replay"
+	// FixtureModeRecord chama o Document AI e grava a resposta como fixture.
+	FixtureModeRecord FixtureMode = "record"
+)
+
+// ErrFixtureNotFound indica que não há resposta gravada para o documento.
+var ErrFixtureNotFound = errors.New("fixture do Document AI não encontrada")
+
+// FixtureExtractor reproduz respostas gravadas do Document AI (documentaipb.Document
+// em JSON, uma por arquivo <sha256 do documento>.json) passando pelo mesmo
+// mapDocumentToExtractedLabs do DocumentAIAdapter.
+type FixtureExtractor struct {
+	files domainstorage.FileReader
+	dir   string
+	mode  FixtureMode
+	// live é usado apenas em FixtureModeRecord.
+	live *DocumentAIAdapter
+}
+
+var _ domainai.DocumentExtractorService = (*FixtureExtractor)(nil)
+
+func NewFixtureExtractor(
+	files domainstorage.FileReader,
+	dir string,
+	mode FixtureMode,
+	live *DocumentAIAdapter,
+) (*FixtureExtractor, error) {
+	switch mode {
+	case FixtureModeReplay:
+	case FixtureModeRecord:
+		if live == nil {
+			return nil, fmt.Errorf("modo record exige o Document AI configurado")
+		}
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("erro ao criar diretório de fixtures: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("modo de fixture inválido: %q", mode)
+	}
+
+	return &FixtureExtractor{
+		files: files,
+		dir:   dir,
+		mode:  mode,
+		live:  live,
+	}, nil
+}
+
+func (e *FixtureExtractor) ExtractLabReport(
+	ctx context.Context,
+	documentURI, mimeType string,
+) (*domainai.ExtractedLabReport, error) {
+	key, err := e.documentKey(ctx, documentURI)
+	if err != nil {
+		return nil, err
+	}
+	log := applog.FromContext(ctx).With(slog.String("fixture", key))
+
+	var doc *documentaipb.Document
+	switch e.mode {
+	case FixtureModeRecord:
+		doc, err = e.live.processDocument(ctx, documentURI, mimeType)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao processar documento: %w", err)
+		}
+		if err := e.save(key, doc); err != nil {
+			return nil, err
+		}
+		log.Info("docai_fixture_recorded")
+	default:
+		doc, err = e.load(key)
+		if err != nil {
+			return nil, err
+		}
+		log.Debug("docai_fixture_replayed")
+	}
+
+	extracted := mapDocumentToExtractedLabs(doc)
+	if len(extracted.Tests) == 0 {
+		return nil, fmt.Errorf("validação falhou: nenhum teste foi extraído do documento")
+	}
+	return extracted, nil
+}
+
+// documentKey é o SHA-256 do conteúdo, estável entre storages e uploads.
+func (e *FixtureExtractor) documentKey(ctx context.Context, documentURI string) (string, error) {
+	rc, err := e.files.Open(ctx, documentURI)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler documento: %w", err)
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", fmt.Errorf("erro ao ler documento: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (e *FixtureExtractor) fixturePath(key string) string {
+	return filepath.Join(e.dir, key+".json")
+}
+
+func (e *FixtureExtractor) load(key string) (*documentaipb.Document, error) {
+	raw, err := os.ReadFile(e.fixturePath(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: sha256=%s", ErrFixtureNotFound, key)
+		}
+		return nil, fmt.Errorf("erro ao ler fixture: %w", err)
+	}
+
+	var doc documentaipb.Document
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("fixture inválida (sha256=%s): %w", key, err)
+	}
+	return &doc, nil
+}
+
+func (e *FixtureExtractor) save(key string, doc *documentaipb.Document) error {
+	raw, err := (protojson.MarshalOptions{Multiline: true, Indent: "  "}).Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar fixture: %w", err)
+	}
+	if err := os.WriteFile(e.fixturePath(key), raw, 0o640); err != nil {
+		return fmt.Errorf("erro ao gravar fixture: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// osFileReader lê URIs como caminhos locais (testdata).
+type osFileReader struct{}
+
+func (osFileReader) Open(_ context.Context, uri string) (io.ReadCloser, error) {
+	return os.Open(uri)
+}
+
+func TestFixtureExtractor_ReplayRunsRealMapper(t *testing.T) {
+	e, err := NewFixtureExtractor(osFileReader{}, filepath.Join("testdata", "docai"), FixtureModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewFixtureExtractor: %v", err)
+	}
+
+	out, err := e.ExtractLabReport(context.Background(), filepath.Join("testdata", "docai", "hemograma.pdf"), "application/pdf")
+	if err != nil {
+		t.Fatalf("ExtractLabReport: %v", err)
+	}
+
+	assertStr(t, "patient_name", out.PatientName, "MARIA DA SILVA")
+	// patient_dob usa o normalizedValue do Document AI.
+	assertStr(t, "patient_dob", out.PatientDOB, "1985-03-12")
+	assertStr(t, "lab_name", out.LabName, "Laboratório Exemplo")
+
+	if len(out.Tests) != 1 || len(out.Tests[0].Items) != 2 {
+		t.Fatalf("unexpected tests %+v", out.Tests)
+	}
+	tr := out.Tests[0]
+	if tr.TestName != "HEMOGRAMA COMPLETO" {
+		t.Fatalf("unexpected test name %q", tr.TestName)
+	}
+	assertStr(t, "material", tr.Material, "Sangue total")
+	assertStr(t, "collected_at", tr.CollectedAt, "14/01/2025 08:30")
+	assertItem(t, tr.Items[0], "Hemoglobina", "14,2", "g/dL", "12,0 a 16,0")
+	assertItem(t, tr.Items[1], "Leucócitos", "6.500", "/mm³", "4.000 a 11.000")
+
+	// Confiança do Document AI por campo; entidades sem confiança ficam de fora.
+	if c := out.FieldConfidence["patient_name"]; c < 0.97 || c > 0.99 {
+		t.Fatalf("unexpected patient_name confidence %v", out.FieldConfidence)
+	}
+	if _, ok := out.FieldConfidence["lab_name"]; ok {
+		t.Fatalf("expected lab_name without confidence, got %v", out.FieldConfidence)
+	}
+	leuko := tr.Items[1]
+	if leuko.Confidence == nil || *leuko.Confidence < 0.89 || leuko.FieldConfidence["result_value"] > 0.56 {
+		t.Fatalf("unexpected item confidence %v %v", leuko.Confidence, leuko.FieldConfidence)
+	}
+	if _, ok := leuko.FieldConfidence["result_unit"]; !ok {
+		t.Fatalf("expected unit confidence under result_unit, got %v", leuko.FieldConfidence)
+	}
+}
+
+func TestFixtureExtractor_ReplayMissingFixture(t *testing.T) {
+	e, err := NewFixtureExtractor(osFileReader{}, t.TempDir(), FixtureModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewFixtureExtractor: %v", err)
+	}
+
+	_, err = e.ExtractLabReport(context.Background(), filepath.Join("testdata", "docai", "hemograma.pdf"), "application/pdf")
+	if !errors.Is(err, ErrFixtureNotFound) {
+		t.Fatalf("expected ErrFixtureNotFound, got %v", err)
+	}
+}
+
+func TestNewFixtureExtractor_RecordRequiresLiveAdapter(t *testing.T) {
+	if _, err := NewFixtureExtractor(osFileReader{}, t.TempDir(), FixtureModeRecord, nil); err == nil {
+		t.Fatal("expected error without live adapter")
+	}
+}
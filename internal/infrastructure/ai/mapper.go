@@ -97,7 +97,10 @@ func mapDocumentToExtractedLabs(doc *documentaipb.Document) *domainai.ExtractedL
 		// -------- test_result (painel com filhos) --------
 		case "test_result":
 			out.Tests = append(out.Tests, mapTestResult(doc, ent))
+			continue
 		}
+
+		recordConfidence(&out.FieldConfidence, ent.GetType(), ent)
 	}
 
 	return out
@@ -138,6 +141,10 @@ func mapTestResult(doc *documentaipb.Document, ent *documentaipb.Document_Entity
 
 func mapTestItem(doc *documentaipb.Document, ent *documentaipb.Document_Entity) domainai.ExtractedTestItem {
 	var item domainai.ExtractedTestItem
+	if c := ent.GetConfidence(); c > 0 {
+		v := float64(c)
+		item.Confidence = &v
+	}
 
 	for _, prop := range ent.GetProperties() {
 		switch prop.GetType() {
@@ -155,8 +162,30 @@ func mapTestItem(doc *documentaipb.Document, ent *documentaipb.Document_Entity)
 		case "reference_text":
 			v := extractEntityText(doc, prop)
 			item.ReferenceText = &v
+
+		default:
+			continue
 		}
+
+		field := prop.GetType()
+		if field == "unit" {
+			field = "result_unit"
+		}
+		recordConfidence(&item.FieldConfidence, field, prop)
 	}
 
 	return item
 }
+
+// recordConfidence guarda a confiança da entidade sob o nome do campo do
+// laudo. Entidades sem confiança (0) não entram no mapa.
+func recordConfidence(m *map[string]float64, field string, ent *documentaipb.Document_Entity) {
+	c := ent.GetConfidence()
+	if c <= 0 {
+		return
+	}
+	if *m == nil {
+		*m = make(map[string]float64)
+	}
+	(*m)[field] = float64(c)
+}
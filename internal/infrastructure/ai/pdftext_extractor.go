@@ -0,0 +1,174 @@
+// internal/infrastructure/ai/pdftext_extractor.go
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+
+	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
+	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
+	applog "github.com/gabrielgcmr/sonnda/internal/kernel/observability"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Abaixo disso consideramos que o PDF não tem camada de texto (documento escaneado).
+const minPDFTextChars = 80
+
+// errNoTextLayer indica que o documento precisa de OCR.
+var errNoTextLayer = errors.New("documento sem camada de texto")
+
+// PDFTextExtractor lê a camada de texto de PDFs localmente e só recorre ao
+// fallback (Document AI) para imagens e PDFs escaneados.
+type PDFTextExtractor struct {
+	files    domainstorage.FileReader
+	fallback domainai.DocumentExtractorService
+}
+
+var _ domainai.DocumentExtractorService = (*PDFTextExtractor)(nil)
+
+// NewPDFTextExtractor cria o extractor; fallback pode ser nil para operar sem GCP.
+func NewPDFTextExtractor(files domainstorage.FileReader, fallback domainai.DocumentExtractorService) *PDFTextExtractor {
+	return &PDFTextExtractor{
+		files:    files,
+		fallback: fallback,
+	}
+}
+
+func (e *PDFTextExtractor) ExtractLabReport(
+	ctx context.Context,
+	documentURI, mimeType string,
+) (*domainai.ExtractedLabReport, error) {
+	if !isPDFMimeType(mimeType) {
+		return e.useFallback(ctx, documentURI, mimeType, "not_pdf")
+	}
+
+	content, err := e.readDocument(ctx, documentURI)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := pdfTextLines(content)
+	if err != nil {
+		applog.FromContext(ctx).Warn("pdf_text_layer_unreadable", slog.Any("err", err))
+		return e.useFallback(ctx, documentURI, mimeType, "unreadable")
+	}
+
+	extracted := parseLabText(lines)
+	if len(extracted.Tests) == 0 {
+		return e.useFallback(ctx, documentURI, mimeType, "no_tests_parsed")
+	}
+
+	return extracted, nil
+}
+
+func (e *PDFTextExtractor) useFallback(
+	ctx context.Context,
+	documentURI, mimeType, reason string,
+) (*domainai.ExtractedLabReport, error) {
+	if e.fallback == nil {
+		return nil, fmt.Errorf("extração local indisponível (%s): %w", reason, errNoTextLayer)
+	}
+
+	applog.FromContext(ctx).Info("lab_extractor_fallback", slog.String("reason", reason))
+	return e.fallback.ExtractLabReport(ctx, documentURI, mimeType)
+}
+
+func (e *PDFTextExtractor) readDocument(ctx context.Context, documentURI string) ([]byte, error) {
+	rc, err := e.files.Open(ctx, documentURI)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler documento: %w", err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler documento: %w", err)
+	}
+	return content, nil
+}
+
+func isPDFMimeType(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	return mimeType == "application/pdf" || mimeType == "image/pdf"
+}
+
+// pdfTextLines reconstrói as linhas de cada página a partir da posição dos
+// trechos de texto. Espaços horizontais largos viram columnSeparator.
+func pdfTextLines(content []byte) (lines []string, err error) {
+	// O parser de PDF entra em panic com arquivos malformados.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("pdf malformado: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	chars := 0
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		rows, err := page.GetTextByRow()
+		if err != nil {
+			return nil, err
+		}
+		// Y cresce de baixo para cima.
+		sort.SliceStable(rows, func(a, b int) bool { return rows[a].Position > rows[b].Position })
+
+		for _, row := range rows {
+			line := joinRowTexts(row.Content)
+			chars += len(strings.TrimSpace(line))
+			lines = append(lines, line)
+		}
+	}
+
+	if chars < minPDFTextChars {
+		return nil, errNoTextLayer
+	}
+	return lines, nil
+}
+
+func joinRowTexts(texts pdf.TextHorizontal) string {
+	sort.SliceStable(texts, func(a, b int) bool { return texts[a].X < texts[b].X })
+
+	var (
+		b    strings.Builder
+		endX = math.Inf(-1)
+	)
+	for _, t := range texts {
+		if t.S == "" {
+			continue
+		}
+		fontSize := t.FontSize
+		if fontSize <= 0 {
+			fontSize = 10
+		}
+
+		gap := t.X - endX
+		switch {
+		case b.Len() == 0:
+		case gap > 2.5*fontSize:
+			b.WriteString(columnSeparator)
+		case gap > 0.2*fontSize:
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(t.S)
+		endX = t.X + t.W
+	}
+	return b.String()
+}
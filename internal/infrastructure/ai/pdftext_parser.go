@@ -0,0 +1,330 @@
+// internal/infrastructure/ai/pdftext_parser.go
+package ai
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
+)
+
+// columnSeparator marca espaços horizontais grandes entre blocos de texto
+// da mesma linha (colunas do laudo: parâmetro | resultado | unidade | referência).
+const columnSeparator = "\t"
+
+var (
+	// Cabeçalho do laudo (avaliado por coluna).
+	rePatientName      = regexp.MustCompile(`(?i)^(?:nome do paciente|paciente|nome)\s*[:\-]\s*(.+)$`)
+	rePatientDOB       = regexp.MustCompile(`(?i)^(?:data de nascimento|dt\.?\s*nasc(?:imento)?\.?|nascimento|d\.?\s*n\.?)\s*[:\-]?\s*(\d{2}/\d{2}/\d{4})`)
+	reRequestingDoctor = regexp.MustCompile(`(?i)^(?:m[ée]dico solicitante|m[ée]dico\(a\)|m[ée]dico|solicitante|dr\(a\)\.?)\s*[:\-]\s*(.+)$`)
+	reInsurance        = regexp.MustCompile(`(?i)^(?:conv[êe]nio|plano)\s*[:\-]\s*(.+)$`)
+	reTechnicalManager = regexp.MustCompile(`(?i)^(?:respons[áa]vel t[ée]cnico|resp\.?\s*t[ée]cnico|diretor t[ée]cnico)\s*[:\-]\s*(.+)$`)
+	reReportDate       = regexp.MustCompile(`(?i)^(?:data do laudo|data de emiss[ãa]o|emiss[ãa]o|emitido em|data)\s*[:\-]?\s*(\d{2}/\d{2}/\d{4})`)
+	reLabPhone         = regexp.MustCompile(`(?i)^(?:tel(?:efone)?|fone)\.?\s*[:\-]?\s*(\(?\d{2}\)?\s*\d{4,5}[\-\s]?\d{4})`)
+	reLabName          = regexp.MustCompile(`(?i)^(laborat[óo]rio\s+.+|.*\b(?:fleury|dasa|hermes pardini|sabin|delboni|lavoisier|a\+ medicina diagn[óo]stica|db diagn[óo]sticos)\b.*)$`)
+
+	// Metadados do exame corrente (avaliados por coluna).
+	reMaterial    = regexp.MustCompile(`(?i)^(?:material|amostra)\s*[:\-]\s*(.+)$`)
+	reMethod      = regexp.MustCompile(`(?i)^(?:m[ée]todo|metodologia)\s*[:\-]\s*(.+)$`)
+	reCollectedAt = regexp.MustCompile(`(?i)^(?:coletado em|data da coleta|data de coleta|coleta)\s*[:\-]?\s*(\d{2}/\d{2}/\d{4}(?:\s+(?:[àa]s\s+)?\d{2}[:h]\d{2}(?::\d{2})?)?)`)
+	reReleaseAt   = regexp.MustCompile(`(?i)^(?:liberado em|data de libera[çc][ãa]o|libera[çc][ãa]o)\s*[:\-]?\s*(\d{2}/\d{2}/\d{4}(?:\s+(?:[àa]s\s+)?\d{2}[:h]\d{2}(?::\d{2})?)?)`)
+
+	// Linha de resultado: parâmetro, separador (pontilhado, ":" ou espaço) e valor.
+	reResultLine = regexp.MustCompile(`(?i)^((?:\d+-)?\p{L}[\p{L}\p{N} ()/,.%+'\-]*?)\s*(?:\.{2,}\s*|:\s*|\s+)` +
+		`([<>≤≥]=?\s*\d+(?:[.,]\d+)*|\d+(?:[.,]\d+)*|n[ãa]o reagente|reagente|negativo|positivo|ausentes?|presentes?|indetect[áa]vel|detect[áa]vel|normal|raras?|numerosas?)` +
+		`(?:\s+|$)(.*)$`)
+
+	reReferencePrefix = regexp.MustCompile(`(?i)^(?:valor(?:es)? de refer[êe]ncia|refer[êe]ncia|ref\.?|v\.?\s*r\.?)(?:\s*[:\-]\s*|\s+)`)
+	reDateLike        = regexp.MustCompile(`^\d{1,2}/\d{1,2}(?:/\d{2,4})?\b`)
+	reStartsNumeric   = regexp.MustCompile(`^[<>≤≥]?\s*\d`)
+)
+
+// Palavras de cabeçalho de tabela e rodapé que nunca são exames.
+var pdfNoiseMarkers = []string{
+	"página", "pagina", "resultado", "valores de referência", "valores de referencia",
+	"valor de referência", "valor de referencia", "laudo", "exame", "assinado",
+	"liberado eletronicamente", "unidade",
+}
+
+var unitWithoutSlash = map[string]struct{}{
+	"%": {}, "fl": {}, "pg": {}, "g": {}, "mg": {}, "seg": {}, "s": {}, "ui": {}, "u": {},
+	"mm": {}, "min": {}, "ratio": {}, "inr": {}, "mmhg": {}, "kg": {}, "cm": {},
+}
+
+// parseLabText converte linhas de texto de um laudo (com colunas separadas por
+// columnSeparator) no mesmo DTO produzido pelo Document AI.
+func parseLabText(lines []string) *domainai.ExtractedLabReport {
+	out := &domainai.ExtractedLabReport{}
+	if len(lines) > 0 {
+		raw := strings.ReplaceAll(strings.Join(lines, "\n"), columnSeparator, "  ")
+		out.RawText = &raw
+	}
+
+	var current *domainai.ExtractedTestResult
+	flush := func() {
+		if current != nil && len(current.Items) > 0 {
+			out.Tests = append(out.Tests, *current)
+		}
+		current = nil
+	}
+
+	for idx, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		columns := splitColumns(line)
+
+		if idx < 8 && out.LabName == nil {
+			if m := reLabName.FindStringSubmatch(columns[0]); m != nil {
+				out.LabName = strPtr(m[1])
+				continue
+			}
+		}
+
+		if parseHeaderColumns(out, columns) {
+			continue
+		}
+		if current != nil && parseTestMetadata(current, columns) {
+			continue
+		}
+		if isReferenceLine(line) {
+			attachReference(current, line)
+			continue
+		}
+
+		if item, ok := parseResultLine(strings.Join(columns, " ")); ok {
+			if startsOwnTest(current, item) {
+				flush()
+			}
+			if current == nil {
+				current = &domainai.ExtractedTestResult{TestName: item.ParameterName}
+			}
+			if isValueLabel(item.ParameterName) {
+				item.ParameterName = current.TestName
+			}
+			current.Items = append(current.Items, item)
+			continue
+		}
+
+		if len(columns) == 1 && isTestHeading(line) {
+			flush()
+			current = &domainai.ExtractedTestResult{TestName: collapseSpaces(line)}
+		}
+	}
+	flush()
+
+	return out
+}
+
+func splitColumns(line string) []string {
+	parts := strings.Split(line, columnSeparator)
+	columns := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = collapseSpaces(p); p != "" {
+			columns = append(columns, p)
+		}
+	}
+	if len(columns) == 0 {
+		return []string{""}
+	}
+	return columns
+}
+
+// parseHeaderColumns preenche os campos do cabeçalho; retorna true se a linha
+// era de cabeçalho.
+func parseHeaderColumns(out *domainai.ExtractedLabReport, columns []string) bool {
+	matched := false
+	for _, col := range columns {
+		switch {
+		case out.PatientDOB == nil && rePatientDOB.MatchString(col):
+			out.PatientDOB = strPtr(rePatientDOB.FindStringSubmatch(col)[1])
+		case out.PatientName == nil && rePatientName.MatchString(col):
+			out.PatientName = strPtr(rePatientName.FindStringSubmatch(col)[1])
+		case out.RequestingDoctor == nil && reRequestingDoctor.MatchString(col):
+			out.RequestingDoctor = strPtr(reRequestingDoctor.FindStringSubmatch(col)[1])
+		case out.InsuranceProvider == nil && reInsurance.MatchString(col):
+			out.InsuranceProvider = strPtr(reInsurance.FindStringSubmatch(col)[1])
+		case out.TechnicalManager == nil && reTechnicalManager.MatchString(col):
+			out.TechnicalManager = strPtr(reTechnicalManager.FindStringSubmatch(col)[1])
+		case out.ReportDate == nil && reReportDate.MatchString(col):
+			out.ReportDate = strPtr(reReportDate.FindStringSubmatch(col)[1])
+		case out.LabPhone == nil && reLabPhone.MatchString(col):
+			out.LabPhone = strPtr(reLabPhone.FindStringSubmatch(col)[1])
+		default:
+			continue
+		}
+		matched = true
+	}
+	return matched
+}
+
+func parseTestMetadata(tr *domainai.ExtractedTestResult, columns []string) bool {
+	matched := false
+	for _, col := range columns {
+		switch {
+		case reMaterial.MatchString(col):
+			tr.Material = strPtr(reMaterial.FindStringSubmatch(col)[1])
+		case reMethod.MatchString(col):
+			tr.Method = strPtr(reMethod.FindStringSubmatch(col)[1])
+		case reCollectedAt.MatchString(col):
+			tr.CollectedAt = strPtr(reCollectedAt.FindStringSubmatch(col)[1])
+		case reReleaseAt.MatchString(col):
+			tr.ReleaseAt = strPtr(reReleaseAt.FindStringSubmatch(col)[1])
+		default:
+			continue
+		}
+		matched = true
+	}
+	return matched
+}
+
+func parseResultLine(line string) (domainai.ExtractedTestItem, bool) {
+	m := reResultLine.FindStringSubmatch(line)
+	if m == nil {
+		return domainai.ExtractedTestItem{}, false
+	}
+
+	name := strings.TrimRight(strings.TrimSpace(m[1]), ".:- ")
+	value := collapseSpaces(m[2])
+	rest := strings.TrimSpace(m[3])
+
+	if countLetters(name) < 2 || (isNoise(name) && !isValueLabel(name)) {
+		return domainai.ExtractedTestItem{}, false
+	}
+	// "10/01/2025" não é resultado.
+	if reDateLike.MatchString(value + rest) {
+		return domainai.ExtractedTestItem{}, false
+	}
+
+	item := domainai.ExtractedTestItem{
+		ParameterName: name,
+		ResultValue:   strPtr(value),
+	}
+
+	if unit, remaining, ok := splitUnit(rest); ok {
+		item.ResultUnit = strPtr(unit)
+		rest = remaining
+	}
+	if ref := strings.TrimSpace(reReferencePrefix.ReplaceAllString(rest, "")); ref != "" {
+		item.ReferenceText = strPtr(ref)
+	}
+
+	return item, true
+}
+
+// splitUnit separa a unidade do início do restante da linha.
+func splitUnit(rest string) (string, string, bool) {
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", rest, false
+	}
+
+	token := fields[0]
+	if reStartsNumeric.MatchString(token) && !strings.Contains(token, "/") {
+		return "", rest, false
+	}
+	if len([]rune(token)) > 14 {
+		return "", rest, false
+	}
+
+	_, known := unitWithoutSlash[strings.ToLower(token)]
+	if !known && !strings.Contains(token, "/") && !strings.HasPrefix(strings.ToLower(token), "x10") {
+		return "", rest, false
+	}
+
+	return token, strings.TrimSpace(strings.TrimPrefix(rest, token)), true
+}
+
+// startsOwnTest decide se um item sem unidade nem referência ("HBsAg  Não
+// reagente") é um exame à parte. Ele só entra no exame corrente quando este
+// ainda não tem itens ou já é um painel de itens assim (ex.: urina tipo I);
+// depois de itens com unidade ou referência, é um exame novo.
+func startsOwnTest(current *domainai.ExtractedTestResult, item domainai.ExtractedTestItem) bool {
+	if current == nil || len(current.Items) == 0 || isValueLabel(item.ParameterName) || !isBareItem(item) {
+		return false
+	}
+	for _, existing := range current.Items {
+		if isBareItem(existing) {
+			return false
+		}
+	}
+	return true
+}
+
+func isBareItem(item domainai.ExtractedTestItem) bool {
+	return item.ResultUnit == nil && item.ReferenceText == nil
+}
+
+func isReferenceLine(line string) bool {
+	return reReferencePrefix.MatchString(line)
+}
+
+// attachReference associa uma linha "Valor de referência: ..." ao último item sem referência.
+func attachReference(tr *domainai.ExtractedTestResult, line string) {
+	if tr == nil || len(tr.Items) == 0 {
+		return
+	}
+	last := &tr.Items[len(tr.Items)-1]
+	if last.ReferenceText != nil {
+		return
+	}
+	ref := collapseSpaces(strings.ReplaceAll(reReferencePrefix.ReplaceAllString(line, ""), columnSeparator, " "))
+	if ref != "" {
+		last.ReferenceText = strPtr(ref)
+	}
+}
+
+// isTestHeading identifica títulos de exame, tipicamente em caixa alta
+// ("HEMOGRAMA COMPLETO", "GLICOSE").
+func isTestHeading(line string) bool {
+	if isNoise(line) || strings.ContainsAny(line, ":") {
+		return false
+	}
+
+	letters, upper := 0, 0
+	for _, r := range line {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 3 && float64(upper)/float64(letters) >= 0.8
+}
+
+func isNoise(s string) bool {
+	lower := strings.ToLower(s)
+	for _, marker := range pdfNoiseMarkers {
+		if strings.HasPrefix(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValueLabel cobre exames de um único parâmetro ("GLICOSE" / "Resultado: 90 mg/dL").
+func isValueLabel(name string) bool {
+	return strings.EqualFold(name, "resultado") || strings.EqualFold(name, "valor")
+}
+
+func countLetters(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+	return n
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func strPtr(s string) *string {
+	s = collapseSpaces(s)
+	return &s
+}
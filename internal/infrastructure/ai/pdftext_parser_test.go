@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	domainai "github.com/gabrielgcmr/sonnda/internal/domain/ai"
+)
+
+const sampleLabText = "Fleury Medicina e Saúde\n" +
+	"Paciente: MARIA DA SILVA\tData de Nascimento: 12/03/1985\n" +
+	"Médico Solicitante: Dr. João Souza\tConvênio: Unimed\n" +
+	"Data do laudo: 15/01/2025\n" +
+	"Exame\tResultado\tUnidade\tValores de Referência\n" +
+	"HEMOGRAMA COMPLETO\n" +
+	"Material: Sangue total\tMétodo: Automatizado\n" +
+	"Coletado em: 14/01/2025 08:30\tLiberado em: 15/01/2025 10:00\n" +
+	"Hemoglobina..........: 14,2\tg/dL\t12,0 a 16,0\n" +
+	"Hematócrito\t42,5\t%\t36,0 a 46,0\n" +
+	"Leucócitos\t6.500\t/mm³\t4.000 a 11.000\n" +
+	"GLICOSE\n" +
+	"Material: Soro\n" +
+	"Resultado: 92 mg/dL\n" +
+	"Valor de referência: 70 a 99 mg/dL\n" +
+	"HBsAg\tNão reagente\n" +
+	"Responsável Técnico: Dra. Ana Lima CRBM 1234\n"
+
+func TestParseLabText_FullReport(t *testing.T) {
+	out := parseLabText(strings.Split(sampleLabText, "\n"))
+
+	assertStr(t, "lab_name", out.LabName, "Fleury Medicina e Saúde")
+	assertStr(t, "patient_name", out.PatientName, "MARIA DA SILVA")
+	assertStr(t, "patient_dob", out.PatientDOB, "12/03/1985")
+	assertStr(t, "requesting_doctor", out.RequestingDoctor, "Dr. João Souza")
+	assertStr(t, "insurance", out.InsuranceProvider, "Unimed")
+	assertStr(t, "report_date", out.ReportDate, "15/01/2025")
+	assertStr(t, "technical_manager", out.TechnicalManager, "Dra. Ana Lima CRBM 1234")
+
+	if len(out.Tests) != 3 {
+		t.Fatalf("expected 3 tests, got %d: %+v", len(out.Tests), out.Tests)
+	}
+
+	hemo := out.Tests[0]
+	if hemo.TestName != "HEMOGRAMA COMPLETO" {
+		t.Fatalf("unexpected test name %q", hemo.TestName)
+	}
+	assertStr(t, "material", hemo.Material, "Sangue total")
+	assertStr(t, "method", hemo.Method, "Automatizado")
+	assertStr(t, "collected_at", hemo.CollectedAt, "14/01/2025 08:30")
+	assertStr(t, "release_at", hemo.ReleaseAt, "15/01/2025 10:00")
+	if len(hemo.Items) != 3 {
+		t.Fatalf("expected 3 hemograma items, got %+v", hemo.Items)
+	}
+	assertItem(t, hemo.Items[0], "Hemoglobina", "14,2", "g/dL", "12,0 a 16,0")
+	assertItem(t, hemo.Items[1], "Hematócrito", "42,5", "%", "36,0 a 46,0")
+	assertItem(t, hemo.Items[2], "Leucócitos", "6.500", "/mm³", "4.000 a 11.000")
+
+	glic := out.Tests[1]
+	if glic.TestName != "GLICOSE" || len(glic.Items) != 1 {
+		t.Fatalf("unexpected glicose test %+v", glic)
+	}
+	assertItem(t, glic.Items[0], "GLICOSE", "92", "mg/dL", "70 a 99 mg/dL")
+
+	hbsag := out.Tests[2]
+	if hbsag.TestName != "HBsAg" || len(hbsag.Items) != 1 {
+		t.Fatalf("unexpected HBsAg test %+v", hbsag)
+	}
+	assertItem(t, hbsag.Items[0], "HBsAg", "Não reagente", "", "")
+}
+
+func TestParseLabText_QualitativePanelKeepsItems(t *testing.T) {
+	lines := []string{
+		"URINA TIPO I",
+		"Proteínas\tAusentes",
+		"Glicose\tNegativo",
+		"Hemoglobina\tNegativo",
+	}
+	out := parseLabText(lines)
+
+	if len(out.Tests) != 1 || len(out.Tests[0].Items) != 3 {
+		t.Fatalf("expected one panel with 3 items, got %+v", out.Tests)
+	}
+	assertItem(t, out.Tests[0].Items[0], "Proteínas", "Ausentes", "", "")
+	assertItem(t, out.Tests[0].Items[2], "Hemoglobina", "Negativo", "", "")
+}
+
+func TestParseLabText_NoResultsForPlainText(t *testing.T) {
+	out := parseLabText([]string{"Prezado cliente,", "seu laudo está disponível no site."})
+	if len(out.Tests) != 0 {
+		t.Fatalf("expected no tests, got %+v", out.Tests)
+	}
+}
+
+func assertStr(t *testing.T, field string, got *string, want string) {
+	t.Helper()
+	if got == nil || *got != want {
+		t.Errorf("%s: got %v, want %q", field, deref(got), want)
+	}
+}
+
+func assertItem(t *testing.T, item domainai.ExtractedTestItem, name, value, unit, ref string) {
+	t.Helper()
+	if item.ParameterName != name {
+		t.Errorf("parameter: got %q, want %q", item.ParameterName, name)
+	}
+	if deref(item.ResultValue) != value {
+		t.Errorf("%s value: got %q, want %q", name, deref(item.ResultValue), value)
+	}
+	if deref(item.ResultUnit) != unit {
+		t.Errorf("%s unit: got %q, want %q", name, deref(item.ResultUnit), unit)
+	}
+	if deref(item.ReferenceText) != ref {
+		t.Errorf("%s reference: got %q, want %q", name, deref(item.ReferenceText), ref)
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,255 @@
+// Package mllp implementa o transporte MLLP (Minimal Lower Layer Protocol)
+// usado por sistemas de laboratório para enviar mensagens HL7 v2 por TCP.
+package mllp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// Envelope MLLP: <VT> mensagem <FS><CR>.
+const (
+	startBlock     byte = 0x0B
+	endBlock       byte = 0x1C
+	carriageReturn byte = 0x0D
+)
+
+var ErrMessageTooLarge = errors.New("mllp: mensagem excede o tamanho máximo")
+
+// Handler processa uma mensagem (sem o envelope) e devolve a resposta, em
+// geral o ACK. Resposta vazia não é enviada.
+type Handler interface {
+	HandleMessage(ctx context.Context, message []byte) []byte
+}
+
+type Config struct {
+	Addr string
+	// Tempo máximo sem receber dados numa conexão aberta; 0 desliga.
+	ReadTimeout time.Duration
+	// Limite por mensagem, em bytes.
+	MaxMessageSize int
+	// Redes de origem aceitas. O protocolo não tem autenticação, então a
+	// lista vazia recusa todas as conexões.
+	AllowedNets []netip.Prefix
+	// Conexões simultâneas; as excedentes são fechadas logo após o accept.
+	// 0 usa o padrão.
+	MaxConns int
+}
+
+// Server atende conexões MLLP; cada conexão processa suas mensagens em ordem.
+type Server struct {
+	cfg     Config
+	handler Handler
+	logger  *slog.Logger
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+func NewServer(cfg Config, handler Handler, logger *slog.Logger) *Server {
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = 1 << 20
+	}
+	if cfg.MaxConns <= 0 {
+		cfg.MaxConns = 16
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Server{
+		cfg:     cfg,
+		handler: handler,
+		logger:  logger,
+		conns:   make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe bloqueia até ctx ser cancelado ou o listener falhar.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("mllp: listen %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve aceita conexões em ln. Ao cancelar ctx, fecha o listener e as
+// conexões abertas e espera as mensagens em andamento terminarem.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	s.mu.Lock()
+	s.listener = ln
+	s.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, s.close)
+	defer stop()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.close()
+			s.wg.Wait()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("mllp: accept: %w", err)
+		}
+
+		if !s.allowed(conn.RemoteAddr()) {
+			s.logger.Warn("mllp: conexão recusada: origem fora da lista permitida",
+				slog.String("remote_addr", conn.RemoteAddr().String()))
+			_ = conn.Close()
+			continue
+		}
+		if !s.track(conn, true) {
+			s.logger.Warn("mllp: conexão recusada: limite de conexões atingido",
+				slog.String("remote_addr", conn.RemoteAddr().String()),
+				slog.Int("max_conns", s.cfg.MaxConns))
+			_ = conn.Close()
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.track(conn, false)
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// Addr devolve o endereço em que o servidor está ouvindo (útil com porta 0).
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+func (s *Server) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+// track registra ou remove conn; ao registrar, devolve false se o limite de
+// conexões já foi atingido.
+func (s *Server) track(conn net.Conn, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if add {
+		if len(s.conns) >= s.cfg.MaxConns {
+			return false
+		}
+		s.conns[conn] = struct{}{}
+		return true
+	}
+	delete(s.conns, conn)
+	_ = conn.Close()
+	return true
+}
+
+func (s *Server) allowed(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	ip, ok := netip.AddrFromSlice(tcpAddr.IP)
+	if !ok {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, prefix := range s.cfg.AllowedNets {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	logger := s.logger.With(slog.String("remote_addr", conn.RemoteAddr().String()))
+	reader := bufio.NewReader(conn)
+
+	for {
+		if s.cfg.ReadTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(s.cfg.ReadTimeout))
+		}
+
+		message, err := ReadMessage(reader, s.cfg.MaxMessageSize)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				logger.Warn("mllp: conexão encerrada", slog.Any("error", err))
+			}
+			return
+		}
+
+		response := s.handler.HandleMessage(ctx, message)
+		if len(response) == 0 {
+			continue
+		}
+		if err := WriteMessage(conn, response); err != nil {
+			logger.Warn("mllp: falha ao enviar resposta", slog.Any("error", err))
+			return
+		}
+	}
+}
+
+// ReadMessage lê o próximo bloco MLLP. Bytes antes do <VT> (inclusive o <CR>
+// do bloco anterior) são descartados.
+func ReadMessage(r *bufio.Reader, maxSize int) ([]byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == startBlock {
+			break
+		}
+	}
+
+	var message []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if b == endBlock {
+			// O <CR> que fecha o envelope é descartado na próxima leitura; não
+			// esperamos por ele porque alguns emissores o omitem.
+			return message, nil
+		}
+		if len(message) >= maxSize {
+			return nil, ErrMessageTooLarge
+		}
+		message = append(message, b)
+	}
+}
+
+// WriteMessage envia message dentro do envelope MLLP.
+func WriteMessage(w io.Writer, message []byte) error {
+	frame := make([]byte, 0, len(message)+3)
+	frame = append(frame, startBlock)
+	frame = append(frame, message...)
+	frame = append(frame, endBlock, carriageReturn)
+	_, err := w.Write(frame)
+	return err
+}
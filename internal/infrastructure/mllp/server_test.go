@@ -0,0 +1,140 @@
+package mllp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+var loopback = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+
+type ackHandler struct{}
+
+func (ackHandler) HandleMessage(ctx context.Context, message []byte) []byte {
+	return append([]byte("ACK:"), message...)
+}
+
+func TestServer_RoundTrip(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := NewServer(Config{ReadTimeout: time.Second, AllowedNets: loopback}, ackHandler{}, nil)
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln) }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	// Segunda mensagem sem o <CR> final, como alguns emissores fazem.
+	frames := [][]byte{
+		{startBlock, 'M', 'S', 'H', '1', endBlock, carriageReturn},
+		{startBlock, 'M', 'S', 'H', '2', endBlock},
+	}
+	for i, frame := range frames {
+		if _, err := conn.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadMessage(reader, 1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "ACK:MSH" + string(rune('1'+i)); string(got) != want {
+			t.Fatalf("response %d = %q, want %q", i, got, want)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Serve returned %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not stop after cancel")
+	}
+}
+
+func TestServer_RejectsUnlistedPeersAndExtraConns(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		holders int
+	}{
+		{name: "origem fora da lista", cfg: Config{AllowedNets: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}},
+		{name: "limite de conexões", cfg: Config{AllowedNets: loopback, MaxConns: 1}, holders: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			srv := NewServer(tt.cfg, ackHandler{}, nil)
+			go func() { _ = srv.Serve(ctx, ln) }()
+
+			for i := 0; i < tt.holders; i++ {
+				holder, err := net.Dial("tcp", ln.Addr().String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer holder.Close()
+				// Garante que o servidor já registrou a conexão.
+				if _, err := holder.Write([]byte{startBlock, 'M', endBlock}); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := ReadMessage(bufio.NewReader(holder), 1024); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			conn, err := net.Dial("tcp", ln.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+			_, _ = conn.Write([]byte{startBlock, 'M', endBlock})
+			if _, err := ReadMessage(bufio.NewReader(conn), 1024); err == nil {
+				t.Fatal("expected the server to close the connection")
+			}
+		})
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	raw := "lixo\x0bMSH|^~\\&|LIS\rPID|1\x1c\r"
+	got, err := ReadMessage(bufio.NewReader(strings.NewReader(raw)), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "MSH|^~\\&|LIS\rPID|1" {
+		t.Fatalf("message = %q", got)
+	}
+
+	if _, err := ReadMessage(bufio.NewReader(strings.NewReader("\x0b0123456789\x1c")), 5); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMessage(&buf, []byte("ACK")); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "\x0bACK\x1c\r" {
+		t.Fatalf("frame = %q", buf.String())
+	}
+}
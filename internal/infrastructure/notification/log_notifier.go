@@ -0,0 +1,40 @@
+// internal/infrastructure/notification/log_notifier.go
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	domainnotification "github.com/gabrielgcmr/sonnda/internal/domain/notification"
+	applog "github.com/gabrielgcmr/sonnda/internal/kernel/observability"
+
+	"github.com/google/uuid"
+)
+
+// LogNotifier registra o aviso no log estruturado. Serve enquanto não há
+// canal de push/e-mail; o profissional vê o alerta na caixa de entrada. Como
+// nada chega ao profissional, o alerta não conta como notificado.
+type LogNotifier struct{}
+
+var _ domainnotification.CriticalValueNotifier = LogNotifier{}
+
+func NewLogNotifier() LogNotifier {
+	return LogNotifier{}
+}
+
+// NotifyCriticalValue implementa [domainnotification.CriticalValueNotifier].
+func (LogNotifier) NotifyCriticalValue(ctx context.Context, userID uuid.UUID, alert labs.CriticalAlert) (bool, error) {
+	applog.FromContext(ctx).Warn("labs: valor crítico",
+		slog.String("alert_id", alert.ID.String()),
+		slog.String("user_id", userID.String()),
+		slog.String("patient_id", alert.PatientID.String()),
+		slog.String("report_id", alert.ReportID.String()),
+		slog.String("analyte_code", alert.AnalyteCode),
+		slog.Float64("value", alert.Value),
+		slog.Float64("limit", alert.Limit),
+		slog.String("unit", alert.Unit),
+		slog.String("interpretation", string(alert.Interpretation)),
+	)
+	return false, nil
+}
@@ -0,0 +1,282 @@
+// internal/infrastructure/pdf/cumulative.go
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+)
+
+// Layout do relatório cumulativo (A4 paisagem, medidas em pontos).
+const (
+	cumMargin      = 36.0
+	cumRowHeight   = 14.0
+	cumFontSize    = 8.0
+	cumNameWidth   = 180.0
+	cumUnitWidth   = 60.0
+	cumRefWidth    = 90.0
+	cumDateWidth   = 56.0
+	cumCellPadding = 3.0
+	cumFooterSpace = 30.0
+)
+
+var (
+	cumGray        = Color{0.45, 0.45, 0.45}
+	cumGridColor   = Color{0.82, 0.82, 0.82}
+	cumHeaderFill  = Color{0.9, 0.92, 0.95}
+	cumGroupFill   = Color{0.96, 0.96, 0.96}
+	cumAbnormal    = Color{0.99, 0.85, 0.85}
+	cumAbnormalTxt = Color{0.65, 0.05, 0.05}
+)
+
+// CumulativeRenderer gera o relatório cumulativo em PDF: analitos nas
+// linhas, datas de coleta nas colunas e valores alterados destacados.
+// Quando as datas não cabem na largura da página, a tabela continua em
+// páginas seguintes com o próximo bloco de datas.
+type CumulativeRenderer struct{}
+
+var _ document.CumulativeReportRenderer = CumulativeRenderer{}
+
+func NewCumulativeRenderer() CumulativeRenderer {
+	return CumulativeRenderer{}
+}
+
+// ContentType implementa [document.CumulativeReportRenderer].
+func (CumulativeRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+// RenderCumulative implementa [document.CumulativeReportRenderer].
+func (CumulativeRenderer) RenderCumulative(ctx context.Context, report document.CumulativeReport) ([]byte, error) {
+	if report.Patient == nil {
+		return nil, fmt.Errorf("pdf: relatório cumulativo sem paciente")
+	}
+
+	r := &cumulativeLayout{
+		doc:    New(A4Height, A4Width, "Relatório cumulativo de exames - "+report.Patient.FullName, report.GeneratedAt),
+		report: report,
+	}
+	r.render()
+	r.footers()
+	return r.doc.Bytes()
+}
+
+type cumulativeLayout struct {
+	doc    *Document
+	report document.CumulativeReport
+	page   *Page
+	y      float64
+	pages  []*Page
+}
+
+func (r *cumulativeLayout) render() {
+	table := r.report.Table
+	if len(table.Dates) == 0 {
+		r.newPage()
+		r.page.Text(cumMargin, r.y+cumRowHeight, Helvetica, 10, Black, "Nenhum resultado no período.")
+		return
+	}
+
+	perPage := int((r.doc.Width() - 2*cumMargin - cumNameWidth - cumUnitWidth - cumRefWidth) / cumDateWidth)
+	if perPage < 1 {
+		perPage = 1
+	}
+	for start := 0; start < len(table.Dates); start += perPage {
+		end := min(start+perPage, len(table.Dates))
+		r.newPage()
+		r.columnHeader(table.Dates[start:end])
+
+		group := ""
+		for _, row := range table.Rows {
+			if !hasCells(row.Cells[start:end]) {
+				continue
+			}
+			if row.Group != group {
+				group = row.Group
+				r.ensureSpace(2, table.Dates[start:end])
+				r.groupHeader(group, end-start)
+			}
+			r.ensureSpace(1, table.Dates[start:end])
+			r.row(row, start, end)
+		}
+	}
+	r.legend()
+}
+
+// newPage abre uma página com o cabeçalho do paciente.
+func (r *cumulativeLayout) newPage() {
+	r.page = r.doc.AddPage()
+	r.pages = append(r.pages, r.page)
+	r.y = cumMargin
+
+	p := r.report.Patient
+	r.page.Text(cumMargin, r.y+12, HelveticaBold, 14, Black, "Relatório cumulativo de exames")
+	r.page.TextRight(r.doc.Width()-cumMargin, r.y+12, HelveticaBold, 11, Black, p.FullName)
+	r.y += 28
+
+	info := []string{"Nascimento: " + formatBirth(p, r.report.GeneratedAt)}
+	if g := genderLabel(p.Gender); g != "" {
+		info = append(info, "Sexo: "+g)
+	}
+	if cpf := formatCPF(p.CPF); cpf != "" {
+		info = append(info, "CPF: "+cpf)
+	}
+	r.page.Text(cumMargin, r.y, Helvetica, 9, Black, strings.Join(info, "   •   "))
+	r.page.TextRight(r.doc.Width()-cumMargin, r.y, Helvetica, 9, cumGray, "Período: "+formatPeriod(r.report.From, r.report.To))
+	r.y += 8
+	r.page.Line(cumMargin, r.y, r.doc.Width()-cumMargin, r.y, 0.8, Black)
+	r.y += 8
+}
+
+func (r *cumulativeLayout) columnHeader(dates []time.Time) {
+	width := cumNameWidth + cumUnitWidth + cumRefWidth + cumDateWidth*float64(len(dates))
+	r.page.FillRect(cumMargin, r.y, width, cumRowHeight, cumHeaderFill)
+
+	base := r.y + cumRowHeight - 4
+	x := cumMargin
+	r.page.Text(x+cumCellPadding, base, HelveticaBold, cumFontSize, Black, "Exame")
+	x += cumNameWidth
+	r.page.Text(x+cumCellPadding, base, HelveticaBold, cumFontSize, Black, "Unidade")
+	x += cumUnitWidth
+	r.page.Text(x+cumCellPadding, base, HelveticaBold, cumFontSize, Black, "Referência")
+	x += cumRefWidth
+	for _, d := range dates {
+		x += cumDateWidth
+		r.page.TextRight(x-cumCellPadding, base, HelveticaBold, cumFontSize, Black, d.Format("02/01/2006"))
+	}
+	r.y += cumRowHeight
+}
+
+func (r *cumulativeLayout) groupHeader(group string, columns int) {
+	width := cumNameWidth + cumUnitWidth + cumRefWidth + cumDateWidth*float64(columns)
+	r.page.FillRect(cumMargin, r.y, width, cumRowHeight, cumGroupFill)
+	if group == "" {
+		group = "Outros"
+	}
+	r.page.Text(cumMargin+cumCellPadding, r.y+cumRowHeight-4, HelveticaBold, cumFontSize, Black,
+		FitText(HelveticaBold, cumFontSize, width-2*cumCellPadding, group))
+	r.y += cumRowHeight
+}
+
+func (r *cumulativeLayout) row(row labs.CumulativeRow, start, end int) {
+	base := r.y + cumRowHeight - 4
+	x := cumMargin
+	r.text(x, cumNameWidth, base, Helvetica, Black, row.Name)
+	x += cumNameWidth
+	r.text(x, cumUnitWidth, base, Helvetica, cumGray, row.Unit)
+	x += cumUnitWidth
+	r.text(x, cumRefWidth, base, Helvetica, cumGray, row.Reference)
+	x += cumRefWidth
+
+	for _, cell := range row.Cells[start:end] {
+		if cell != nil {
+			value := cell.Value
+			if cell.Abnormal {
+				r.page.FillRect(x, r.y, cumDateWidth, cumRowHeight, cumAbnormal)
+				if cell.Interpretation != labs.InterpretationUnknown {
+					value += " " + string(cell.Interpretation)
+				}
+				value = FitText(HelveticaBold, cumFontSize, cumDateWidth-2*cumCellPadding, value)
+				r.page.TextRight(x+cumDateWidth-cumCellPadding, base, HelveticaBold, cumFontSize, cumAbnormalTxt, value)
+			} else {
+				value = FitText(Helvetica, cumFontSize, cumDateWidth-2*cumCellPadding, value)
+				r.page.TextRight(x+cumDateWidth-cumCellPadding, base, Helvetica, cumFontSize, Black, value)
+			}
+		}
+		x += cumDateWidth
+	}
+
+	r.y += cumRowHeight
+	r.page.Line(cumMargin, r.y, x, r.y, 0.3, cumGridColor)
+}
+
+func (r *cumulativeLayout) text(x, width, base float64, font Font, color Color, s string) {
+	r.page.Text(x+cumCellPadding, base, font, cumFontSize, color, FitText(font, cumFontSize, width-2*cumCellPadding, s))
+}
+
+// ensureSpace quebra a página quando as próximas linhas não cabem,
+// repetindo o cabeçalho das colunas.
+func (r *cumulativeLayout) ensureSpace(rows int, dates []time.Time) {
+	if r.y+float64(rows)*cumRowHeight <= r.doc.Height()-cumMargin-cumFooterSpace {
+		return
+	}
+	r.newPage()
+	r.columnHeader(dates)
+}
+
+func (r *cumulativeLayout) legend() {
+	r.y += 12
+	if r.y > r.doc.Height()-cumMargin-cumFooterSpace {
+		return
+	}
+	r.page.Text(cumMargin, r.y, Helvetica, 7.5, cumGray,
+		"Valores fora da referência em destaque: L baixo, H alto, LL/HH crítico, A alterado. "+
+			"Valores convertidos para a unidade da linha; no mesmo dia, vale o resultado mais recente.")
+}
+
+// footers numera as páginas depois que o total é conhecido.
+func (r *cumulativeLayout) footers() {
+	y := r.doc.Height() - cumMargin + 10
+	generated := "Gerado em " + r.report.GeneratedAt.Format("02/01/2006 15:04") + " (UTC)"
+	for i, page := range r.pages {
+		page.Text(cumMargin, y, Helvetica, 7.5, cumGray, generated)
+		page.TextRight(r.doc.Width()-cumMargin, y, Helvetica, 7.5, cumGray,
+			fmt.Sprintf("Página %d de %d", i+1, len(r.pages)))
+	}
+}
+
+func hasCells(cells []*labs.CumulativeCell) bool {
+	for _, c := range cells {
+		if c != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func formatBirth(p *patient.Patient, at time.Time) string {
+	if p.BirthDate.IsZero() {
+		return "não informado"
+	}
+	return fmt.Sprintf("%s (%d anos)", p.BirthDate.Format("02/01/2006"), labs.AgeInYears(p.BirthDate, at))
+}
+
+func formatPeriod(from, to *time.Time) string {
+	switch {
+	case from != nil && to != nil:
+		return from.Format("02/01/2006") + " a " + to.Format("02/01/2006")
+	case from != nil:
+		return "desde " + from.Format("02/01/2006")
+	case to != nil:
+		return "até " + to.Format("02/01/2006")
+	default:
+		return "todo o histórico"
+	}
+}
+
+func genderLabel(g demographics.Gender) string {
+	switch g {
+	case demographics.GenderMale:
+		return "Masculino"
+	case demographics.GenderFemale:
+		return "Feminino"
+	case demographics.GenderOther:
+		return "Outro"
+	default:
+		return ""
+	}
+}
+
+func formatCPF(cpf string) string {
+	digits := demographics.CleanDigits(cpf)
+	if len(digits) != 11 {
+		return ""
+	}
+	return digits[:3] + "." + digits[3:6] + "." + digits[6:9] + "-" + digits[9:]
+}
@@ -0,0 +1,115 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/patient"
+
+	pdfreader "github.com/ledongthuc/pdf"
+)
+
+// pageTexts lê o PDF gerado com o mesmo parser usado na extração de laudos.
+func pageTexts(t *testing.T, content []byte) []string {
+	t.Helper()
+	reader, err := pdfreader.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("invalid pdf: %v", err)
+	}
+	var pages []string
+	for i := 1; i <= reader.NumPage(); i++ {
+		rows, err := reader.Page(i).GetTextByRow()
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		var b strings.Builder
+		for _, row := range rows {
+			for _, text := range row.Content {
+				b.WriteString(text.S)
+			}
+			b.WriteByte('\n')
+		}
+		pages = append(pages, b.String())
+	}
+	return pages
+}
+
+func TestCumulativeRenderer(t *testing.T) {
+	generated := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	p := &patient.Patient{
+		FullName:  "João Conceição",
+		CPF:       "12345678901",
+		BirthDate: time.Date(1980, 5, 20, 0, 0, 0, 0, time.UTC),
+		Gender:    demographics.GenderMale,
+	}
+
+	// 12 datas não cabem numa página: a tabela continua na seguinte.
+	var items []labs.LabResultItemTimeline
+	for i := 0; i < 12; i++ {
+		at := time.Date(2025, time.Month(12-i), 10, 0, 0, 0, 0, time.UTC)
+		value, unit, interp := "95", "mg/dL", labs.InterpretationNormal
+		if i == 0 {
+			value, interp = "126", labs.InterpretationHigh
+		}
+		items = append(items, labs.LabResultItemTimeline{
+			CollectedAt: &at, TestName: "Bioquímica", ParameterName: "Glicose",
+			ResultValue: &value, ResultUnit: &unit, Interpretation: interp,
+		})
+	}
+
+	content, err := NewCumulativeRenderer().RenderCumulative(context.Background(), document.CumulativeReport{
+		Patient:     p,
+		Table:       labs.BuildCumulativeTable(items),
+		GeneratedAt: generated,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("%PDF-1.4")) {
+		t.Fatalf("expected a pdf header, got %q", content[:16])
+	}
+
+	pages := pageTexts(t, content)
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+	for _, want := range []string{"João Conceição", "123.456.789-01", "Bioquímica", "Glicose", "10/01/2025", "Página 1 de 2"} {
+		if !strings.Contains(pages[0], want) {
+			t.Errorf("expected %q on page 1, got:\n%s", want, pages[0])
+		}
+	}
+	for _, want := range []string{"126 H", "10/12/2025", "Página 2 de 2"} {
+		if !strings.Contains(pages[1], want) {
+			t.Errorf("expected %q on page 2, got:\n%s", want, pages[1])
+		}
+	}
+}
+
+func TestCumulativeRendererEmpty(t *testing.T) {
+	content, err := NewCumulativeRenderer().RenderCumulative(context.Background(), document.CumulativeReport{
+		Patient:     &patient.Patient{FullName: "Ana"},
+		GeneratedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages := pageTexts(t, content); len(pages) != 1 || !strings.Contains(pages[0], "Nenhum resultado") {
+		t.Fatalf("expected a single page without results, got %q", pages)
+	}
+}
+
+func TestFitText(t *testing.T) {
+	if got := FitText(Helvetica, 8, 100, "Glicose"); got != "Glicose" {
+		t.Fatalf("expected text to fit, got %q", got)
+	}
+	got := FitText(Helvetica, 8, 40, "Hemoglobina glicada")
+	if !strings.HasSuffix(got, "…") || TextWidth(Helvetica, 8, got) > 40 {
+		t.Fatalf("expected truncated text within 40pt, got %q", got)
+	}
+}
@@ -0,0 +1,183 @@
+// internal/infrastructure/pdf/document.go
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Tamanho A4 em pontos (1/72 pol.).
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Color é uma cor RGB com componentes de 0 a 1.
+type Color struct{ R, G, B float64 }
+
+var Black = Color{0, 0, 0}
+
+// Document monta um PDF 1.4 mínimo: páginas com texto nas fontes padrão
+// Helvetica (WinAnsiEncoding), retângulos e linhas. As coordenadas da API
+// partem do canto superior esquerdo da página, com y crescendo para baixo.
+type Document struct {
+	width, height float64
+	title         string
+	createdAt     time.Time
+	pages         []*Page
+}
+
+// Page acumula os operadores de desenho de uma página.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// New cria um documento com páginas do tamanho informado (em pontos).
+func New(width, height float64, title string, createdAt time.Time) *Document {
+	return &Document{width: width, height: height, title: title, createdAt: createdAt}
+}
+
+// Width e Height são as dimensões da página.
+func (d *Document) Width() float64  { return d.width }
+func (d *Document) Height() float64 { return d.height }
+
+// PageCount é o número de páginas já criadas.
+func (d *Document) PageCount() int { return len(d.pages) }
+
+// AddPage acrescenta uma página em branco e a devolve.
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text escreve s com a linha de base em (x, y).
+func (p *Page) Text(x, y float64, font Font, size float64, color Color, s string) {
+	fmt.Fprintf(&p.content, "BT %s rg /%s %s Tf %s %s Td ",
+		rgb(color), font.resourceName(), num(size), num(x), num(p.doc.height-y))
+	writeString(&p.content, encodeWinAnsi(s))
+	p.content.WriteString(" Tj ET\n")
+}
+
+// TextRight escreve s alinhado à direita em x.
+func (p *Page) TextRight(x, y float64, font Font, size float64, color Color, s string) {
+	p.Text(x-TextWidth(font, size, s), y, font, size, color, s)
+}
+
+// FillRect pinta o retângulo com canto superior esquerdo em (x, y).
+func (p *Page) FillRect(x, y, w, h float64, color Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n",
+		rgb(color), num(x), num(p.doc.height-y-h), num(w), num(h))
+}
+
+// Line traça uma linha de (x1, y1) a (x2, y2).
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s m %s %s l S\n",
+		rgb(color), num(width), num(x1), num(p.doc.height-y1), num(x2), num(p.doc.height-y2))
+}
+
+// Bytes serializa o documento. Sem páginas, gera uma página em branco,
+// já que um PDF precisa de ao menos uma.
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	// Objetos fixos: 1 catálogo, 2 árvore de páginas, 3 e 4 fontes, 5 info.
+	// Cada página ocupa dois objetos a partir do 6: a página e seu conteúdo.
+	object := func(body func()) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n", len(offsets))
+		body()
+		buf.WriteString("\nendobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	object(func() { buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>") })
+	object(func() {
+		buf.WriteString("<< /Type /Pages /Kids [")
+		for i := range d.pages {
+			fmt.Fprintf(&buf, " %d 0 R", 6+2*i)
+		}
+		fmt.Fprintf(&buf, " ] /Count %d >>", len(d.pages))
+	})
+	for _, font := range []Font{Helvetica, HelveticaBold} {
+		object(func() {
+			fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.baseName())
+		})
+	}
+	object(func() {
+		buf.WriteString("<< /Title ")
+		writeString(&buf, encodeWinAnsi(d.title))
+		buf.WriteString(" /Producer (Sonnda) /CreationDate ")
+		writeString(&buf, []byte(d.createdAt.UTC().Format("D:20060102150405Z")))
+		buf.WriteString(" >>")
+	})
+
+	for i, page := range d.pages {
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		if _, err := zw.Write(page.content.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		object(func() {
+			fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				num(d.width), num(d.height), 7+2*i)
+		})
+		object(func() {
+			fmt.Fprintf(&buf, "<< /Length %d /Filter /FlateDecode >>\nstream\n", stream.Len())
+			buf.Write(stream.Bytes())
+			buf.WriteString("\nendstream")
+		})
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xref)
+
+	return buf.Bytes(), nil
+}
+
+// writeString grava uma string literal do PDF; bytes fora do ASCII vão
+// como escape octal para o arquivo continuar legível.
+func writeString(buf *bytes.Buffer, s []byte) {
+	buf.WriteByte('(')
+	for _, b := range s {
+		switch {
+		case b == '(' || b == ')' || b == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(b)
+		case b < 0x20 || b > 0x7e:
+			fmt.Fprintf(buf, "\\%03o", b)
+		default:
+			buf.WriteByte(b)
+		}
+	}
+	buf.WriteByte(')')
+}
+
+func rgb(c Color) string {
+	return num(c.R) + " " + num(c.G) + " " + num(c.B)
+}
+
+// num formata coordenadas com até duas casas, sem zeros à direita.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
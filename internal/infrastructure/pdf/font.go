@@ -0,0 +1,132 @@
+// internal/infrastructure/pdf/font.go
+package pdf
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Font é uma das fontes padrão do PDF (não precisam ser embutidas).
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+func (f Font) baseName() string {
+	if f == HelveticaBold {
+		return "Helvetica-Bold"
+	}
+	return "Helvetica"
+}
+
+func (f Font) resourceName() string {
+	if f == HelveticaBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// Larguras (em milésimos do corpo) dos caracteres 32..126, das métricas AFM
+// da Helvetica. Letras acentuadas usam a largura da letra base.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // espaço a /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0-9
+	278, 278, 584, 584, 584, 556, 1015, // : a @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A-M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N-Z
+	278, 278, 278, 469, 556, 333, // [ a `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a-m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n-z
+	334, 260, 334, 584, // { a ~
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+	333, 333, 584, 584, 584, 611, 975,
+	722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833,
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+	333, 278, 333, 584, 556, 333,
+	556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889,
+	611, 611, 611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500,
+	389, 280, 389, 584,
+}
+
+// Caracteres do Windows-1252 fora do Latin-1 (0x80-0x9F) mais comuns.
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// Letra base de cada caractere Latin-1 de 0xC0 a 0xFF.
+const latin1Base = "AAAAAAACEEEEIIIIDNOOOOOxOUUUUYPs" + "aaaaaaaceeeeiiiidnooooo/ouuuuypy"
+
+// encodeWinAnsi converte o texto para WinAnsiEncoding. Caracteres sem
+// representação viram "?".
+func encodeWinAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		case r == '\t' || r == '\n' || r == '\r':
+			out = append(out, ' ')
+		default:
+			if b, ok := winAnsiExtra[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// TextWidth devolve a largura do texto em pontos.
+func TextWidth(font Font, size float64, s string) float64 {
+	widths := &helveticaWidths
+	if font == HelveticaBold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, r := range s {
+		total += runeWidth(widths, r)
+	}
+	return float64(total) * size / 1000
+}
+
+func runeWidth(widths *[95]int, r rune) int {
+	if r >= 32 && r <= 126 {
+		return widths[r-32]
+	}
+	// Letra acentuada: mede a letra base (á -> a).
+	if r >= 0xc0 && r <= 0xff {
+		return widths[latin1Base[r-0xc0]-32]
+	}
+	switch r {
+	case '…', '—', '€':
+		return 1000
+	case 'º', 'ª', '°':
+		return 370
+	}
+	return 556
+}
+
+// FitText corta o texto com reticências para caber na largura.
+func FitText(font Font, size, width float64, s string) string {
+	s = strings.TrimSpace(s)
+	if TextWidth(font, size, s) <= width {
+		return s
+	}
+	ellipsis := TextWidth(font, size, "…")
+	for len(s) > 0 {
+		_, n := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-n]
+		if TextWidth(font, size, s)+ellipsis <= width {
+			return strings.TrimSpace(s) + "…"
+		}
+	}
+	return ""
+}
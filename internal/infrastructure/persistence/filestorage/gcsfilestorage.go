@@ -26,7 +26,10 @@ type GCSObjectStorage struct {
 	projectID  string
 }
 
-var _ domainstorage.FileStorageService = (*GCSObjectStorage)(nil)
+var (
+	_ domainstorage.FileStorageService = (*GCSObjectStorage)(nil)
+	_ domainstorage.FileReader         = (*GCSObjectStorage)(nil)
+)
 
 func NewGCSObjectStorage(
 	ctx context.Context,
@@ -111,6 +114,17 @@ func (a *GCSObjectStorage) GetSignedURL(
 	return url, nil
 }
 
+// Open implements [domainstorage.FileReader].
+func (a *GCSObjectStorage) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	objectName := extractObjectName(uri, a.bucketName)
+
+	reader, err := a.client.Bucket(a.bucketName).Object(objectName).NewReader(ctx)
+	if err != nil {
+		return nil, wrapStorageError("falha ao ler arquivo", "gcs.open", fmt.Errorf("uri=%s: %w", uri, err))
+	}
+	return reader, nil
+}
+
 // Close libera o client subjacente do GCS.
 func (a *GCSObjectStorage) Close() error {
 	if a.client != nil {
@@ -0,0 +1,254 @@
+// internal/infrastructure/persistence/filestorage/localfilestorage.go
+// Implementação em disco do FileStorageService, pensada para desenvolvimento
+// offline e testes. As URIs seguem o formato file:///caminho/absoluto e os
+// links de download são assinados com HMAC e servidos pelo FilesHandler.
+
+package filestorage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+// LocalFilesRoute é o prefixo HTTP que serve os links assinados.
+const LocalFilesRoute = "/files"
+
+type LocalFileStorage struct {
+	baseDir    string
+	publicURL  string
+	signingKey []byte
+	now        func() time.Time
+}
+
+var (
+	_ domainstorage.FileStorageService = (*LocalFileStorage)(nil)
+	_ domainstorage.FileReader         = (*LocalFileStorage)(nil)
+)
+
+func NewLocalFileStorage(baseDir, publicURL string, signingKey []byte) (*LocalFileStorage, error) {
+	if len(signingKey) == 0 {
+		return nil, &apperr.AppError{
+			Kind:    apperr.INFRA_STORAGE_ERROR,
+			Message: "falha ao inicializar storage",
+			Cause:   errors.New("local.new: signing key is required"),
+		}
+	}
+
+	absDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, wrapLocalStorageError("falha ao inicializar storage", "local.new.abs", err)
+	}
+	if err := os.MkdirAll(absDir, 0o750); err != nil {
+		return nil, wrapLocalStorageError("falha ao inicializar storage", "local.new.mkdir", err)
+	}
+
+	return &LocalFileStorage{
+		baseDir:    absDir,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+		signingKey: signingKey,
+		now:        time.Now,
+	}, nil
+}
+
+func (s *LocalFileStorage) Upload(
+	ctx context.Context,
+	file io.Reader,
+	objectName string,
+	contentType string) (string, error) {
+
+	fullPath, err := s.resolve(objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", wrapLocalStorageError("falha ao enviar arquivo", "local.upload", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+		return "", wrapLocalStorageError("falha ao enviar arquivo", "local.upload.mkdir", err)
+	}
+
+	// Escreve em arquivo temporário e renomeia, para nunca expor arquivo parcial.
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return "", wrapLocalStorageError("falha ao enviar arquivo", "local.upload.create", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := io.Copy(tmp, file); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return "", wrapLocalStorageError("falha ao enviar arquivo", "local.upload.copy", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return "", wrapLocalStorageError("falha ao enviar arquivo", "local.upload.close", err)
+	}
+	if err := os.Rename(tmpName, fullPath); err != nil {
+		_ = os.Remove(tmpName)
+		return "", wrapLocalStorageError("falha ao enviar arquivo", "local.upload.rename", err)
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(fullPath)}).String(), nil
+}
+
+func (s *LocalFileStorage) Delete(ctx context.Context, uri string) error {
+	objectName, err := s.objectNameFromURI(uri)
+	if err != nil {
+		return err
+	}
+	fullPath, err := s.resolve(objectName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(fullPath); err != nil {
+		return wrapLocalStorageError("falha ao remover arquivo", "local.delete", fmt.Errorf("uri=%s: %w", uri, err))
+	}
+	return nil
+}
+
+// GetSignedURL devolve um link para o FilesHandler válido por expirationMinutes.
+func (s *LocalFileStorage) GetSignedURL(
+	ctx context.Context,
+	uri string,
+	expirationMinutes int,
+) (string, error) {
+	objectName, err := s.objectNameFromURI(uri)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.resolve(objectName); err != nil {
+		return "", err
+	}
+
+	expires := s.now().Add(time.Duration(expirationMinutes) * time.Minute).Unix()
+	expiresStr := strconv.FormatInt(expires, 10)
+
+	q := url.Values{}
+	q.Set("expires", expiresStr)
+	q.Set("signature", s.sign(objectName, expiresStr))
+
+	return s.publicURL + LocalFilesRoute + "/" + (&url.URL{Path: objectName}).EscapedPath() + "?" + q.Encode(), nil
+}
+
+// Open implements [domainstorage.FileReader].
+func (s *LocalFileStorage) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	objectName, err := s.objectNameFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	fullPath, err := s.resolve(objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return nil, wrapLocalStorageError("falha ao ler arquivo", "local.open", err)
+	}
+	return f, nil
+}
+
+// OpenSigned valida a assinatura de um link gerado por GetSignedURL e abre o arquivo.
+func (s *LocalFileStorage) OpenSigned(objectName, expires, signature string) (*os.File, error) {
+	objectName = strings.TrimPrefix(objectName, "/")
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(s.sign(objectName, expires))) {
+		return nil, &apperr.AppError{
+			Kind:    apperr.ACCESS_DENIED,
+			Message: "link de download inválido",
+		}
+	}
+	if s.now().Unix() > expiresAt {
+		return nil, &apperr.AppError{
+			Kind:    apperr.ACCESS_DENIED,
+			Message: "link de download expirado",
+		}
+	}
+
+	fullPath, err := s.resolve(objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return nil, wrapLocalStorageError("falha ao ler arquivo", "local.open", err)
+	}
+	return f, nil
+}
+
+func (s *LocalFileStorage) sign(objectName, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(objectName))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// resolve converte o object name em caminho absoluto, impedindo escapar de baseDir.
+func (s *LocalFileStorage) resolve(objectName string) (string, error) {
+	cleaned := path.Clean("/" + strings.TrimSpace(objectName))
+	if cleaned == "/" {
+		return "", &apperr.AppError{
+			Kind:    apperr.VALIDATION_FAILED,
+			Message: "nome de arquivo inválido",
+		}
+	}
+	return filepath.Join(s.baseDir, filepath.FromSlash(cleaned)), nil
+}
+
+// objectNameFromURI aceita tanto a URI file:// devolvida por Upload quanto o object name puro.
+func (s *LocalFileStorage) objectNameFromURI(uri string) (string, error) {
+	if !strings.HasPrefix(uri, "file://") {
+		return uri, nil
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", &apperr.AppError{
+			Kind:    apperr.VALIDATION_FAILED,
+			Message: "uri de arquivo inválida",
+			Cause:   err,
+		}
+	}
+
+	rel, err := filepath.Rel(s.baseDir, filepath.FromSlash(u.Path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", &apperr.AppError{
+			Kind:    apperr.VALIDATION_FAILED,
+			Message: "uri de arquivo fora do storage",
+			Cause:   fmt.Errorf("uri=%s", uri),
+		}
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func wrapLocalStorageError(message, op string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "arquivo não encontrado",
+			Cause:   fmt.Errorf("%s: %w", op, err),
+		}
+	}
+	return wrapStorageError(message, op, err)
+}
@@ -0,0 +1,109 @@
+package filestorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+)
+
+func newTestLocalStorage(t *testing.T) *LocalFileStorage {
+	t.Helper()
+	s, err := NewLocalFileStorage(t.TempDir(), "http://localhost:8080/", []byte("test-key"))
+	if err != nil {
+		t.Fatalf("NewLocalFileStorage: %v", err)
+	}
+	return s
+}
+
+func signedParams(t *testing.T, signedURL string) (string, string, string) {
+	t.Helper()
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+	return strings.TrimPrefix(u.Path, LocalFilesRoute), u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+func TestLocalFileStorage_UploadSignedDownloadDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+
+	uri, err := s.Upload(ctx, strings.NewReader("%PDF-1.4"), "patients/p1/lab-reports/a.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if !strings.HasPrefix(uri, "file://") {
+		t.Fatalf("expected file:// uri, got %q", uri)
+	}
+
+	signedURL, err := s.GetSignedURL(ctx, uri, 5)
+	if err != nil {
+		t.Fatalf("GetSignedURL: %v", err)
+	}
+	if !strings.HasPrefix(signedURL, "http://localhost:8080/files/patients/p1/lab-reports/a.pdf?") {
+		t.Fatalf("unexpected signed url %q", signedURL)
+	}
+
+	objectName, expires, signature := signedParams(t, signedURL)
+	f, err := s.OpenSigned(objectName, expires, signature)
+	if err != nil {
+		t.Fatalf("OpenSigned: %v", err)
+	}
+	content, _ := io.ReadAll(f)
+	_ = f.Close()
+	if string(content) != "%PDF-1.4" {
+		t.Fatalf("unexpected content %q", content)
+	}
+
+	if err := s.Delete(ctx, uri); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = s.OpenSigned(objectName, expires, signature)
+	assertKind(t, err, apperr.NOT_FOUND)
+}
+
+func TestLocalFileStorage_OpenSigned_RejectsTamperedAndExpired(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+
+	uri, err := s.Upload(ctx, strings.NewReader("x"), "a.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	signedURL, err := s.GetSignedURL(ctx, uri, 5)
+	if err != nil {
+		t.Fatalf("GetSignedURL: %v", err)
+	}
+	objectName, expires, signature := signedParams(t, signedURL)
+
+	_, err = s.OpenSigned("b.pdf", expires, signature)
+	assertKind(t, err, apperr.ACCESS_DENIED)
+
+	_, err = s.OpenSigned(objectName, expires+"0", signature)
+	assertKind(t, err, apperr.ACCESS_DENIED)
+
+	s.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+	_, err = s.OpenSigned(objectName, expires, signature)
+	assertKind(t, err, apperr.ACCESS_DENIED)
+}
+
+func TestLocalFileStorage_DeleteOutsideBaseDir(t *testing.T) {
+	s := newTestLocalStorage(t)
+
+	err := s.Delete(context.Background(), "file:///etc/passwd")
+	assertKind(t, err, apperr.VALIDATION_FAILED)
+}
+
+func assertKind(t *testing.T, err error, kind apperr.ErrorKind) {
+	t.Helper()
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) || appErr.Kind != kind {
+		t.Fatalf("expected %s, got %v", kind, err)
+	}
+}
@@ -0,0 +1,154 @@
+// internal/infrastructure/persistence/filestorage/s3filestorage.go
+// Implementação S3-compatível (AWS S3, MinIO) do FileStorageService.
+// As URIs seguem o formato s3://bucket/objeto.
+
+package filestorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	domainstorage "github.com/gabrielgcmr/sonnda/internal/domain/storage"
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type S3Config struct {
+	// Host[:porta] sem esquema (ex.: s3.amazonaws.com, minio.clinica.local:9000).
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+	// Path-style (endpoint/bucket/objeto) é o que o MinIO espera.
+	ForcePathStyle bool
+}
+
+type S3ObjectStorage struct {
+	client     *minio.Client
+	bucketName string
+}
+
+var (
+	_ domainstorage.FileStorageService = (*S3ObjectStorage)(nil)
+	_ domainstorage.FileReader         = (*S3ObjectStorage)(nil)
+)
+
+func NewS3ObjectStorage(cfg S3Config) (*S3ObjectStorage, error) {
+	lookup := minio.BucketLookupAuto
+	if cfg.ForcePathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, wrapS3Error("falha ao inicializar storage", "s3.new_client", err)
+	}
+
+	return &S3ObjectStorage{
+		client:     client,
+		bucketName: cfg.Bucket,
+	}, nil
+}
+
+func (a *S3ObjectStorage) Upload(
+	ctx context.Context,
+	file io.Reader,
+	objectName string,
+	contentType string) (string, error) {
+
+	opts := minio.PutObjectOptions{}
+	if contentType != "" {
+		opts.ContentType = contentType
+	}
+
+	// Tamanho -1: o SDK faz multipart upload em partes conforme lê o stream.
+	if _, err := a.client.PutObject(ctx, a.bucketName, objectName, file, -1, opts); err != nil {
+		return "", wrapS3Error("falha ao enviar arquivo", "s3.upload", err)
+	}
+
+	return fmt.Sprintf("s3://%s/%s", a.bucketName, objectName), nil
+}
+
+func (a *S3ObjectStorage) Delete(ctx context.Context, uri string) error {
+	objectName := extractS3ObjectName(uri, a.bucketName)
+
+	// RemoveObject não falha para objeto inexistente; checa antes para manter o NOT_FOUND do GCS.
+	if _, err := a.client.StatObject(ctx, a.bucketName, objectName, minio.StatObjectOptions{}); err != nil {
+		return wrapS3Error("falha ao remover arquivo", "s3.delete.stat", fmt.Errorf("uri=%s: %w", uri, err))
+	}
+	if err := a.client.RemoveObject(ctx, a.bucketName, objectName, minio.RemoveObjectOptions{}); err != nil {
+		return wrapS3Error("falha ao remover arquivo", "s3.delete", fmt.Errorf("uri=%s: %w", uri, err))
+	}
+	return nil
+}
+
+func (a *S3ObjectStorage) GetSignedURL(
+	ctx context.Context,
+	uri string,
+	expirationMinutes int,
+) (string, error) {
+	objectName := extractS3ObjectName(uri, a.bucketName)
+
+	url, err := a.client.PresignedGetObject(ctx, a.bucketName, objectName, time.Duration(expirationMinutes)*time.Minute, nil)
+	if err != nil {
+		return "", wrapS3Error("falha ao gerar URL assinada", "s3.signed_url", err)
+	}
+
+	return url.String(), nil
+}
+
+// Open implements [domainstorage.FileReader].
+func (a *S3ObjectStorage) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	objectName := extractS3ObjectName(uri, a.bucketName)
+
+	obj, err := a.client.GetObject(ctx, a.bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, wrapS3Error("falha ao ler arquivo", "s3.open", err)
+	}
+	// GetObject é lazy; o Stat força a requisição e revela objeto inexistente.
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		return nil, wrapS3Error("falha ao ler arquivo", "s3.open.stat", fmt.Errorf("uri=%s: %w", uri, err))
+	}
+	return obj, nil
+}
+
+// extractS3ObjectName extrai o nome do objeto da URI
+// Ex: "s3://bucket-name/path/to/file.pdf" -> "path/to/file.pdf"
+func extractS3ObjectName(uri, bucketName string) string {
+	prefix := fmt.Sprintf("s3://%s/", bucketName)
+	if name, ok := strings.CutPrefix(uri, prefix); ok && name != "" {
+		return name
+	}
+	// Se não tiver o prefixo, assume que já é o object name
+	return uri
+}
+
+func wrapS3Error(message, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	resp := minio.ToErrorResponse(err)
+	if resp.StatusCode == http.StatusNotFound || resp.Code == "NoSuchKey" {
+		return &apperr.AppError{
+			Kind:    apperr.NOT_FOUND,
+			Message: "arquivo não encontrado",
+			Cause:   fmt.Errorf("%s: %w", op, err),
+		}
+	}
+	return wrapStorageError(message, op, err)
+}
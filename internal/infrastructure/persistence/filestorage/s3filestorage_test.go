@@ -0,0 +1,35 @@
+package filestorage
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gabrielgcmr/sonnda/internal/kernel/apperr"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestExtractS3ObjectName(t *testing.T) {
+	cases := map[string]string{
+		"s3://labs/patients/p1/a.pdf":  "patients/p1/a.pdf",
+		"patients/p1/a.pdf":            "patients/p1/a.pdf",
+		"s3://other/patients/p1/a.pdf": "s3://other/patients/p1/a.pdf",
+	}
+	for uri, want := range cases {
+		if got := extractS3ObjectName(uri, "labs"); got != want {
+			t.Errorf("extractS3ObjectName(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
+
+func TestWrapS3Error_NoSuchKeyIsNotFound(t *testing.T) {
+	err := wrapS3Error("falha ao ler arquivo", "s3.open", minio.ErrorResponse{
+		Code:       "NoSuchKey",
+		StatusCode: http.StatusNotFound,
+	})
+	assertKind(t, err, apperr.NOT_FOUND)
+
+	err = wrapS3Error("falha ao ler arquivo", "s3.open", errors.New("boom"))
+	assertKind(t, err, apperr.INFRA_STORAGE_ERROR)
+}
@@ -0,0 +1,147 @@
+// internal/infrastructure/persistence/postgres/repo/analytes.go
+package repo
+
+import (
+	"context"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	postgress "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres"
+	labsqlc "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres/sqlc/generated/lab"
+)
+
+type AnalytesRepository struct {
+	client  *postgress.Client
+	queries *labsqlc.Queries
+}
+
+var _ repository.Analytes = (*AnalytesRepository)(nil)
+
+func NewAnalytesRepository(client *postgress.Client) repository.Analytes {
+	return &AnalytesRepository{
+		client:  client,
+		queries: labsqlc.New(client.Pool()),
+	}
+}
+
+// List implements [repository.Analytes].
+func (r *AnalytesRepository) List(ctx context.Context) ([]labs.Analyte, error) {
+	rows, err := r.queries.ListLabAnalytes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	synonyms, err := r.queries.ListLabAnalyteSynonyms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := make(map[string][]string, len(rows))
+	for _, s := range synonyms {
+		byCode[s.AnalyteCode] = append(byCode[s.AnalyteCode], s.Name)
+	}
+
+	out := make([]labs.Analyte, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, labs.Analyte{
+			Code:        row.Code,
+			LOINC:       FromPgTextToNullableString(row.LoincCode),
+			DisplayName: row.DisplayName,
+			DefaultUnit: FromPgTextToNullableString(row.DefaultUnit),
+			Synonyms:    byCode[row.Code],
+		})
+	}
+	return out, nil
+}
+
+// FindByCode implements [repository.Analytes].
+func (r *AnalytesRepository) FindByCode(ctx context.Context, code string) (*labs.Analyte, error) {
+	row, err := r.queries.GetLabAnalyteByCode(ctx, code)
+	if err != nil {
+		if IsPgNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	synonyms, err := r.queries.ListLabAnalyteSynonyms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	analyte := &labs.Analyte{
+		Code:        row.Code,
+		LOINC:       FromPgTextToNullableString(row.LoincCode),
+		DisplayName: row.DisplayName,
+		DefaultUnit: FromPgTextToNullableString(row.DefaultUnit),
+	}
+	for _, s := range synonyms {
+		if s.AnalyteCode == code {
+			analyte.Synonyms = append(analyte.Synonyms, s.Name)
+		}
+	}
+	return analyte, nil
+}
+
+// FindSynonymOwner implements [repository.Analytes].
+func (r *AnalytesRepository) FindSynonymOwner(ctx context.Context, nameKey string) (string, error) {
+	row, err := r.queries.GetLabAnalyteSynonymByKey(ctx, nameKey)
+	if err != nil {
+		if IsPgNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return row.AnalyteCode, nil
+}
+
+// AddSynonym implements [repository.Analytes].
+func (r *AnalytesRepository) AddSynonym(ctx context.Context, analyteCode, name string, relinks []labs.AnalyteRelink) error {
+	tx, err := r.client.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	q := r.queries.WithTx(tx)
+	err = q.CreateLabAnalyteSynonym(ctx, labsqlc.CreateLabAnalyteSynonymParams{
+		NameKey:     labs.NormalizeAnalyteName(name),
+		AnalyteCode: analyteCode,
+		Name:        name,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := relinkParameterNames(ctx, q, relinks); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// RemoveSynonym implements [repository.Analytes].
+func (r *AnalytesRepository) RemoveSynonym(ctx context.Context, analyteCode, name string, relinks []labs.AnalyteRelink) (bool, error) {
+	tx, err := r.client.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	q := r.queries.WithTx(tx)
+	n, err := q.DeleteLabAnalyteSynonym(ctx, labsqlc.DeleteLabAnalyteSynonymParams{
+		NameKey:     labs.NormalizeAnalyteName(name),
+		AnalyteCode: analyteCode,
+	})
+	if err != nil || n == 0 {
+		return false, err
+	}
+	if _, err := relinkParameterNames(ctx, q, relinks); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,279 @@
+// internal/infrastructure/persistence/postgres/repo/critical_alerts.go
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/demographics"
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	postgress "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres"
+	labsqlc "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres/sqlc/generated/lab"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CriticalAlertsRepository struct {
+	client  *postgress.Client
+	queries *labsqlc.Queries
+}
+
+var _ repository.CriticalAlerts = (*CriticalAlertsRepository)(nil)
+
+func NewCriticalAlertsRepository(client *postgress.Client) repository.CriticalAlerts {
+	return &CriticalAlertsRepository{
+		client:  client,
+		queries: labsqlc.New(client.Pool()),
+	}
+}
+
+// ListRules implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) ListRules(ctx context.Context) ([]labs.CriticalRule, error) {
+	rows, err := r.queries.ListLabCriticalRules(ctx)
+	if err != nil {
+		return nil, errors.Join(ErrRepositoryFailure, err)
+	}
+
+	rules := make([]labs.CriticalRule, 0, len(rows))
+	for _, row := range rows {
+		rule := labs.CriticalRule{
+			ID:          row.ID,
+			AnalyteCode: row.AnalyteCode,
+			MinAge:      fromPgInt4ToNullableInt(row.MinAge),
+			MaxAge:      fromPgInt4ToNullableInt(row.MaxAge),
+			Low:         FromPgFloat8ToNullableFloat64(row.Low),
+			High:        FromPgFloat8ToNullableFloat64(row.High),
+			Unit:        row.Unit,
+			CreatedAt:   row.CreatedAt.Time,
+			CreatedBy:   FromPgUUIDToNullableUUID(row.CreatedBy),
+		}
+		if row.Sex.Valid {
+			sex := demographics.Gender(row.Sex.String)
+			rule.Sex = &sex
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// CreateRule implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) CreateRule(ctx context.Context, rule *labs.CriticalRule) error {
+	sex := pgtype.Text{}
+	if rule.Sex != nil {
+		sex = pgtype.Text{String: string(*rule.Sex), Valid: true}
+	}
+
+	err := r.queries.CreateLabCriticalRule(ctx, labsqlc.CreateLabCriticalRuleParams{
+		ID:          rule.ID,
+		AnalyteCode: rule.AnalyteCode,
+		Sex:         sex,
+		MinAge:      fromNullableIntToPgInt4(rule.MinAge),
+		MaxAge:      fromNullableIntToPgInt4(rule.MaxAge),
+		Low:         FromNullableFloat64ToPgFloat8(rule.Low),
+		High:        FromNullableFloat64ToPgFloat8(rule.High),
+		Unit:        rule.Unit,
+		CreatedAt:   FromRequiredTimestamptzToPgTimestamptz(rule.CreatedAt),
+		CreatedBy:   FromNullableUUIDToPgUUID(rule.CreatedBy),
+	})
+	if err != nil {
+		if IsUniqueViolationError(err) {
+			return labs.ErrCriticalRuleExists
+		}
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	return nil
+}
+
+// DeleteRule implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) DeleteRule(ctx context.Context, id, deletedBy uuid.UUID, at time.Time) (bool, error) {
+	n, err := r.queries.DeleteLabCriticalRule(ctx, labsqlc.DeleteLabCriticalRuleParams{
+		ID:        id,
+		DeletedAt: FromRequiredTimestamptzToPgTimestamptz(at),
+		DeletedBy: FromNullableUUIDToPgUUID(&deletedBy),
+	})
+	if err != nil {
+		return false, errors.Join(ErrRepositoryFailure, err)
+	}
+	return n > 0, nil
+}
+
+// FlagItems implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) FlagItems(ctx context.Context, alerts []labs.CriticalAlert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	tx, err := r.client.BeginTx(ctx)
+	if err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	q := r.queries.WithTx(tx)
+	for _, alert := range alerts {
+		err := q.SetLabResultItemInterpretation(ctx, labsqlc.SetLabResultItemInterpretationParams{
+			ID:             alert.ItemID,
+			Interpretation: pgtype.Text{String: string(alert.Interpretation), Valid: true},
+		})
+		if err != nil {
+			return errors.Join(ErrRepositoryFailure, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	return nil
+}
+
+// ListAlertedItemIDs implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) ListAlertedItemIDs(ctx context.Context, reportID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := r.queries.ListLabCriticalAlertItemIDsByReport(ctx, reportID)
+	if err != nil {
+		return nil, errors.Join(ErrRepositoryFailure, err)
+	}
+	return ids, nil
+}
+
+// CreateAlerts implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) CreateAlerts(ctx context.Context, alerts []labs.CriticalAlert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	tx, err := r.client.BeginTx(ctx)
+	if err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	q := r.queries.WithTx(tx)
+	for _, alert := range alerts {
+		ruleID := alert.RuleID
+		err := q.CreateLabCriticalAlert(ctx, labsqlc.CreateLabCriticalAlertParams{
+			ID:              alert.ID,
+			LabReportID:     alert.ReportID,
+			PatientID:       alert.PatientID,
+			LabResultItemID: alert.ItemID,
+			RuleID:          FromNullableUUIDToPgUUID(&ruleID),
+			AnalyteCode:     alert.AnalyteCode,
+			ParameterName:   alert.ParameterName,
+			Value:           alert.Value,
+			LimitValue:      alert.Limit,
+			Unit:            alert.Unit,
+			Interpretation:  string(alert.Interpretation),
+			CreatedAt:       FromRequiredTimestamptzToPgTimestamptz(alert.CreatedAt),
+		})
+		if err != nil {
+			return errors.Join(ErrRepositoryFailure, err)
+		}
+
+		for _, recipient := range alert.Recipients {
+			err := q.CreateLabCriticalAlertRecipient(ctx, labsqlc.CreateLabCriticalAlertRecipientParams{
+				AlertID: alert.ID,
+				UserID:  recipient.UserID,
+			})
+			if err != nil {
+				return errors.Join(ErrRepositoryFailure, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	return nil
+}
+
+// MarkNotified implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) MarkNotified(ctx context.Context, alertID, userID uuid.UUID, at time.Time) error {
+	err := r.queries.MarkLabCriticalAlertNotified(ctx, labsqlc.MarkLabCriticalAlertNotifiedParams{
+		AlertID:    alertID,
+		UserID:     userID,
+		NotifiedAt: FromRequiredTimestamptzToPgTimestamptz(at),
+	})
+	if err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	return nil
+}
+
+// ListByRecipient implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) ListByRecipient(
+	ctx context.Context,
+	userID uuid.UUID,
+	onlyOpen bool,
+	limit, offset int,
+) ([]labs.CriticalAlert, error) {
+	rows, err := r.queries.ListLabCriticalAlertsByRecipient(ctx, labsqlc.ListLabCriticalAlertsByRecipientParams{
+		UserID:   userID,
+		OnlyOpen: onlyOpen,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return nil, errors.Join(ErrRepositoryFailure, err)
+	}
+
+	alerts := make([]labs.CriticalAlert, 0, len(rows))
+	for _, row := range rows {
+		alert := labs.CriticalAlert{
+			ID:             row.ID,
+			ReportID:       row.LabReportID,
+			PatientID:      row.PatientID,
+			ItemID:         row.LabResultItemID,
+			AnalyteCode:    row.AnalyteCode,
+			ParameterName:  row.ParameterName,
+			Value:          row.Value,
+			Limit:          row.LimitValue,
+			Unit:           row.Unit,
+			Interpretation: labs.Interpretation(row.Interpretation),
+			CreatedAt:      row.CreatedAt.Time,
+			Recipients: []labs.AlertRecipient{{
+				UserID:         userID,
+				NotifiedAt:     FromPgTimestamptzToNullableTimestamptz(row.NotifiedAt),
+				AcknowledgedAt: FromPgTimestamptzToNullableTimestamptz(row.AcknowledgedAt),
+			}},
+		}
+		if ruleID := FromPgUUIDToNullableUUID(row.RuleID); ruleID != nil {
+			alert.RuleID = *ruleID
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// Acknowledge implements [repository.CriticalAlerts].
+func (r *CriticalAlertsRepository) Acknowledge(ctx context.Context, alertID, userID uuid.UUID, at time.Time) (bool, error) {
+	n, err := r.queries.AcknowledgeLabCriticalAlert(ctx, labsqlc.AcknowledgeLabCriticalAlertParams{
+		AcknowledgedAt: FromRequiredTimestamptzToPgTimestamptz(at),
+		AlertID:        alertID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return false, errors.Join(ErrRepositoryFailure, err)
+	}
+	return n > 0, nil
+}
+
+func fromNullableIntToPgInt4(v *int) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*v), Valid: true}
+}
+
+func fromPgInt4ToNullableInt(v pgtype.Int4) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int32)
+	return &n
+}
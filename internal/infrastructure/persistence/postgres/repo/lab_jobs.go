@@ -0,0 +1,157 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
+	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
+	postgress "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres"
+	labsqlc "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres/sqlc/generated/lab"
+
+	"github.com/google/uuid"
+)
+
+type LabJobsRepository struct {
+	client  *postgress.Client
+	queries *labsqlc.Queries
+}
+
+var _ repository.LabJobs = (*LabJobsRepository)(nil)
+
+func NewLabJobsRepository(client *postgress.Client) repository.LabJobs {
+	return &LabJobsRepository{
+		client:  client,
+		queries: labsqlc.New(client.Pool()),
+	}
+}
+
+// Create implements [repository.LabJobs].
+func (r *LabJobsRepository) Create(ctx context.Context, job *labs.ProcessingJob) error {
+	if job == nil {
+		return ErrRepositoryFailure
+	}
+
+	row, err := r.queries.CreateLabProcessingJob(ctx, labsqlc.CreateLabProcessingJobParams{
+		ID:               job.ID,
+		PatientID:        job.PatientID,
+		UploadedByUserID: job.UploadedBy,
+		DocumentUri:      job.DocumentURI,
+		MimeType:         job.MimeType,
+		ContentSha256:    FromNullableStringToPgText(job.ContentHash),
+		IdentityOverride: job.IdentityOverride,
+	})
+	if err != nil {
+		return err
+	}
+
+	job.Status = labs.JobStatus(row.Status)
+	job.CreatedAt = row.CreatedAt.Time
+	job.UpdatedAt = row.UpdatedAt.Time
+	return nil
+}
+
+// FindByID implements [repository.LabJobs].
+func (r *LabJobsRepository) FindByID(ctx context.Context, jobID uuid.UUID) (*labs.ProcessingJob, error) {
+	row, err := r.queries.GetLabProcessingJobByID(ctx, jobID)
+	if err != nil {
+		if IsPgNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mapLabProcessingJob(row), nil
+}
+
+// FindActiveIDByContentHash implements [repository.LabJobs].
+func (r *LabJobsRepository) FindActiveIDByContentHash(ctx context.Context, patientID uuid.UUID, contentHash string) (*uuid.UUID, error) {
+	id, err := r.queries.FindActiveLabProcessingJobIDByContentHash(ctx, labsqlc.FindActiveLabProcessingJobIDByContentHashParams{
+		PatientID:     patientID,
+		ContentSha256: FromRequiredStringToPgText(contentHash),
+	})
+	if err != nil {
+		if IsPgNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &id, nil
+}
+
+// ClaimNext implements [repository.LabJobs].
+func (r *LabJobsRepository) ClaimNext(ctx context.Context) (*labs.ProcessingJob, error) {
+	row, err := r.queries.ClaimNextLabProcessingJob(ctx)
+	if err != nil {
+		if IsPgNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mapLabProcessingJob(row), nil
+}
+
+// MarkSucceeded implements [repository.LabJobs].
+func (r *LabJobsRepository) MarkSucceeded(ctx context.Context, jobID uuid.UUID, attempt int, reportID uuid.UUID) (bool, error) {
+	rows, err := r.queries.MarkLabProcessingJobSucceeded(ctx, labsqlc.MarkLabProcessingJobSucceededParams{
+		ID:          jobID,
+		LabReportID: FromNullableUUIDToPgUUID(&reportID),
+		Attempts:    int32(attempt),
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// MarkFailed implements [repository.LabJobs].
+func (r *LabJobsRepository) MarkFailed(ctx context.Context, jobID uuid.UUID, attempt int, errorCode, errorMessage string) (bool, error) {
+	rows, err := r.queries.MarkLabProcessingJobFailed(ctx, labsqlc.MarkLabProcessingJobFailedParams{
+		ID:           jobID,
+		ErrorCode:    FromRequiredStringToPgText(errorCode),
+		ErrorMessage: FromRequiredStringToPgText(errorMessage),
+		Attempts:     int32(attempt),
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// RequeueStale implements [repository.LabJobs].
+func (r *LabJobsRepository) RequeueStale(ctx context.Context, startedBefore time.Time, maxAttempts int) (int64, error) {
+	return r.queries.RequeueStaleLabProcessingJobs(ctx, labsqlc.RequeueStaleLabProcessingJobsParams{
+		StartedBefore: FromRequiredTimestamptzToPgTimestamptz(startedBefore),
+		MaxAttempts:   int32(maxAttempts),
+	})
+}
+
+// FailStale implements [repository.LabJobs].
+func (r *LabJobsRepository) FailStale(ctx context.Context, startedBefore time.Time, maxAttempts int, errorCode, errorMessage string) (int64, error) {
+	return r.queries.FailStaleLabProcessingJobs(ctx, labsqlc.FailStaleLabProcessingJobsParams{
+		ErrorCode:     FromRequiredStringToPgText(errorCode),
+		ErrorMessage:  FromRequiredStringToPgText(errorMessage),
+		StartedBefore: FromRequiredTimestamptzToPgTimestamptz(startedBefore),
+		MaxAttempts:   int32(maxAttempts),
+	})
+}
+
+func mapLabProcessingJob(row labsqlc.LabProcessingJob) *labs.ProcessingJob {
+	return &labs.ProcessingJob{
+		ID:               row.ID,
+		PatientID:        row.PatientID,
+		UploadedBy:       row.UploadedByUserID,
+		DocumentURI:      row.DocumentUri,
+		MimeType:         row.MimeType,
+		ContentHash:      FromPgTextToNullableString(row.ContentSha256),
+		IdentityOverride: row.IdentityOverride,
+		Status:           labs.JobStatus(row.Status),
+		Attempts:         int(row.Attempts),
+		LabReportID:      FromPgUUIDToNullableUUID(row.LabReportID),
+		ErrorCode:        FromPgTextToNullableString(row.ErrorCode),
+		ErrorMessage:     FromPgTextToNullableString(row.ErrorMessage),
+		CreatedAt:        row.CreatedAt.Time,
+		UpdatedAt:        row.UpdatedAt.Time,
+		StartedAt:        FromPgTimestamptzToNullableTimestamptz(row.StartedAt),
+		FinishedAt:       FromPgTimestamptzToNullableTimestamptz(row.FinishedAt),
+	}
+}
@@ -4,6 +4,11 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gabrielgcmr/sonnda/internal/domain/entity/labs"
 	"github.com/gabrielgcmr/sonnda/internal/domain/repository"
@@ -11,6 +16,8 @@ import (
 	labsqlc "github.com/gabrielgcmr/sonnda/internal/infrastructure/persistence/postgres/sqlc/generated/lab"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type LabsRepository struct {
@@ -33,6 +40,11 @@ func (l *LabsRepository) Create(ctx context.Context, report *labs.LabReport) err
 		return ErrRepositoryFailure
 	}
 
+	fieldConfidence, err := marshalFieldConfidence(report.FieldConfidence)
+	if err != nil {
+		return err
+	}
+
 	// Create the lab report
 	reportRow, err := l.queries.CreateLabReport(ctx, labsqlc.CreateLabReportParams{
 		ID:                report.ID,
@@ -48,41 +60,106 @@ func (l *LabsRepository) Create(ctx context.Context, report *labs.LabReport) err
 		RawText:           FromNullableStringToPgText(report.RawText),
 		UploadedByUserID:  report.UploadedBy,
 		Fingerprint:       FromNullableStringToPgText(report.Fingerprint),
+		DocumentUri:       FromNullableStringToPgText(report.DocumentURI),
+		MimeType:          FromNullableStringToPgText(report.MimeType),
+		ContentSha256:     FromNullableStringToPgText(report.ContentHash),
+		IdentityStatus:    identityStatusToPgText(report.IdentityStatus),
+		IdentityScore:     FromNullableFloat64ToPgFloat8(report.IdentityScore),
+		FieldConfidence:   fieldConfidence,
+		ReviewStatus:      reviewStatusToPgText(report.ReviewStatus),
+		Source:            string(report.Source),
+		Status:            string(report.Status),
 	})
 	if err != nil {
+		if isContentHashViolation(err) {
+			return labs.ErrDuplicateDocument
+		}
 		return err
 	}
 
 	// Create test results and their items
 	for _, tr := range report.TestResults {
-		_, err := l.queries.CreateLabResult(ctx, labsqlc.CreateLabResultParams{
-			ID:          tr.ID,
-			LabReportID: reportRow.ID,
-			TestName:    tr.TestName,
-			Material:    FromNullableStringToPgText(tr.Material),
-			Method:      FromNullableStringToPgText(tr.Method),
-			CollectedAt: FromNullableTimestamptzToPgTimestamptz(tr.CollectedAt),
-			ReleaseAt:   FromNullableTimestamptzToPgTimestamptz(tr.ReleaseAt),
-		})
-		if err != nil {
+		if err := createLabResult(ctx, l.queries, reportRow.ID, tr); err != nil {
 			return err
 		}
+	}
 
-		for _, item := range tr.Items {
-			_, err := l.queries.CreateLabResultItem(ctx, labsqlc.CreateLabResultItemParams{
-				ID:            item.ID,
-				LabResultID:   item.LabResultID,
-				ParameterName: item.ParameterName,
-				ResultValue:   FromNullableStringToPgText(item.ResultValue),
-				ResultUnit:    FromNullableStringToPgText(item.ResultUnit),
-				ReferenceText: FromNullableStringToPgText(item.ReferenceText),
-			})
-			if err != nil {
-				return err
-			}
+	return nil
+}
+
+// ReplaceDerived implements [repository.Labs].
+// Apaga os itens derivados do laudo e grava os novos na mesma transação.
+func (l *LabsRepository) ReplaceDerived(ctx context.Context, reportID uuid.UUID, result *labs.LabResult) error {
+	tx, err := l.client.BeginTx(ctx)
+	if err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	q := l.queries.WithTx(tx)
+	if err := q.DeleteDerivedLabItemsByReportID(ctx, reportID); err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	if result != nil && len(result.Items) > 0 {
+		if err := createLabResult(ctx, q, reportID, *result); err != nil {
+			return errors.Join(ErrRepositoryFailure, err)
 		}
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	return nil
+}
+
+// createLabResult grava o resultado e seus itens.
+func createLabResult(ctx context.Context, q *labsqlc.Queries, reportID uuid.UUID, tr labs.LabResult) error {
+	_, err := q.CreateLabResult(ctx, labsqlc.CreateLabResultParams{
+		ID:          tr.ID,
+		LabReportID: reportID,
+		TestName:    tr.TestName,
+		Material:    FromNullableStringToPgText(tr.Material),
+		Method:      FromNullableStringToPgText(tr.Method),
+		CollectedAt: FromNullableTimestamptzToPgTimestamptz(tr.CollectedAt),
+		ReleaseAt:   FromNullableTimestamptzToPgTimestamptz(tr.ReleaseAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range tr.Items {
+		itemConfidence, err := marshalFieldConfidence(item.FieldConfidence)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateLabResultItem(ctx, labsqlc.CreateLabResultItemParams{
+			ID:               item.ID,
+			LabResultID:      item.LabResultID,
+			ParameterName:    item.ParameterName,
+			ResultValue:      FromNullableStringToPgText(item.ResultValue),
+			ResultUnit:       FromNullableStringToPgText(item.ResultUnit),
+			ReferenceText:    FromNullableStringToPgText(item.ReferenceText),
+			NumericValue:     FromNullableFloat64ToPgFloat8(item.NumericValue),
+			Comparator:       FromOptionalStringToPgText(string(item.Comparator)),
+			QualitativeValue: FromNullableStringToPgText(item.QualitativeValue),
+			ReferenceLow:     FromNullableFloat64ToPgFloat8(item.ReferenceLow),
+			ReferenceHigh:    FromNullableFloat64ToPgFloat8(item.ReferenceHigh),
+			Interpretation:   FromOptionalStringToPgText(string(item.Interpretation)),
+			AnalyteCode:      FromNullableStringToPgText(item.AnalyteCode),
+			UcumUnit:         FromNullableStringToPgText(item.UCUMUnit),
+			Confidence:       FromNullableFloat64ToPgFloat8(item.Confidence),
+			FieldConfidence:  itemConfidence,
+			Confirmed:        item.Confirmed,
+			Derived:          item.Derived,
+			Formula:          FromOptionalStringToPgText(string(item.Formula)),
+			AnalyteLink:      FromOptionalStringToPgText(string(item.AnalyteLink)),
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -114,6 +191,29 @@ func (l *LabsRepository) ExistsBySignature(ctx context.Context, patientID uuid.U
 	return exists, err
 }
 
+// FindIDByContentHash implements [repository.Labs].
+func (l *LabsRepository) FindIDByContentHash(ctx context.Context, patientID uuid.UUID, contentHash string) (*uuid.UUID, error) {
+	id, err := l.queries.FindLabReportIDByContentHash(ctx, labsqlc.FindLabReportIDByContentHashParams{
+		PatientID:     patientID,
+		ContentSha256: FromRequiredStringToPgText(contentHash),
+	})
+	if err != nil {
+		if IsPgNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &id, nil
+}
+
+// isContentHashViolation detecta a corrida entre dois uploads do mesmo
+// arquivo que passaram juntos pela checagem de duplicidade.
+func isContentHashViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" &&
+		pgErr.ConstraintName == "idx_lab_reports_content_sha256"
+}
+
 // FindByID implements [repository.LabsRepository].
 func (l *LabsRepository) FindByID(ctx context.Context, reportID uuid.UUID) (*labs.LabReport, error) {
 	reportRow, err := l.queries.GetLabReportByID(ctx, reportID)
@@ -139,13 +239,31 @@ func (l *LabsRepository) FindByID(ctx context.Context, reportID uuid.UUID) (*lab
 
 		var items []labs.LabResultItem
 		for _, itemRow := range itemsRows {
+			itemConfidence, err := unmarshalFieldConfidence(itemRow.FieldConfidence)
+			if err != nil {
+				return nil, err
+			}
 			items = append(items, labs.LabResultItem{
-				ID:            itemRow.ID,
-				LabResultID:   itemRow.LabResultID,
-				ParameterName: itemRow.ParameterName,
-				ResultValue:   FromPgTextToNullableString(itemRow.ResultValue),
-				ResultUnit:    FromPgTextToNullableString(itemRow.ResultUnit),
-				ReferenceText: FromPgTextToNullableString(itemRow.ReferenceText),
+				ID:               itemRow.ID,
+				LabResultID:      itemRow.LabResultID,
+				ParameterName:    itemRow.ParameterName,
+				ResultValue:      FromPgTextToNullableString(itemRow.ResultValue),
+				ResultUnit:       FromPgTextToNullableString(itemRow.ResultUnit),
+				ReferenceText:    FromPgTextToNullableString(itemRow.ReferenceText),
+				NumericValue:     FromPgFloat8ToNullableFloat64(itemRow.NumericValue),
+				Comparator:       labs.Comparator(itemRow.Comparator.String),
+				QualitativeValue: FromPgTextToNullableString(itemRow.QualitativeValue),
+				ReferenceLow:     FromPgFloat8ToNullableFloat64(itemRow.ReferenceLow),
+				ReferenceHigh:    FromPgFloat8ToNullableFloat64(itemRow.ReferenceHigh),
+				Interpretation:   labs.Interpretation(itemRow.Interpretation.String),
+				AnalyteCode:      FromPgTextToNullableString(itemRow.AnalyteCode),
+				UCUMUnit:         FromPgTextToNullableString(itemRow.UcumUnit),
+				Confidence:       FromPgFloat8ToNullableFloat64(itemRow.Confidence),
+				FieldConfidence:  itemConfidence,
+				Confirmed:        itemRow.Confirmed,
+				Derived:          itemRow.Derived,
+				Formula:          labs.Formula(itemRow.Formula.String),
+				AnalyteLink:      labs.AnalyteLink(itemRow.AnalyteLink.String),
 			})
 		}
 
@@ -161,6 +279,11 @@ func (l *LabsRepository) FindByID(ctx context.Context, reportID uuid.UUID) (*lab
 		})
 	}
 
+	fieldConfidence, err := unmarshalFieldConfidence(reportRow.FieldConfidence)
+	if err != nil {
+		return nil, err
+	}
+
 	return &labs.LabReport{
 		ID:                reportRow.ID,
 		PatientID:         reportRow.PatientID,
@@ -174,6 +297,18 @@ func (l *LabsRepository) FindByID(ctx context.Context, reportID uuid.UUID) (*lab
 		ReportDate:        FromPgTimestamptzToNullableTimestamptz(reportRow.ReportDate),
 		Fingerprint:       FromPgTextToNullableString(reportRow.Fingerprint),
 		RawText:           FromPgTextToNullableString(reportRow.RawText),
+		DocumentURI:       FromPgTextToNullableString(reportRow.DocumentUri),
+		MimeType:          FromPgTextToNullableString(reportRow.MimeType),
+		ContentHash:       FromPgTextToNullableString(reportRow.ContentSha256),
+		IdentityStatus:    identityStatusFromPgText(reportRow.IdentityStatus),
+		IdentityScore:     FromPgFloat8ToNullableFloat64(reportRow.IdentityScore),
+		FieldConfidence:   fieldConfidence,
+		ReviewStatus:      reviewStatusFromPgText(reportRow.ReviewStatus),
+		ReviewedBy:        FromPgUUIDToNullableUUID(reportRow.ReviewedByUserID),
+		ReviewedAt:        FromPgTimestamptzToNullableTimestamptz(reportRow.ReviewedAt),
+		Source:            labs.ReportSource(reportRow.Source),
+		Status:            labs.ReportStatus(reportRow.Status),
+		Version:           int(reportRow.Version),
 		TestResults:       testResults,
 		CreatedAt:         reportRow.CreatedAt.Time,
 		UpdatedAt:         reportRow.UpdatedAt.Time,
@@ -181,11 +316,199 @@ func (l *LabsRepository) FindByID(ctx context.Context, reportID uuid.UUID) (*lab
 	}, nil
 }
 
+// Amend implements [repository.Labs].
+// Persiste o estado corrigido do laudo e a revisão na mesma transação; o
+// laudo só é atualizado se a versão gravada ainda for a anterior à revisão.
+func (l *LabsRepository) Amend(ctx context.Context, report *labs.LabReport, revision *labs.Revision) error {
+	if report == nil || revision == nil {
+		return ErrRepositoryFailure
+	}
+
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+
+	tx, err := l.client.BeginTx(ctx)
+	if err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	q := l.queries.WithTx(tx)
+
+	updated, err := q.AmendLabReport(ctx, labsqlc.AmendLabReportParams{
+		PatientName:       FromNullableStringToPgText(report.PatientName),
+		LabName:           FromNullableStringToPgText(report.LabName),
+		LabPhone:          FromNullableStringToPgText(report.LabPhone),
+		InsuranceProvider: FromNullableStringToPgText(report.InsuranceProvider),
+		RequestingDoctor:  FromNullableStringToPgText(report.RequestingDoctor),
+		TechnicalManager:  FromNullableStringToPgText(report.TechnicalManager),
+		ReportDate:        FromNullableTimestamptzToPgTimestamptz(report.ReportDate),
+		Status:            string(report.Status),
+		ReviewStatus:      reviewStatusToPgText(report.ReviewStatus),
+		ReviewedByUserID:  FromNullableUUIDToPgUUID(report.ReviewedBy),
+		ReviewedAt:        FromNullableTimestamptzToPgTimestamptz(report.ReviewedAt),
+		Version:           int32(report.Version),
+		UpdatedAt:         FromRequiredTimestamptzToPgTimestamptz(report.UpdatedAt),
+		ID:                report.ID,
+		ExpectedVersion:   int32(report.Version - 1),
+	})
+	if err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	if updated == 0 {
+		return labs.ErrVersionConflict
+	}
+
+	for _, tr := range report.TestResults {
+		err := q.UpdateLabResult(ctx, labsqlc.UpdateLabResultParams{
+			ID:          tr.ID,
+			TestName:    tr.TestName,
+			Material:    FromNullableStringToPgText(tr.Material),
+			Method:      FromNullableStringToPgText(tr.Method),
+			CollectedAt: FromNullableTimestamptzToPgTimestamptz(tr.CollectedAt),
+			ReleaseAt:   FromNullableTimestamptzToPgTimestamptz(tr.ReleaseAt),
+		})
+		if err != nil {
+			return errors.Join(ErrRepositoryFailure, err)
+		}
+
+		for _, item := range tr.Items {
+			err := q.UpdateLabResultItem(ctx, labsqlc.UpdateLabResultItemParams{
+				ID:               item.ID,
+				ParameterName:    item.ParameterName,
+				ResultValue:      FromNullableStringToPgText(item.ResultValue),
+				ResultUnit:       FromNullableStringToPgText(item.ResultUnit),
+				ReferenceText:    FromNullableStringToPgText(item.ReferenceText),
+				NumericValue:     FromNullableFloat64ToPgFloat8(item.NumericValue),
+				Comparator:       FromOptionalStringToPgText(string(item.Comparator)),
+				QualitativeValue: FromNullableStringToPgText(item.QualitativeValue),
+				ReferenceLow:     FromNullableFloat64ToPgFloat8(item.ReferenceLow),
+				ReferenceHigh:    FromNullableFloat64ToPgFloat8(item.ReferenceHigh),
+				Interpretation:   FromOptionalStringToPgText(string(item.Interpretation)),
+				AnalyteCode:      FromNullableStringToPgText(item.AnalyteCode),
+				UcumUnit:         FromNullableStringToPgText(item.UCUMUnit),
+				Confirmed:        item.Confirmed,
+				AnalyteLink:      FromOptionalStringToPgText(string(item.AnalyteLink)),
+			})
+			if err != nil {
+				return errors.Join(ErrRepositoryFailure, err)
+			}
+		}
+	}
+
+	err = q.CreateLabReportRevision(ctx, labsqlc.CreateLabReportRevisionParams{
+		ID:              revision.ID,
+		LabReportID:     revision.ReportID,
+		Version:         int32(revision.Version),
+		Status:          string(revision.Status),
+		ChangedByUserID: revision.ChangedBy,
+		ChangedAt:       FromRequiredTimestamptzToPgTimestamptz(revision.ChangedAt),
+		Reason:          FromNullableStringToPgText(revision.Reason),
+		Changes:         changes,
+	})
+	if err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Join(ErrRepositoryFailure, err)
+	}
+	return nil
+}
+
+// ListPendingReviews implements [repository.Labs].
+func (l *LabsRepository) ListPendingReviews(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]labs.ReviewQueueEntry, error) {
+	rows, err := l.queries.ListPendingLabReviews(ctx, labsqlc.ListPendingLabReviewsParams{
+		UserID: pgtype.UUID{Bytes: userID, Valid: true},
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]labs.ReviewQueueEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, labs.ReviewQueueEntry{
+			ReportID:     row.ID,
+			PatientID:    row.PatientID,
+			PatientName:  row.PatientName,
+			LabName:      FromPgTextToNullableString(row.LabName),
+			ReportDate:   FromPgTimestamptzToNullableTimestamptz(row.ReportDate),
+			CreatedAt:    row.CreatedAt.Time,
+			PendingItems: int(row.PendingItems),
+		})
+	}
+	return entries, nil
+}
+
+// SearchRawText implements [repository.Labs].
+func (l *LabsRepository) SearchRawText(ctx context.Context, patientID uuid.UUID, query string, limit int, offset int) ([]labs.SearchHit, error) {
+	rows, err := l.queries.SearchLabReportsByRawText(ctx, labsqlc.SearchLabReportsByRawTextParams{
+		Query:     query,
+		PatientID: patientID,
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]labs.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, labs.SearchHit{
+			ReportID:   row.ID,
+			LabName:    FromPgTextToNullableString(row.LabName),
+			ReportDate: FromPgTimestamptzToNullableTimestamptz(row.ReportDate),
+			Source:     labs.ReportSource(row.Source),
+			Status:     labs.ReportStatus(row.Status),
+			CreatedAt:  row.CreatedAt.Time,
+			Rank:       row.Rank,
+			Snippet:    row.Snippet,
+		})
+	}
+	return hits, nil
+}
+
+// ListRevisions implements [repository.Labs].
+func (l *LabsRepository) ListRevisions(ctx context.Context, reportID uuid.UUID) ([]labs.Revision, error) {
+	rows, err := l.queries.ListLabReportRevisions(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]labs.Revision, 0, len(rows))
+	for _, row := range rows {
+		var changes []labs.FieldChange
+		if err := json.Unmarshal(row.Changes, &changes); err != nil {
+			return nil, errors.Join(ErrRepositoryFailure, err)
+		}
+		revisions = append(revisions, labs.Revision{
+			ID:        row.ID,
+			ReportID:  row.LabReportID,
+			Version:   int(row.Version),
+			Status:    labs.ReportStatus(row.Status),
+			ChangedBy: row.ChangedByUserID,
+			ChangedAt: row.ChangedAt.Time,
+			Reason:    FromPgTextToNullableString(row.Reason),
+			Changes:   changes,
+		})
+	}
+	return revisions, nil
+}
+
 // ListItemsByPatientAndParameter implements [repository.LabsRepository].
-func (l *LabsRepository) ListItemsByPatientAndParameter(ctx context.Context, patientID uuid.UUID, parameterName string, limit int, offset int) ([]labs.LabResultItemTimeline, error) {
+func (l *LabsRepository) ListItemsByPatientAndParameter(ctx context.Context, patientID uuid.UUID, parameterName string, from, to *time.Time, limit int, offset int) ([]labs.LabResultItemTimeline, error) {
 	rows, err := l.queries.ListLabItemTimelineByPatientAndParameter(ctx, labsqlc.ListLabItemTimelineByPatientAndParameterParams{
 		PatientID:     patientID,
 		ParameterName: parameterName,
+		NameKey:       labs.NormalizeAnalyteName(parameterName),
+		FromTime:      FromNullableTimestamptzToPgTimestamptz(from),
+		ToTime:        FromNullableTimestamptzToPgTimestamptz(to),
 		Limit:         int32(limit),
 		Offset:        int32(offset),
 	})
@@ -193,23 +516,169 @@ func (l *LabsRepository) ListItemsByPatientAndParameter(ctx context.Context, pat
 		return nil, err
 	}
 
-	var items []labs.LabResultItemTimeline
+	items := make([]labs.LabResultItemTimeline, 0, len(rows))
 	for _, row := range rows {
-		items = append(items, labs.LabResultItemTimeline{
-			ReportID:      row.ReportID,
-			LabResultID:   row.LabResultID,
-			ItemID:        row.ItemID,
-			ReportDate:    FromPgTimestamptzToNullableTimestamptz(row.ReportDate),
-			TestName:      row.TestName,
-			ParameterName: row.ParameterName,
-			ResultValue:   FromPgTextToNullableString(row.ResultValue),
-			ResultUnit:    FromPgTextToNullableString(row.ResultUnit),
-		})
+		items = append(items, toTimelineItem(row))
+	}
+
+	return items, nil
+}
+
+// ListItemsByPatient implements [repository.LabsRepository].
+func (l *LabsRepository) ListItemsByPatient(ctx context.Context, patientID uuid.UUID, from, to *time.Time, limit int, offset int) ([]labs.LabResultItemTimeline, error) {
+	rows, err := l.queries.ListLabItemTimelineByPatient(ctx, labsqlc.ListLabItemTimelineByPatientParams{
+		PatientID: patientID,
+		FromTime:  FromNullableTimestamptzToPgTimestamptz(from),
+		ToTime:    FromNullableTimestamptzToPgTimestamptz(to),
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]labs.LabResultItemTimeline, 0, len(rows))
+	for _, row := range rows {
+		// As duas consultas devolvem as mesmas colunas.
+		items = append(items, toTimelineItem(labsqlc.ListLabItemTimelineByPatientAndParameterRow(row)))
 	}
 
 	return items, nil
 }
 
+// ListHistory implements [repository.LabsRepository].
+func (l *LabsRepository) ListHistory(ctx context.Context, patientID uuid.UUID, limit int, offset int) ([]labs.HistoryRow, error) {
+	rows, err := l.queries.ListLabHistoryByPatient(ctx, labsqlc.ListLabHistoryByPatientParams{
+		PatientID: patientID,
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]labs.HistoryRow, 0, len(rows))
+	for _, row := range rows {
+		history = append(history, labs.HistoryRow{
+			ReportID:    row.ReportID,
+			ReportDate:  FromPgTimestamptzToNullableTimestamptz(row.ReportDate),
+			LabName:     FromPgTextToNullableString(row.LabName),
+			Source:      labs.ReportSource(row.Source),
+			Status:      labs.ReportStatus(row.Status),
+			ResultID:    row.ResultID,
+			TestName:    row.TestName,
+			Material:    FromPgTextToNullableString(row.Material),
+			Method:      FromPgTextToNullableString(row.Method),
+			CollectedAt: FromPgTimestamptzToNullableTimestamptz(row.CollectedAt),
+			Item: labs.LabResultItem{
+				ID:               row.ItemID,
+				LabResultID:      row.ResultID,
+				ParameterName:    row.ParameterName,
+				ResultValue:      FromPgTextToNullableString(row.ResultValue),
+				ResultUnit:       FromPgTextToNullableString(row.ResultUnit),
+				ReferenceText:    FromPgTextToNullableString(row.ReferenceText),
+				NumericValue:     FromPgFloat8ToNullableFloat64(row.NumericValue),
+				Comparator:       labs.Comparator(row.Comparator.String),
+				QualitativeValue: FromPgTextToNullableString(row.QualitativeValue),
+				ReferenceLow:     FromPgFloat8ToNullableFloat64(row.ReferenceLow),
+				ReferenceHigh:    FromPgFloat8ToNullableFloat64(row.ReferenceHigh),
+				Interpretation:   labs.Interpretation(row.Interpretation.String),
+				AnalyteCode:      FromPgTextToNullableString(row.AnalyteCode),
+				UCUMUnit:         FromPgTextToNullableString(row.UcumUnit),
+				Derived:          row.Derived,
+				Formula:          labs.Formula(row.Formula.String),
+			},
+			CanonicalUnit: FromPgTextToNullableString(row.CanonicalUnit),
+		})
+	}
+
+	return history, nil
+}
+
+func toTimelineItem(row labsqlc.ListLabItemTimelineByPatientAndParameterRow) labs.LabResultItemTimeline {
+	return labs.LabResultItemTimeline{
+		ReportID:       row.ReportID,
+		LabResultID:    row.LabResultID,
+		ItemID:         row.ItemID,
+		ReportDate:     FromPgTimestamptzToNullableTimestamptz(row.ReportDate),
+		CollectedAt:    FromPgTimestamptzToNullableTimestamptz(row.CollectedAt),
+		TestName:       row.TestName,
+		ParameterName:  row.ParameterName,
+		AnalyteCode:    FromPgTextToNullableString(row.AnalyteCode),
+		ResultValue:    FromPgTextToNullableString(row.ResultValue),
+		ResultUnit:     FromPgTextToNullableString(row.ResultUnit),
+		ReferenceText:  FromPgTextToNullableString(row.ReferenceText),
+		NumericValue:   FromPgFloat8ToNullableFloat64(row.NumericValue),
+		UCUMUnit:       FromPgTextToNullableString(row.UcumUnit),
+		ReferenceLow:   FromPgFloat8ToNullableFloat64(row.ReferenceLow),
+		ReferenceHigh:  FromPgFloat8ToNullableFloat64(row.ReferenceHigh),
+		Interpretation: labs.Interpretation(row.Interpretation.String),
+		Derived:        row.Derived,
+		Formula:        labs.Formula(row.Formula.String),
+		CanonicalUnit:  FromPgTextToNullableString(row.CanonicalUnit),
+	}
+}
+
+// ListDistinctParameterNames implements [repository.LabsRepository].
+func (l *LabsRepository) ListDistinctParameterNames(ctx context.Context) ([]string, error) {
+	return l.queries.ListDistinctLabParameterNames(ctx)
+}
+
+// ListParameterNamesByKey implements [repository.LabsRepository].
+func (l *LabsRepository) ListParameterNamesByKey(ctx context.Context, nameKey string) ([]string, error) {
+	if nameKey == "" {
+		return nil, nil
+	}
+	candidates, err := l.queries.ListLabParameterNamesLike(ctx, "%"+strings.ReplaceAll(nameKey, " ", "%")+"%")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, name := range candidates {
+		if slices.Contains(labs.MatchKeys(name), nameKey) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Relink implements [repository.LabsRepository].
+func (l *LabsRepository) Relink(ctx context.Context, relinks []labs.AnalyteRelink) (int64, error) {
+	tx, err := l.client.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	n, err := relinkParameterNames(ctx, l.queries.WithTx(tx), relinks)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// relinkParameterNames aplica os vínculos dentro da transação de q.
+func relinkParameterNames(ctx context.Context, q *labsqlc.Queries, relinks []labs.AnalyteRelink) (int64, error) {
+	var total int64
+	for _, relink := range relinks {
+		n, err := q.SetLabResultItemsAnalyteByParameterName(ctx, labsqlc.SetLabResultItemsAnalyteByParameterNameParams{
+			ParameterName: relink.ParameterName,
+			AnalyteCode:   FromNullableStringToPgText(relink.AnalyteCode),
+		})
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
 // ListLabs implements [repository.LabsRepository].
 func (l *LabsRepository) ListLabs(ctx context.Context, patientID uuid.UUID, limit int, offset int) ([]labs.LabReport, error) {
 	rows, err := l.queries.ListLabReportsByPatientID(ctx, labsqlc.ListLabReportsByPatientIDParams{
@@ -224,17 +693,69 @@ func (l *LabsRepository) ListLabs(ctx context.Context, patientID uuid.UUID, limi
 	var reports []labs.LabReport
 	for _, row := range rows {
 		reports = append(reports, labs.LabReport{
-			ID:          row.ID,
-			PatientID:   row.PatientID,
-			PatientName: FromPgTextToNullableString(row.PatientName),
-			LabName:     FromPgTextToNullableString(row.LabName),
-			ReportDate:  FromPgTimestamptzToNullableTimestamptz(row.ReportDate),
-			Fingerprint: FromPgTextToNullableString(row.Fingerprint),
-			CreatedAt:   row.CreatedAt.Time,
-			UpdatedAt:   row.UpdatedAt.Time,
-			UploadedBy:  row.UploadedByUserID,
+			ID:             row.ID,
+			PatientID:      row.PatientID,
+			PatientName:    FromPgTextToNullableString(row.PatientName),
+			LabName:        FromPgTextToNullableString(row.LabName),
+			ReportDate:     FromPgTimestamptzToNullableTimestamptz(row.ReportDate),
+			Fingerprint:    FromPgTextToNullableString(row.Fingerprint),
+			IdentityStatus: identityStatusFromPgText(row.IdentityStatus),
+			ReviewStatus:   reviewStatusFromPgText(row.ReviewStatus),
+			Status:         labs.ReportStatus(row.Status),
+			CreatedAt:      row.CreatedAt.Time,
+			UpdatedAt:      row.UpdatedAt.Time,
+			UploadedBy:     row.UploadedByUserID,
 		})
 	}
 
 	return reports, nil
 }
+
+func identityStatusToPgText(status *labs.IdentityStatus) pgtype.Text {
+	if status == nil {
+		return pgtype.Text{}
+	}
+	return FromRequiredStringToPgText(string(*status))
+}
+
+func identityStatusFromPgText(t pgtype.Text) *labs.IdentityStatus {
+	if !t.Valid {
+		return nil
+	}
+	status := labs.IdentityStatus(t.String)
+	return &status
+}
+
+func reviewStatusToPgText(status *labs.ReviewStatus) pgtype.Text {
+	if status == nil {
+		return pgtype.Text{}
+	}
+	return FromRequiredStringToPgText(string(*status))
+}
+
+func reviewStatusFromPgText(t pgtype.Text) *labs.ReviewStatus {
+	if !t.Valid {
+		return nil
+	}
+	status := labs.ReviewStatus(t.String)
+	return &status
+}
+
+// marshalFieldConfidence grava NULL quando o extrator não pontuou nada.
+func marshalFieldConfidence(c labs.FieldConfidence) ([]byte, error) {
+	if len(c) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+func unmarshalFieldConfidence(data []byte) (labs.FieldConfidence, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var c labs.FieldConfidence
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -26,6 +26,14 @@ func FromRequiredStringToPgText(s string) pgtype.Text {
 	return pgtype.Text{String: s, Valid: true}
 }
 
+// FromOptionalStringToPgText maps the empty string to NULL.
+func FromOptionalStringToPgText(s string) pgtype.Text {
+	if s == "" {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: s, Valid: true}
+}
+
 func FromPgTextToNullableString(t pgtype.Text) *string {
 	if !t.Valid {
 		return nil
@@ -40,6 +48,25 @@ func FromPgTextToRequiredString(t pgtype.Text) (string, error) {
 	return t.String, nil
 }
 
+/* ============================================================
+   Float8 conversions (*float64 <-> pgtype.Float8)
+   ============================================================ */
+
+func FromNullableFloat64ToPgFloat8(f *float64) pgtype.Float8 {
+	if f == nil {
+		return pgtype.Float8{Valid: false}
+	}
+	return pgtype.Float8{Float64: *f, Valid: true}
+}
+
+func FromPgFloat8ToNullableFloat64(f pgtype.Float8) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	v := f.Float64
+	return &v
+}
+
 /* ============================================================
    Date conversions (*time.Time <-> pgtype.Date)
    ============================================================ */
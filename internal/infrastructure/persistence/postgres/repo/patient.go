@@ -183,6 +183,32 @@ func (p *PatientRepository) FindByCPF(ctx context.Context, cpf string) (*patient
 	}, nil
 }
 
+// FindByCNS implements [repository.Patient].
+func (p *PatientRepository) FindByCNS(ctx context.Context, cns string) (*patient.Patient, error) {
+	row, err := p.queries.GetPatientByCNS(ctx, FromNullableStringToPgText(&cns))
+	if err != nil {
+		if IsPgNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Join(ErrRepositoryFailure, err)
+	}
+
+	return &patient.Patient{
+		ID:          row.ID,
+		OwnerUserID: FromPgUUIDToNullableUUID(row.OwnerUserID),
+		CPF:         row.Cpf,
+		CNS:         FromPgTextToNullableString(row.Cns),
+		FullName:    row.FullName,
+		BirthDate:   row.BirthDate.Time,
+		Gender:      demographics.Gender(row.Gender),
+		Race:        demographics.Race(row.Race),
+		AvatarURL:   row.AvatarUrl.String,
+		Phone:       FromPgTextToNullableString(row.Phone),
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}, nil
+}
+
 // FindByID implements [repository.Patient].
 func (p *PatientRepository) FindByID(ctx context.Context, id uuid.UUID) (*patient.Patient, error) {
 	row, err := p.queries.GetPatientByID(ctx, id)
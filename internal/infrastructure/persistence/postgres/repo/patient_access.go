@@ -104,3 +104,20 @@ func (p *PatientAccessRepository) Upsert(ctx context.Context, access *patientacc
 
 	return nil
 }
+
+// ListActiveGrantees implements [repository.PatientAccessRepo].
+func (p *PatientAccessRepository) ListActiveGrantees(ctx context.Context, patientID uuid.UUID, relation patientaccess.RelationshipType) ([]uuid.UUID, error) {
+	rows, err := p.queries.ListActiveGranteesByRelation(ctx, patientaccesssqlc.ListActiveGranteesByRelationParams{
+		PatientID:    pgtype.UUID{Bytes: patientID, Valid: true},
+		RelationType: string(relation),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list grantees: %w", err)
+	}
+
+	grantees := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		grantees = append(grantees, row.Bytes)
+	}
+	return grantees, nil
+}
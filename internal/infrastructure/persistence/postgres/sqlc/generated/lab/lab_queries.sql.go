@@ -12,6 +12,305 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acknowledgeLabCriticalAlert = `-- name: AcknowledgeLabCriticalAlert :execrows
+UPDATE lab_critical_alert_recipients
+SET acknowledged_at = COALESCE(acknowledged_at, $1)
+WHERE alert_id = $2
+  AND user_id = $3
+`
+
+type AcknowledgeLabCriticalAlertParams struct {
+	AcknowledgedAt pgtype.Timestamptz `json:"acknowledged_at"`
+	AlertID        uuid.UUID          `json:"alert_id"`
+	UserID         uuid.UUID          `json:"user_id"`
+}
+
+// Keeps the first acknowledgement; zero rows when the user is not a recipient.
+func (q *Queries) AcknowledgeLabCriticalAlert(ctx context.Context, arg AcknowledgeLabCriticalAlertParams) (int64, error) {
+	result, err := q.db.Exec(ctx, acknowledgeLabCriticalAlert, arg.AcknowledgedAt, arg.AlertID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const amendLabReport = `-- name: AmendLabReport :execrows
+
+UPDATE lab_reports
+SET patient_name       = $1,
+    lab_name           = $2,
+    lab_phone          = $3,
+    insurance_provider = $4,
+    requesting_doctor  = $5,
+    technical_manager  = $6,
+    report_date        = $7,
+    status             = $8,
+    review_status      = $9,
+    reviewed_by_user_id = $10,
+    reviewed_at        = $11,
+    version            = $12,
+    updated_at         = $13
+WHERE id = $14
+  AND version = $15
+`
+
+type AmendLabReportParams struct {
+	PatientName       pgtype.Text        `json:"patient_name"`
+	LabName           pgtype.Text        `json:"lab_name"`
+	LabPhone          pgtype.Text        `json:"lab_phone"`
+	InsuranceProvider pgtype.Text        `json:"insurance_provider"`
+	RequestingDoctor  pgtype.Text        `json:"requesting_doctor"`
+	TechnicalManager  pgtype.Text        `json:"technical_manager"`
+	ReportDate        pgtype.Timestamptz `json:"report_date"`
+	Status            string             `json:"status"`
+	ReviewStatus      pgtype.Text        `json:"review_status"`
+	ReviewedByUserID  pgtype.UUID        `json:"reviewed_by_user_id"`
+	ReviewedAt        pgtype.Timestamptz `json:"reviewed_at"`
+	Version           int32              `json:"version"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	ID                uuid.UUID          `json:"id"`
+	ExpectedVersion   int32              `json:"expected_version"`
+}
+
+// ============================================================
+// Amendments
+// ============================================================
+// Optimistic lock: only applies when the stored version is still the one read.
+func (q *Queries) AmendLabReport(ctx context.Context, arg AmendLabReportParams) (int64, error) {
+	result, err := q.db.Exec(ctx, amendLabReport,
+		arg.PatientName,
+		arg.LabName,
+		arg.LabPhone,
+		arg.InsuranceProvider,
+		arg.RequestingDoctor,
+		arg.TechnicalManager,
+		arg.ReportDate,
+		arg.Status,
+		arg.ReviewStatus,
+		arg.ReviewedByUserID,
+		arg.ReviewedAt,
+		arg.Version,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const claimNextLabProcessingJob = `-- name: ClaimNextLabProcessingJob :one
+UPDATE lab_processing_jobs
+SET
+    status     = 'running',
+    attempts   = attempts + 1,
+    started_at = now(),
+    updated_at = now()
+WHERE id = (
+    SELECT id
+    FROM lab_processing_jobs
+    WHERE status = 'queued'
+    ORDER BY created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING id, patient_id, uploaded_by_user_id, document_uri, mime_type, status, attempts, lab_report_id, error_code, error_message, created_at, updated_at, started_at, finished_at, content_sha256, identity_override
+`
+
+// Claims the oldest queued job. SKIP LOCKED lets several workers poll concurrently.
+func (q *Queries) ClaimNextLabProcessingJob(ctx context.Context) (LabProcessingJob, error) {
+	row := q.db.QueryRow(ctx, claimNextLabProcessingJob)
+	var i LabProcessingJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.UploadedByUserID,
+		&i.DocumentUri,
+		&i.MimeType,
+		&i.Status,
+		&i.Attempts,
+		&i.LabReportID,
+		&i.ErrorCode,
+		&i.ErrorMessage,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.ContentSha256,
+		&i.IdentityOverride,
+	)
+	return i, err
+}
+
+const createLabAnalyteSynonym = `-- name: CreateLabAnalyteSynonym :exec
+INSERT INTO lab_analyte_synonyms (name_key, analyte_code, name)
+VALUES ($1, $2, $3)
+`
+
+type CreateLabAnalyteSynonymParams struct {
+	NameKey     string `json:"name_key"`
+	AnalyteCode string `json:"analyte_code"`
+	Name        string `json:"name"`
+}
+
+func (q *Queries) CreateLabAnalyteSynonym(ctx context.Context, arg CreateLabAnalyteSynonymParams) error {
+	_, err := q.db.Exec(ctx, createLabAnalyteSynonym, arg.NameKey, arg.AnalyteCode, arg.Name)
+	return err
+}
+
+const createLabCriticalAlert = `-- name: CreateLabCriticalAlert :exec
+INSERT INTO lab_critical_alerts (
+    id, lab_report_id, patient_id, lab_result_item_id, rule_id, analyte_code,
+    parameter_name, value, limit_value, unit, interpretation, created_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+`
+
+type CreateLabCriticalAlertParams struct {
+	ID              uuid.UUID          `json:"id"`
+	LabReportID     uuid.UUID          `json:"lab_report_id"`
+	PatientID       uuid.UUID          `json:"patient_id"`
+	LabResultItemID uuid.UUID          `json:"lab_result_item_id"`
+	RuleID          pgtype.UUID        `json:"rule_id"`
+	AnalyteCode     string             `json:"analyte_code"`
+	ParameterName   string             `json:"parameter_name"`
+	Value           float64            `json:"value"`
+	LimitValue      float64            `json:"limit_value"`
+	Unit            string             `json:"unit"`
+	Interpretation  string             `json:"interpretation"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) CreateLabCriticalAlert(ctx context.Context, arg CreateLabCriticalAlertParams) error {
+	_, err := q.db.Exec(ctx, createLabCriticalAlert,
+		arg.ID,
+		arg.LabReportID,
+		arg.PatientID,
+		arg.LabResultItemID,
+		arg.RuleID,
+		arg.AnalyteCode,
+		arg.ParameterName,
+		arg.Value,
+		arg.LimitValue,
+		arg.Unit,
+		arg.Interpretation,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const createLabCriticalAlertRecipient = `-- name: CreateLabCriticalAlertRecipient :exec
+INSERT INTO lab_critical_alert_recipients (alert_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (alert_id, user_id) DO NOTHING
+`
+
+type CreateLabCriticalAlertRecipientParams struct {
+	AlertID uuid.UUID `json:"alert_id"`
+	UserID  uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) CreateLabCriticalAlertRecipient(ctx context.Context, arg CreateLabCriticalAlertRecipientParams) error {
+	_, err := q.db.Exec(ctx, createLabCriticalAlertRecipient, arg.AlertID, arg.UserID)
+	return err
+}
+
+const createLabCriticalRule = `-- name: CreateLabCriticalRule :exec
+INSERT INTO lab_critical_rules (id, analyte_code, sex, min_age, max_age, low, high, unit, created_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+type CreateLabCriticalRuleParams struct {
+	ID          uuid.UUID          `json:"id"`
+	AnalyteCode string             `json:"analyte_code"`
+	Sex         pgtype.Text        `json:"sex"`
+	MinAge      pgtype.Int4        `json:"min_age"`
+	MaxAge      pgtype.Int4        `json:"max_age"`
+	Low         pgtype.Float8      `json:"low"`
+	High        pgtype.Float8      `json:"high"`
+	Unit        string             `json:"unit"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+}
+
+func (q *Queries) CreateLabCriticalRule(ctx context.Context, arg CreateLabCriticalRuleParams) error {
+	_, err := q.db.Exec(ctx, createLabCriticalRule,
+		arg.ID,
+		arg.AnalyteCode,
+		arg.Sex,
+		arg.MinAge,
+		arg.MaxAge,
+		arg.Low,
+		arg.High,
+		arg.Unit,
+		arg.CreatedAt,
+		arg.CreatedBy,
+	)
+	return err
+}
+
+const createLabProcessingJob = `-- name: CreateLabProcessingJob :one
+
+INSERT INTO lab_processing_jobs (
+    id,
+    patient_id,
+    uploaded_by_user_id,
+    document_uri,
+    mime_type,
+    content_sha256,
+    identity_override,
+    status
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 'queued')
+RETURNING id, patient_id, uploaded_by_user_id, document_uri, mime_type, status, attempts, lab_report_id, error_code, error_message, created_at, updated_at, started_at, finished_at, content_sha256, identity_override
+`
+
+type CreateLabProcessingJobParams struct {
+	ID               uuid.UUID   `json:"id"`
+	PatientID        uuid.UUID   `json:"patient_id"`
+	UploadedByUserID uuid.UUID   `json:"uploaded_by_user_id"`
+	DocumentUri      string      `json:"document_uri"`
+	MimeType         string      `json:"mime_type"`
+	ContentSha256    pgtype.Text `json:"content_sha256"`
+	IdentityOverride bool        `json:"identity_override"`
+}
+
+// ============================================================
+// Processing jobs
+// ============================================================
+func (q *Queries) CreateLabProcessingJob(ctx context.Context, arg CreateLabProcessingJobParams) (LabProcessingJob, error) {
+	row := q.db.QueryRow(ctx, createLabProcessingJob,
+		arg.ID,
+		arg.PatientID,
+		arg.UploadedByUserID,
+		arg.DocumentUri,
+		arg.MimeType,
+		arg.ContentSha256,
+		arg.IdentityOverride,
+	)
+	var i LabProcessingJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.UploadedByUserID,
+		&i.DocumentUri,
+		&i.MimeType,
+		&i.Status,
+		&i.Attempts,
+		&i.LabReportID,
+		&i.ErrorCode,
+		&i.ErrorMessage,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.ContentSha256,
+		&i.IdentityOverride,
+	)
+	return i, err
+}
+
 const createLabReport = `-- name: CreateLabReport :one
 
 INSERT INTO lab_reports (
@@ -27,12 +326,22 @@ INSERT INTO lab_reports (
     report_date,
     raw_text,
     uploaded_by_user_id,
-    fingerprint
+    fingerprint,
+    document_uri,
+    mime_type,
+    source,
+    status,
+    content_sha256,
+    identity_status,
+    identity_score,
+    field_confidence,
+    review_status
 )
 VALUES (
     $1, $2, $3, $4, $5, $6,
     $7, $8, $9, $10, $11, $12,
-    $13
+    $13, $14, $15, $16, $17, $18,
+    $19, $20, $21, $22
 )
 RETURNING
     id,
@@ -48,6 +357,18 @@ RETURNING
     raw_text,
     uploaded_by_user_id,
     fingerprint,
+    document_uri,
+    mime_type,
+    content_sha256,
+    identity_status,
+    identity_score,
+    field_confidence,
+    review_status,
+    reviewed_by_user_id,
+    reviewed_at,
+    source,
+    status,
+    version,
     created_at,
     updated_at
 `
@@ -66,6 +387,15 @@ type CreateLabReportParams struct {
 	RawText           pgtype.Text        `json:"raw_text"`
 	UploadedByUserID  uuid.UUID          `json:"uploaded_by_user_id"`
 	Fingerprint       pgtype.Text        `json:"fingerprint"`
+	DocumentUri       pgtype.Text        `json:"document_uri"`
+	MimeType          pgtype.Text        `json:"mime_type"`
+	Source            string             `json:"source"`
+	Status            string             `json:"status"`
+	ContentSha256     pgtype.Text        `json:"content_sha256"`
+	IdentityStatus    pgtype.Text        `json:"identity_status"`
+	IdentityScore     pgtype.Float8      `json:"identity_score"`
+	FieldConfidence   []byte             `json:"field_confidence"`
+	ReviewStatus      pgtype.Text        `json:"review_status"`
 }
 
 type CreateLabReportRow struct {
@@ -82,6 +412,18 @@ type CreateLabReportRow struct {
 	RawText           pgtype.Text        `json:"raw_text"`
 	UploadedByUserID  uuid.UUID          `json:"uploaded_by_user_id"`
 	Fingerprint       pgtype.Text        `json:"fingerprint"`
+	DocumentUri       pgtype.Text        `json:"document_uri"`
+	MimeType          pgtype.Text        `json:"mime_type"`
+	ContentSha256     pgtype.Text        `json:"content_sha256"`
+	IdentityStatus    pgtype.Text        `json:"identity_status"`
+	IdentityScore     pgtype.Float8      `json:"identity_score"`
+	FieldConfidence   []byte             `json:"field_confidence"`
+	ReviewStatus      pgtype.Text        `json:"review_status"`
+	ReviewedByUserID  pgtype.UUID        `json:"reviewed_by_user_id"`
+	ReviewedAt        pgtype.Timestamptz `json:"reviewed_at"`
+	Source            string             `json:"source"`
+	Status            string             `json:"status"`
+	Version           int32              `json:"version"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
@@ -104,6 +446,15 @@ func (q *Queries) CreateLabReport(ctx context.Context, arg CreateLabReportParams
 		arg.RawText,
 		arg.UploadedByUserID,
 		arg.Fingerprint,
+		arg.DocumentUri,
+		arg.MimeType,
+		arg.Source,
+		arg.Status,
+		arg.ContentSha256,
+		arg.IdentityStatus,
+		arg.IdentityScore,
+		arg.FieldConfidence,
+		arg.ReviewStatus,
 	)
 	var i CreateLabReportRow
 	err := row.Scan(
@@ -120,12 +471,63 @@ func (q *Queries) CreateLabReport(ctx context.Context, arg CreateLabReportParams
 		&i.RawText,
 		&i.UploadedByUserID,
 		&i.Fingerprint,
+		&i.DocumentUri,
+		&i.MimeType,
+		&i.ContentSha256,
+		&i.IdentityStatus,
+		&i.IdentityScore,
+		&i.FieldConfidence,
+		&i.ReviewStatus,
+		&i.ReviewedByUserID,
+		&i.ReviewedAt,
+		&i.Source,
+		&i.Status,
+		&i.Version,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const createLabReportRevision = `-- name: CreateLabReportRevision :exec
+INSERT INTO lab_report_revisions (
+    id,
+    lab_report_id,
+    version,
+    status,
+    changed_by_user_id,
+    changed_at,
+    reason,
+    changes
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type CreateLabReportRevisionParams struct {
+	ID              uuid.UUID          `json:"id"`
+	LabReportID     uuid.UUID          `json:"lab_report_id"`
+	Version         int32              `json:"version"`
+	Status          string             `json:"status"`
+	ChangedByUserID uuid.UUID          `json:"changed_by_user_id"`
+	ChangedAt       pgtype.Timestamptz `json:"changed_at"`
+	Reason          pgtype.Text        `json:"reason"`
+	Changes         []byte             `json:"changes"`
+}
+
+func (q *Queries) CreateLabReportRevision(ctx context.Context, arg CreateLabReportRevisionParams) error {
+	_, err := q.db.Exec(ctx, createLabReportRevision,
+		arg.ID,
+		arg.LabReportID,
+		arg.Version,
+		arg.Status,
+		arg.ChangedByUserID,
+		arg.ChangedAt,
+		arg.Reason,
+		arg.Changes,
+	)
+	return err
+}
+
 const createLabResult = `-- name: CreateLabResult :one
 INSERT INTO lab_results(
     id,
@@ -172,19 +574,47 @@ INSERT INTO lab_result_items (
     parameter_name,
     result_value,
     result_unit,
-    reference_text
+    reference_text,
+    numeric_value,
+    comparator,
+    qualitative_value,
+    reference_low,
+    reference_high,
+    interpretation,
+    analyte_code,
+    ucum_unit,
+    confidence,
+    field_confidence,
+    confirmed,
+    derived,
+    formula,
+    analyte_link
 )
-VALUES ($1,$2,$3,$4,$5,$6)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20)
 RETURNING id
 `
 
 type CreateLabResultItemParams struct {
-	ID            uuid.UUID   `json:"id"`
-	LabResultID   uuid.UUID   `json:"lab_result_id"`
-	ParameterName string      `json:"parameter_name"`
-	ResultValue   pgtype.Text `json:"result_value"`
-	ResultUnit    pgtype.Text `json:"result_unit"`
-	ReferenceText pgtype.Text `json:"reference_text"`
+	ID               uuid.UUID     `json:"id"`
+	LabResultID      uuid.UUID     `json:"lab_result_id"`
+	ParameterName    string        `json:"parameter_name"`
+	ResultValue      pgtype.Text   `json:"result_value"`
+	ResultUnit       pgtype.Text   `json:"result_unit"`
+	ReferenceText    pgtype.Text   `json:"reference_text"`
+	NumericValue     pgtype.Float8 `json:"numeric_value"`
+	Comparator       pgtype.Text   `json:"comparator"`
+	QualitativeValue pgtype.Text   `json:"qualitative_value"`
+	ReferenceLow     pgtype.Float8 `json:"reference_low"`
+	ReferenceHigh    pgtype.Float8 `json:"reference_high"`
+	Interpretation   pgtype.Text   `json:"interpretation"`
+	AnalyteCode      pgtype.Text   `json:"analyte_code"`
+	UcumUnit         pgtype.Text   `json:"ucum_unit"`
+	Confidence       pgtype.Float8 `json:"confidence"`
+	FieldConfidence  []byte        `json:"field_confidence"`
+	Confirmed        bool          `json:"confirmed"`
+	Derived          bool          `json:"derived"`
+	Formula          pgtype.Text   `json:"formula"`
+	AnalyteLink      pgtype.Text   `json:"analyte_link"`
 }
 
 func (q *Queries) CreateLabResultItem(ctx context.Context, arg CreateLabResultItemParams) (uuid.UUID, error) {
@@ -195,12 +625,92 @@ func (q *Queries) CreateLabResultItem(ctx context.Context, arg CreateLabResultIt
 		arg.ResultValue,
 		arg.ResultUnit,
 		arg.ReferenceText,
+		arg.NumericValue,
+		arg.Comparator,
+		arg.QualitativeValue,
+		arg.ReferenceLow,
+		arg.ReferenceHigh,
+		arg.Interpretation,
+		arg.AnalyteCode,
+		arg.UcumUnit,
+		arg.Confidence,
+		arg.FieldConfidence,
+		arg.Confirmed,
+		arg.Derived,
+		arg.Formula,
+		arg.AnalyteLink,
 	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
 }
 
+const deleteDerivedLabItemsByReportID = `-- name: DeleteDerivedLabItemsByReportID :exec
+WITH deleted AS (
+  DELETE FROM lab_result_items i
+  USING lab_results r
+  WHERE i.lab_result_id = r.id
+    AND r.lab_report_id = $1
+    AND i.derived
+  RETURNING i.lab_result_id
+)
+DELETE FROM lab_results r
+WHERE r.id IN (SELECT lab_result_id FROM deleted)
+  AND NOT EXISTS (
+    SELECT 1 FROM lab_result_items i WHERE i.lab_result_id = r.id AND NOT i.derived
+  )
+`
+
+// Derived items are replaced as a whole when the report is recomputed; the
+// results that only held derived items go with them. The CTE runs on the
+// same snapshot, so the NOT EXISTS still sees the deleted rows.
+func (q *Queries) DeleteDerivedLabItemsByReportID(ctx context.Context, labReportID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteDerivedLabItemsByReportID, labReportID)
+	return err
+}
+
+const deleteLabAnalyteSynonym = `-- name: DeleteLabAnalyteSynonym :execrows
+DELETE FROM lab_analyte_synonyms
+WHERE name_key = $1
+  AND analyte_code = $2
+`
+
+type DeleteLabAnalyteSynonymParams struct {
+	NameKey     string `json:"name_key"`
+	AnalyteCode string `json:"analyte_code"`
+}
+
+func (q *Queries) DeleteLabAnalyteSynonym(ctx context.Context, arg DeleteLabAnalyteSynonymParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteLabAnalyteSynonym, arg.NameKey, arg.AnalyteCode)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteLabCriticalRule = `-- name: DeleteLabCriticalRule :execrows
+UPDATE lab_critical_rules
+SET deleted_at = $1,
+    deleted_by = $2
+WHERE id = $3
+  AND deleted_at IS NULL
+`
+
+type DeleteLabCriticalRuleParams struct {
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	DeletedBy pgtype.UUID        `json:"deleted_by"`
+	ID        uuid.UUID          `json:"id"`
+}
+
+// Soft delete: alerts keep pointing at the rule that fired them.
+func (q *Queries) DeleteLabCriticalRule(ctx context.Context, arg DeleteLabCriticalRuleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteLabCriticalRule, arg.DeletedAt, arg.DeletedBy, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteLabReport = `-- name: DeleteLabReport :execrows
 DELETE FROM lab_reports
 WHERE id = $1
@@ -247,7 +757,6 @@ func (q *Queries) DeleteLabResultsByReportID(ctx context.Context, labReportID uu
 }
 
 const existsLabReportByPatientAndFingerprint = `-- name: ExistsLabReportByPatientAndFingerprint :one
-
 SELECT EXISTS(
   SELECT 1
   FROM lab_reports
@@ -261,9 +770,6 @@ type ExistsLabReportByPatientAndFingerprintParams struct {
 	Fingerprint pgtype.Text `json:"fingerprint"`
 }
 
-// ============================================================
-// Dedupe (Existence checks)
-// ============================================================
 func (q *Queries) ExistsLabReportByPatientAndFingerprint(ctx context.Context, arg ExistsLabReportByPatientAndFingerprintParams) (bool, error) {
 	row := q.db.QueryRow(ctx, existsLabReportByPatientAndFingerprint, arg.PatientID, arg.Fingerprint)
 	var exists bool
@@ -271,6 +777,163 @@ func (q *Queries) ExistsLabReportByPatientAndFingerprint(ctx context.Context, ar
 	return exists, err
 }
 
+const failStaleLabProcessingJobs = `-- name: FailStaleLabProcessingJobs :execrows
+UPDATE lab_processing_jobs
+SET
+    status        = 'failed',
+    error_code    = $1,
+    error_message = $2,
+    finished_at   = now(),
+    updated_at    = now()
+WHERE status = 'running'
+  AND started_at < $3
+  AND attempts >= $4::int
+`
+
+type FailStaleLabProcessingJobsParams struct {
+	ErrorCode     pgtype.Text        `json:"error_code"`
+	ErrorMessage  pgtype.Text        `json:"error_message"`
+	StartedBefore pgtype.Timestamptz `json:"started_before"`
+	MaxAttempts   int32              `json:"max_attempts"`
+}
+
+// Stale jobs that already used every attempt (e.g. a document that crashes
+// the worker) are failed instead of requeued.
+func (q *Queries) FailStaleLabProcessingJobs(ctx context.Context, arg FailStaleLabProcessingJobsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, failStaleLabProcessingJobs,
+		arg.ErrorCode,
+		arg.ErrorMessage,
+		arg.StartedBefore,
+		arg.MaxAttempts,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findActiveLabProcessingJobIDByContentHash = `-- name: FindActiveLabProcessingJobIDByContentHash :one
+SELECT id
+FROM lab_processing_jobs
+WHERE patient_id = $1
+  AND content_sha256 = $2
+  AND status IN ('queued', 'running')
+ORDER BY created_at
+LIMIT 1
+`
+
+type FindActiveLabProcessingJobIDByContentHashParams struct {
+	PatientID     uuid.UUID   `json:"patient_id"`
+	ContentSha256 pgtype.Text `json:"content_sha256"`
+}
+
+// Jobs still being processed for the same file and patient.
+func (q *Queries) FindActiveLabProcessingJobIDByContentHash(ctx context.Context, arg FindActiveLabProcessingJobIDByContentHashParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, findActiveLabProcessingJobIDByContentHash, arg.PatientID, arg.ContentSha256)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const findLabReportIDByContentHash = `-- name: FindLabReportIDByContentHash :one
+
+SELECT id
+FROM lab_reports
+WHERE patient_id = $1
+  AND content_sha256 = $2
+ORDER BY created_at
+LIMIT 1
+`
+
+type FindLabReportIDByContentHashParams struct {
+	PatientID     uuid.UUID   `json:"patient_id"`
+	ContentSha256 pgtype.Text `json:"content_sha256"`
+}
+
+// ============================================================
+// Dedupe (Existence checks)
+// ============================================================
+func (q *Queries) FindLabReportIDByContentHash(ctx context.Context, arg FindLabReportIDByContentHashParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, findLabReportIDByContentHash, arg.PatientID, arg.ContentSha256)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getLabAnalyteByCode = `-- name: GetLabAnalyteByCode :one
+SELECT code, loinc_code, display_name, default_unit
+FROM lab_analytes
+WHERE code = $1
+`
+
+type GetLabAnalyteByCodeRow struct {
+	Code        string      `json:"code"`
+	LoincCode   pgtype.Text `json:"loinc_code"`
+	DisplayName string      `json:"display_name"`
+	DefaultUnit pgtype.Text `json:"default_unit"`
+}
+
+func (q *Queries) GetLabAnalyteByCode(ctx context.Context, code string) (GetLabAnalyteByCodeRow, error) {
+	row := q.db.QueryRow(ctx, getLabAnalyteByCode, code)
+	var i GetLabAnalyteByCodeRow
+	err := row.Scan(
+		&i.Code,
+		&i.LoincCode,
+		&i.DisplayName,
+		&i.DefaultUnit,
+	)
+	return i, err
+}
+
+const getLabAnalyteSynonymByKey = `-- name: GetLabAnalyteSynonymByKey :one
+SELECT name_key, analyte_code, name
+FROM lab_analyte_synonyms
+WHERE name_key = $1
+`
+
+type GetLabAnalyteSynonymByKeyRow struct {
+	NameKey     string `json:"name_key"`
+	AnalyteCode string `json:"analyte_code"`
+	Name        string `json:"name"`
+}
+
+func (q *Queries) GetLabAnalyteSynonymByKey(ctx context.Context, nameKey string) (GetLabAnalyteSynonymByKeyRow, error) {
+	row := q.db.QueryRow(ctx, getLabAnalyteSynonymByKey, nameKey)
+	var i GetLabAnalyteSynonymByKeyRow
+	err := row.Scan(&i.NameKey, &i.AnalyteCode, &i.Name)
+	return i, err
+}
+
+const getLabProcessingJobByID = `-- name: GetLabProcessingJobByID :one
+SELECT id, patient_id, uploaded_by_user_id, document_uri, mime_type, status, attempts, lab_report_id, error_code, error_message, created_at, updated_at, started_at, finished_at, content_sha256, identity_override
+FROM lab_processing_jobs
+WHERE id = $1
+`
+
+func (q *Queries) GetLabProcessingJobByID(ctx context.Context, id uuid.UUID) (LabProcessingJob, error) {
+	row := q.db.QueryRow(ctx, getLabProcessingJobByID, id)
+	var i LabProcessingJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.UploadedByUserID,
+		&i.DocumentUri,
+		&i.MimeType,
+		&i.Status,
+		&i.Attempts,
+		&i.LabReportID,
+		&i.ErrorCode,
+		&i.ErrorMessage,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.ContentSha256,
+		&i.IdentityOverride,
+	)
+	return i, err
+}
+
 const getLabReportByID = `-- name: GetLabReportByID :one
 
 SELECT
@@ -287,6 +950,18 @@ SELECT
     raw_text,
     uploaded_by_user_id,
     fingerprint,
+    document_uri,
+    mime_type,
+    content_sha256,
+    identity_status,
+    identity_score,
+    field_confidence,
+    review_status,
+    reviewed_by_user_id,
+    reviewed_at,
+    source,
+    status,
+    version,
     created_at,
     updated_at
 FROM lab_reports
@@ -307,6 +982,18 @@ type GetLabReportByIDRow struct {
 	RawText           pgtype.Text        `json:"raw_text"`
 	UploadedByUserID  uuid.UUID          `json:"uploaded_by_user_id"`
 	Fingerprint       pgtype.Text        `json:"fingerprint"`
+	DocumentUri       pgtype.Text        `json:"document_uri"`
+	MimeType          pgtype.Text        `json:"mime_type"`
+	ContentSha256     pgtype.Text        `json:"content_sha256"`
+	IdentityStatus    pgtype.Text        `json:"identity_status"`
+	IdentityScore     pgtype.Float8      `json:"identity_score"`
+	FieldConfidence   []byte             `json:"field_confidence"`
+	ReviewStatus      pgtype.Text        `json:"review_status"`
+	ReviewedByUserID  pgtype.UUID        `json:"reviewed_by_user_id"`
+	ReviewedAt        pgtype.Timestamptz `json:"reviewed_at"`
+	Source            string             `json:"source"`
+	Status            string             `json:"status"`
+	Version           int32              `json:"version"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
@@ -331,6 +1018,18 @@ func (q *Queries) GetLabReportByID(ctx context.Context, id uuid.UUID) (GetLabRep
 		&i.RawText,
 		&i.UploadedByUserID,
 		&i.Fingerprint,
+		&i.DocumentUri,
+		&i.MimeType,
+		&i.ContentSha256,
+		&i.IdentityStatus,
+		&i.IdentityScore,
+		&i.FieldConfidence,
+		&i.ReviewStatus,
+		&i.ReviewedByUserID,
+		&i.ReviewedAt,
+		&i.Source,
+		&i.Status,
+		&i.Version,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -373,70 +1072,700 @@ func (q *Queries) GetLabResultsByReportID(ctx context.Context, labReportID uuid.
 	return i, err
 }
 
-const listLabItemTimelineByPatientAndParameter = `-- name: ListLabItemTimelineByPatientAndParameter :many
-
-SELECT
-  lr.id           AS report_id,
-  r.id          AS lab_result_id,
-  i.id          AS item_id,
-  lr.report_date  AS report_date,
-  r.test_name   AS test_name,
-  i.parameter_name,
-  i.result_value,
-  i.result_unit
-FROM lab_result_items i
-JOIN lab_results r ON i.lab_result_id = r.id
-JOIN lab_reports      lr  ON r.lab_report_id      = lr.id
-WHERE lr.patient_id      = $1
-  AND i.parameter_name = $2
-ORDER BY lr.report_date DESC NULLS LAST, lr.created_at DESC
-LIMIT $3 OFFSET $4
+const listDistinctLabParameterNames = `-- name: ListDistinctLabParameterNames :many
+SELECT DISTINCT parameter_name
+FROM lab_result_items
+WHERE NOT derived
+  AND analyte_link IS DISTINCT FROM 'code'
+ORDER BY parameter_name
 `
 
-type ListLabItemTimelineByPatientAndParameterParams struct {
-	PatientID     uuid.UUID `json:"patient_id"`
-	ParameterName string    `json:"parameter_name"`
-	Limit         int32     `json:"limit"`
-	Offset        int32     `json:"offset"`
-}
-
-type ListLabItemTimelineByPatientAndParameterRow struct {
-	ReportID      uuid.UUID          `json:"report_id"`
-	LabResultID   uuid.UUID          `json:"lab_result_id"`
-	ItemID        uuid.UUID          `json:"item_id"`
-	ReportDate    pgtype.Timestamptz `json:"report_date"`
-	TestName      string             `json:"test_name"`
-	ParameterName string             `json:"parameter_name"`
-	ResultValue   pgtype.Text        `json:"result_value"`
-	ResultUnit    pgtype.Text        `json:"result_unit"`
-}
-
-// ============================================================
-// Timeline
-// ============================================================
-func (q *Queries) ListLabItemTimelineByPatientAndParameter(ctx context.Context, arg ListLabItemTimelineByPatientAndParameterParams) ([]ListLabItemTimelineByPatientAndParameterRow, error) {
-	rows, err := q.db.Query(ctx, listLabItemTimelineByPatientAndParameter,
-		arg.PatientID,
-		arg.ParameterName,
-		arg.Limit,
-		arg.Offset,
-	)
+func (q *Queries) ListDistinctLabParameterNames(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listDistinctLabParameterNames)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListLabItemTimelineByPatientAndParameterRow
+	var items []string
 	for rows.Next() {
-		var i ListLabItemTimelineByPatientAndParameterRow
-		if err := rows.Scan(
-			&i.ReportID,
+		var parameter_name string
+		if err := rows.Scan(&parameter_name); err != nil {
+			return nil, err
+		}
+		items = append(items, parameter_name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabAnalyteSynonyms = `-- name: ListLabAnalyteSynonyms :many
+SELECT name_key, analyte_code, name
+FROM lab_analyte_synonyms
+ORDER BY analyte_code, name
+`
+
+type ListLabAnalyteSynonymsRow struct {
+	NameKey     string `json:"name_key"`
+	AnalyteCode string `json:"analyte_code"`
+	Name        string `json:"name"`
+}
+
+func (q *Queries) ListLabAnalyteSynonyms(ctx context.Context) ([]ListLabAnalyteSynonymsRow, error) {
+	rows, err := q.db.Query(ctx, listLabAnalyteSynonyms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLabAnalyteSynonymsRow
+	for rows.Next() {
+		var i ListLabAnalyteSynonymsRow
+		if err := rows.Scan(&i.NameKey, &i.AnalyteCode, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabAnalytes = `-- name: ListLabAnalytes :many
+
+SELECT code, loinc_code, display_name, default_unit
+FROM lab_analytes
+ORDER BY display_name
+`
+
+type ListLabAnalytesRow struct {
+	Code        string      `json:"code"`
+	LoincCode   pgtype.Text `json:"loinc_code"`
+	DisplayName string      `json:"display_name"`
+	DefaultUnit pgtype.Text `json:"default_unit"`
+}
+
+// ============================================================
+// Analyte catalog
+// ============================================================
+func (q *Queries) ListLabAnalytes(ctx context.Context) ([]ListLabAnalytesRow, error) {
+	rows, err := q.db.Query(ctx, listLabAnalytes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLabAnalytesRow
+	for rows.Next() {
+		var i ListLabAnalytesRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.LoincCode,
+			&i.DisplayName,
+			&i.DefaultUnit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabCriticalAlertItemIDsByReport = `-- name: ListLabCriticalAlertItemIDsByReport :many
+SELECT lab_result_item_id
+FROM lab_critical_alerts
+WHERE lab_report_id = $1
+`
+
+func (q *Queries) ListLabCriticalAlertItemIDsByReport(ctx context.Context, labReportID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listLabCriticalAlertItemIDsByReport, labReportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var lab_result_item_id uuid.UUID
+		if err := rows.Scan(&lab_result_item_id); err != nil {
+			return nil, err
+		}
+		items = append(items, lab_result_item_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabCriticalAlertsByRecipient = `-- name: ListLabCriticalAlertsByRecipient :many
+SELECT
+    a.id,
+    a.lab_report_id,
+    a.patient_id,
+    a.lab_result_item_id,
+    a.rule_id,
+    a.analyte_code,
+    a.parameter_name,
+    a.value,
+    a.limit_value,
+    a.unit,
+    a.interpretation,
+    a.created_at,
+    r.notified_at,
+    r.acknowledged_at
+FROM lab_critical_alerts a
+JOIN lab_critical_alert_recipients r ON r.alert_id = a.id
+WHERE r.user_id = $1
+  AND (NOT $2::boolean OR r.acknowledged_at IS NULL)
+ORDER BY a.created_at DESC, a.id
+LIMIT $4 OFFSET $3
+`
+
+type ListLabCriticalAlertsByRecipientParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	OnlyOpen bool      `json:"only_open"`
+	Offset   int32     `json:"offset"`
+	Limit    int32     `json:"limit"`
+}
+
+type ListLabCriticalAlertsByRecipientRow struct {
+	ID              uuid.UUID          `json:"id"`
+	LabReportID     uuid.UUID          `json:"lab_report_id"`
+	PatientID       uuid.UUID          `json:"patient_id"`
+	LabResultItemID uuid.UUID          `json:"lab_result_item_id"`
+	RuleID          pgtype.UUID        `json:"rule_id"`
+	AnalyteCode     string             `json:"analyte_code"`
+	ParameterName   string             `json:"parameter_name"`
+	Value           float64            `json:"value"`
+	LimitValue      float64            `json:"limit_value"`
+	Unit            string             `json:"unit"`
+	Interpretation  string             `json:"interpretation"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	NotifiedAt      pgtype.Timestamptz `json:"notified_at"`
+	AcknowledgedAt  pgtype.Timestamptz `json:"acknowledged_at"`
+}
+
+// Alerts sent to the user, newest first; only_open hides acknowledged ones.
+func (q *Queries) ListLabCriticalAlertsByRecipient(ctx context.Context, arg ListLabCriticalAlertsByRecipientParams) ([]ListLabCriticalAlertsByRecipientRow, error) {
+	rows, err := q.db.Query(ctx, listLabCriticalAlertsByRecipient,
+		arg.UserID,
+		arg.OnlyOpen,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLabCriticalAlertsByRecipientRow
+	for rows.Next() {
+		var i ListLabCriticalAlertsByRecipientRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.LabReportID,
+			&i.PatientID,
+			&i.LabResultItemID,
+			&i.RuleID,
+			&i.AnalyteCode,
+			&i.ParameterName,
+			&i.Value,
+			&i.LimitValue,
+			&i.Unit,
+			&i.Interpretation,
+			&i.CreatedAt,
+			&i.NotifiedAt,
+			&i.AcknowledgedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabCriticalRules = `-- name: ListLabCriticalRules :many
+
+SELECT id, analyte_code, sex, min_age, max_age, low, high, unit, created_at, created_by
+FROM lab_critical_rules
+WHERE deleted_at IS NULL
+ORDER BY analyte_code, sex NULLS FIRST, min_age NULLS FIRST, id
+`
+
+type ListLabCriticalRulesRow struct {
+	ID          uuid.UUID          `json:"id"`
+	AnalyteCode string             `json:"analyte_code"`
+	Sex         pgtype.Text        `json:"sex"`
+	MinAge      pgtype.Int4        `json:"min_age"`
+	MaxAge      pgtype.Int4        `json:"max_age"`
+	Low         pgtype.Float8      `json:"low"`
+	High        pgtype.Float8      `json:"high"`
+	Unit        string             `json:"unit"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+}
+
+// ============================================================
+// Critical values
+// ============================================================
+func (q *Queries) ListLabCriticalRules(ctx context.Context) ([]ListLabCriticalRulesRow, error) {
+	rows, err := q.db.Query(ctx, listLabCriticalRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLabCriticalRulesRow
+	for rows.Next() {
+		var i ListLabCriticalRulesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AnalyteCode,
+			&i.Sex,
+			&i.MinAge,
+			&i.MaxAge,
+			&i.Low,
+			&i.High,
+			&i.Unit,
+			&i.CreatedAt,
+			&i.CreatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabHistoryByPatient = `-- name: ListLabHistoryByPatient :many
+
+SELECT
+  lr.id           AS report_id,
+  lr.report_date,
+  lr.lab_name,
+  lr.source,
+  lr.status,
+  r.id            AS result_id,
+  r.test_name,
+  r.material,
+  r.method,
+  r.collected_at,
+  i.id            AS item_id,
+  i.parameter_name,
+  i.result_value,
+  i.result_unit,
+  i.reference_text,
+  i.numeric_value,
+  i.ucum_unit,
+  i.comparator,
+  i.qualitative_value,
+  i.reference_low,
+  i.reference_high,
+  i.interpretation,
+  i.analyte_code,
+  i.derived,
+  i.formula,
+  a.default_unit  AS canonical_unit
+FROM lab_result_items i
+JOIN lab_results r ON i.lab_result_id = r.id
+JOIN lab_reports      lr  ON r.lab_report_id      = lr.id
+LEFT JOIN lab_analytes a  ON a.code               = i.analyte_code
+WHERE lr.patient_id = $1
+ORDER BY COALESCE(r.collected_at, lr.report_date) ASC NULLS LAST, lr.created_at, r.id, i.id
+LIMIT $3 OFFSET $2
+`
+
+type ListLabHistoryByPatientParams struct {
+	PatientID uuid.UUID `json:"patient_id"`
+	Offset    int32     `json:"offset"`
+	Limit     int32     `json:"limit"`
+}
+
+type ListLabHistoryByPatientRow struct {
+	ReportID         uuid.UUID          `json:"report_id"`
+	ReportDate       pgtype.Timestamptz `json:"report_date"`
+	LabName          pgtype.Text        `json:"lab_name"`
+	Source           string             `json:"source"`
+	Status           string             `json:"status"`
+	ResultID         uuid.UUID          `json:"result_id"`
+	TestName         string             `json:"test_name"`
+	Material         pgtype.Text        `json:"material"`
+	Method           pgtype.Text        `json:"method"`
+	CollectedAt      pgtype.Timestamptz `json:"collected_at"`
+	ItemID           uuid.UUID          `json:"item_id"`
+	ParameterName    string             `json:"parameter_name"`
+	ResultValue      pgtype.Text        `json:"result_value"`
+	ResultUnit       pgtype.Text        `json:"result_unit"`
+	ReferenceText    pgtype.Text        `json:"reference_text"`
+	NumericValue     pgtype.Float8      `json:"numeric_value"`
+	UcumUnit         pgtype.Text        `json:"ucum_unit"`
+	Comparator       pgtype.Text        `json:"comparator"`
+	QualitativeValue pgtype.Text        `json:"qualitative_value"`
+	ReferenceLow     pgtype.Float8      `json:"reference_low"`
+	ReferenceHigh    pgtype.Float8      `json:"reference_high"`
+	Interpretation   pgtype.Text        `json:"interpretation"`
+	AnalyteCode      pgtype.Text        `json:"analyte_code"`
+	Derived          bool               `json:"derived"`
+	Formula          pgtype.Text        `json:"formula"`
+	CanonicalUnit    pgtype.Text        `json:"canonical_unit"`
+}
+
+// ============================================================
+// History export
+// ============================================================
+// One row per item with its report and result, oldest collection first.
+func (q *Queries) ListLabHistoryByPatient(ctx context.Context, arg ListLabHistoryByPatientParams) ([]ListLabHistoryByPatientRow, error) {
+	rows, err := q.db.Query(ctx, listLabHistoryByPatient, arg.PatientID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLabHistoryByPatientRow
+	for rows.Next() {
+		var i ListLabHistoryByPatientRow
+		if err := rows.Scan(
+			&i.ReportID,
+			&i.ReportDate,
+			&i.LabName,
+			&i.Source,
+			&i.Status,
+			&i.ResultID,
+			&i.TestName,
+			&i.Material,
+			&i.Method,
+			&i.CollectedAt,
+			&i.ItemID,
+			&i.ParameterName,
+			&i.ResultValue,
+			&i.ResultUnit,
+			&i.ReferenceText,
+			&i.NumericValue,
+			&i.UcumUnit,
+			&i.Comparator,
+			&i.QualitativeValue,
+			&i.ReferenceLow,
+			&i.ReferenceHigh,
+			&i.Interpretation,
+			&i.AnalyteCode,
+			&i.Derived,
+			&i.Formula,
+			&i.CanonicalUnit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabItemTimelineByPatient = `-- name: ListLabItemTimelineByPatient :many
+SELECT
+  lr.id           AS report_id,
+  r.id          AS lab_result_id,
+  i.id          AS item_id,
+  lr.report_date  AS report_date,
+  r.collected_at,
+  r.test_name   AS test_name,
+  i.parameter_name,
+  i.analyte_code,
+  i.result_value,
+  i.result_unit,
+  i.reference_text,
+  i.numeric_value,
+  i.ucum_unit,
+  i.reference_low,
+  i.reference_high,
+  i.interpretation,
+  i.derived,
+  i.formula,
+  a.default_unit AS canonical_unit
+FROM lab_result_items i
+JOIN lab_results r ON i.lab_result_id = r.id
+JOIN lab_reports      lr  ON r.lab_report_id      = lr.id
+LEFT JOIN lab_analytes a  ON a.code               = i.analyte_code
+WHERE lr.patient_id      = $1
+  AND ($2::timestamptz IS NULL
+       OR COALESCE(r.collected_at, lr.report_date) >= $2)
+  AND ($3::timestamptz IS NULL
+       OR COALESCE(r.collected_at, lr.report_date) <= $3)
+ORDER BY COALESCE(r.collected_at, lr.report_date) DESC NULLS LAST, lr.created_at DESC, i.id
+LIMIT $5 OFFSET $4
+`
+
+type ListLabItemTimelineByPatientParams struct {
+	PatientID uuid.UUID          `json:"patient_id"`
+	FromTime  pgtype.Timestamptz `json:"from_time"`
+	ToTime    pgtype.Timestamptz `json:"to_time"`
+	Offset    int32              `json:"offset"`
+	Limit     int32              `json:"limit"`
+}
+
+type ListLabItemTimelineByPatientRow struct {
+	ReportID       uuid.UUID          `json:"report_id"`
+	LabResultID    uuid.UUID          `json:"lab_result_id"`
+	ItemID         uuid.UUID          `json:"item_id"`
+	ReportDate     pgtype.Timestamptz `json:"report_date"`
+	CollectedAt    pgtype.Timestamptz `json:"collected_at"`
+	TestName       string             `json:"test_name"`
+	ParameterName  string             `json:"parameter_name"`
+	AnalyteCode    pgtype.Text        `json:"analyte_code"`
+	ResultValue    pgtype.Text        `json:"result_value"`
+	ResultUnit     pgtype.Text        `json:"result_unit"`
+	ReferenceText  pgtype.Text        `json:"reference_text"`
+	NumericValue   pgtype.Float8      `json:"numeric_value"`
+	UcumUnit       pgtype.Text        `json:"ucum_unit"`
+	ReferenceLow   pgtype.Float8      `json:"reference_low"`
+	ReferenceHigh  pgtype.Float8      `json:"reference_high"`
+	Interpretation pgtype.Text        `json:"interpretation"`
+	Derived        bool               `json:"derived"`
+	Formula        pgtype.Text        `json:"formula"`
+	CanonicalUnit  pgtype.Text        `json:"canonical_unit"`
+}
+
+// Every item of the patient in the period, for the cumulative report and exports.
+func (q *Queries) ListLabItemTimelineByPatient(ctx context.Context, arg ListLabItemTimelineByPatientParams) ([]ListLabItemTimelineByPatientRow, error) {
+	rows, err := q.db.Query(ctx, listLabItemTimelineByPatient,
+		arg.PatientID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLabItemTimelineByPatientRow
+	for rows.Next() {
+		var i ListLabItemTimelineByPatientRow
+		if err := rows.Scan(
+			&i.ReportID,
+			&i.LabResultID,
+			&i.ItemID,
+			&i.ReportDate,
+			&i.CollectedAt,
+			&i.TestName,
+			&i.ParameterName,
+			&i.AnalyteCode,
+			&i.ResultValue,
+			&i.ResultUnit,
+			&i.ReferenceText,
+			&i.NumericValue,
+			&i.UcumUnit,
+			&i.ReferenceLow,
+			&i.ReferenceHigh,
+			&i.Interpretation,
+			&i.Derived,
+			&i.Formula,
+			&i.CanonicalUnit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabItemTimelineByPatientAndParameter = `-- name: ListLabItemTimelineByPatientAndParameter :many
+
+SELECT
+  lr.id           AS report_id,
+  r.id          AS lab_result_id,
+  i.id          AS item_id,
+  lr.report_date  AS report_date,
+  r.collected_at,
+  r.test_name   AS test_name,
+  i.parameter_name,
+  i.analyte_code,
+  i.result_value,
+  i.result_unit,
+  i.reference_text,
+  i.numeric_value,
+  i.ucum_unit,
+  i.reference_low,
+  i.reference_high,
+  i.interpretation,
+  i.derived,
+  i.formula,
+  a.default_unit AS canonical_unit
+FROM lab_result_items i
+JOIN lab_results r ON i.lab_result_id = r.id
+JOIN lab_reports      lr  ON r.lab_report_id      = lr.id
+LEFT JOIN lab_analytes a  ON a.code               = i.analyte_code
+WHERE lr.patient_id      = $1
+  AND (
+        i.parameter_name = $2
+     OR i.analyte_code   = $2
+     OR i.analyte_code   = (
+          SELECT s.analyte_code
+          FROM lab_analyte_synonyms s
+          WHERE s.name_key = $3
+        )
+  )
+  AND ($4::timestamptz IS NULL
+       OR COALESCE(r.collected_at, lr.report_date) >= $4)
+  AND ($5::timestamptz IS NULL
+       OR COALESCE(r.collected_at, lr.report_date) <= $5)
+ORDER BY COALESCE(r.collected_at, lr.report_date) DESC NULLS LAST, lr.created_at DESC
+LIMIT $7 OFFSET $6
+`
+
+type ListLabItemTimelineByPatientAndParameterParams struct {
+	PatientID     uuid.UUID          `json:"patient_id"`
+	ParameterName string             `json:"parameter_name"`
+	NameKey       string             `json:"name_key"`
+	FromTime      pgtype.Timestamptz `json:"from_time"`
+	ToTime        pgtype.Timestamptz `json:"to_time"`
+	Offset        int32              `json:"offset"`
+	Limit         int32              `json:"limit"`
+}
+
+type ListLabItemTimelineByPatientAndParameterRow struct {
+	ReportID       uuid.UUID          `json:"report_id"`
+	LabResultID    uuid.UUID          `json:"lab_result_id"`
+	ItemID         uuid.UUID          `json:"item_id"`
+	ReportDate     pgtype.Timestamptz `json:"report_date"`
+	CollectedAt    pgtype.Timestamptz `json:"collected_at"`
+	TestName       string             `json:"test_name"`
+	ParameterName  string             `json:"parameter_name"`
+	AnalyteCode    pgtype.Text        `json:"analyte_code"`
+	ResultValue    pgtype.Text        `json:"result_value"`
+	ResultUnit     pgtype.Text        `json:"result_unit"`
+	ReferenceText  pgtype.Text        `json:"reference_text"`
+	NumericValue   pgtype.Float8      `json:"numeric_value"`
+	UcumUnit       pgtype.Text        `json:"ucum_unit"`
+	ReferenceLow   pgtype.Float8      `json:"reference_low"`
+	ReferenceHigh  pgtype.Float8      `json:"reference_high"`
+	Interpretation pgtype.Text        `json:"interpretation"`
+	Derived        bool               `json:"derived"`
+	Formula        pgtype.Text        `json:"formula"`
+	CanonicalUnit  pgtype.Text        `json:"canonical_unit"`
+}
+
+// ============================================================
+// Timeline
+// ============================================================
+// Matches the exact parameter name or, when it resolves to a catalog analyte,
+// every item linked to that analyte.
+// Period filters use the collection time, falling back to the report date.
+func (q *Queries) ListLabItemTimelineByPatientAndParameter(ctx context.Context, arg ListLabItemTimelineByPatientAndParameterParams) ([]ListLabItemTimelineByPatientAndParameterRow, error) {
+	rows, err := q.db.Query(ctx, listLabItemTimelineByPatientAndParameter,
+		arg.PatientID,
+		arg.ParameterName,
+		arg.NameKey,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLabItemTimelineByPatientAndParameterRow
+	for rows.Next() {
+		var i ListLabItemTimelineByPatientAndParameterRow
+		if err := rows.Scan(
+			&i.ReportID,
 			&i.LabResultID,
 			&i.ItemID,
 			&i.ReportDate,
+			&i.CollectedAt,
 			&i.TestName,
 			&i.ParameterName,
+			&i.AnalyteCode,
 			&i.ResultValue,
 			&i.ResultUnit,
+			&i.ReferenceText,
+			&i.NumericValue,
+			&i.UcumUnit,
+			&i.ReferenceLow,
+			&i.ReferenceHigh,
+			&i.Interpretation,
+			&i.Derived,
+			&i.Formula,
+			&i.CanonicalUnit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabParameterNamesLike = `-- name: ListLabParameterNamesLike :many
+SELECT DISTINCT parameter_name
+FROM lab_result_items
+WHERE NOT derived
+  AND analyte_link IS DISTINCT FROM 'code'
+  AND translate(lower(parameter_name), 'áàâãéêíóôõúüç', 'aaaaeeiooouuc') LIKE $1::text
+ORDER BY parameter_name
+`
+
+// Candidates for a name key: the name folded like labs.NormalizeAnalyteName
+// (lower case, same accents) contains the key words in order. Callers filter
+// the exact matches with labs.MatchKeys.
+func (q *Queries) ListLabParameterNamesLike(ctx context.Context, pattern string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listLabParameterNamesLike, pattern)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var parameter_name string
+		if err := rows.Scan(&parameter_name); err != nil {
+			return nil, err
+		}
+		items = append(items, parameter_name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabReportRevisions = `-- name: ListLabReportRevisions :many
+SELECT id, lab_report_id, version, status, changed_by_user_id, changed_at, reason, changes
+FROM lab_report_revisions
+WHERE lab_report_id = $1
+ORDER BY version DESC
+`
+
+func (q *Queries) ListLabReportRevisions(ctx context.Context, labReportID uuid.UUID) ([]LabReportRevision, error) {
+	rows, err := q.db.Query(ctx, listLabReportRevisions, labReportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabReportRevision
+	for rows.Next() {
+		var i LabReportRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.LabReportID,
+			&i.Version,
+			&i.Status,
+			&i.ChangedByUserID,
+			&i.ChangedAt,
+			&i.Reason,
+			&i.Changes,
 		); err != nil {
 			return nil, err
 		}
@@ -458,6 +1787,9 @@ SELECT
     report_date,
     uploaded_by_user_id,
     fingerprint,
+    identity_status,
+    review_status,
+    status,
     created_at,
     updated_at
 FROM lab_reports
@@ -480,6 +1812,9 @@ type ListLabReportsByPatientIDRow struct {
 	ReportDate       pgtype.Timestamptz `json:"report_date"`
 	UploadedByUserID uuid.UUID          `json:"uploaded_by_user_id"`
 	Fingerprint      pgtype.Text        `json:"fingerprint"`
+	IdentityStatus   pgtype.Text        `json:"identity_status"`
+	ReviewStatus     pgtype.Text        `json:"review_status"`
+	Status           string             `json:"status"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
@@ -504,6 +1839,9 @@ func (q *Queries) ListLabReportsByPatientID(ctx context.Context, arg ListLabRepo
 			&i.ReportDate,
 			&i.UploadedByUserID,
 			&i.Fingerprint,
+			&i.IdentityStatus,
+			&i.ReviewStatus,
+			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -519,21 +1857,47 @@ func (q *Queries) ListLabReportsByPatientID(ctx context.Context, arg ListLabRepo
 
 const listLabResultItemsByResultID = `-- name: ListLabResultItemsByResultID :many
 SELECT
-  id, lab_result_id, parameter_name, result_value, result_unit, reference_text
+  id, lab_result_id, parameter_name, result_value, result_unit, reference_text,
+  numeric_value, comparator, qualitative_value, reference_low, reference_high, interpretation,
+  analyte_code, ucum_unit, confidence, field_confidence, confirmed, derived, formula,
+  analyte_link
 FROM lab_result_items
 WHERE lab_result_id = $1
 ORDER BY id
 `
 
-func (q *Queries) ListLabResultItemsByResultID(ctx context.Context, labResultID uuid.UUID) ([]LabResultItem, error) {
+type ListLabResultItemsByResultIDRow struct {
+	ID               uuid.UUID     `json:"id"`
+	LabResultID      uuid.UUID     `json:"lab_result_id"`
+	ParameterName    string        `json:"parameter_name"`
+	ResultValue      pgtype.Text   `json:"result_value"`
+	ResultUnit       pgtype.Text   `json:"result_unit"`
+	ReferenceText    pgtype.Text   `json:"reference_text"`
+	NumericValue     pgtype.Float8 `json:"numeric_value"`
+	Comparator       pgtype.Text   `json:"comparator"`
+	QualitativeValue pgtype.Text   `json:"qualitative_value"`
+	ReferenceLow     pgtype.Float8 `json:"reference_low"`
+	ReferenceHigh    pgtype.Float8 `json:"reference_high"`
+	Interpretation   pgtype.Text   `json:"interpretation"`
+	AnalyteCode      pgtype.Text   `json:"analyte_code"`
+	UcumUnit         pgtype.Text   `json:"ucum_unit"`
+	Confidence       pgtype.Float8 `json:"confidence"`
+	FieldConfidence  []byte        `json:"field_confidence"`
+	Confirmed        bool          `json:"confirmed"`
+	Derived          bool          `json:"derived"`
+	Formula          pgtype.Text   `json:"formula"`
+	AnalyteLink      pgtype.Text   `json:"analyte_link"`
+}
+
+func (q *Queries) ListLabResultItemsByResultID(ctx context.Context, labResultID uuid.UUID) ([]ListLabResultItemsByResultIDRow, error) {
 	rows, err := q.db.Query(ctx, listLabResultItemsByResultID, labResultID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []LabResultItem
+	var items []ListLabResultItemsByResultIDRow
 	for rows.Next() {
-		var i LabResultItem
+		var i ListLabResultItemsByResultIDRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.LabResultID,
@@ -541,6 +1905,20 @@ func (q *Queries) ListLabResultItemsByResultID(ctx context.Context, labResultID
 			&i.ResultValue,
 			&i.ResultUnit,
 			&i.ReferenceText,
+			&i.NumericValue,
+			&i.Comparator,
+			&i.QualitativeValue,
+			&i.ReferenceLow,
+			&i.ReferenceHigh,
+			&i.Interpretation,
+			&i.AnalyteCode,
+			&i.UcumUnit,
+			&i.Confidence,
+			&i.FieldConfidence,
+			&i.Confirmed,
+			&i.Derived,
+			&i.Formula,
+			&i.AnalyteLink,
 		); err != nil {
 			return nil, err
 		}
@@ -587,3 +1965,405 @@ func (q *Queries) ListLabResultsByReportID(ctx context.Context, labReportID uuid
 	}
 	return items, nil
 }
+
+const listPendingLabReviews = `-- name: ListPendingLabReviews :many
+
+SELECT
+    r.id,
+    r.patient_id,
+    p.full_name AS patient_name,
+    r.lab_name,
+    r.report_date,
+    r.created_at,
+    (
+        SELECT count(*)
+        FROM lab_result_items i
+        JOIN lab_results lr ON lr.id = i.lab_result_id
+        WHERE lr.lab_report_id = r.id
+          AND NOT i.confirmed
+          AND (
+              i.confidence < 0.8
+              OR EXISTS (
+                  SELECT 1 FROM jsonb_each_text(i.field_confidence) fc
+                  WHERE fc.value::double precision < 0.8
+              )
+          )
+    )::bigint AS pending_items
+FROM lab_reports r
+JOIN patients p ON p.id = r.patient_id
+WHERE r.review_status = 'needs_review'
+  AND p.deleted_at IS NULL
+  AND (
+      p.owner_user_id = $1
+      OR EXISTS (
+          SELECT 1 FROM patient_access pa
+          WHERE pa.patient_id = r.patient_id
+            AND pa.grantee_id = $1
+            AND pa.revoked_at IS NULL
+      )
+  )
+ORDER BY r.created_at, r.id
+LIMIT $3 OFFSET $2
+`
+
+type ListPendingLabReviewsParams struct {
+	UserID pgtype.UUID `json:"user_id"`
+	Offset int32       `json:"offset"`
+	Limit  int32       `json:"limit"`
+}
+
+type ListPendingLabReviewsRow struct {
+	ID           uuid.UUID          `json:"id"`
+	PatientID    uuid.UUID          `json:"patient_id"`
+	PatientName  string             `json:"patient_name"`
+	LabName      pgtype.Text        `json:"lab_name"`
+	ReportDate   pgtype.Timestamptz `json:"report_date"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	PendingItems int64              `json:"pending_items"`
+}
+
+// ============================================================
+// Review
+// ============================================================
+// Reports waiting for review on patients the user owns or has active access to.
+// pending_items counts unconfirmed items with any confidence below 0.8
+// (labs.LowConfidenceThreshold).
+func (q *Queries) ListPendingLabReviews(ctx context.Context, arg ListPendingLabReviewsParams) ([]ListPendingLabReviewsRow, error) {
+	rows, err := q.db.Query(ctx, listPendingLabReviews, arg.UserID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPendingLabReviewsRow
+	for rows.Next() {
+		var i ListPendingLabReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.PatientName,
+			&i.LabName,
+			&i.ReportDate,
+			&i.CreatedAt,
+			&i.PendingItems,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markLabCriticalAlertNotified = `-- name: MarkLabCriticalAlertNotified :exec
+UPDATE lab_critical_alert_recipients
+SET notified_at = $3
+WHERE alert_id = $1
+  AND user_id = $2
+`
+
+type MarkLabCriticalAlertNotifiedParams struct {
+	AlertID    uuid.UUID          `json:"alert_id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	NotifiedAt pgtype.Timestamptz `json:"notified_at"`
+}
+
+func (q *Queries) MarkLabCriticalAlertNotified(ctx context.Context, arg MarkLabCriticalAlertNotifiedParams) error {
+	_, err := q.db.Exec(ctx, markLabCriticalAlertNotified, arg.AlertID, arg.UserID, arg.NotifiedAt)
+	return err
+}
+
+const markLabProcessingJobFailed = `-- name: MarkLabProcessingJobFailed :execrows
+UPDATE lab_processing_jobs
+SET
+    status        = 'failed',
+    error_code    = $2,
+    error_message = $3,
+    finished_at   = now(),
+    updated_at    = now()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = $4
+`
+
+type MarkLabProcessingJobFailedParams struct {
+	ID           uuid.UUID   `json:"id"`
+	ErrorCode    pgtype.Text `json:"error_code"`
+	ErrorMessage pgtype.Text `json:"error_message"`
+	Attempts     int32       `json:"attempts"`
+}
+
+func (q *Queries) MarkLabProcessingJobFailed(ctx context.Context, arg MarkLabProcessingJobFailedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markLabProcessingJobFailed,
+		arg.ID,
+		arg.ErrorCode,
+		arg.ErrorMessage,
+		arg.Attempts,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markLabProcessingJobSucceeded = `-- name: MarkLabProcessingJobSucceeded :execrows
+UPDATE lab_processing_jobs
+SET
+    status        = 'succeeded',
+    lab_report_id = $2,
+    error_code    = NULL,
+    error_message = NULL,
+    finished_at   = now(),
+    updated_at    = now()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = $3
+`
+
+type MarkLabProcessingJobSucceededParams struct {
+	ID          uuid.UUID   `json:"id"`
+	LabReportID pgtype.UUID `json:"lab_report_id"`
+	Attempts    int32       `json:"attempts"`
+}
+
+// The Mark* updates only apply while the caller still owns the claim: a job
+// requeued as stale and claimed again has a different attempts value.
+func (q *Queries) MarkLabProcessingJobSucceeded(ctx context.Context, arg MarkLabProcessingJobSucceededParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markLabProcessingJobSucceeded, arg.ID, arg.LabReportID, arg.Attempts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const requeueStaleLabProcessingJobs = `-- name: RequeueStaleLabProcessingJobs :execrows
+UPDATE lab_processing_jobs
+SET
+    status     = 'queued',
+    updated_at = now()
+WHERE status = 'running'
+  AND started_at < $1
+  AND attempts < $2::int
+`
+
+type RequeueStaleLabProcessingJobsParams struct {
+	StartedBefore pgtype.Timestamptz `json:"started_before"`
+	MaxAttempts   int32              `json:"max_attempts"`
+}
+
+// Jobs left in running by a crashed worker go back to the queue.
+func (q *Queries) RequeueStaleLabProcessingJobs(ctx context.Context, arg RequeueStaleLabProcessingJobsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, requeueStaleLabProcessingJobs, arg.StartedBefore, arg.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const searchLabReportsByRawText = `-- name: SearchLabReportsByRawText :many
+SELECT
+    id,
+    lab_name,
+    report_date,
+    source,
+    status,
+    created_at,
+    ts_rank(raw_text_tsv, websearch_to_tsquery('portuguese_unaccent', $1::text))::float8 AS rank,
+    -- O texto é escapado antes do destaque: o <mark> do ts_headline é a única
+    -- marcação HTML do trecho.
+    ts_headline(
+        'portuguese_unaccent',
+        replace(replace(replace(COALESCE(raw_text, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+        websearch_to_tsquery('portuguese_unaccent', $1::text),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=25, MinWords=8, FragmentDelimiter=" … "'
+    )::text AS snippet
+FROM lab_reports
+WHERE patient_id = $2
+  AND raw_text_tsv @@ websearch_to_tsquery('portuguese_unaccent', $1::text)
+ORDER BY rank DESC, report_date DESC NULLS LAST, created_at DESC
+LIMIT $4 OFFSET $3
+`
+
+type SearchLabReportsByRawTextParams struct {
+	Query     string    `json:"query"`
+	PatientID uuid.UUID `json:"patient_id"`
+	Offset    int32     `json:"offset"`
+	Limit     int32     `json:"limit"`
+}
+
+type SearchLabReportsByRawTextRow struct {
+	ID         uuid.UUID          `json:"id"`
+	LabName    pgtype.Text        `json:"lab_name"`
+	ReportDate pgtype.Timestamptz `json:"report_date"`
+	Source     string             `json:"source"`
+	Status     string             `json:"status"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Rank       float64            `json:"rank"`
+	Snippet    string             `json:"snippet"`
+}
+
+// Ranked full-text search over raw_text; snippet marks the hits with <mark>.
+func (q *Queries) SearchLabReportsByRawText(ctx context.Context, arg SearchLabReportsByRawTextParams) ([]SearchLabReportsByRawTextRow, error) {
+	rows, err := q.db.Query(ctx, searchLabReportsByRawText,
+		arg.Query,
+		arg.PatientID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchLabReportsByRawTextRow
+	for rows.Next() {
+		var i SearchLabReportsByRawTextRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.LabName,
+			&i.ReportDate,
+			&i.Source,
+			&i.Status,
+			&i.CreatedAt,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setLabResultItemInterpretation = `-- name: SetLabResultItemInterpretation :exec
+UPDATE lab_result_items
+SET interpretation = $2
+WHERE id = $1
+`
+
+type SetLabResultItemInterpretationParams struct {
+	ID             uuid.UUID   `json:"id"`
+	Interpretation pgtype.Text `json:"interpretation"`
+}
+
+func (q *Queries) SetLabResultItemInterpretation(ctx context.Context, arg SetLabResultItemInterpretationParams) error {
+	_, err := q.db.Exec(ctx, setLabResultItemInterpretation, arg.ID, arg.Interpretation)
+	return err
+}
+
+const setLabResultItemsAnalyteByParameterName = `-- name: SetLabResultItemsAnalyteByParameterName :execrows
+UPDATE lab_result_items
+SET analyte_code = $1,
+    analyte_link = CASE WHEN $1::text IS NULL THEN NULL ELSE 'name' END
+WHERE parameter_name = $2
+  AND NOT derived
+  AND analyte_link IS DISTINCT FROM 'code'
+  AND analyte_code IS DISTINCT FROM $1
+`
+
+type SetLabResultItemsAnalyteByParameterNameParams struct {
+	AnalyteCode   pgtype.Text `json:"analyte_code"`
+	ParameterName string      `json:"parameter_name"`
+}
+
+// Only touches name-linked rows whose link actually changes; derived items and
+// items linked by code (LOINC from FHIR/HL7 v2) keep theirs.
+func (q *Queries) SetLabResultItemsAnalyteByParameterName(ctx context.Context, arg SetLabResultItemsAnalyteByParameterNameParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setLabResultItemsAnalyteByParameterName, arg.AnalyteCode, arg.ParameterName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateLabResult = `-- name: UpdateLabResult :exec
+UPDATE lab_results
+SET test_name    = $2,
+    material     = $3,
+    method       = $4,
+    collected_at = $5,
+    release_at   = $6
+WHERE id = $1
+`
+
+type UpdateLabResultParams struct {
+	ID          uuid.UUID          `json:"id"`
+	TestName    string             `json:"test_name"`
+	Material    pgtype.Text        `json:"material"`
+	Method      pgtype.Text        `json:"method"`
+	CollectedAt pgtype.Timestamptz `json:"collected_at"`
+	ReleaseAt   pgtype.Timestamptz `json:"release_at"`
+}
+
+func (q *Queries) UpdateLabResult(ctx context.Context, arg UpdateLabResultParams) error {
+	_, err := q.db.Exec(ctx, updateLabResult,
+		arg.ID,
+		arg.TestName,
+		arg.Material,
+		arg.Method,
+		arg.CollectedAt,
+		arg.ReleaseAt,
+	)
+	return err
+}
+
+const updateLabResultItem = `-- name: UpdateLabResultItem :exec
+UPDATE lab_result_items
+SET parameter_name    = $2,
+    result_value      = $3,
+    result_unit       = $4,
+    reference_text    = $5,
+    numeric_value     = $6,
+    comparator        = $7,
+    qualitative_value = $8,
+    reference_low     = $9,
+    reference_high    = $10,
+    interpretation    = $11,
+    analyte_code      = $12,
+    ucum_unit         = $13,
+    confirmed         = $14,
+    analyte_link      = $15
+WHERE id = $1
+`
+
+type UpdateLabResultItemParams struct {
+	ID               uuid.UUID     `json:"id"`
+	ParameterName    string        `json:"parameter_name"`
+	ResultValue      pgtype.Text   `json:"result_value"`
+	ResultUnit       pgtype.Text   `json:"result_unit"`
+	ReferenceText    pgtype.Text   `json:"reference_text"`
+	NumericValue     pgtype.Float8 `json:"numeric_value"`
+	Comparator       pgtype.Text   `json:"comparator"`
+	QualitativeValue pgtype.Text   `json:"qualitative_value"`
+	ReferenceLow     pgtype.Float8 `json:"reference_low"`
+	ReferenceHigh    pgtype.Float8 `json:"reference_high"`
+	Interpretation   pgtype.Text   `json:"interpretation"`
+	AnalyteCode      pgtype.Text   `json:"analyte_code"`
+	UcumUnit         pgtype.Text   `json:"ucum_unit"`
+	Confirmed        bool          `json:"confirmed"`
+	AnalyteLink      pgtype.Text   `json:"analyte_link"`
+}
+
+func (q *Queries) UpdateLabResultItem(ctx context.Context, arg UpdateLabResultItemParams) error {
+	_, err := q.db.Exec(ctx, updateLabResultItem,
+		arg.ID,
+		arg.ParameterName,
+		arg.ResultValue,
+		arg.ResultUnit,
+		arg.ReferenceText,
+		arg.NumericValue,
+		arg.Comparator,
+		arg.QualitativeValue,
+		arg.ReferenceLow,
+		arg.ReferenceHigh,
+		arg.Interpretation,
+		arg.AnalyteCode,
+		arg.UcumUnit,
+		arg.Confirmed,
+		arg.AnalyteLink,
+	)
+	return err
+}
@@ -9,6 +9,78 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type LabAnalyte struct {
+	Code        string             `json:"code"`
+	LoincCode   pgtype.Text        `json:"loinc_code"`
+	DisplayName string             `json:"display_name"`
+	DefaultUnit pgtype.Text        `json:"default_unit"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type LabAnalyteSynonym struct {
+	NameKey     string             `json:"name_key"`
+	AnalyteCode string             `json:"analyte_code"`
+	Name        string             `json:"name"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type LabCriticalAlert struct {
+	ID              uuid.UUID          `json:"id"`
+	LabReportID     uuid.UUID          `json:"lab_report_id"`
+	PatientID       uuid.UUID          `json:"patient_id"`
+	LabResultItemID uuid.UUID          `json:"lab_result_item_id"`
+	RuleID          pgtype.UUID        `json:"rule_id"`
+	AnalyteCode     string             `json:"analyte_code"`
+	ParameterName   string             `json:"parameter_name"`
+	Value           float64            `json:"value"`
+	LimitValue      float64            `json:"limit_value"`
+	Unit            string             `json:"unit"`
+	Interpretation  string             `json:"interpretation"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type LabCriticalAlertRecipient struct {
+	AlertID        uuid.UUID          `json:"alert_id"`
+	UserID         uuid.UUID          `json:"user_id"`
+	NotifiedAt     pgtype.Timestamptz `json:"notified_at"`
+	AcknowledgedAt pgtype.Timestamptz `json:"acknowledged_at"`
+}
+
+type LabCriticalRule struct {
+	ID          uuid.UUID          `json:"id"`
+	AnalyteCode string             `json:"analyte_code"`
+	Sex         pgtype.Text        `json:"sex"`
+	MinAge      pgtype.Int4        `json:"min_age"`
+	MaxAge      pgtype.Int4        `json:"max_age"`
+	Low         pgtype.Float8      `json:"low"`
+	High        pgtype.Float8      `json:"high"`
+	Unit        string             `json:"unit"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	DeletedBy   pgtype.UUID        `json:"deleted_by"`
+}
+
+type LabProcessingJob struct {
+	ID               uuid.UUID          `json:"id"`
+	PatientID        uuid.UUID          `json:"patient_id"`
+	UploadedByUserID uuid.UUID          `json:"uploaded_by_user_id"`
+	DocumentUri      string             `json:"document_uri"`
+	MimeType         string             `json:"mime_type"`
+	Status           string             `json:"status"`
+	Attempts         int32              `json:"attempts"`
+	LabReportID      pgtype.UUID        `json:"lab_report_id"`
+	ErrorCode        pgtype.Text        `json:"error_code"`
+	ErrorMessage     pgtype.Text        `json:"error_message"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	StartedAt        pgtype.Timestamptz `json:"started_at"`
+	FinishedAt       pgtype.Timestamptz `json:"finished_at"`
+	ContentSha256    pgtype.Text        `json:"content_sha256"`
+	IdentityOverride bool               `json:"identity_override"`
+}
+
 type LabReport struct {
 	ID                uuid.UUID          `json:"id"`
 	PatientID         uuid.UUID          `json:"patient_id"`
@@ -22,11 +94,35 @@ type LabReport struct {
 	TechnicalManager  pgtype.Text        `json:"technical_manager"`
 	ReportDate        pgtype.Timestamptz `json:"report_date"`
 	RawText           pgtype.Text        `json:"raw_text"`
+	RawTextTsv        interface{}        `json:"raw_text_tsv"`
 	Fingerprint       pgtype.Text        `json:"fingerprint"`
+	DocumentUri       pgtype.Text        `json:"document_uri"`
+	MimeType          pgtype.Text        `json:"mime_type"`
+	ContentSha256     pgtype.Text        `json:"content_sha256"`
+	IdentityStatus    pgtype.Text        `json:"identity_status"`
+	IdentityScore     pgtype.Float8      `json:"identity_score"`
+	FieldConfidence   []byte             `json:"field_confidence"`
+	ReviewStatus      pgtype.Text        `json:"review_status"`
+	ReviewedByUserID  pgtype.UUID        `json:"reviewed_by_user_id"`
+	ReviewedAt        pgtype.Timestamptz `json:"reviewed_at"`
+	Source            string             `json:"source"`
+	Status            string             `json:"status"`
+	Version           int32              `json:"version"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
+type LabReportRevision struct {
+	ID              uuid.UUID          `json:"id"`
+	LabReportID     uuid.UUID          `json:"lab_report_id"`
+	Version         int32              `json:"version"`
+	Status          string             `json:"status"`
+	ChangedByUserID uuid.UUID          `json:"changed_by_user_id"`
+	ChangedAt       pgtype.Timestamptz `json:"changed_at"`
+	Reason          pgtype.Text        `json:"reason"`
+	Changes         []byte             `json:"changes"`
+}
+
 type LabResult struct {
 	ID          uuid.UUID          `json:"id"`
 	LabReportID uuid.UUID          `json:"lab_report_id"`
@@ -38,12 +134,26 @@ type LabResult struct {
 }
 
 type LabResultItem struct {
-	ID            uuid.UUID   `json:"id"`
-	LabResultID   uuid.UUID   `json:"lab_result_id"`
-	ParameterName string      `json:"parameter_name"`
-	ResultValue   pgtype.Text `json:"result_value"`
-	ResultUnit    pgtype.Text `json:"result_unit"`
-	ReferenceText pgtype.Text `json:"reference_text"`
+	ID               uuid.UUID     `json:"id"`
+	LabResultID      uuid.UUID     `json:"lab_result_id"`
+	ParameterName    string        `json:"parameter_name"`
+	ResultValue      pgtype.Text   `json:"result_value"`
+	ResultUnit       pgtype.Text   `json:"result_unit"`
+	ReferenceText    pgtype.Text   `json:"reference_text"`
+	NumericValue     pgtype.Float8 `json:"numeric_value"`
+	UcumUnit         pgtype.Text   `json:"ucum_unit"`
+	Comparator       pgtype.Text   `json:"comparator"`
+	QualitativeValue pgtype.Text   `json:"qualitative_value"`
+	ReferenceLow     pgtype.Float8 `json:"reference_low"`
+	ReferenceHigh    pgtype.Float8 `json:"reference_high"`
+	Interpretation   pgtype.Text   `json:"interpretation"`
+	AnalyteCode      pgtype.Text   `json:"analyte_code"`
+	AnalyteLink      pgtype.Text   `json:"analyte_link"`
+	Confidence       pgtype.Float8 `json:"confidence"`
+	FieldConfidence  []byte        `json:"field_confidence"`
+	Confirmed        bool          `json:"confirmed"`
+	Derived          bool          `json:"derived"`
+	Formula          pgtype.Text   `json:"formula"`
 }
 
 type Patient struct {
@@ -62,6 +172,15 @@ type Patient struct {
 	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type PatientAccess struct {
+	PatientID    uuid.UUID          `json:"patient_id"`
+	GranteeID    uuid.UUID          `json:"grantee_id"`
+	RelationType string             `json:"relation_type"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	RevokedAt    pgtype.Timestamptz `json:"revoked_at"`
+	GrantedBy    pgtype.UUID        `json:"granted_by"`
+}
+
 type User struct {
 	ID          uuid.UUID          `json:"id"`
 	AuthIssuer  string             `json:"auth_issuer"`
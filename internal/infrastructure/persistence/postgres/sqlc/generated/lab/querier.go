@@ -11,37 +11,121 @@ import (
 )
 
 type Querier interface {
+	// Keeps the first acknowledgement; zero rows when the user is not a recipient.
+	AcknowledgeLabCriticalAlert(ctx context.Context, arg AcknowledgeLabCriticalAlertParams) (int64, error)
+	// ============================================================
+	// Amendments
+	// ============================================================
+	// Optimistic lock: only applies when the stored version is still the one read.
+	AmendLabReport(ctx context.Context, arg AmendLabReportParams) (int64, error)
+	// Claims the oldest queued job. SKIP LOCKED lets several workers poll concurrently.
+	ClaimNextLabProcessingJob(ctx context.Context) (LabProcessingJob, error)
+	CreateLabAnalyteSynonym(ctx context.Context, arg CreateLabAnalyteSynonymParams) error
+	CreateLabCriticalAlert(ctx context.Context, arg CreateLabCriticalAlertParams) error
+	CreateLabCriticalAlertRecipient(ctx context.Context, arg CreateLabCriticalAlertRecipientParams) error
+	CreateLabCriticalRule(ctx context.Context, arg CreateLabCriticalRuleParams) error
+	// ============================================================
+	// Processing jobs
+	// ============================================================
+	CreateLabProcessingJob(ctx context.Context, arg CreateLabProcessingJobParams) (LabProcessingJob, error)
 	// ============================================================
 	// Creators
 	// ============================================================
 	CreateLabReport(ctx context.Context, arg CreateLabReportParams) (CreateLabReportRow, error)
+	CreateLabReportRevision(ctx context.Context, arg CreateLabReportRevisionParams) error
 	CreateLabResult(ctx context.Context, arg CreateLabResultParams) (uuid.UUID, error)
 	CreateLabResultItem(ctx context.Context, arg CreateLabResultItemParams) (uuid.UUID, error)
+	// Derived items are replaced as a whole when the report is recomputed; the
+	// results that only held derived items go with them. The CTE runs on the
+	// same snapshot, so the NOT EXISTS still sees the deleted rows.
+	DeleteDerivedLabItemsByReportID(ctx context.Context, labReportID uuid.UUID) error
+	DeleteLabAnalyteSynonym(ctx context.Context, arg DeleteLabAnalyteSynonymParams) (int64, error)
+	// Soft delete: alerts keep pointing at the rule that fired them.
+	DeleteLabCriticalRule(ctx context.Context, arg DeleteLabCriticalRuleParams) (int64, error)
 	DeleteLabReport(ctx context.Context, id uuid.UUID) (int64, error)
 	// ============================================================
 	// Deletes
 	// ============================================================
 	DeleteLabResultItemsByReportID(ctx context.Context, labReportID uuid.UUID) (int64, error)
 	DeleteLabResultsByReportID(ctx context.Context, labReportID uuid.UUID) (int64, error)
+	ExistsLabReportByPatientAndFingerprint(ctx context.Context, arg ExistsLabReportByPatientAndFingerprintParams) (bool, error)
+	// Stale jobs that already used every attempt (e.g. a document that crashes
+	// the worker) are failed instead of requeued.
+	FailStaleLabProcessingJobs(ctx context.Context, arg FailStaleLabProcessingJobsParams) (int64, error)
+	// Jobs still being processed for the same file and patient.
+	FindActiveLabProcessingJobIDByContentHash(ctx context.Context, arg FindActiveLabProcessingJobIDByContentHashParams) (uuid.UUID, error)
 	// ============================================================
 	// Dedupe (Existence checks)
 	// ============================================================
-	ExistsLabReportByPatientAndFingerprint(ctx context.Context, arg ExistsLabReportByPatientAndFingerprintParams) (bool, error)
+	FindLabReportIDByContentHash(ctx context.Context, arg FindLabReportIDByContentHashParams) (uuid.UUID, error)
+	GetLabAnalyteByCode(ctx context.Context, code string) (GetLabAnalyteByCodeRow, error)
+	GetLabAnalyteSynonymByKey(ctx context.Context, nameKey string) (GetLabAnalyteSynonymByKeyRow, error)
+	GetLabProcessingJobByID(ctx context.Context, id uuid.UUID) (LabProcessingJob, error)
 	// ============================================================
 	// Getters
 	// ============================================================
 	GetLabReportByID(ctx context.Context, id uuid.UUID) (GetLabReportByIDRow, error)
 	GetLabResultsByReportID(ctx context.Context, labReportID uuid.UUID) (GetLabResultsByReportIDRow, error)
+	ListDistinctLabParameterNames(ctx context.Context) ([]string, error)
+	ListLabAnalyteSynonyms(ctx context.Context) ([]ListLabAnalyteSynonymsRow, error)
+	// ============================================================
+	// Analyte catalog
+	// ============================================================
+	ListLabAnalytes(ctx context.Context) ([]ListLabAnalytesRow, error)
+	ListLabCriticalAlertItemIDsByReport(ctx context.Context, labReportID uuid.UUID) ([]uuid.UUID, error)
+	// Alerts sent to the user, newest first; only_open hides acknowledged ones.
+	ListLabCriticalAlertsByRecipient(ctx context.Context, arg ListLabCriticalAlertsByRecipientParams) ([]ListLabCriticalAlertsByRecipientRow, error)
+	// ============================================================
+	// Critical values
+	// ============================================================
+	ListLabCriticalRules(ctx context.Context) ([]ListLabCriticalRulesRow, error)
+	// ============================================================
+	// History export
+	// ============================================================
+	// One row per item with its report and result, oldest collection first.
+	ListLabHistoryByPatient(ctx context.Context, arg ListLabHistoryByPatientParams) ([]ListLabHistoryByPatientRow, error)
+	// Every item of the patient in the period, for the cumulative report and exports.
+	ListLabItemTimelineByPatient(ctx context.Context, arg ListLabItemTimelineByPatientParams) ([]ListLabItemTimelineByPatientRow, error)
 	// ============================================================
 	// Timeline
 	// ============================================================
+	// Matches the exact parameter name or, when it resolves to a catalog analyte,
+	// every item linked to that analyte.
+	// Period filters use the collection time, falling back to the report date.
 	ListLabItemTimelineByPatientAndParameter(ctx context.Context, arg ListLabItemTimelineByPatientAndParameterParams) ([]ListLabItemTimelineByPatientAndParameterRow, error)
+	// Candidates for a name key: the name folded like labs.NormalizeAnalyteName
+	// (lower case, same accents) contains the key words in order. Callers filter
+	// the exact matches with labs.MatchKeys.
+	ListLabParameterNamesLike(ctx context.Context, pattern string) ([]string, error)
+	ListLabReportRevisions(ctx context.Context, labReportID uuid.UUID) ([]LabReportRevision, error)
 	// ============================================================
 	// List
 	// ============================================================
 	ListLabReportsByPatientID(ctx context.Context, arg ListLabReportsByPatientIDParams) ([]ListLabReportsByPatientIDRow, error)
-	ListLabResultItemsByResultID(ctx context.Context, labResultID uuid.UUID) ([]LabResultItem, error)
+	ListLabResultItemsByResultID(ctx context.Context, labResultID uuid.UUID) ([]ListLabResultItemsByResultIDRow, error)
 	ListLabResultsByReportID(ctx context.Context, labReportID uuid.UUID) ([]LabResult, error)
+	// ============================================================
+	// Review
+	// ============================================================
+	// Reports waiting for review on patients the user owns or has active access to.
+	// pending_items counts unconfirmed items with any confidence below 0.8
+	// (labs.LowConfidenceThreshold).
+	ListPendingLabReviews(ctx context.Context, arg ListPendingLabReviewsParams) ([]ListPendingLabReviewsRow, error)
+	MarkLabCriticalAlertNotified(ctx context.Context, arg MarkLabCriticalAlertNotifiedParams) error
+	MarkLabProcessingJobFailed(ctx context.Context, arg MarkLabProcessingJobFailedParams) (int64, error)
+	// The Mark* updates only apply while the caller still owns the claim: a job
+	// requeued as stale and claimed again has a different attempts value.
+	MarkLabProcessingJobSucceeded(ctx context.Context, arg MarkLabProcessingJobSucceededParams) (int64, error)
+	// Jobs left in running by a crashed worker go back to the queue.
+	RequeueStaleLabProcessingJobs(ctx context.Context, arg RequeueStaleLabProcessingJobsParams) (int64, error)
+	// Ranked full-text search over raw_text; snippet marks the hits with <mark>.
+	SearchLabReportsByRawText(ctx context.Context, arg SearchLabReportsByRawTextParams) ([]SearchLabReportsByRawTextRow, error)
+	SetLabResultItemInterpretation(ctx context.Context, arg SetLabResultItemInterpretationParams) error
+	// Only touches name-linked rows whose link actually changes; derived items and
+	// items linked by code (LOINC from FHIR/HL7 v2) keep theirs.
+	SetLabResultItemsAnalyteByParameterName(ctx context.Context, arg SetLabResultItemsAnalyteByParameterNameParams) (int64, error)
+	UpdateLabResult(ctx context.Context, arg UpdateLabResultParams) error
+	UpdateLabResultItem(ctx context.Context, arg UpdateLabResultItemParams) error
 }
 
 var _ Querier = (*Queries)(nil)
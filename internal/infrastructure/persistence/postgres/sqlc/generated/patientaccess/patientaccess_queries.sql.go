@@ -115,6 +115,40 @@ func (q *Queries) ListAccessiblePatientsByUser(ctx context.Context, arg ListAcce
 	return items, nil
 }
 
+const listActiveGranteesByRelation = `-- name: ListActiveGranteesByRelation :many
+SELECT grantee_id
+FROM patient_access
+WHERE patient_id = $1
+  AND relation_type = $2
+  AND revoked_at IS NULL
+ORDER BY grantee_id
+`
+
+type ListActiveGranteesByRelationParams struct {
+	PatientID    pgtype.UUID `json:"patient_id"`
+	RelationType string      `json:"relation_type"`
+}
+
+func (q *Queries) ListActiveGranteesByRelation(ctx context.Context, arg ListActiveGranteesByRelationParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listActiveGranteesByRelation, arg.PatientID, arg.RelationType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var grantee_id pgtype.UUID
+		if err := rows.Scan(&grantee_id); err != nil {
+			return nil, err
+		}
+		items = append(items, grantee_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPatientAccessByPatient = `-- name: ListPatientAccessByPatient :many
 SELECT
     patient_id,
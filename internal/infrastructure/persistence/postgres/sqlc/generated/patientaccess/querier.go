@@ -17,6 +17,7 @@ type Querier interface {
 	// Minimal list of patients accessible by a user (for UI listing)
 	// Returns patient basic info and the relation type. Paginates by full_name.
 	ListAccessiblePatientsByUser(ctx context.Context, arg ListAccessiblePatientsByUserParams) ([]ListAccessiblePatientsByUserRow, error)
+	ListActiveGranteesByRelation(ctx context.Context, arg ListActiveGranteesByRelationParams) ([]pgtype.UUID, error)
 	ListPatientAccessByPatient(ctx context.Context, patientID pgtype.UUID) ([]PatientAccess, error)
 	ListPatientAccessByUser(ctx context.Context, granteeID pgtype.UUID) ([]PatientAccess, error)
 	RevokePatientAccess(ctx context.Context, arg RevokePatientAccessParams) (int64, error)
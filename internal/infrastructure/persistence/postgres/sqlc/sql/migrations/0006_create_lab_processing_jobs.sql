@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Lab processing jobs: async extraction queue for uploaded lab documents.
+CREATE TABLE lab_processing_jobs (
+    id                  UUID PRIMARY KEY,
+    patient_id          UUID NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    uploaded_by_user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    document_uri        TEXT NOT NULL,
+    mime_type           TEXT NOT NULL,
+    status              TEXT NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    attempts            INTEGER NOT NULL DEFAULT 0,
+    lab_report_id       UUID REFERENCES lab_reports(id) ON DELETE SET NULL,
+    error_code          TEXT,
+    error_message       TEXT,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    started_at          TIMESTAMP WITH TIME ZONE,
+    finished_at         TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_lab_processing_jobs_queued ON lab_processing_jobs(created_at) WHERE status = 'queued';
+CREATE INDEX idx_lab_processing_jobs_patient ON lab_processing_jobs(patient_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_lab_processing_jobs_patient;
+DROP INDEX IF EXISTS idx_lab_processing_jobs_queued;
+DROP TABLE IF EXISTS lab_processing_jobs;
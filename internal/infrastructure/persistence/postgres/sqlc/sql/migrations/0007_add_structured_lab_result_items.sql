@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Structured results: numeric value, parsed reference range and interpretation flag.
+ALTER TABLE lab_result_items
+    ADD COLUMN numeric_value     DOUBLE PRECISION,
+    ADD COLUMN comparator        TEXT
+        CHECK (comparator IN ('<', '<=', '>', '>=')),
+    ADD COLUMN qualitative_value TEXT,
+    ADD COLUMN reference_low     DOUBLE PRECISION,
+    ADD COLUMN reference_high    DOUBLE PRECISION,
+    ADD COLUMN interpretation    TEXT
+        CHECK (interpretation IN ('L', 'N', 'H', 'LL', 'HH', 'A'));
+
+-- +migrate Down
+ALTER TABLE lab_result_items
+    DROP COLUMN IF EXISTS interpretation,
+    DROP COLUMN IF EXISTS reference_high,
+    DROP COLUMN IF EXISTS reference_low,
+    DROP COLUMN IF EXISTS qualitative_value,
+    DROP COLUMN IF EXISTS comparator,
+    DROP COLUMN IF EXISTS numeric_value;
@@ -0,0 +1,187 @@
+-- +migrate Up
+-- Analyte catalog: canonical analytes (LOINC) and the names labs print for them.
+CREATE TABLE lab_analytes (
+    code         TEXT PRIMARY KEY,
+    loinc_code   TEXT UNIQUE,
+    display_name TEXT NOT NULL,
+    default_unit TEXT,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- name_key is the normalized name (lower case, no accents/punctuation), so each
+-- spelling resolves to exactly one analyte.
+CREATE TABLE lab_analyte_synonyms (
+    name_key     TEXT PRIMARY KEY,
+    analyte_code TEXT NOT NULL REFERENCES lab_analytes(code) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_lab_analyte_synonyms_analyte ON lab_analyte_synonyms(analyte_code);
+
+ALTER TABLE lab_result_items
+    ADD COLUMN analyte_code TEXT REFERENCES lab_analytes(code) ON DELETE SET NULL;
+
+CREATE INDEX idx_lab_result_items_analyte ON lab_result_items(analyte_code);
+
+INSERT INTO lab_analytes (code, loinc_code, display_name, default_unit) VALUES
+    ('hemoglobin', '718-7', 'Hemoglobina', 'g/dL'),
+    ('hematocrit', '4544-3', 'Hematócrito', '%'),
+    ('erythrocytes', '789-8', 'Hemácias', '10*6/uL'),
+    ('mcv', '787-2', 'VCM', 'fL'),
+    ('mch', '785-6', 'HCM', 'pg'),
+    ('mchc', '786-4', 'CHCM', 'g/dL'),
+    ('rdw', '788-0', 'RDW', '%'),
+    ('leukocytes', '6690-2', 'Leucócitos', '10*3/uL'),
+    ('platelets', '777-3', 'Plaquetas', '10*3/uL'),
+    ('glucose', '2345-7', 'Glicose', 'mg/dL'),
+    ('hba1c', '4548-4', 'Hemoglobina glicada', '%'),
+    ('insulin', '20448-7', 'Insulina', 'u[IU]/mL'),
+    ('creatinine', '2160-0', 'Creatinina', 'mg/dL'),
+    ('urea', '3091-6', 'Ureia', 'mg/dL'),
+    ('uric-acid', '3084-1', 'Ácido úrico', 'mg/dL'),
+    ('cholesterol-total', '2093-3', 'Colesterol total', 'mg/dL'),
+    ('cholesterol-hdl', '2085-9', 'HDL colesterol', 'mg/dL'),
+    ('cholesterol-ldl', '13457-7', 'LDL colesterol', 'mg/dL'),
+    ('cholesterol-vldl', '13458-5', 'VLDL colesterol', 'mg/dL'),
+    ('cholesterol-non-hdl', '43396-1', 'Colesterol não-HDL', 'mg/dL'),
+    ('triglycerides', '2571-8', 'Triglicérides', 'mg/dL'),
+    ('tsh', '3016-3', 'TSH', 'm[IU]/L'),
+    ('free-t4', '3024-7', 'T4 livre', 'ng/dL'),
+    ('ast', '1920-8', 'AST', 'U/L'),
+    ('alt', '1742-6', 'ALT', 'U/L'),
+    ('ggt', '2324-2', 'Gama GT', 'U/L'),
+    ('alkaline-phosphatase', '6768-6', 'Fosfatase alcalina', 'U/L'),
+    ('bilirubin-total', '1975-2', 'Bilirrubina total', 'mg/dL'),
+    ('albumin', '1751-7', 'Albumina', 'g/dL'),
+    ('sodium', '2951-2', 'Sódio', 'mmol/L'),
+    ('potassium', '2823-3', 'Potássio', 'mmol/L'),
+    ('calcium', '17861-6', 'Cálcio', 'mg/dL'),
+    ('vitamin-d-25oh', '1989-3', '25-hidroxivitamina D', 'ng/mL'),
+    ('vitamin-b12', '2132-9', 'Vitamina B12', 'pg/mL'),
+    ('ferritin', '2276-4', 'Ferritina', 'ng/mL'),
+    ('crp', '1988-5', 'Proteína C reativa', 'mg/L'),
+    ('psa-total', '2857-1', 'PSA total', 'ng/mL'),
+    ('egfr', '98979-8', 'TFG estimada', 'mL/min/{1.73_m2}');
+
+INSERT INTO lab_analyte_synonyms (name_key, analyte_code, name) VALUES
+    ('hemoglobina', 'hemoglobin', 'Hemoglobina'),
+    ('hb', 'hemoglobin', 'Hb'),
+    ('hgb', 'hemoglobin', 'Hgb'),
+    ('hemoglobina total', 'hemoglobin', 'Hemoglobina total'),
+    ('hematocrito', 'hematocrit', 'Hematócrito'),
+    ('ht', 'hematocrit', 'Ht'),
+    ('hct', 'hematocrit', 'Hct'),
+    ('hemacias', 'erythrocytes', 'Hemácias'),
+    ('eritrocitos', 'erythrocytes', 'Eritrócitos'),
+    ('globulos vermelhos', 'erythrocytes', 'Glóbulos vermelhos'),
+    ('contagem de hemacias', 'erythrocytes', 'Contagem de hemácias'),
+    ('vcm', 'mcv', 'VCM'),
+    ('volume corpuscular medio', 'mcv', 'Volume corpuscular médio'),
+    ('hcm', 'mch', 'HCM'),
+    ('hemoglobina corpuscular media', 'mch', 'Hemoglobina corpuscular média'),
+    ('chcm', 'mchc', 'CHCM'),
+    ('concentracao de hemoglobina corpuscular media', 'mchc', 'Concentração de hemoglobina corpuscular média'),
+    ('rdw', 'rdw', 'RDW'),
+    ('rdw cv', 'rdw', 'RDW-CV'),
+    ('leucocitos', 'leukocytes', 'Leucócitos'),
+    ('leucocitos totais', 'leukocytes', 'Leucócitos totais'),
+    ('globulos brancos', 'leukocytes', 'Glóbulos brancos'),
+    ('leucometria', 'leukocytes', 'Leucometria'),
+    ('plaquetas', 'platelets', 'Plaquetas'),
+    ('contagem de plaquetas', 'platelets', 'Contagem de plaquetas'),
+    ('plaquetometria', 'platelets', 'Plaquetometria'),
+    ('glicose', 'glucose', 'Glicose'),
+    ('glicemia', 'glucose', 'Glicemia'),
+    ('glicemia de jejum', 'glucose', 'Glicemia de jejum'),
+    ('glicose em jejum', 'glucose', 'Glicose em jejum'),
+    ('glicose jejum', 'glucose', 'Glicose jejum'),
+    ('hemoglobina glicada', 'hba1c', 'Hemoglobina glicada'),
+    ('hba1c', 'hba1c', 'HbA1c'),
+    ('a1c', 'hba1c', 'A1C'),
+    ('hemoglobina glicosilada', 'hba1c', 'Hemoglobina glicosilada'),
+    ('hemoglobina glicada a1c', 'hba1c', 'Hemoglobina glicada A1c'),
+    ('insulina', 'insulin', 'Insulina'),
+    ('insulina basal', 'insulin', 'Insulina basal'),
+    ('insulina de jejum', 'insulin', 'Insulina de jejum'),
+    ('creatinina', 'creatinine', 'Creatinina'),
+    ('creatinina serica', 'creatinine', 'Creatinina sérica'),
+    ('ureia', 'urea', 'Ureia'),
+    ('acido urico', 'uric-acid', 'Ácido úrico'),
+    ('colesterol total', 'cholesterol-total', 'Colesterol total'),
+    ('colesterol', 'cholesterol-total', 'Colesterol'),
+    ('hdl colesterol', 'cholesterol-hdl', 'HDL colesterol'),
+    ('hdl', 'cholesterol-hdl', 'HDL'),
+    ('colesterol hdl', 'cholesterol-hdl', 'Colesterol HDL'),
+    ('hdl c', 'cholesterol-hdl', 'HDL-C'),
+    ('ldl colesterol', 'cholesterol-ldl', 'LDL colesterol'),
+    ('ldl', 'cholesterol-ldl', 'LDL'),
+    ('colesterol ldl', 'cholesterol-ldl', 'Colesterol LDL'),
+    ('ldl c', 'cholesterol-ldl', 'LDL-C'),
+    ('vldl colesterol', 'cholesterol-vldl', 'VLDL colesterol'),
+    ('vldl', 'cholesterol-vldl', 'VLDL'),
+    ('colesterol vldl', 'cholesterol-vldl', 'Colesterol VLDL'),
+    ('colesterol nao hdl', 'cholesterol-non-hdl', 'Colesterol não-HDL'),
+    ('nao hdl', 'cholesterol-non-hdl', 'Não-HDL'),
+    ('triglicerides', 'triglycerides', 'Triglicérides'),
+    ('triglicerideos', 'triglycerides', 'Triglicerídeos'),
+    ('tg', 'triglycerides', 'TG'),
+    ('tsh', 'tsh', 'TSH'),
+    ('hormonio tireoestimulante', 'tsh', 'Hormônio tireoestimulante'),
+    ('tsh ultrassensivel', 'tsh', 'TSH ultrassensível'),
+    ('tsh ultra sensivel', 'tsh', 'TSH ultra-sensível'),
+    ('t4 livre', 'free-t4', 'T4 livre'),
+    ('tiroxina livre', 'free-t4', 'Tiroxina livre'),
+    ('ft4', 'free-t4', 'FT4'),
+    ('ast', 'ast', 'AST'),
+    ('tgo', 'ast', 'TGO'),
+    ('aspartato aminotransferase', 'ast', 'Aspartato aminotransferase'),
+    ('transaminase oxalacetica', 'ast', 'Transaminase oxalacética'),
+    ('alt', 'alt', 'ALT'),
+    ('tgp', 'alt', 'TGP'),
+    ('alanina aminotransferase', 'alt', 'Alanina aminotransferase'),
+    ('transaminase piruvica', 'alt', 'Transaminase pirúvica'),
+    ('gama gt', 'ggt', 'Gama GT'),
+    ('ggt', 'ggt', 'GGT'),
+    ('gama glutamil transferase', 'ggt', 'Gama glutamil transferase'),
+    ('gama glutamiltransferase', 'ggt', 'Gama-glutamiltransferase'),
+    ('fosfatase alcalina', 'alkaline-phosphatase', 'Fosfatase alcalina'),
+    ('fa', 'alkaline-phosphatase', 'FA'),
+    ('bilirrubina total', 'bilirubin-total', 'Bilirrubina total'),
+    ('albumina', 'albumin', 'Albumina'),
+    ('albumina serica', 'albumin', 'Albumina sérica'),
+    ('sodio', 'sodium', 'Sódio'),
+    ('na', 'sodium', 'Na'),
+    ('potassio', 'potassium', 'Potássio'),
+    ('k', 'potassium', 'K'),
+    ('calcio', 'calcium', 'Cálcio'),
+    ('calcio total', 'calcium', 'Cálcio total'),
+    ('25 hidroxivitamina d', 'vitamin-d-25oh', '25-hidroxivitamina D'),
+    ('vitamina d', 'vitamin-d-25oh', 'Vitamina D'),
+    ('25 oh vitamina d', 'vitamin-d-25oh', '25-OH vitamina D'),
+    ('vitamina d 25 oh', 'vitamin-d-25oh', 'Vitamina D 25-OH'),
+    ('25 oh d', 'vitamin-d-25oh', '25(OH)D'),
+    ('vitamina b12', 'vitamin-b12', 'Vitamina B12'),
+    ('cianocobalamina', 'vitamin-b12', 'Cianocobalamina'),
+    ('b12', 'vitamin-b12', 'B12'),
+    ('ferritina', 'ferritin', 'Ferritina'),
+    ('proteina c reativa', 'crp', 'Proteína C reativa'),
+    ('pcr', 'crp', 'PCR'),
+    ('psa total', 'psa-total', 'PSA total'),
+    ('psa', 'psa-total', 'PSA'),
+    ('antigeno prostatico especifico', 'psa-total', 'Antígeno prostático específico'),
+    ('tfg estimada', 'egfr', 'TFG estimada'),
+    ('egfr', 'egfr', 'eGFR'),
+    ('taxa de filtracao glomerular', 'egfr', 'Taxa de filtração glomerular'),
+    ('tfge', 'egfr', 'TFGe'),
+    ('filtracao glomerular estimada', 'egfr', 'Filtração glomerular estimada');
+
+-- Existing items are linked by POST /v1/labs/analytes/remap.
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_lab_result_items_analyte;
+ALTER TABLE lab_result_items DROP COLUMN IF EXISTS analyte_code;
+DROP INDEX IF EXISTS idx_lab_analyte_synonyms_analyte;
+DROP TABLE IF EXISTS lab_analyte_synonyms;
+DROP TABLE IF EXISTS lab_analytes;
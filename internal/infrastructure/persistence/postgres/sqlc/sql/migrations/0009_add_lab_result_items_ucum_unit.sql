@@ -0,0 +1,8 @@
+-- +migrate Up
+-- UCUM code parsed from result_unit at ingestion; NULL when the unit is not recognized.
+ALTER TABLE lab_result_items
+    ADD COLUMN ucum_unit TEXT;
+
+-- +migrate Down
+ALTER TABLE lab_result_items
+    DROP COLUMN IF EXISTS ucum_unit;
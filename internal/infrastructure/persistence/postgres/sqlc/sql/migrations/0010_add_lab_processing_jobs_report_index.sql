@@ -0,0 +1,6 @@
+-- +migrate Up
+-- FHIR export looks up the job that produced a report to link the original document.
+CREATE INDEX IF NOT EXISTS idx_lab_processing_jobs_report ON lab_processing_jobs(lab_report_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_lab_processing_jobs_report;
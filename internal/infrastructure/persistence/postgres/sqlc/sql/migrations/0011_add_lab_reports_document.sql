@@ -0,0 +1,18 @@
+-- +migrate Up
+-- Original document of the report (storage URI returned by Upload); NULL for
+-- reports that did not come from a file (FHIR, HL7 v2).
+ALTER TABLE lab_reports
+    ADD COLUMN document_uri TEXT,
+    ADD COLUMN mime_type    TEXT;
+
+UPDATE lab_reports lr
+SET document_uri = j.document_uri,
+    mime_type    = j.mime_type
+FROM lab_processing_jobs j
+WHERE j.lab_report_id = lr.id
+  AND lr.document_uri IS NULL;
+
+-- +migrate Down
+ALTER TABLE lab_reports
+    DROP COLUMN IF EXISTS mime_type,
+    DROP COLUMN IF EXISTS document_uri;
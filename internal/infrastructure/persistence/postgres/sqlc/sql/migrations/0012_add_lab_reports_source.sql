@@ -0,0 +1,15 @@
+-- +migrate Up
+-- How the report entered the system: AI extraction of an uploaded document,
+-- manual entry, FHIR or HL7 v2 import.
+ALTER TABLE lab_reports
+    ADD COLUMN source TEXT NOT NULL DEFAULT 'document'
+        CHECK (source IN ('document', 'manual', 'fhir', 'hl7v2'));
+
+-- Existing rows keep 'document': the imports that skip the upload cannot be
+-- told apart after the fact. New rows must state their source explicitly.
+ALTER TABLE lab_reports
+    ALTER COLUMN source DROP DEFAULT;
+
+-- +migrate Down
+ALTER TABLE lab_reports
+    DROP COLUMN IF EXISTS source;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- FHIR DiagnosticReport.status and the version bumped by each amendment.
+ALTER TABLE lab_reports
+    ADD COLUMN status  TEXT NOT NULL DEFAULT 'final'
+        CHECK (status IN ('preliminary', 'final', 'amended', 'corrected')),
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- One row per amendment; changes holds each field with its previous value.
+CREATE TABLE IF NOT EXISTS lab_report_revisions (
+    id                 UUID PRIMARY KEY,
+    lab_report_id      UUID NOT NULL REFERENCES lab_reports(id) ON DELETE CASCADE,
+    version            INTEGER NOT NULL,
+    status             TEXT NOT NULL
+        CHECK (status IN ('preliminary', 'final', 'amended', 'corrected')),
+    changed_by_user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    changed_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    reason             TEXT,
+    changes            JSONB NOT NULL,
+    UNIQUE (lab_report_id, version)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS lab_report_revisions;
+
+ALTER TABLE lab_reports
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,23 @@
+-- +migrate Up
+-- SHA-256 (hex) of the uploaded file, computed before storage upload and
+-- extraction so a re-uploaded document is rejected without paying for AI.
+ALTER TABLE lab_reports
+    ADD COLUMN content_sha256 TEXT;
+
+ALTER TABLE lab_processing_jobs
+    ADD COLUMN content_sha256 TEXT;
+
+-- Scoped by patient: the same file for another patient is not a duplicate.
+-- Unique so two concurrent uploads of the same file cannot both be saved.
+CREATE UNIQUE INDEX idx_lab_reports_content_sha256
+    ON lab_reports(patient_id, content_sha256)
+    WHERE content_sha256 IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_lab_reports_content_sha256;
+
+ALTER TABLE lab_processing_jobs
+    DROP COLUMN IF EXISTS content_sha256;
+
+ALTER TABLE lab_reports
+    DROP COLUMN IF EXISTS content_sha256;
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Result of cross-checking the name, birth date and CPF printed on an
+-- extracted report against the patient record. NULL for sources that are
+-- matched by identifier (manual, FHIR, HL7 v2).
+ALTER TABLE lab_reports
+    ADD COLUMN identity_status TEXT
+        CHECK (identity_status IN ('verified', 'flagged', 'unverified', 'overridden')),
+    ADD COLUMN identity_score  DOUBLE PRECISION;
+
+-- Set at upload time by a user allowed to accept a mismatching document.
+ALTER TABLE lab_processing_jobs
+    ADD COLUMN identity_override BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE lab_processing_jobs
+    DROP COLUMN IF EXISTS identity_override;
+
+ALTER TABLE lab_reports
+    DROP COLUMN IF EXISTS identity_score,
+    DROP COLUMN IF EXISTS identity_status;
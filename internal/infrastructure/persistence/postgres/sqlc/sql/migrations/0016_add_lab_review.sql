@@ -0,0 +1,30 @@
+-- +migrate Up
+-- Extraction confidence (0..1) per header field; items keep their own below.
+-- review_status is set only for reports that were sent to human review.
+ALTER TABLE lab_reports
+    ADD COLUMN field_confidence    JSONB,
+    ADD COLUMN review_status       TEXT
+        CHECK (review_status IN ('needs_review', 'approved')),
+    ADD COLUMN reviewed_by_user_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    ADD COLUMN reviewed_at         TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE lab_result_items
+    ADD COLUMN confidence       DOUBLE PRECISION,
+    ADD COLUMN field_confidence JSONB,
+    ADD COLUMN confirmed        BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_lab_reports_needs_review ON lab_reports(created_at) WHERE review_status = 'needs_review';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_lab_reports_needs_review;
+
+ALTER TABLE lab_result_items
+    DROP COLUMN IF EXISTS confirmed,
+    DROP COLUMN IF EXISTS field_confidence,
+    DROP COLUMN IF EXISTS confidence;
+
+ALTER TABLE lab_reports
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reviewed_by_user_id,
+    DROP COLUMN IF EXISTS review_status,
+    DROP COLUMN IF EXISTS field_confidence;
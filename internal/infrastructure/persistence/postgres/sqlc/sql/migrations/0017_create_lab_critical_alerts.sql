@@ -0,0 +1,65 @@
+-- +migrate Up
+-- Critical (panic) limits per analyte, optionally per sex and age band
+-- (min_age inclusive, max_age exclusive, in years). Limits are in unit (UCUM).
+-- Rules are global, so deletes are soft and both ends record the admin
+-- (created_by is NULL for the defaults seeded below).
+CREATE TABLE lab_critical_rules (
+    id           UUID PRIMARY KEY,
+    analyte_code TEXT NOT NULL REFERENCES lab_analytes(code) ON DELETE CASCADE,
+    sex          TEXT CHECK (sex IN ('MALE', 'FEMALE', 'OTHER', 'UNKNOWN')),
+    min_age      INTEGER CHECK (min_age >= 0),
+    max_age      INTEGER CHECK (max_age > 0),
+    low          DOUBLE PRECISION,
+    high         DOUBLE PRECISION,
+    unit         TEXT NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    created_by   UUID REFERENCES users(id) ON DELETE SET NULL,
+    deleted_at   TIMESTAMP WITH TIME ZONE,
+    deleted_by   UUID REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (low IS NOT NULL OR high IS NOT NULL),
+    CHECK (min_age IS NULL OR max_age IS NULL OR min_age < max_age)
+);
+
+CREATE UNIQUE INDEX ux_lab_critical_rules_scope
+    ON lab_critical_rules (analyte_code, COALESCE(sex, ''), COALESCE(min_age, -1), COALESCE(max_age, -1))
+    WHERE deleted_at IS NULL;
+
+-- One alert per item beyond a critical limit; value/limit_value are in unit.
+CREATE TABLE lab_critical_alerts (
+    id                 UUID PRIMARY KEY,
+    lab_report_id      UUID NOT NULL REFERENCES lab_reports(id) ON DELETE CASCADE,
+    patient_id         UUID NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    lab_result_item_id UUID NOT NULL REFERENCES lab_result_items(id) ON DELETE CASCADE,
+    rule_id            UUID REFERENCES lab_critical_rules(id) ON DELETE SET NULL,
+    analyte_code       TEXT NOT NULL,
+    parameter_name     TEXT NOT NULL,
+    value              DOUBLE PRECISION NOT NULL,
+    limit_value        DOUBLE PRECISION NOT NULL,
+    unit               TEXT NOT NULL,
+    interpretation     TEXT NOT NULL CHECK (interpretation IN ('LL', 'HH')),
+    created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- Professionals notified of each alert and when they acknowledged it.
+CREATE TABLE lab_critical_alert_recipients (
+    alert_id        UUID NOT NULL REFERENCES lab_critical_alerts(id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notified_at     TIMESTAMP WITH TIME ZONE,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (alert_id, user_id)
+);
+
+CREATE INDEX idx_lab_critical_alerts_report ON lab_critical_alerts(lab_report_id);
+CREATE INDEX idx_lab_critical_alert_recipients_open ON lab_critical_alert_recipients(user_id) WHERE acknowledged_at IS NULL;
+
+-- Defaults: adult and neonatal (< 1 year) limits for potassium and glucose.
+INSERT INTO lab_critical_rules (id, analyte_code, sex, min_age, max_age, low, high, unit) VALUES
+    ('0190c1d2-0000-7000-8000-00000000c001', 'potassium', NULL, 1,    NULL, 2.5, 6.5, 'mmol/L'),
+    ('0190c1d2-0000-7000-8000-00000000c002', 'potassium', NULL, NULL, 1,    2.5, 7.0, 'mmol/L'),
+    ('0190c1d2-0000-7000-8000-00000000c003', 'glucose',   NULL, 1,    NULL, 40,  450, 'mg/dL'),
+    ('0190c1d2-0000-7000-8000-00000000c004', 'glucose',   NULL, NULL, 1,    30,  300, 'mg/dL');
+
+-- +migrate Down
+DROP TABLE IF EXISTS lab_critical_alert_recipients;
+DROP TABLE IF EXISTS lab_critical_alerts;
+DROP TABLE IF EXISTS lab_critical_rules;
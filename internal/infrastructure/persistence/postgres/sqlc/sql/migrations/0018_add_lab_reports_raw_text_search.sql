@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Full-text search over the extracted text. unaccent is not immutable, so it
+-- is wired into a text search configuration instead of wrapping raw_text:
+-- "hepatite" matches "Hepatite", "HEPATITE" and "hepatíte" alike.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE TEXT SEARCH CONFIGURATION portuguese_unaccent (COPY = portuguese);
+ALTER TEXT SEARCH CONFIGURATION portuguese_unaccent
+    ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+
+ALTER TABLE lab_reports
+    ADD COLUMN raw_text_tsv TSVECTOR
+        GENERATED ALWAYS AS (to_tsvector('portuguese_unaccent'::regconfig, COALESCE(raw_text, ''))) STORED;
+
+CREATE INDEX idx_lab_reports_raw_text_tsv ON lab_reports USING GIN (raw_text_tsv);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_lab_reports_raw_text_tsv;
+ALTER TABLE lab_reports DROP COLUMN IF EXISTS raw_text_tsv;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS portuguese_unaccent;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Values computed from other items of the same report (eGFR, LDL, non-HDL,
+-- HOMA-IR). formula names the equation; derived items are never edited,
+-- only recomputed.
+ALTER TABLE lab_result_items
+    ADD COLUMN derived BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN formula TEXT
+        CHECK (formula IN ('ckd-epi-2021', 'friedewald', 'martin-hopkins', 'non-hdl', 'homa-ir')),
+    ADD CONSTRAINT lab_result_items_derived_formula CHECK (derived = (formula IS NOT NULL));
+
+INSERT INTO lab_analytes (code, loinc_code, display_name, default_unit) VALUES
+    ('homa-ir', NULL, 'HOMA-IR', '1')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO lab_analyte_synonyms (name_key, analyte_code, name) VALUES
+    ('homa ir', 'homa-ir', 'HOMA-IR'),
+    ('indice homa ir', 'homa-ir', 'Índice HOMA-IR'),
+    ('homa', 'homa-ir', 'HOMA')
+ON CONFLICT (name_key) DO NOTHING;
+
+-- +migrate Down
+DELETE FROM lab_result_items WHERE derived;
+DELETE FROM lab_analytes WHERE code = 'homa-ir';
+ALTER TABLE lab_result_items
+    DROP CONSTRAINT IF EXISTS lab_result_items_derived_formula,
+    DROP COLUMN IF EXISTS formula,
+    DROP COLUMN IF EXISTS derived;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Platform administrators manage global data (analyte catalog, critical value
+-- rules). There is no sign-up for them: promote an existing account with
+--   UPDATE users SET account_type = 'admin' WHERE id = '<uuid>';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_account_type_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_account_type_check CHECK (account_type IN ('professional', 'basic_care', 'admin'));
+
+-- +migrate Down
+UPDATE users SET account_type = 'basic_care' WHERE account_type = 'admin';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_account_type_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_account_type_check CHECK (account_type IN ('professional', 'basic_care'));
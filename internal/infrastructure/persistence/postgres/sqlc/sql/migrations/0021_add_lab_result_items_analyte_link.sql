@@ -0,0 +1,13 @@
+-- +migrate Up
+-- How analyte_code was set: 'name' follows the catalog (synonym changes and
+-- remaps relink it); 'code' came from a code sent with the result (LOINC in
+-- FHIR/HL7 v2) or from a formula, and is never relinked by name.
+ALTER TABLE lab_result_items
+    ADD COLUMN analyte_link TEXT CHECK (analyte_link IN ('name', 'code'));
+
+UPDATE lab_result_items
+SET analyte_link = CASE WHEN derived THEN 'code' ELSE 'name' END
+WHERE analyte_code IS NOT NULL;
+
+-- +migrate Down
+ALTER TABLE lab_result_items DROP COLUMN IF EXISTS analyte_link;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Nothing looks up jobs by report anymore (the FHIR export reads document_uri
+-- from the report itself), so the index from 0010 only costs writes.
+DROP INDEX IF EXISTS idx_lab_processing_jobs_report;
+
+-- +migrate Down
+CREATE INDEX IF NOT EXISTS idx_lab_processing_jobs_report ON lab_processing_jobs(lab_report_id);
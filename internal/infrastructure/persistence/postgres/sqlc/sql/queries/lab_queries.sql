@@ -16,12 +16,22 @@ INSERT INTO lab_reports (
     report_date,
     raw_text,
     uploaded_by_user_id,
-    fingerprint
+    fingerprint,
+    document_uri,
+    mime_type,
+    source,
+    status,
+    content_sha256,
+    identity_status,
+    identity_score,
+    field_confidence,
+    review_status
 )
 VALUES (
     $1, $2, $3, $4, $5, $6,
     $7, $8, $9, $10, $11, $12,
-    $13
+    $13, $14, $15, $16, $17, $18,
+    $19, $20, $21, $22
 )
 RETURNING
     id,
@@ -37,6 +47,18 @@ RETURNING
     raw_text,
     uploaded_by_user_id,
     fingerprint,
+    document_uri,
+    mime_type,
+    content_sha256,
+    identity_status,
+    identity_score,
+    field_confidence,
+    review_status,
+    reviewed_by_user_id,
+    reviewed_at,
+    source,
+    status,
+    version,
     created_at,
     updated_at;
 
@@ -60,9 +82,23 @@ INSERT INTO lab_result_items (
     parameter_name,
     result_value,
     result_unit,
-    reference_text
+    reference_text,
+    numeric_value,
+    comparator,
+    qualitative_value,
+    reference_low,
+    reference_high,
+    interpretation,
+    analyte_code,
+    ucum_unit,
+    confidence,
+    field_confidence,
+    confirmed,
+    derived,
+    formula,
+    analyte_link
 )
-VALUES ($1,$2,$3,$4,$5,$6)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20)
 RETURNING id;
 
 -- ============================================================
@@ -84,6 +120,18 @@ SELECT
     raw_text,
     uploaded_by_user_id,
     fingerprint,
+    document_uri,
+    mime_type,
+    content_sha256,
+    identity_status,
+    identity_score,
+    field_confidence,
+    review_status,
+    reviewed_by_user_id,
+    reviewed_at,
+    source,
+    status,
+    version,
     created_at,
     updated_at
 FROM lab_reports
@@ -105,6 +153,14 @@ ORDER BY test_name;
 -- Dedupe (Existence checks)
 -- ============================================================
 
+-- name: FindLabReportIDByContentHash :one
+SELECT id
+FROM lab_reports
+WHERE patient_id = $1
+  AND content_sha256 = $2
+ORDER BY created_at
+LIMIT 1;
+
 -- name: ExistsLabReportByPatientAndFingerprint :one
 SELECT EXISTS(
   SELECT 1
@@ -126,6 +182,9 @@ SELECT
     report_date,
     uploaded_by_user_id,
     fingerprint,
+    identity_status,
+    review_status,
+    status,
     created_at,
     updated_at
 FROM lab_reports
@@ -133,6 +192,30 @@ WHERE patient_id = $1
 ORDER BY report_date DESC NULLS LAST, created_at DESC
 LIMIT $2 OFFSET $3;
 
+-- Ranked full-text search over raw_text; snippet marks the hits with <mark>.
+-- name: SearchLabReportsByRawText :many
+SELECT
+    id,
+    lab_name,
+    report_date,
+    source,
+    status,
+    created_at,
+    ts_rank(raw_text_tsv, websearch_to_tsquery('portuguese_unaccent', sqlc.arg('query')::text))::float8 AS rank,
+    -- O texto é escapado antes do destaque: o <mark> do ts_headline é a única
+    -- marcação HTML do trecho.
+    ts_headline(
+        'portuguese_unaccent',
+        replace(replace(replace(COALESCE(raw_text, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+        websearch_to_tsquery('portuguese_unaccent', sqlc.arg('query')::text),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=25, MinWords=8, FragmentDelimiter=" … "'
+    )::text AS snippet
+FROM lab_reports
+WHERE patient_id = sqlc.arg('patient_id')
+  AND raw_text_tsv @@ websearch_to_tsquery('portuguese_unaccent', sqlc.arg('query')::text)
+ORDER BY rank DESC, report_date DESC NULLS LAST, created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
 -- name: ListLabResultsByReportID :many
 SELECT
   id, lab_report_id, test_name, material, method, collected_at, release_at
@@ -142,7 +225,10 @@ ORDER BY collected_at NULLS LAST, id;
 
 -- name: ListLabResultItemsByResultID :many
 SELECT
-  id, lab_result_id, parameter_name, result_value, result_unit, reference_text
+  id, lab_result_id, parameter_name, result_value, result_unit, reference_text,
+  numeric_value, comparator, qualitative_value, reference_low, reference_high, interpretation,
+  analyte_code, ucum_unit, confidence, field_confidence, confirmed, derived, formula,
+  analyte_link
 FROM lab_result_items
 WHERE lab_result_id = $1
 ORDER BY id;
@@ -151,23 +237,382 @@ ORDER BY id;
 -- Timeline
 -- ============================================================
 
+-- Matches the exact parameter name or, when it resolves to a catalog analyte,
+-- every item linked to that analyte.
 -- name: ListLabItemTimelineByPatientAndParameter :many
+-- Period filters use the collection time, falling back to the report date.
+SELECT
+  lr.id           AS report_id,
+  r.id          AS lab_result_id,
+  i.id          AS item_id,
+  lr.report_date  AS report_date,
+  r.collected_at,
+  r.test_name   AS test_name,
+  i.parameter_name,
+  i.analyte_code,
+  i.result_value,
+  i.result_unit,
+  i.reference_text,
+  i.numeric_value,
+  i.ucum_unit,
+  i.reference_low,
+  i.reference_high,
+  i.interpretation,
+  i.derived,
+  i.formula,
+  a.default_unit AS canonical_unit
+FROM lab_result_items i
+JOIN lab_results r ON i.lab_result_id = r.id
+JOIN lab_reports      lr  ON r.lab_report_id      = lr.id
+LEFT JOIN lab_analytes a  ON a.code               = i.analyte_code
+WHERE lr.patient_id      = sqlc.arg(patient_id)
+  AND (
+        i.parameter_name = sqlc.arg(parameter_name)
+     OR i.analyte_code   = sqlc.arg(parameter_name)
+     OR i.analyte_code   = (
+          SELECT s.analyte_code
+          FROM lab_analyte_synonyms s
+          WHERE s.name_key = sqlc.arg(name_key)
+        )
+  )
+  AND (sqlc.narg(from_time)::timestamptz IS NULL
+       OR COALESCE(r.collected_at, lr.report_date) >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL
+       OR COALESCE(r.collected_at, lr.report_date) <= sqlc.narg(to_time))
+ORDER BY COALESCE(r.collected_at, lr.report_date) DESC NULLS LAST, lr.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- Every item of the patient in the period, for the cumulative report and exports.
+-- name: ListLabItemTimelineByPatient :many
 SELECT
   lr.id           AS report_id,
   r.id          AS lab_result_id,
   i.id          AS item_id,
   lr.report_date  AS report_date,
+  r.collected_at,
   r.test_name   AS test_name,
   i.parameter_name,
+  i.analyte_code,
+  i.result_value,
+  i.result_unit,
+  i.reference_text,
+  i.numeric_value,
+  i.ucum_unit,
+  i.reference_low,
+  i.reference_high,
+  i.interpretation,
+  i.derived,
+  i.formula,
+  a.default_unit AS canonical_unit
+FROM lab_result_items i
+JOIN lab_results r ON i.lab_result_id = r.id
+JOIN lab_reports      lr  ON r.lab_report_id      = lr.id
+LEFT JOIN lab_analytes a  ON a.code               = i.analyte_code
+WHERE lr.patient_id      = sqlc.arg(patient_id)
+  AND (sqlc.narg(from_time)::timestamptz IS NULL
+       OR COALESCE(r.collected_at, lr.report_date) >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL
+       OR COALESCE(r.collected_at, lr.report_date) <= sqlc.narg(to_time))
+ORDER BY COALESCE(r.collected_at, lr.report_date) DESC NULLS LAST, lr.created_at DESC, i.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- ============================================================
+-- History export
+-- ============================================================
+
+-- One row per item with its report and result, oldest collection first.
+-- name: ListLabHistoryByPatient :many
+SELECT
+  lr.id           AS report_id,
+  lr.report_date,
+  lr.lab_name,
+  lr.source,
+  lr.status,
+  r.id            AS result_id,
+  r.test_name,
+  r.material,
+  r.method,
+  r.collected_at,
+  i.id            AS item_id,
+  i.parameter_name,
   i.result_value,
-  i.result_unit
+  i.result_unit,
+  i.reference_text,
+  i.numeric_value,
+  i.ucum_unit,
+  i.comparator,
+  i.qualitative_value,
+  i.reference_low,
+  i.reference_high,
+  i.interpretation,
+  i.analyte_code,
+  i.derived,
+  i.formula,
+  a.default_unit  AS canonical_unit
 FROM lab_result_items i
 JOIN lab_results r ON i.lab_result_id = r.id
 JOIN lab_reports      lr  ON r.lab_report_id      = lr.id
-WHERE lr.patient_id      = $1
-  AND i.parameter_name = $2
-ORDER BY lr.report_date DESC NULLS LAST, lr.created_at DESC
-LIMIT $3 OFFSET $4;
+LEFT JOIN lab_analytes a  ON a.code               = i.analyte_code
+WHERE lr.patient_id = sqlc.arg(patient_id)
+ORDER BY COALESCE(r.collected_at, lr.report_date) ASC NULLS LAST, lr.created_at, r.id, i.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- ============================================================
+-- Analyte catalog
+-- ============================================================
+
+-- name: ListLabAnalytes :many
+SELECT code, loinc_code, display_name, default_unit
+FROM lab_analytes
+ORDER BY display_name;
+
+-- name: GetLabAnalyteByCode :one
+SELECT code, loinc_code, display_name, default_unit
+FROM lab_analytes
+WHERE code = $1;
+
+-- name: ListLabAnalyteSynonyms :many
+SELECT name_key, analyte_code, name
+FROM lab_analyte_synonyms
+ORDER BY analyte_code, name;
+
+-- name: GetLabAnalyteSynonymByKey :one
+SELECT name_key, analyte_code, name
+FROM lab_analyte_synonyms
+WHERE name_key = $1;
+
+-- name: CreateLabAnalyteSynonym :exec
+INSERT INTO lab_analyte_synonyms (name_key, analyte_code, name)
+VALUES ($1, $2, $3);
+
+-- name: DeleteLabAnalyteSynonym :execrows
+DELETE FROM lab_analyte_synonyms
+WHERE name_key = $1
+  AND analyte_code = $2;
+
+-- name: ListDistinctLabParameterNames :many
+SELECT DISTINCT parameter_name
+FROM lab_result_items
+WHERE NOT derived
+  AND analyte_link IS DISTINCT FROM 'code'
+ORDER BY parameter_name;
+
+-- name: ListLabParameterNamesLike :many
+-- Candidates for a name key: the name folded like labs.NormalizeAnalyteName
+-- (lower case, same accents) contains the key words in order. Callers filter
+-- the exact matches with labs.MatchKeys.
+SELECT DISTINCT parameter_name
+FROM lab_result_items
+WHERE NOT derived
+  AND analyte_link IS DISTINCT FROM 'code'
+  AND translate(lower(parameter_name), 'áàâãéêíóôõúüç', 'aaaaeeiooouuc') LIKE sqlc.arg(pattern)::text
+ORDER BY parameter_name;
+
+-- Only touches name-linked rows whose link actually changes; derived items and
+-- items linked by code (LOINC from FHIR/HL7 v2) keep theirs.
+-- name: SetLabResultItemsAnalyteByParameterName :execrows
+UPDATE lab_result_items
+SET analyte_code = sqlc.narg(analyte_code),
+    analyte_link = CASE WHEN sqlc.narg(analyte_code)::text IS NULL THEN NULL ELSE 'name' END
+WHERE parameter_name = sqlc.arg(parameter_name)
+  AND NOT derived
+  AND analyte_link IS DISTINCT FROM 'code'
+  AND analyte_code IS DISTINCT FROM sqlc.narg(analyte_code);
+
+-- ============================================================
+-- Amendments
+-- ============================================================
+
+-- name: AmendLabReport :execrows
+-- Optimistic lock: only applies when the stored version is still the one read.
+UPDATE lab_reports
+SET patient_name       = sqlc.narg(patient_name),
+    lab_name           = sqlc.narg(lab_name),
+    lab_phone          = sqlc.narg(lab_phone),
+    insurance_provider = sqlc.narg(insurance_provider),
+    requesting_doctor  = sqlc.narg(requesting_doctor),
+    technical_manager  = sqlc.narg(technical_manager),
+    report_date        = sqlc.narg(report_date),
+    status             = sqlc.arg(status),
+    review_status      = sqlc.narg(review_status),
+    reviewed_by_user_id = sqlc.narg(reviewed_by_user_id),
+    reviewed_at        = sqlc.narg(reviewed_at),
+    version            = sqlc.arg(version),
+    updated_at         = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id)
+  AND version = sqlc.arg(expected_version);
+
+-- ============================================================
+-- Critical values
+-- ============================================================
+
+-- name: ListLabCriticalRules :many
+SELECT id, analyte_code, sex, min_age, max_age, low, high, unit, created_at, created_by
+FROM lab_critical_rules
+WHERE deleted_at IS NULL
+ORDER BY analyte_code, sex NULLS FIRST, min_age NULLS FIRST, id;
+
+-- name: CreateLabCriticalRule :exec
+INSERT INTO lab_critical_rules (id, analyte_code, sex, min_age, max_age, low, high, unit, created_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- Soft delete: alerts keep pointing at the rule that fired them.
+-- name: DeleteLabCriticalRule :execrows
+UPDATE lab_critical_rules
+SET deleted_at = sqlc.arg(deleted_at),
+    deleted_by = sqlc.arg(deleted_by)
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: SetLabResultItemInterpretation :exec
+UPDATE lab_result_items
+SET interpretation = $2
+WHERE id = $1;
+
+-- name: ListLabCriticalAlertItemIDsByReport :many
+SELECT lab_result_item_id
+FROM lab_critical_alerts
+WHERE lab_report_id = $1;
+
+-- name: CreateLabCriticalAlert :exec
+INSERT INTO lab_critical_alerts (
+    id, lab_report_id, patient_id, lab_result_item_id, rule_id, analyte_code,
+    parameter_name, value, limit_value, unit, interpretation, created_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+
+-- name: CreateLabCriticalAlertRecipient :exec
+INSERT INTO lab_critical_alert_recipients (alert_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (alert_id, user_id) DO NOTHING;
+
+-- name: MarkLabCriticalAlertNotified :exec
+UPDATE lab_critical_alert_recipients
+SET notified_at = $3
+WHERE alert_id = $1
+  AND user_id = $2;
+
+-- name: ListLabCriticalAlertsByRecipient :many
+-- Alerts sent to the user, newest first; only_open hides acknowledged ones.
+SELECT
+    a.id,
+    a.lab_report_id,
+    a.patient_id,
+    a.lab_result_item_id,
+    a.rule_id,
+    a.analyte_code,
+    a.parameter_name,
+    a.value,
+    a.limit_value,
+    a.unit,
+    a.interpretation,
+    a.created_at,
+    r.notified_at,
+    r.acknowledged_at
+FROM lab_critical_alerts a
+JOIN lab_critical_alert_recipients r ON r.alert_id = a.id
+WHERE r.user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(only_open)::boolean OR r.acknowledged_at IS NULL)
+ORDER BY a.created_at DESC, a.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: AcknowledgeLabCriticalAlert :execrows
+-- Keeps the first acknowledgement; zero rows when the user is not a recipient.
+UPDATE lab_critical_alert_recipients
+SET acknowledged_at = COALESCE(acknowledged_at, sqlc.arg(acknowledged_at))
+WHERE alert_id = sqlc.arg(alert_id)
+  AND user_id = sqlc.arg(user_id);
+
+-- ============================================================
+-- Review
+-- ============================================================
+
+-- name: ListPendingLabReviews :many
+-- Reports waiting for review on patients the user owns or has active access to.
+-- pending_items counts unconfirmed items with any confidence below 0.8
+-- (labs.LowConfidenceThreshold).
+SELECT
+    r.id,
+    r.patient_id,
+    p.full_name AS patient_name,
+    r.lab_name,
+    r.report_date,
+    r.created_at,
+    (
+        SELECT count(*)
+        FROM lab_result_items i
+        JOIN lab_results lr ON lr.id = i.lab_result_id
+        WHERE lr.lab_report_id = r.id
+          AND NOT i.confirmed
+          AND (
+              i.confidence < 0.8
+              OR EXISTS (
+                  SELECT 1 FROM jsonb_each_text(i.field_confidence) fc
+                  WHERE fc.value::double precision < 0.8
+              )
+          )
+    )::bigint AS pending_items
+FROM lab_reports r
+JOIN patients p ON p.id = r.patient_id
+WHERE r.review_status = 'needs_review'
+  AND p.deleted_at IS NULL
+  AND (
+      p.owner_user_id = sqlc.arg(user_id)
+      OR EXISTS (
+          SELECT 1 FROM patient_access pa
+          WHERE pa.patient_id = r.patient_id
+            AND pa.grantee_id = sqlc.arg(user_id)
+            AND pa.revoked_at IS NULL
+      )
+  )
+ORDER BY r.created_at, r.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateLabResult :exec
+UPDATE lab_results
+SET test_name    = $2,
+    material     = $3,
+    method       = $4,
+    collected_at = $5,
+    release_at   = $6
+WHERE id = $1;
+
+-- name: UpdateLabResultItem :exec
+UPDATE lab_result_items
+SET parameter_name    = $2,
+    result_value      = $3,
+    result_unit       = $4,
+    reference_text    = $5,
+    numeric_value     = $6,
+    comparator        = $7,
+    qualitative_value = $8,
+    reference_low     = $9,
+    reference_high    = $10,
+    interpretation    = $11,
+    analyte_code      = $12,
+    ucum_unit         = $13,
+    confirmed         = $14,
+    analyte_link      = $15
+WHERE id = $1;
+
+-- name: CreateLabReportRevision :exec
+INSERT INTO lab_report_revisions (
+    id,
+    lab_report_id,
+    version,
+    status,
+    changed_by_user_id,
+    changed_at,
+    reason,
+    changes
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListLabReportRevisions :many
+SELECT id, lab_report_id, version, status, changed_by_user_id, changed_at, reason, changes
+FROM lab_report_revisions
+WHERE lab_report_id = $1
+ORDER BY version DESC;
 
 -- ============================================================
 -- Deletes
@@ -183,6 +628,126 @@ WHERE lab_result_id IN (
 DELETE FROM lab_results
 WHERE lab_report_id = $1;
 
+-- Derived items are replaced as a whole when the report is recomputed; the
+-- results that only held derived items go with them. The CTE runs on the
+-- same snapshot, so the NOT EXISTS still sees the deleted rows.
+-- name: DeleteDerivedLabItemsByReportID :exec
+WITH deleted AS (
+  DELETE FROM lab_result_items i
+  USING lab_results r
+  WHERE i.lab_result_id = r.id
+    AND r.lab_report_id = $1
+    AND i.derived
+  RETURNING i.lab_result_id
+)
+DELETE FROM lab_results r
+WHERE r.id IN (SELECT lab_result_id FROM deleted)
+  AND NOT EXISTS (
+    SELECT 1 FROM lab_result_items i WHERE i.lab_result_id = r.id AND NOT i.derived
+  );
+
 -- name: DeleteLabReport :execrows
 DELETE FROM lab_reports
 WHERE id = $1;
+
+-- ============================================================
+-- Processing jobs
+-- ============================================================
+
+-- name: CreateLabProcessingJob :one
+INSERT INTO lab_processing_jobs (
+    id,
+    patient_id,
+    uploaded_by_user_id,
+    document_uri,
+    mime_type,
+    content_sha256,
+    identity_override,
+    status
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 'queued')
+RETURNING *;
+
+-- name: GetLabProcessingJobByID :one
+SELECT *
+FROM lab_processing_jobs
+WHERE id = $1;
+
+-- Jobs still being processed for the same file and patient.
+-- name: FindActiveLabProcessingJobIDByContentHash :one
+SELECT id
+FROM lab_processing_jobs
+WHERE patient_id = $1
+  AND content_sha256 = $2
+  AND status IN ('queued', 'running')
+ORDER BY created_at
+LIMIT 1;
+
+-- Claims the oldest queued job. SKIP LOCKED lets several workers poll concurrently.
+-- name: ClaimNextLabProcessingJob :one
+UPDATE lab_processing_jobs
+SET
+    status     = 'running',
+    attempts   = attempts + 1,
+    started_at = now(),
+    updated_at = now()
+WHERE id = (
+    SELECT id
+    FROM lab_processing_jobs
+    WHERE status = 'queued'
+    ORDER BY created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- The Mark* updates only apply while the caller still owns the claim: a job
+-- requeued as stale and claimed again has a different attempts value.
+-- name: MarkLabProcessingJobSucceeded :execrows
+UPDATE lab_processing_jobs
+SET
+    status        = 'succeeded',
+    lab_report_id = $2,
+    error_code    = NULL,
+    error_message = NULL,
+    finished_at   = now(),
+    updated_at    = now()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = $3;
+
+-- name: MarkLabProcessingJobFailed :execrows
+UPDATE lab_processing_jobs
+SET
+    status        = 'failed',
+    error_code    = $2,
+    error_message = $3,
+    finished_at   = now(),
+    updated_at    = now()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = $4;
+
+-- Jobs left in running by a crashed worker go back to the queue.
+-- name: RequeueStaleLabProcessingJobs :execrows
+UPDATE lab_processing_jobs
+SET
+    status     = 'queued',
+    updated_at = now()
+WHERE status = 'running'
+  AND started_at < sqlc.arg('started_before')
+  AND attempts < sqlc.arg('max_attempts')::int;
+
+-- Stale jobs that already used every attempt (e.g. a document that crashes
+-- the worker) are failed instead of requeued.
+-- name: FailStaleLabProcessingJobs :execrows
+UPDATE lab_processing_jobs
+SET
+    status        = 'failed',
+    error_code    = sqlc.arg('error_code'),
+    error_message = sqlc.arg('error_message'),
+    finished_at   = now(),
+    updated_at    = now()
+WHERE status = 'running'
+  AND started_at < sqlc.arg('started_before')
+  AND attempts >= sqlc.arg('max_attempts')::int;
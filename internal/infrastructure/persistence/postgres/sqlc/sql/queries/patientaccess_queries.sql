@@ -49,6 +49,14 @@ FROM patient_access
 WHERE grantee_id = $1
 ORDER BY patient_id;
 
+-- name: ListActiveGranteesByRelation :many
+SELECT grantee_id
+FROM patient_access
+WHERE patient_id = $1
+  AND relation_type = $2
+  AND revoked_at IS NULL
+ORDER BY grantee_id;
+
 -- name: RevokePatientAccess :execrows
 UPDATE patient_access
 SET revoked_at = now()
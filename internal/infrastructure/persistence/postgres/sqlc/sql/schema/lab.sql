@@ -1,3 +1,20 @@
+-- Analyte catalog: canonical analytes (LOINC) and the names labs print for them.
+CREATE TABLE lab_analytes (
+    code         TEXT PRIMARY KEY,
+    loinc_code   TEXT UNIQUE,
+    display_name TEXT NOT NULL,
+    default_unit TEXT,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE lab_analyte_synonyms (
+    name_key     TEXT PRIMARY KEY,
+    analyte_code TEXT NOT NULL REFERENCES lab_analytes(code) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
 -- Lab reports: optional extracted metadata, linked to patient and uploader.
 CREATE TABLE lab_reports (
     id                 UUID PRIMARY KEY,
@@ -12,7 +29,31 @@ CREATE TABLE lab_reports (
     technical_manager  TEXT,
     report_date        TIMESTAMP WITH TIME ZONE,
     raw_text           TEXT,
+    -- Full-text index of raw_text (portuguese_unaccent, see migration 0018).
+    raw_text_tsv       TSVECTOR
+        GENERATED ALWAYS AS (to_tsvector('portuguese_unaccent'::regconfig, COALESCE(raw_text, ''))) STORED,
     fingerprint        TEXT,
+    -- Original document (storage URI); NULL when the report did not come from a file.
+    document_uri       TEXT,
+    mime_type          TEXT,
+    -- SHA-256 (hex) of the uploaded file, checked before extraction.
+    content_sha256     TEXT,
+    -- Cross-check of the printed identity against the patient (documents only).
+    identity_status    TEXT
+        CHECK (identity_status IN ('verified', 'flagged', 'unverified', 'overridden')),
+    identity_score     DOUBLE PRECISION,
+    -- Extraction confidence per header field; review of low-confidence reports.
+    field_confidence   JSONB,
+    review_status      TEXT
+        CHECK (review_status IN ('needs_review', 'approved')),
+    reviewed_by_user_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    reviewed_at        TIMESTAMP WITH TIME ZONE,
+    source             TEXT NOT NULL
+        CHECK (source IN ('document', 'manual', 'fhir', 'hl7v2')),
+    -- FHIR DiagnosticReport.status; version grows with each amendment.
+    status             TEXT NOT NULL DEFAULT 'final'
+        CHECK (status IN ('preliminary', 'final', 'amended', 'corrected')),
+    version            INTEGER NOT NULL DEFAULT 1,
     created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
     updated_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
 );
@@ -35,12 +76,128 @@ CREATE TABLE lab_result_items (
     parameter_name TEXT NOT NULL,
     result_value   TEXT,
     result_unit    TEXT,
-    reference_text TEXT
+    reference_text TEXT,
+    -- Structured values parsed from result_value/reference_text at ingestion.
+    numeric_value     DOUBLE PRECISION,
+    ucum_unit         TEXT,
+    comparator        TEXT
+        CHECK (comparator IN ('<', '<=', '>', '>=')),
+    qualitative_value TEXT,
+    reference_low     DOUBLE PRECISION,
+    reference_high    DOUBLE PRECISION,
+    interpretation    TEXT
+        CHECK (interpretation IN ('L', 'N', 'H', 'LL', 'HH', 'A')),
+    analyte_code      TEXT REFERENCES lab_analytes(code) ON DELETE SET NULL,
+    -- 'name': linked by parameter name (relinked by remaps); 'code': by LOINC
+    -- or formula (kept by remaps).
+    analyte_link      TEXT CHECK (analyte_link IN ('name', 'code')),
+    -- Extraction confidence (item and per field); confirmed once reviewed.
+    confidence        DOUBLE PRECISION,
+    field_confidence  JSONB,
+    confirmed         BOOLEAN NOT NULL DEFAULT false,
+    -- Computed from other items of the report; formula names the equation.
+    derived           BOOLEAN NOT NULL DEFAULT false,
+    formula           TEXT
+        CHECK (formula IN ('ckd-epi-2021', 'friedewald', 'martin-hopkins', 'non-hdl', 'homa-ir')),
+    CONSTRAINT lab_result_items_derived_formula CHECK (derived = (formula IS NOT NULL))
+);
+
+-- Amendment history: one row per version, changes keeps each previous value.
+CREATE TABLE lab_report_revisions (
+    id                 UUID PRIMARY KEY,
+    lab_report_id      UUID NOT NULL REFERENCES lab_reports(id) ON DELETE CASCADE,
+    version            INTEGER NOT NULL,
+    status             TEXT NOT NULL
+        CHECK (status IN ('preliminary', 'final', 'amended', 'corrected')),
+    changed_by_user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    changed_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    reason             TEXT,
+    changes            JSONB NOT NULL,
+    UNIQUE (lab_report_id, version)
 );
 
 -- Useful indexes/uniqueness for lookups and idempotency
 CREATE UNIQUE INDEX idx_lab_reports_fingerprint ON lab_reports(fingerprint) WHERE fingerprint IS NOT NULL;
 CREATE INDEX idx_lab_reports_patient ON lab_reports(patient_id);
 CREATE INDEX idx_lab_reports_report_date ON lab_reports(report_date);
+CREATE UNIQUE INDEX idx_lab_reports_content_sha256 ON lab_reports(patient_id, content_sha256) WHERE content_sha256 IS NOT NULL;
+CREATE INDEX idx_lab_reports_needs_review ON lab_reports(created_at) WHERE review_status = 'needs_review';
+CREATE INDEX idx_lab_reports_raw_text_tsv ON lab_reports USING GIN (raw_text_tsv);
 CREATE INDEX idx_lab_results_report ON lab_results(lab_report_id);
 CREATE INDEX idx_lab_result_items_result ON lab_result_items(lab_result_id);
+CREATE INDEX idx_lab_result_items_analyte ON lab_result_items(analyte_code);
+CREATE INDEX idx_lab_analyte_synonyms_analyte ON lab_analyte_synonyms(analyte_code);
+
+-- Critical (panic) limits per analyte, sex and age band (years, max exclusive).
+CREATE TABLE lab_critical_rules (
+    id           UUID PRIMARY KEY,
+    analyte_code TEXT NOT NULL REFERENCES lab_analytes(code) ON DELETE CASCADE,
+    sex          TEXT CHECK (sex IN ('MALE', 'FEMALE', 'OTHER', 'UNKNOWN')),
+    min_age      INTEGER CHECK (min_age >= 0),
+    max_age      INTEGER CHECK (max_age > 0),
+    low          DOUBLE PRECISION,
+    high         DOUBLE PRECISION,
+    unit         TEXT NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    created_by   UUID REFERENCES users(id) ON DELETE SET NULL,
+    deleted_at   TIMESTAMP WITH TIME ZONE,
+    deleted_by   UUID REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (low IS NOT NULL OR high IS NOT NULL),
+    CHECK (min_age IS NULL OR max_age IS NULL OR min_age < max_age)
+);
+
+-- Critical alerts: one per item beyond a limit, with the professionals notified.
+CREATE TABLE lab_critical_alerts (
+    id                 UUID PRIMARY KEY,
+    lab_report_id      UUID NOT NULL REFERENCES lab_reports(id) ON DELETE CASCADE,
+    patient_id         UUID NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    lab_result_item_id UUID NOT NULL REFERENCES lab_result_items(id) ON DELETE CASCADE,
+    rule_id            UUID REFERENCES lab_critical_rules(id) ON DELETE SET NULL,
+    analyte_code       TEXT NOT NULL,
+    parameter_name     TEXT NOT NULL,
+    value              DOUBLE PRECISION NOT NULL,
+    limit_value        DOUBLE PRECISION NOT NULL,
+    unit               TEXT NOT NULL,
+    interpretation     TEXT NOT NULL CHECK (interpretation IN ('LL', 'HH')),
+    created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE lab_critical_alert_recipients (
+    alert_id        UUID NOT NULL REFERENCES lab_critical_alerts(id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notified_at     TIMESTAMP WITH TIME ZONE,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (alert_id, user_id)
+);
+
+CREATE UNIQUE INDEX ux_lab_critical_rules_scope
+    ON lab_critical_rules (analyte_code, COALESCE(sex, ''), COALESCE(min_age, -1), COALESCE(max_age, -1))
+    WHERE deleted_at IS NULL;
+CREATE INDEX idx_lab_critical_alerts_report ON lab_critical_alerts(lab_report_id);
+CREATE INDEX idx_lab_critical_alert_recipients_open ON lab_critical_alert_recipients(user_id) WHERE acknowledged_at IS NULL;
+
+-- Lab processing jobs: async extraction queue for uploaded lab documents.
+CREATE TABLE lab_processing_jobs (
+    id                  UUID PRIMARY KEY,
+    patient_id          UUID NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    uploaded_by_user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    document_uri        TEXT NOT NULL,
+    mime_type           TEXT NOT NULL,
+    status              TEXT NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    attempts            INTEGER NOT NULL DEFAULT 0,
+    lab_report_id       UUID REFERENCES lab_reports(id) ON DELETE SET NULL,
+    error_code          TEXT,
+    error_message       TEXT,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    started_at          TIMESTAMP WITH TIME ZONE,
+    finished_at         TIMESTAMP WITH TIME ZONE,
+    -- Carried to the report so re-uploads can be rejected before extraction.
+    content_sha256      TEXT,
+    -- Accept the document even if the identity cross-check fails.
+    identity_override   BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX idx_lab_processing_jobs_queued ON lab_processing_jobs(created_at) WHERE status = 'queued';
+CREATE INDEX idx_lab_processing_jobs_patient ON lab_processing_jobs(patient_id);
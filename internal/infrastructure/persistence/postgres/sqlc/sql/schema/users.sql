@@ -8,7 +8,7 @@ CREATE TABLE users (
   birth_date DATE NOT NULL,
   cpf TEXT NOT NULL UNIQUE,
   phone TEXT NOT NULL,
-  account_type TEXT NOT NULL CHECK (account_type IN ('professional', 'basic_care')),
+  account_type TEXT NOT NULL CHECK (account_type IN ('professional', 'basic_care', 'admin')),
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
   updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
   deleted_at TIMESTAMP WITH TIME ZONE
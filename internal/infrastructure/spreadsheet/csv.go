@@ -0,0 +1,79 @@
+// internal/infrastructure/spreadsheet/csv.go
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+)
+
+// CSVFormat grava CSV (RFC 4180) em UTF-8, separado por vírgula, com ponto
+// decimal e datas em RFC 3339.
+type CSVFormat struct{}
+
+var _ document.TableFormat = CSVFormat{}
+
+func NewCSVFormat() CSVFormat {
+	return CSVFormat{}
+}
+
+// ContentType implementa [document.TableFormat].
+func (CSVFormat) ContentType() string { return "text/csv; charset=utf-8" }
+
+// Extension implementa [document.TableFormat].
+func (CSVFormat) Extension() string { return "csv" }
+
+// NewTableWriter implementa [document.TableFormat]. CSV não tem abas.
+func (CSVFormat) NewTableWriter(w io.Writer, sheet string) (document.TableWriter, error) {
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) WriteRow(cells []document.Cell) error {
+	c.record = c.record[:0]
+	for _, cell := range cells {
+		switch v := cell.(type) {
+		case nil:
+			c.record = append(c.record, "")
+		case string:
+			c.record = append(c.record, escapeFormula(v))
+		case float64:
+			c.record = append(c.record, strconv.FormatFloat(v, 'f', -1, 64))
+		case time.Time:
+			c.record = append(c.record, v.UTC().Format(time.RFC3339))
+		default:
+			return fmt.Errorf("spreadsheet: tipo de célula não suportado: %T", cell)
+		}
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeFormula evita que textos vindos dos laudos virem fórmula ao abrir o
+// CSV numa planilha (=, +, -, @ no início).
+func escapeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+)
+
+func TestCSVFormat(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCSVFormat().NewTableWriter(&buf, "Exames")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.WriteHeader([]string{"parameter_name", "numeric_value", "collected_at", "result_value"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	at := time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)
+	rows := [][]document.Cell{
+		{"Glicose, jejum", 99.5, at, "=HYPERLINK(\"x\")"},
+		{"Hemoglobina", nil, nil, "-"},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d", len(records))
+	}
+	got := records[1]
+	if got[0] != "Glicose, jejum" || got[1] != "99.5" || got[2] != "2026-03-01T08:30:00Z" || got[3] != "'=HYPERLINK(\"x\")" {
+		t.Fatalf("unexpected row: %q", got)
+	}
+	if got := records[2]; got[1] != "" || got[2] != "" || got[3] != "'-" {
+		t.Fatalf("unexpected row: %q", got)
+	}
+}
+
+func TestCSVFormat_UnsupportedCell(t *testing.T) {
+	w, _ := NewCSVFormat().NewTableWriter(&bytes.Buffer{}, "")
+	if err := w.WriteRow([]document.Cell{42}); err == nil {
+		t.Fatalf("expected error for int cell")
+	}
+}
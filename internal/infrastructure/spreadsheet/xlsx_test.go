@@ -0,0 +1,102 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gabrielgcmr/sonnda/internal/domain/document"
+)
+
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Style  string `xml:"s,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXFormat(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXFormat().NewTableWriter(&buf, "Exames: 2026")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.WriteHeader([]string{"parameter_name", "numeric_value", "collected_at"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := w.WriteRow([]document.Cell{"Colesterol <HDL> & \"não\"", 41.5, at}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.WriteRow([]document.Cell{"Sódio", nil, nil}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	parts := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		parts[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Fatalf("missing part %s", name)
+		}
+	}
+	if !bytes.Contains(parts["xl/workbook.xml"], []byte(`name="Exames 2026"`)) {
+		t.Fatalf("expected sanitized sheet name, got %s", parts["xl/workbook.xml"])
+	}
+
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("invalid sheet xml: %v", err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(sheet.Rows))
+	}
+	if h := sheet.Rows[0].Cells[0]; h.Style != "1" || h.Inline != "parameter_name" {
+		t.Fatalf("expected bold header, got %+v", h)
+	}
+	row := sheet.Rows[1].Cells
+	if row[0].Type != "inlineStr" || row[0].Inline != "Colesterol <HDL> & \"não\"" {
+		t.Fatalf("unexpected text cell: %+v", row[0])
+	}
+	if row[1].Ref != "B2" || row[1].Value != "41.5" {
+		t.Fatalf("unexpected number cell: %+v", row[1])
+	}
+	// 2026-03-01 12:00 UTC é o serial 46082,5 do Excel.
+	if row[2].Ref != "C2" || row[2].Style != "2" || row[2].Value != "46082.5" {
+		t.Fatalf("unexpected date cell: %+v", row[2])
+	}
+	if len(sheet.Rows[2].Cells) != 1 {
+		t.Fatalf("expected empty cells to be omitted, got %+v", sheet.Rows[2].Cells)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for in, want := range cases {
+		if got := columnName(in); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -24,6 +24,8 @@ const (
 	RESOURCE_ALREADY_EXISTS ErrorKind = "RESOURCE_ALREADY_EXISTS"
 	//DOMAIN
 	DOMAIN_RULE_VIOLATION ErrorKind = "DOMAIN_RULE_VIOLATION"
+	// Laudo cuja identificação (nome, nascimento, CPF) não confere com o paciente.
+	PATIENT_IDENTITY_MISMATCH ErrorKind = "PATIENT_IDENTITY_MISMATCH"
 	//INFRA
 	INFRA_AUTHENTICATION_ERROR   ErrorKind = "INFRA_AUTHENTICATION_ERROR"
 	INFRA_DATABASE_ERROR         ErrorKind = "INFRA_DATABASE_ERROR"
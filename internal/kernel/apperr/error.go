@@ -11,6 +11,8 @@ type AppError struct {
 	Message    string
 	Cause      error
 	Violations []Violation
+	// ResourceID aponta o recurso já existente em conflitos (ex.: laudo duplicado).
+	ResourceID string
 }
 
 func (e *AppError) Error() string {
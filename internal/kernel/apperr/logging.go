@@ -32,6 +32,10 @@ func LogLevelOf(err error) slog.Level {
 		UPLOAD_SIZE_EXCEEDED:
 		return slog.LevelWarn
 
+	// Possível laudo de outro paciente: vale acompanhar
+	case PATIENT_IDENTITY_MISMATCH:
+		return slog.LevelWarn
+
 	// Tudo que é erro esperado de cliente → Info
 	case AUTH_REQUIRED,
 		AUTH_TOKEN_INVALID,
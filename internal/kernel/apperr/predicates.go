@@ -45,7 +45,7 @@ func IsConflict(err error) bool {
 }
 
 func IsDomainRuleViolation(err error) bool {
-	return HasCode(err, DOMAIN_RULE_VIOLATION)
+	return HasCode(err, DOMAIN_RULE_VIOLATION, PATIENT_IDENTITY_MISMATCH)
 }
 
 func IsRateLimited(err error) bool {